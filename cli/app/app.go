@@ -8,6 +8,8 @@ import (
 	"github.com/nspcc-dev/neo-go/cli/query"
 	"github.com/nspcc-dev/neo-go/cli/server"
 	"github.com/nspcc-dev/neo-go/cli/smartcontract"
+	"github.com/nspcc-dev/neo-go/cli/smartcontract/contractdebug"
+	"github.com/nspcc-dev/neo-go/cli/smartcontract/contracttest"
 	"github.com/nspcc-dev/neo-go/cli/util"
 	"github.com/nspcc-dev/neo-go/cli/vm"
 	"github.com/nspcc-dev/neo-go/cli/wallet"
@@ -32,7 +34,7 @@ func New() *cli.App {
 	ctl.ErrWriter = os.Stdout
 
 	ctl.Commands = append(ctl.Commands, server.NewCommands()...)
-	ctl.Commands = append(ctl.Commands, smartcontract.NewCommands()...)
+	ctl.Commands = append(ctl.Commands, smartcontract.NewCommands(contractdebug.NewCommand(), contracttest.NewCommand())...)
 	ctl.Commands = append(ctl.Commands, wallet.NewCommands()...)
 	ctl.Commands = append(ctl.Commands, vm.NewCommands()...)
 	ctl.Commands = append(ctl.Commands, util.NewCommands()...)
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/hex"
 	"encoding/json"
+	"encoding/xml"
 	"fmt"
 	"math/big"
 	"os"
@@ -17,6 +18,7 @@ import (
 	"github.com/nspcc-dev/neo-go/internal/testchain"
 	"github.com/nspcc-dev/neo-go/internal/testcli"
 	"github.com/nspcc-dev/neo-go/internal/versionutil"
+	"github.com/nspcc-dev/neo-go/pkg/compiler"
 	"github.com/nspcc-dev/neo-go/pkg/config"
 	"github.com/nspcc-dev/neo-go/pkg/core/interop/storage"
 	"github.com/nspcc-dev/neo-go/pkg/core/state"
@@ -367,6 +369,25 @@ func TestContractInitAndCompile(t *testing.T) {
 	})
 }
 
+func TestContractCompileOptimize(t *testing.T) {
+	e := testcli.NewExecutor(t, false)
+	tmpDir := t.TempDir()
+
+	var sizes []int
+	for _, flags := range [][]string{nil, {"-O"}, {"--optimize"}} {
+		nefName := filepath.Join(tmpDir, "deploy.nef")
+		e.Run(t, append([]string{"neo-go", "contract", "compile",
+			"--in", "testdata/deploy/main.go",
+			"--config", "testdata/deploy/neo-go.yml",
+			"--out", nefName, "--manifest", filepath.Join(tmpDir, "deploy.manifest.json")}, flags...)...)
+		f, err := os.ReadFile(nefName)
+		require.NoError(t, err)
+		sizes = append(sizes, len(f))
+	}
+	require.Less(t, sizes[1], sizes[0])
+	require.Equal(t, sizes[1], sizes[2])
+}
+
 // Checks that error is returned if GAS available for test-invoke exceeds
 // GAS needed to be consumed.
 func TestDeployBigContract(t *testing.T) {
@@ -1328,6 +1349,248 @@ func TestContractInspect(t *testing.T) {
 	})
 }
 
+func TestContractDecompile(t *testing.T) {
+	e := testcli.NewExecutor(t, false)
+	const srcPath = "testdata/deploy/main.go"
+	tmpDir := t.TempDir()
+
+	nefName := filepath.Join(tmpDir, "deploy.nef")
+	manifestName := filepath.Join(tmpDir, "deploy.manifest.json")
+	debugName := filepath.Join(tmpDir, "deploy.debug.json")
+	e.Run(t, "neo-go", "contract", "compile",
+		"--in", srcPath,
+		"--config", "testdata/deploy/neo-go.yml",
+		"--out", nefName, "--manifest", manifestName, "--debug", debugName)
+
+	cmd := []string{"neo-go", "contract", "decompile"}
+	t.Run("missing input", func(t *testing.T) {
+		e.RunWithErrorCheck(t, `Required flag "in" not set`, cmd...)
+	})
+	t.Run("invalid files", func(t *testing.T) {
+		e.RunWithError(t, append(cmd, "--in", filepath.Join(tmpDir, "not.exists"))...)
+		e.RunWithError(t, append(cmd, "--in", srcPath)...)
+		e.RunWithError(t, append(cmd, "--in", nefName, "--manifest", nefName)...)
+		e.RunWithError(t, append(cmd, "--in", nefName, "--debug", nefName)...)
+		e.RunWithError(t, append(cmd, "--in", nefName, "something")...)
+	})
+	t.Run("nef only", func(t *testing.T) {
+		e.Run(t, append(cmd, "--in", nefName)...)
+		out := e.Out.String()
+		require.Contains(t, out, "package contract")
+		require.Contains(t, out, "func sub_0000(")
+		require.Contains(t, out, "storage.GetContext()")
+	})
+	t.Run("with manifest and debug info", func(t *testing.T) {
+		e.Run(t, append(cmd, "--in", nefName, "-m", manifestName, "-d", debugName)...)
+		out := e.Out.String()
+		require.Contains(t, out, `// Contract: "Test deploy".`)
+		require.Contains(t, out, `// ABI method "update".`)
+		require.Contains(t, out, "func Update(script []byte, manifest []byte, data any) {")
+		require.NotContains(t, out, "Can't decompile")
+	})
+	t.Run("to file", func(t *testing.T) {
+		out := filepath.Join(tmpDir, "deploy.go")
+		e.Run(t, append(cmd, "--in", nefName, "-m", manifestName, "-o", out)...)
+		e.CheckEOF(t)
+		data, err := os.ReadFile(out)
+		require.NoError(t, err)
+		require.Contains(t, string(data), "package contract")
+	})
+}
+
+func TestContractTest(t *testing.T) {
+	e := testcli.NewExecutor(t, false)
+	tmpDir := t.TempDir()
+
+	e.Run(t, "neo-go", "contract", "compile", "--in", "testdata/verify.go", "--config", "testdata/verify.yml",
+		"--out", filepath.Join(tmpDir, "verify.nef"), "--manifest", filepath.Join(tmpDir, "verify.manifest.json"))
+	e.CheckEOF(t)
+	passing := filepath.Join(tmpDir, "verify.yml")
+	require.NoError(t, os.WriteFile(passing, []byte(`name: Verify
+accounts:
+  - name: alice
+contracts:
+  - name: verify
+    nef: verify.nef
+    manifest: verify.manifest.json
+tests:
+  - name: verify
+    steps:
+      - contract: verify
+        method: verify
+        expect:
+          stack: [true]
+  - name: payment
+    steps:
+      - contract: GasToken
+        method: transfer
+        args: ["${alice}", "${verify}", 1, nil]
+        signers: [alice]
+        expect:
+          stack: [true]
+          events:
+            - name: Transfer
+              args: ["${alice}", "${verify}", 1]
+      - balance: {token: GasToken, account: verify, amount: "0.00000001"}
+`), 0o644))
+
+	cmd := []string{"neo-go", "contract", "test"}
+	t.Run("missing input", func(t *testing.T) {
+		e.RunWithErrorCheckExit(t, "no scenario files specified", cmd...)
+	})
+	t.Run("pass", func(t *testing.T) {
+		e.Run(t, append(cmd, passing)...)
+		e.CheckNextLine(t, `^=== Verify$`)
+		e.CheckNextLine(t, `^PASS verify \(\d+\.\d{3}s\)$`)
+		e.CheckNextLine(t, `^PASS payment \(\d+\.\d{3}s\)$`)
+		e.CheckNextLine(t, `^2 passed, 0 failed$`)
+		e.CheckEOF(t)
+	})
+	t.Run("JUnit", func(t *testing.T) {
+		failing := filepath.Join(tmpDir, "failing.yml")
+		require.NoError(t, os.WriteFile(failing, []byte(`name: Failing
+contracts:
+  - name: verify
+    nef: verify.nef
+    manifest: verify.manifest.json
+tests:
+  - name: verify
+    steps:
+      - contract: verify
+        method: verify
+        expect:
+          stack: [false]
+`), 0o644))
+		broken := filepath.Join(tmpDir, "broken.yml")
+		require.NoError(t, os.WriteFile(broken, []byte(`contracts:
+  - name: verify
+    nef: missing.nef
+    manifest: missing.manifest.json
+`), 0o644))
+
+		out := filepath.Join(tmpDir, "report.xml")
+		e.RunWithErrorCheckExit(t, "2 test(s) failed", append(cmd, "--junit", out, passing, failing, broken)...)
+		e.CheckNextLine(t, `^=== Verify$`)
+		e.CheckNextLine(t, `^PASS verify `)
+		e.CheckNextLine(t, `^PASS payment `)
+		e.CheckNextLine(t, `^=== Failing$`)
+		e.CheckNextLine(t, `^FAIL verify `)
+		e.CheckNextLine(t, `^    step #0: verify returned \[{"type":"Boolean","value":true}\], expected \[{"type":"Boolean","value":false}\]$`)
+		e.CheckNextLine(t, `^=== broken.yml$`)
+		e.CheckNextLine(t, `^ERROR broken.yml: .*missing.nef`)
+		e.CheckNextLine(t, `^2 passed, 2 failed$`)
+		e.CheckEOF(t)
+
+		data, err := os.ReadFile(out)
+		require.NoError(t, err)
+		var report struct {
+			Suites []struct {
+				Name     string `xml:"name,attr"`
+				Tests    int    `xml:"tests,attr"`
+				Failures int    `xml:"failures,attr"`
+				Errors   int    `xml:"errors,attr"`
+				Cases    []struct {
+					Name      string `xml:"name,attr"`
+					Classname string `xml:"classname,attr"`
+					Failure   *struct {
+						Message string `xml:"message,attr"`
+					} `xml:"failure"`
+					Error *struct {
+						Message string `xml:"message,attr"`
+					} `xml:"error"`
+				} `xml:"testcase"`
+			} `xml:"testsuite"`
+		}
+		require.NoError(t, xml.Unmarshal(data, &report))
+		require.Len(t, report.Suites, 3)
+
+		s := report.Suites[0]
+		require.Equal(t, "Verify", s.Name)
+		require.Equal(t, 2, s.Tests)
+		require.Zero(t, s.Failures)
+		require.Equal(t, "payment", s.Cases[1].Name)
+		require.Equal(t, "Verify", s.Cases[1].Classname)
+		require.Nil(t, s.Cases[1].Failure)
+
+		s = report.Suites[1]
+		require.Equal(t, 1, s.Failures)
+		require.NotNil(t, s.Cases[0].Failure)
+		require.Contains(t, s.Cases[0].Failure.Message, "verify returned")
+
+		s = report.Suites[2]
+		require.Equal(t, "broken.yml", s.Name)
+		require.Equal(t, 1, s.Errors)
+		require.Equal(t, "setup", s.Cases[0].Name)
+		require.NotNil(t, s.Cases[0].Error)
+	})
+}
+
+func TestContractLint(t *testing.T) {
+	e := testcli.NewExecutor(t, false)
+	tmpDir := t.TempDir()
+
+	cmd := []string{"neo-go", "contract", "lint"}
+	t.Run("missing input", func(t *testing.T) {
+		e.RunWithErrorCheck(t, `Required flag "in" not set`, cmd...)
+	})
+	t.Run("invalid arguments", func(t *testing.T) {
+		e.RunWithError(t, append(cmd, "--in", "testdata/verify.go", "something")...)
+		e.RunWithErrorCheckExit(t, "unknown output format", append(cmd, "--in", "testdata/verify.go", "-f", "xml")...)
+		e.RunWithErrorCheckExit(t, "failed to compile", append(cmd, "--in", "testdata/invalid1")...)
+	})
+	t.Run("clean", func(t *testing.T) {
+		e.Run(t, append(cmd, "--in", "testdata/verify.go")...)
+		e.CheckEOF(t)
+	})
+	t.Run("text", func(t *testing.T) {
+		e.RunWithErrorCheckExit(t, "1 issue(s) found", append(cmd, "--in", "testdata/deploy")...)
+		e.CheckNextLine(t, `^testdata/deploy/main.go:75:2: missing-witness: method TestFind modifies the storage without runtime.CheckWitness check$`)
+		e.CheckEOF(t)
+	})
+	t.Run("SARIF to file", func(t *testing.T) {
+		out := filepath.Join(tmpDir, "lint.sarif")
+		e.RunWithErrorCheckExit(t, "1 issue(s) found", append(cmd, "--in", "testdata/deploy", "-f", "sarif", "-o", out)...)
+		e.CheckEOF(t)
+		data, err := os.ReadFile(out)
+		require.NoError(t, err)
+
+		var log struct {
+			Version string `json:"version"`
+			Runs    []struct {
+				Tool struct {
+					Driver struct {
+						Rules []struct {
+							ID string `json:"id"`
+						} `json:"rules"`
+					} `json:"driver"`
+				} `json:"tool"`
+				Results []struct {
+					RuleID    string `json:"ruleId"`
+					Locations []struct {
+						PhysicalLocation struct {
+							ArtifactLocation struct {
+								URI string `json:"uri"`
+							} `json:"artifactLocation"`
+							Region struct {
+								StartLine int `json:"startLine"`
+							} `json:"region"`
+						} `json:"physicalLocation"`
+					} `json:"locations"`
+				} `json:"results"`
+			} `json:"runs"`
+		}
+		require.NoError(t, json.Unmarshal(data, &log))
+		require.Equal(t, "2.1.0", log.Version)
+		require.Len(t, log.Runs, 1)
+		require.Len(t, log.Runs[0].Tool.Driver.Rules, len(compiler.LintRules))
+		require.Len(t, log.Runs[0].Results, 1)
+		res := log.Runs[0].Results[0]
+		require.Equal(t, compiler.LintMissingWitness, res.RuleID)
+		require.Equal(t, "testdata/deploy/main.go", res.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+		require.Equal(t, 75, res.Locations[0].PhysicalLocation.Region.StartLine)
+	})
+}
+
 func TestCompileExamples(t *testing.T) {
 	tmpDir := t.TempDir()
 	const examplePath = "../../examples"
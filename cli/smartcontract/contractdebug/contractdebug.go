@@ -0,0 +1,157 @@
+/*
+Package contractdebug implements 'contract debug' command starting Debug Adapter
+Protocol server. It's separated from the smartcontract package because it needs
+the core package which depends on smartcontract in tests.
+*/
+package contractdebug
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"slices"
+
+	"github.com/nspcc-dev/neo-go/cli/cmdargs"
+	"github.com/nspcc-dev/neo-go/cli/options"
+	"github.com/nspcc-dev/neo-go/pkg/core"
+	"github.com/nspcc-dev/neo-go/pkg/core/interop"
+	"github.com/nspcc-dev/neo-go/pkg/core/storage"
+	"github.com/nspcc-dev/neo-go/pkg/core/storage/dbconfig"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/dap"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/trigger"
+	"github.com/urfave/cli/v2"
+	"go.uber.org/zap"
+)
+
+// NewCommand returns 'contract debug' command.
+func NewCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "debug",
+		Usage:     "Start Debug Adapter Protocol server for contract debugging",
+		UsageText: "neo-go contract debug [--listen address] [--historic height] [--config-path path] [-p/-m/-t]",
+		Description: `Starts Debug Adapter Protocol (DAP) server allowing to debug contracts from
+   any DAP-capable editor. The server communicates via stdin/stdout by default,
+   if --listen flag is specified, it accepts TCP connections on the given
+   address and serves them one by one. Every connection is a separate debug
+   session running a single contract method, launch request arguments are:
+     program     path to the contract Go source file/package directory or NEF
+     manifest    path to the manifest (NEF programs only)
+     debugInfo   path to the debug info file (NEF programs only)
+     hash        contract hash used for execution (zero by default)
+     method      contract method to run
+     args        method parameters in the same format as used by RPC
+     signers     transaction signers in the same format as used by RPC
+     gasLimit    GAS limit for the execution (in GAS fractions), RPC
+                 MaxGasInvoke setting is used by default
+     stopOnEntry stop at the first instruction
+   Chain state used for execution is taken from the node database specified
+   by configuration flags (clean in-memory chain is used if no configuration
+   flags are given), --historic flag allows to use the state of the given
+   height.
+`,
+		Action: contractDebug,
+		Flags: append([]cli.Flag{
+			&cli.StringFlag{
+				Name:    "listen",
+				Aliases: []string{"l"},
+				Usage:   "TCP address to accept connections on (stdin/stdout are used if not specified)",
+			},
+			&cli.UintFlag{
+				Name:  "historic",
+				Usage: "Use the chain state of the given height (requires node database with historic states)",
+			},
+			options.Config,
+			options.ConfigFile,
+			options.RelativePath,
+		}, options.Network...),
+	}
+}
+
+// stdio joins stdin and stdout into a single connection.
+type stdio struct {
+	io.Reader
+	io.Writer
+}
+
+func contractDebug(ctx *cli.Context) error {
+	if err := cmdargs.EnsureNone(ctx); err != nil {
+		return err
+	}
+	cfg, err := options.GetConfigFromContext(ctx)
+	if err != nil {
+		return cli.Exit(err, 1)
+	}
+	if !slices.ContainsFunc([]string{"config-path", "config-file", "relative-path", "privnet", "mainnet", "testnet", "unittest"}, ctx.IsSet) {
+		cfg.ApplicationConfiguration.DBConfiguration.Type = dbconfig.InMemoryDB
+	}
+	if cfg.ApplicationConfiguration.DBConfiguration.Type != dbconfig.InMemoryDB {
+		cfg.ApplicationConfiguration.DBConfiguration.LevelDBOptions.ReadOnly = true
+		cfg.ApplicationConfiguration.DBConfiguration.BoltDBOptions.ReadOnly = true
+		cfg.ApplicationConfiguration.DBConfiguration.PebbleDBOptions.ReadOnly = true
+	}
+	log, _, logCloser, err := options.HandleLoggingParams(ctx, cfg.ApplicationConfiguration)
+	if err != nil {
+		return cli.Exit(fmt.Errorf("failed to init logger: %w", err), 1)
+	}
+	if logCloser != nil {
+		defer func() { _ = logCloser() }()
+	}
+	store, err := storage.NewStore(cfg.ApplicationConfiguration.DBConfiguration)
+	if err != nil {
+		return cli.Exit(fmt.Errorf("failed to open DB: %w", err), 1)
+	}
+	defer func() { _ = store.Close() }()
+	chain, err := core.NewBlockchain(store, cfg.Blockchain(), log)
+	if err != nil {
+		return cli.Exit(fmt.Errorf("could not initialize blockchain: %w", err), 1)
+	}
+	// Do not run chain, we need only state-related functionality from it.
+	var newContext = func(tx *transaction.Transaction) (*interop.Context, error) {
+		tx.ValidUntilBlock = chain.BlockHeight() + 1
+		return chain.GetTestVM(trigger.Application, tx, nil)
+	}
+	if ctx.IsSet("historic") {
+		height := uint32(ctx.Uint("historic"))
+		if height > chain.BlockHeight() {
+			return cli.Exit(fmt.Errorf("historic height %d is higher than the current chain height %d", height, chain.BlockHeight()), 1)
+		}
+		newContext = func(tx *transaction.Transaction) (*interop.Context, error) {
+			tx.ValidUntilBlock = height + 1
+			return chain.GetTestHistoricVM(trigger.Application, tx, height+1)
+		}
+	}
+	srv := dap.New(dap.Config{
+		NewContext: newContext,
+		GasLimit:   int64(cfg.ApplicationConfiguration.RPC.MaxGasInvoke),
+	}, log)
+
+	addr := ctx.String("listen")
+	if addr == "" {
+		if err := srv.ServeConn(stdio{Reader: os.Stdin, Writer: os.Stdout}); err != nil {
+			return cli.Exit(err, 1)
+		}
+		return nil
+	}
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return cli.Exit(fmt.Errorf("failed to listen on %s: %w", addr, err), 1)
+	}
+	defer l.Close()
+	fmt.Fprintf(ctx.App.ErrWriter, "Listening on %s\n", l.Addr())
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return cli.Exit(fmt.Errorf("failed to accept connection: %w", err), 1)
+		}
+		if err := srv.ServeConn(conn); err != nil {
+			log.Info("debug session failed", zap.Error(err))
+		}
+		_ = conn.Close()
+	}
+}
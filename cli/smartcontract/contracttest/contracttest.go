@@ -0,0 +1,166 @@
+/*
+Package contracttest implements 'contract test' command running declarative
+contract test scenarios. It's separated from the smartcontract package because
+neotest depends on it.
+*/
+package contracttest
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/neotest/scenario"
+	"github.com/urfave/cli/v2"
+)
+
+// NewCommand returns 'contract test' command.
+func NewCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "test",
+		Usage:     "Runs declarative contract test scenarios",
+		UsageText: "neo-go contract test [--junit file] scenario.yml [scenario.yml ...]",
+		Description: `Runs tests described in YAML scenario files. Every scenario is executed
+   on a fresh in-memory chain with the given accounts and contracts (NEF and
+   manifest pairs) deployed, every test is a sequence of contract invocations
+   with expected results and events, NEP-17 balance and contract storage
+   checks. Tests are independent, each of them starts from the state right
+   after the deployment. Results are printed and can also be saved as a JUnit
+   XML report, the command fails if any test fails. See docs/compiler.md for
+   the scenario format.
+`,
+		Action: contractTest,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "junit",
+				Aliases: []string{"j"},
+				Usage:   "Write JUnit XML report to the file",
+			},
+		},
+	}
+}
+
+// junitTestSuites is a JUnit XML report with a test suite per scenario.
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Errors   int             `xml:"errors,attr"`
+	Time     string          `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Error     *junitFailure `xml:"error,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// junitTime formats the duration in seconds.
+func junitTime(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
+
+func contractTest(ctx *cli.Context) error {
+	if ctx.NArg() == 0 {
+		return cli.Exit("no scenario files specified", 1)
+	}
+	var (
+		w              = ctx.App.Writer
+		report         junitTestSuites
+		passed, failed int
+	)
+	for _, path := range ctx.Args().Slice() {
+		var (
+			suite = junitTestSuite{Name: path}
+			start = time.Now()
+		)
+		s, err := scenario.Load(path)
+		var res []scenario.Result
+		if err == nil {
+			suite.Name = s.Name
+			fmt.Fprintf(w, "=== %s\n", s.Name)
+			res, err = s.Run()
+		}
+		for _, r := range res {
+			tc := junitTestCase{Name: r.Name, Classname: suite.Name, Time: junitTime(r.Duration)}
+			if r.Failure != "" {
+				failed++
+				suite.Failures++
+				tc.Failure = &junitFailure{Message: firstLine(r.Failure), Text: r.Failure}
+				fmt.Fprintf(w, "FAIL %s (%ss)\n", r.Name, junitTime(r.Duration))
+				for _, l := range strings.Split(r.Failure, "\n") {
+					fmt.Fprintf(w, "    %s\n", l)
+				}
+			} else {
+				passed++
+				fmt.Fprintf(w, "PASS %s (%ss)\n", r.Name, junitTime(r.Duration))
+			}
+			suite.Cases = append(suite.Cases, tc)
+		}
+		if err != nil {
+			failed++
+			suite.Errors++
+			suite.Cases = append(suite.Cases, junitTestCase{
+				Name:      "setup",
+				Classname: suite.Name,
+				Time:      "0.000",
+				Error:     &junitFailure{Message: firstLine(err.Error()), Text: err.Error()},
+			})
+			fmt.Fprintf(w, "ERROR %s: %s\n", suite.Name, err)
+		}
+		suite.Tests = len(suite.Cases)
+		suite.Time = junitTime(time.Since(start))
+		report.Suites = append(report.Suites, suite)
+	}
+	fmt.Fprintf(w, "%d passed, %d failed\n", passed, failed)
+
+	if out := ctx.String("junit"); out != "" {
+		if err := writeJUnit(out, &report); err != nil {
+			return cli.Exit(fmt.Errorf("failed to write JUnit report: %w", err), 1)
+		}
+	}
+	if failed != 0 {
+		return cli.Exit(fmt.Errorf("%d test(s) failed", failed), 1)
+	}
+	return nil
+}
+
+// firstLine returns the first line of the message.
+func firstLine(s string) string {
+	l, _, _ := strings.Cut(s, "\n")
+	return l
+}
+
+func writeJUnit(path string, report *junitTestSuites) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := io.WriteString(f, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(f)
+	enc.Indent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		return err
+	}
+	_, err = io.WriteString(f, "\n")
+	return err
+}
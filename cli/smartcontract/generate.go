@@ -9,6 +9,7 @@ import (
 	"github.com/nspcc-dev/neo-go/cli/cmdargs"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/binding"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/rpcbinding"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/tsbinding"
 	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/urfave/cli/v2"
 	"gopkg.in/yaml.v3"
@@ -63,6 +64,14 @@ var generateRPCWrapperCmd = &cli.Command{
 	Flags:     generatorFlags,
 }
 
+var generateTSWrapperCmd = &cli.Command{
+	Name:      "generate-tswrapper",
+	Usage:     "Generate TypeScript RPC wrapper to use for data reads and script building",
+	UsageText: "neo-go contract generate-tswrapper --manifest <file.json> --out <file.ts> [--hash <hash>] [--config <config>]",
+	Action:    contractGenerateTSWrapper,
+	Flags:     generatorFlags,
+}
+
 func contractGenerateWrapper(ctx *cli.Context) error {
 	return contractGenerateSomething(ctx, binding.Generate)
 }
@@ -71,6 +80,10 @@ func contractGenerateRPCWrapper(ctx *cli.Context) error {
 	return contractGenerateSomething(ctx, rpcbinding.Generate)
 }
 
+func contractGenerateTSWrapper(ctx *cli.Context) error {
+	return contractGenerateSomething(ctx, tsbinding.Generate)
+}
+
 // contractGenerateSomething reads generator parameters and calls the given callback.
 func contractGenerateSomething(ctx *cli.Context, cb func(binding.Config) error) error {
 	if err := cmdargs.EnsureNone(ctx); err != nil {
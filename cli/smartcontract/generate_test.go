@@ -555,6 +555,70 @@ func TestAssistedRPCBindings(t *testing.T) {
 	require.False(t, rewriteExpectedOutputs)
 }
 
+func TestGenerateTSBindings(t *testing.T) {
+	tmpDir := t.TempDir()
+	e := testcli.NewExecutor(t, false)
+
+	var checkBinding = func(manifest, configFile, hash, good string) {
+		t.Run(good, func(t *testing.T) {
+			outFile := filepath.Join(tmpDir, "out.ts")
+			cmds := []string{"", "contract", "generate-tswrapper",
+				"--manifest", manifest,
+				"--out", outFile,
+			}
+			if configFile != "" {
+				cmds = append(cmds, "--config", configFile)
+			}
+			if hash != "" {
+				cmds = append(cmds, "--hash", hash)
+			}
+			e.Run(t, cmds...)
+
+			data, err := os.ReadFile(outFile)
+			require.NoError(t, err)
+			data = bytes.ReplaceAll(data, []byte("\r"), []byte{}) // Windows.
+			if rewriteExpectedOutputs {
+				require.NoError(t, os.WriteFile(good, data, os.ModePerm))
+			} else {
+				expected, err := os.ReadFile(good)
+				require.NoError(t, err)
+				expected = bytes.ReplaceAll(expected, []byte("\r"), []byte{}) // Windows.
+				require.Equal(t, string(expected), string(data))
+			}
+		})
+	}
+
+	checkBinding(filepath.Join("testdata", "gas", "gas.manifest.json"), "",
+		"0xd2a4cff31913016155e38e474a2c06d08be276cf",
+		filepath.Join("testdata", "gas", "gas.ts"))
+	checkBinding(filepath.Join("testdata", "nameservice", "nns.manifest.json"), "",
+		"0x50ac1c37690cc2cfc594472833cf57505d5f46de",
+		filepath.Join("testdata", "nameservice", "nns.ts"))
+
+	var compile = func(source string) (string, string) {
+		manifestF := filepath.Join(tmpDir, filepath.Base(source)+".manifest.json")
+		bindingF := filepath.Join(tmpDir, filepath.Base(source)+".yml")
+		e.Run(t, "", "contract", "compile",
+			"--in", source,
+			"--config", filepath.Join(source, "config.yml"),
+			"--manifest", manifestF,
+			"--bindings", bindingF,
+			"--out", filepath.Join(tmpDir, "out.nef"))
+		return manifestF, bindingF
+	}
+	for _, name := range []string{"types", "structs", "notifications"} {
+		source := filepath.Join("testdata", "rpcbindings", name)
+		manifestF, bindingF := compile(source)
+		checkBinding(manifestF, bindingF, "0x00112233445566778899aabbccddeeff00112233",
+			filepath.Join(source, "tsbindings.out"))
+	}
+	source := filepath.Join("testdata", "rpcbindings", "structs")
+	manifestF, bindingF := compile(source)
+	checkBinding(manifestF, bindingF, "", filepath.Join(source, "tsbindings_dynamic_hash.out"))
+
+	require.False(t, rewriteExpectedOutputs)
+}
+
 func TestGenerate_Errors(t *testing.T) {
 	e := testcli.NewExecutor(t, false)
 	args := []string{"neo-go", "contract", "generate-wrapper"}
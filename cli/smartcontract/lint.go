@@ -0,0 +1,175 @@
+package smartcontract
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/nspcc-dev/neo-go/cli/cmdargs"
+	"github.com/nspcc-dev/neo-go/pkg/compiler"
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/urfave/cli/v2"
+)
+
+// sarifSchema is the JSON schema of SARIF 2.1.0 logs.
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+// sarifLog is a minimal SARIF 2.1.0 log with a single run.
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+}
+
+func contractLint(ctx *cli.Context) error {
+	if err := cmdargs.EnsureNone(ctx); err != nil {
+		return err
+	}
+	format := ctx.String("format")
+	if format != "text" && format != "sarif" {
+		return cli.Exit(fmt.Errorf("unknown output format: %s", format), 1)
+	}
+	issues, err := compiler.Lint(ctx.String("in"), nil, nil)
+	if err != nil {
+		return cli.Exit(fmt.Errorf("failed to compile: %w", err), 1)
+	}
+	w := ctx.App.Writer
+	if out := ctx.String("out"); out != "" {
+		f, err := os.Create(out)
+		if err != nil {
+			return cli.Exit(fmt.Errorf("can't create output file: %w", err), 1)
+		}
+		defer f.Close()
+		w = f
+	}
+	for i := range issues {
+		issues[i].Pos.Filename = relativePath(issues[i].Pos.Filename)
+	}
+	if format == "sarif" {
+		err = writeSARIF(w, issues)
+	} else {
+		for _, is := range issues {
+			if _, err = fmt.Fprintf(w, "%s: %s: %s\n", is.Pos, is.Rule, is.Message); err != nil {
+				break
+			}
+		}
+	}
+	if err != nil {
+		return cli.Exit(fmt.Errorf("failed to write output: %w", err), 1)
+	}
+	if len(issues) != 0 {
+		return cli.Exit(fmt.Errorf("%d issue(s) found", len(issues)), 1)
+	}
+	return nil
+}
+
+// relativePath returns the path relative to the current directory if it's
+// located inside it.
+func relativePath(path string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	rel, err := filepath.Rel(wd, path)
+	if err != nil || !filepath.IsLocal(rel) {
+		return path
+	}
+	return rel
+}
+
+// writeSARIF writes issues as a SARIF log.
+func writeSARIF(w io.Writer, issues []compiler.LintIssue) error {
+	driver := sarifDriver{
+		Name:           "neo-go",
+		Version:        config.Version,
+		InformationURI: "https://github.com/nspcc-dev/neo-go",
+	}
+	for _, r := range compiler.LintRules {
+		driver.Rules = append(driver.Rules, sarifRule{
+			ID:               r.ID,
+			ShortDescription: sarifMessage{Text: r.Description},
+		})
+	}
+	results := make([]sarifResult, 0, len(issues))
+	for _, is := range issues {
+		results = append(results, sarifResult{
+			RuleID:  is.Rule,
+			Level:   "warning",
+			Message: sarifMessage{Text: is.Message},
+			Locations: []sarifLocation{{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(is.Pos.Filename)},
+					Region: sarifRegion{
+						StartLine:   is.Pos.Line,
+						StartColumn: is.Pos.Column,
+					},
+				},
+			}},
+		})
+	}
+	data, err := json.MarshalIndent(sarifLog{
+		Version: "2.1.0",
+		Schema:  sarifSchema,
+		Runs: []sarifRun{{
+			Tool:    sarifTool{Driver: driver},
+			Results: results,
+		}},
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("can't marshal SARIF log: %w", err)
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
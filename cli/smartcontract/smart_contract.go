@@ -26,6 +26,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/nef"
 	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/nspcc-dev/neo-go/pkg/vm"
+	"github.com/nspcc-dev/neo-go/pkg/vm/decompiler"
 	"github.com/nspcc-dev/neo-go/pkg/wallet"
 	"github.com/urfave/cli/v2"
 	"gopkg.in/yaml.v3"
@@ -87,8 +88,9 @@ func RuntimeNotify(args []any) {
 }`
 )
 
-// NewCommands returns 'contract' command.
-func NewCommands() []*cli.Command {
+// NewCommands returns 'contract' command, extra commands are added to its
+// subcommands (for those that can't be defined in this package).
+func NewCommands(extra ...*cli.Command) []*cli.Command {
 	testInvokeScriptFlags := []cli.Flag{
 		&cli.StringFlag{
 			Name:     "in",
@@ -152,14 +154,14 @@ func NewCommands() []*cli.Command {
 			Action:   cmdargs.EnsureNotEmpty("manifest"),
 		},
 	}, options.Wallet...)
-	return []*cli.Command{{
+	cmds := []*cli.Command{{
 		Name:  "contract",
 		Usage: "Compile - debug - deploy smart contracts",
 		Subcommands: []*cli.Command{
 			{
 				Name:      "compile",
 				Usage:     "Compile a smart contract to a .nef file",
-				UsageText: "neo-go contract compile -i path [-o nef] [-v] [-d] [-m manifest] [-c yaml] [--bindings file] [--no-standards] [--no-events] [--no-permissions] [--guess-eventtypes]",
+				UsageText: "neo-go contract compile -i path [-o nef] [-v] [-d] [-m manifest] [-c yaml] [--bindings file] [--no-standards] [--no-events] [--no-permissions] [--guess-eventtypes] [-O]",
 				Description: `Compiles given smart contract to a .nef file and emits other associated
    information (manifest, bindings configuration, debug information files) if
    asked to. If none of --out, --manifest, --config, --bindings flags are specified,
@@ -218,6 +220,11 @@ func NewCommands() []*cli.Command {
 						Name:  "guess-eventtypes",
 						Usage: "Guess event types for smart-contract bindings configuration from the code usages",
 					},
+					&cli.BoolFlag{
+						Name:    "optimize",
+						Aliases: []string{"O"},
+						Usage:   "Optimize resulting bytecode (peephole optimizations and dead code elimination)",
+					},
 					&cli.StringFlag{
 						Name:  "bindings",
 						Usage: "Output file for smart-contract bindings configuration",
@@ -263,6 +270,7 @@ func NewCommands() []*cli.Command {
 			},
 			generateWrapperCmd,
 			generateRPCWrapperCmd,
+			generateTSWrapperCmd,
 			{
 				Name:      "invokefunction",
 				Usage:     "Invoke deployed contract on the blockchain",
@@ -349,6 +357,76 @@ func NewCommands() []*cli.Command {
 					},
 				},
 			},
+			{
+				Name:      "decompile",
+				Usage:     "Reconstructs pseudo-Go code of the contract from its NEF file",
+				UsageText: "neo-go contract decompile -i file.nef [-m file.manifest.json] [-d file.debug.json] [-o file.go]",
+				Description: `Decompiles the contract script into readable pseudo-Go code. Control flow
+   is restored as if/else statements, loops and try/catch/finally blocks,
+   syscalls and native contract calls are shown with their names. Manifest
+   and debug info are optional, they're used to name methods, their
+   parameters and variables. The result is not intended to be compiled back.
+`,
+				Action: decompile,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "in",
+						Aliases:  []string{"i"},
+						Required: true,
+						Usage:    "Input NEF file",
+						Action:   cmdargs.EnsureNotEmpty("in"),
+					},
+					&cli.StringFlag{
+						Name:    "manifest",
+						Aliases: []string{"m"},
+						Usage:   "Contract manifest file",
+					},
+					&cli.StringFlag{
+						Name:    "debug",
+						Aliases: []string{"d"},
+						Usage:   "Contract debug info file",
+					},
+					&cli.StringFlag{
+						Name:    "out",
+						Aliases: []string{"o"},
+						Usage:   "Output file (standard output if not specified)",
+					},
+				},
+			},
+			{
+				Name:      "lint",
+				Usage:     "Checks contract source code for common security issues",
+				UsageText: "neo-go contract lint -i path [-f text|sarif] [-o file]",
+				Description: `Compiles the contract and checks it for common security issues:
+   storage modifications without runtime.CheckWitness check in public methods,
+   ignored contract.Call and token transfer results, storage modifications
+   after token transfers, storage key prefix collisions between packages
+   and contract updates or destruction without authorization check. Issues
+   are printed in text or SARIF format, the command fails if any are found.
+   The analysis is heuristic, so false positives are possible.
+`,
+				Action: contractLint,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "in",
+						Aliases:  []string{"i"},
+						Required: true,
+						Usage:    "Input file or directory with the contract source code",
+						Action:   cmdargs.EnsureNotEmpty("in"),
+					},
+					&cli.StringFlag{
+						Name:    "format",
+						Aliases: []string{"f"},
+						Value:   "text",
+						Usage:   "Output format (text or sarif)",
+					},
+					&cli.StringFlag{
+						Name:    "out",
+						Aliases: []string{"o"},
+						Usage:   "Output file (standard output if not specified)",
+					},
+				},
+			},
 			{
 				Name:      "calc-hash",
 				Usage:     "Calculates hash of a contract after deployment",
@@ -391,6 +469,8 @@ func NewCommands() []*cli.Command {
 			},
 		},
 	}}
+	cmds[0].Subcommands = append(cmds[0].Subcommands, extra...)
+	return cmds
 }
 
 // initSmartContract initializes a given directory with some boiler plate code.
@@ -518,6 +598,7 @@ func contractCompile(ctx *cli.Context) error {
 		NoPermissionsCheck: ctx.Bool("no-permissions"),
 
 		GuessEventTypes: ctx.Bool("guess-eventtypes"),
+		Optimize:        ctx.Bool("optimize"),
 	}
 
 	if len(confFile) != 0 {
@@ -799,6 +880,47 @@ func inspect(ctx *cli.Context) error {
 	return nil
 }
 
+func decompile(ctx *cli.Context) error {
+	if err := cmdargs.EnsureNone(ctx); err != nil {
+		return err
+	}
+	nefFile, _, err := readNEFFile(ctx.String("in"))
+	if err != nil {
+		return cli.Exit(fmt.Errorf("failed to read .nef file: %w", err), 1)
+	}
+	var m *manifest.Manifest
+	if path := ctx.String("manifest"); path != "" {
+		m, _, err = readManifest(path, util.Uint160{})
+		if err != nil {
+			return cli.Exit(fmt.Errorf("failed to read manifest file: %w", err), 1)
+		}
+	}
+	var di *compiler.DebugInfo
+	if path := ctx.String("debug"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return cli.Exit(fmt.Errorf("failed to read debug info file: %w", err), 1)
+		}
+		di = new(compiler.DebugInfo)
+		if err := json.Unmarshal(data, di); err != nil {
+			return cli.Exit(fmt.Errorf("failed to parse debug info: %w", err), 1)
+		}
+	}
+	w := ctx.App.Writer
+	if out := ctx.String("out"); out != "" {
+		f, err := os.Create(out)
+		if err != nil {
+			return cli.Exit(fmt.Errorf("can't create output file: %w", err), 1)
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := decompiler.Decompile(w, nefFile, m, di); err != nil {
+		return cli.Exit(fmt.Errorf("failed to decompile: %w", err), 1)
+	}
+	return nil
+}
+
 // contractDeploy deploys contract.
 func contractDeploy(ctx *cli.Context) error {
 	nefFile, f, err := readNEFFile(ctx.String("in"))
@@ -54,6 +54,9 @@ const (
 	chainCfgKey         = "chainCfg"
 	icKey               = "ic"
 	contractStateKey    = "contractState"
+	debugInfoKey        = "debugInfo"
+	gasProfilerKey      = "gasProfiler"
+	gasTracerKey        = "gasTracer"
 	exitFuncKey         = "exitFunc"
 	readlineInstanceKey = "readlineKey"
 	printLogoKey        = "printLogoKey"
@@ -103,21 +106,29 @@ var commands = []*cli.Command{
 	{
 		Name:      "break",
 		Usage:     "Place a breakpoint",
-		UsageText: `break <ip>`,
-		Description: `<ip> is mandatory parameter.
+		UsageText: `break <ip> | <file>:<line>`,
+		Description: `<ip> (instruction offset) or <file>:<line> (Go source location) is
+mandatory parameter. Source locations are only supported for contracts loaded
+with 'loadgo', the breakpoint is placed at the first instruction of the
+statement starting at the given line (or at the nearest subsequent line having
+code). File can be specified by its full path or by the unambiguous suffix of
+it (like base file name).
 
 Example:
-> break 12`,
+> break 12
+> break contract.go:42`,
 		Action: handleBreak,
 	},
 	{
 		Name:      "delete",
 		Usage:     "Remove a breakpoint",
-		UsageText: `delete <ip>`,
-		Description: `<ip> is mandatory parameter.
+		UsageText: `delete <ip> | <file>:<line>`,
+		Description: `<ip> (instruction offset) or <file>:<line> (Go source location) is
+mandatory parameter, see 'break' command for details.
 
 Example:
-> delete 12`,
+> delete 12
+> delete contract.go:42`,
 		Action: handleRemoveBreak,
 	},
 	{
@@ -140,6 +151,43 @@ Example:
 > jump 12`,
 		Action: handleJump,
 	},
+	{
+		Name:      "list",
+		Usage:     "Show Go source code around the current instruction or the specified line",
+		UsageText: `list [<file>:<line>]`,
+		Description: `Show Go source code around the current instruction (marked with '=>')
+or around the specified source location if it's given. It's only supported for
+contracts loaded with 'loadgo'.
+
+Example:
+> list
+> list contract.go:42`,
+		Action: handleList,
+	},
+	{
+		Name:        "locals",
+		Usage:       "Show local variables of the current Go function",
+		UsageText:   "locals",
+		Description: "Show local variables of the current Go function with their names and types taken from debug info. It's only supported for contracts loaded with 'loadgo'.",
+		Action:      handleSourceVars,
+	},
+	{
+		Name:        "args",
+		Usage:       "Show arguments of the current Go function",
+		UsageText:   "args",
+		Description: "Show arguments of the current Go function with their names and types taken from debug info. It's only supported for contracts loaded with 'loadgo'.",
+		Action:      handleSourceVars,
+	},
+	{
+		Name:      "bt",
+		Usage:     "Show call stack",
+		UsageText: "bt",
+		Description: `Show call stack (including calls to other contracts), the current frame goes
+first. Go function names and source locations are shown for frames of the
+contract loaded with 'loadgo', script hashes and instruction offsets are
+shown for others.`,
+		Action: handleBacktrace,
+	},
 	{
 		Name:        "estack",
 		Usage:       "Show evaluation stack contents",
@@ -354,6 +402,19 @@ Example:
 		Description: "Dump events emitted by the current loaded program",
 		Action:      handleEvents,
 	},
+	{
+		Name:      "gasprofile",
+		Usage:     "Write GAS profile of the current loaded program executions in pprof format",
+		UsageText: `gasprofile <file>`,
+		Description: `<file> is the file to write profile to. GAS spent by all the executions of the
+        current loaded program (since it was loaded) is accounted per contract function
+        and source code line (for programs loaded with 'loadgo') and written in pprof
+        format, use 'go tool pprof' to analyze it.
+
+Example:
+> gasprofile gas.pprof`,
+		Action: handleGasProfile,
+	},
 	{
 		Name:      "env",
 		Usage:     "Dump state of the chain that is used for VM CLI invocations (use -v for verbose node configuration)",
@@ -520,10 +581,13 @@ func NewWithConfig(printLogotype bool, onExit func(int), c *readline.Config, cfg
 		chainCfgKey:         cfg,
 		icKey:               ic,
 		contractStateKey:    new(state.ContractBase),
+		debugInfoKey:        (*compiler.DebugInfo)(nil),
+		gasProfilerKey:      newGasProfiler(chain),
 		exitFuncKey:         exitF,
 		readlineInstanceKey: l,
 		printLogoKey:        printLogotype,
 	}
+	attachGasTracer(vmcli.shell, ic.VM)
 	changePrompt(vmcli.shell)
 	return &vmcli, nil
 }
@@ -595,7 +659,7 @@ func handleIP(c *cli.Context) error {
 	ctx := v.Context()
 	if ctx.NextIP() < ctx.LenInstr() {
 		ip, opcode := v.Context().NextInstr()
-		fmt.Fprintf(c.App.Writer, "instruction pointer at %d (%s)\n", ip, opcode)
+		fmt.Fprintf(c.App.Writer, "instruction pointer at %d (%s)%s\n", ip, opcode, locationSuffix(c.App, ctx, ip))
 	} else {
 		fmt.Fprintln(c.App.Writer, "execution has finished")
 	}
@@ -606,14 +670,14 @@ func handleBreak(c *cli.Context) error {
 	if !checkVMIsReady(c.App) {
 		return nil
 	}
-	n, err := getInstructionParameter(c)
+	n, loc, err := getBreakpointParameter(c)
 	if err != nil {
 		return err
 	}
 
 	v := getVMFromContext(c.App)
 	v.AddBreakPoint(n)
-	fmt.Fprintf(c.App.Writer, "breakpoint added at instruction %d\n", n)
+	fmt.Fprintf(c.App.Writer, "breakpoint added at instruction %d%s\n", n, formatLocation(loc))
 	return nil
 }
 
@@ -621,14 +685,14 @@ func handleRemoveBreak(c *cli.Context) error {
 	if !checkVMIsReady(c.App) {
 		return nil
 	}
-	n, err := getInstructionParameter(c)
+	n, loc, err := getBreakpointParameter(c)
 	if err != nil {
 		return err
 	}
 
 	v := getVMFromContext(c.App)
 	v.RemoveBreakPoint(n)
-	fmt.Fprintf(c.App.Writer, "breakpoint removed at instruction %d\n", n)
+	fmt.Fprintf(c.App.Writer, "breakpoint removed at instruction %d%s\n", n, formatLocation(loc))
 	return nil
 }
 
@@ -932,6 +996,7 @@ func handleLoadGo(c *cli.Context) error {
 		Manifest: *m,
 	}
 	setContractStateInContext(c.App, cs)
+	setDebugInfoInContext(c.App, di)
 
 	v := getVMFromContext(c.App)
 	fmt.Fprintf(c.App.Writer, "READY: loaded %d instructions\n", v.Context().LenInstr())
@@ -1080,24 +1145,28 @@ func resetInteropContext(app *cli.App, tx *transaction.Transaction, height ...ui
 	if tx != nil {
 		newIc.VM.LoadWithFlags(tx.Script, callflag.All)
 	}
+	attachGasTracer(app, newIc.VM)
 
 	setInteropContextInContext(app, newIc)
 	return nil
 }
 
-// resetContractState removes loaded contract state from app context.
+// resetContractState removes loaded contract state and debug info from app
+// context.
 func resetContractState(app *cli.App) {
 	setContractStateInContext(app, nil)
+	setDebugInfoInContext(app, nil)
 }
 
-// resetState resets state of the app (clear interop context and manifest) so that it's ready
-// to load new program.
+// resetState resets state of the app (clear interop context, manifest and GAS
+// profile) so that it's ready to load new program.
 func resetState(app *cli.App, tx *transaction.Transaction, height ...uint32) error {
 	err := resetInteropContext(app, tx, height...)
 	if err != nil {
 		return err
 	}
 	resetContractState(app)
+	getGasProfilerFromContext(app).Reset()
 	return nil
 }
 
@@ -1158,6 +1227,7 @@ func handleRun(c *cli.Context) error {
 			ic := getInteropContextFromContext(c.App)
 			gasLimit := v.GasLimit
 			breaks := v.Context().BreakPoints() // We ensure that there's a context loaded.
+			flushGasTracer(c.App)
 			ic.ReuseVM(v)
 			v.GasLimit = gasLimit
 			v.LoadNEFMethod(&cs.NEF, &cs.Manifest, util.Uint160{}, cs.Hash, callflag.All, hasRet, offset, initOff, nil)
@@ -1197,7 +1267,7 @@ func runVMWithHandling(c *cli.Context) {
 		ctx := v.Context()
 		if ctx.NextIP() < ctx.LenInstr() {
 			i, op := ctx.NextInstr()
-			message = fmt.Sprintf("at breakpoint %d (%s)", i, op)
+			message = fmt.Sprintf("at breakpoint %d (%s)%s", i, op, locationSuffix(c.App, ctx, i))
 		} else {
 			message = "execution has finished"
 		}
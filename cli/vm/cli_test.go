@@ -19,6 +19,7 @@ import (
 	"time"
 
 	"github.com/chzyer/readline"
+	"github.com/google/pprof/profile"
 	"github.com/nspcc-dev/neo-go/cli/cmdargs"
 	"github.com/nspcc-dev/neo-go/cli/paramcontext"
 	"github.com/nspcc-dev/neo-go/internal/basicchain"
@@ -1428,3 +1429,140 @@ func TestJump(t *testing.T) {
 	e.checkNextLine(t, fmt.Sprintf("jumped to instruction %d", jmpTo))
 	e.checkStack(t, 9)
 }
+
+func TestSourceLevelDebugging(t *testing.T) {
+	src := `package kek
+func Main(a int) int {
+	b := a + 1
+	return double(b)
+}
+func double(x int) int {
+	y := x * 2
+	return y
+}`
+	tmpDir := t.TempDir()
+	filename := prepareLoadgoSrc(t, tmpDir, src)
+
+	t.Run("no debug info", func(t *testing.T) {
+		e := newTestVMCLI(t)
+		e.runProg(t,
+			"loadhex "+hex.EncodeToString([]byte{byte(opcode.PUSH1)}),
+			"break file.go:1",
+			"list",
+			"locals",
+			"args")
+		e.checkNextLine(t, "READY: loaded 1 instructions")
+		e.checkError(t, errNoDebugInfo)
+		e.checkError(t, errNoDebugInfo)
+		e.checkError(t, errNoDebugInfo)
+		e.checkError(t, errNoDebugInfo)
+	})
+	t.Run("invalid location", func(t *testing.T) {
+		e := newTestVMCLI(t)
+		e.runProgWithTimeout(t, 10*time.Second,
+			"loadgo "+filename,
+			"break unknown.go:1",
+			"break vmtestcontract.go:100",
+			"break vmtestcontract.go:x",
+			"break abc",
+			"list unknown.go:1")
+		e.checkNextLine(t, "READY: loaded \\d+ instructions")
+		e.checkNextLine(t, "Error: file unknown.go not found in debug info")
+		e.checkNextLine(t, "Error: no code found at vmtestcontract.go:100 or below")
+		e.checkNextLine(t, "Error: "+ErrInvalidParameter.Error())
+		e.checkNextLine(t, "Error: "+ErrInvalidParameter.Error())
+		e.checkNextLine(t, "Error: file unknown.go not found in debug info")
+	})
+	t.Run("good", func(t *testing.T) {
+		e := newTestVMCLI(t)
+		e.runProgWithTimeout(t, 10*time.Second,
+			"loadgo "+filename,
+			"break vmtestcontract.go:8",
+			"run main 3",
+			"ip",
+			"list",
+			"args",
+			"locals",
+			"bt",
+			"delete vmtestcontract.go:8",
+			"run")
+		e.checkNextLine(t, "READY: loaded \\d+ instructions")
+		e.checkNextLine(t, "breakpoint added at instruction \\d+ at vmtestcontract.go:8")
+		e.checkNextLine(t, "at breakpoint \\d+ \\(.*\\) at .*vmtestcontract.go:8")
+		e.checkNextLine(t, "instruction pointer at \\d+ \\(.*\\) at .*vmtestcontract.go:8")
+		e.checkNextLine(t, "vmtestcontract.go:8\n")
+		e.checkNextLine(t, "^\\s+3\\s+b := a \\+ 1")
+		e.checkNextLine(t, "^\\s+4\\s+return double\\(b\\)")
+		e.checkNextLine(t, "^\\s+5\\s+}")
+		e.checkNextLine(t, "^\\s+6\\s+func double\\(x int\\) int {")
+		e.checkNextLine(t, "^\\s+7\\s+y := x \\* 2")
+		e.checkNextLine(t, "^=>\\s+8\\s+return y")
+		e.checkNextLine(t, "^\\s+9\\s+}")
+		e.checkNextLine(t, `^x \(Integer\) = {"type":"Integer","value":"4"}`)
+		e.checkNextLine(t, `^y \(Integer\) = {"type":"Integer","value":"8"}`)
+		e.checkNextLine(t, "^#0 .*double at .*vmtestcontract.go:8 \\(ip \\d+\\)")
+		e.checkNextLine(t, "^#1 .*Main at .*vmtestcontract.go:4 \\(ip \\d+\\)")
+		e.checkNextLine(t, "breakpoint removed at instruction \\d+ at vmtestcontract.go:8")
+		e.checkStack(t, 8)
+	})
+}
+
+func TestGasProfile(t *testing.T) {
+	src := `package kek
+func Main(a int) int {
+	b := a + 1
+	return double(b)
+}
+func double(x int) int {
+	y := x * 2
+	return y
+}`
+	tmpDir := t.TempDir()
+	filename := prepareLoadgoSrc(t, tmpDir, src)
+	profileFile := filepath.Join(tmpDir, "gas.pprof")
+	emptyFile := filepath.Join(tmpDir, "empty.pprof")
+
+	e := newTestVMCLI(t)
+	e.runProgWithTimeout(t, 10*time.Second,
+		"gasprofile",
+		"loadgo "+filename,
+		"break vmtestcontract.go:8",
+		"run main 3",
+		"cont",
+		"gasprofile "+profileFile,
+		"loadhex "+hex.EncodeToString([]byte{byte(opcode.PUSH1)}),
+		"gasprofile "+emptyFile)
+	e.checkError(t, ErrMissingParameter)
+	e.checkNextLine(t, "READY: loaded \\d+ instructions")
+	e.checkNextLine(t, "breakpoint added at instruction \\d+ at vmtestcontract.go:8")
+	e.checkNextLine(t, "at breakpoint \\d+ \\(.*\\) at .*vmtestcontract.go:8")
+	e.checkStack(t, 8)
+	e.checkNextLine(t, "GAS profile is written to .*gas.pprof")
+	e.checkNextLine(t, "READY: loaded 1 instructions")
+	e.checkNextLine(t, "GAS profile is written to .*empty.pprof")
+
+	readProfile := func(t *testing.T, name string) *profile.Profile {
+		f, err := os.Open(name)
+		require.NoError(t, err)
+		defer f.Close()
+		p, err := profile.Parse(f)
+		require.NoError(t, err)
+		return p
+	}
+	p := readProfile(t, profileFile)
+	var (
+		perFunc = make(map[string]int64)
+		callers = make(map[string]bool)
+	)
+	for _, s := range p.Sample {
+		perFunc[s.Location[0].Line[0].Function.Name] += s.Value[0]
+		for _, l := range s.Location[1:] {
+			callers[l.Line[0].Function.Name] = true
+		}
+	}
+	require.Positive(t, perFunc["kek.Main"])
+	require.Positive(t, perFunc["kek.double"])
+	require.True(t, callers["kek.Main"])
+
+	require.Empty(t, readProfile(t, emptyFile).Sample)
+}
@@ -0,0 +1,73 @@
+package vm
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/nspcc-dev/neo-go/pkg/core"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm"
+	"github.com/nspcc-dev/neo-go/pkg/vm/gasprofile"
+	"github.com/urfave/cli/v2"
+)
+
+func getGasProfilerFromContext(app *cli.App) *gasprofile.Profiler {
+	return app.Metadata[gasProfilerKey].(*gasprofile.Profiler)
+}
+
+// newGasProfiler creates a profiler with all native contracts of the chain
+// registered.
+func newGasProfiler(chain *core.Blockchain) *gasprofile.Profiler {
+	p := gasprofile.New()
+	for _, cs := range chain.GetNatives() {
+		p.AddContract(cs.Hash, &cs.Manifest, nil)
+	}
+	return p
+}
+
+// attachGasTracer accounts the last instruction executed by the current VM
+// and attaches the GAS profiler to the given (not yet started) VM.
+func attachGasTracer(app *cli.App, v *vm.VM) {
+	flushGasTracer(app)
+	app.Metadata[gasTracerKey] = getGasProfilerFromContext(app).Attach(v)
+}
+
+// flushGasTracer accounts the last instruction executed by the current VM, it
+// must be called before the VM is reused.
+func flushGasTracer(app *cli.App) {
+	if t, ok := app.Metadata[gasTracerKey].(*gasprofile.Tracer); ok {
+		t.Flush()
+	}
+}
+
+func handleGasProfile(c *cli.Context) error {
+	args := c.Args().Slice()
+	if len(args) != 1 {
+		return fmt.Errorf("%w: <file>", ErrMissingParameter)
+	}
+	var (
+		p  = getGasProfilerFromContext(c.App)
+		cs = getContractStateFromContext(c.App)
+	)
+	flushGasTracer(c.App)
+	if cs != nil {
+		// The script is executed with the given hash by 'run <method>' and
+		// with the script hash if loaded without a method.
+		di := getDebugInfoFromContext(c.App)
+		p.AddContract(hash.Hash160(cs.NEF.Script), &cs.Manifest, di)
+		if !cs.Hash.Equals(util.Uint160{}) {
+			p.AddContract(cs.Hash, &cs.Manifest, di)
+		}
+	}
+	f, err := os.Create(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to create profile file: %w", err)
+	}
+	defer f.Close()
+	if err := p.Write(f); err != nil {
+		return fmt.Errorf("failed to write profile: %w", err)
+	}
+	fmt.Fprintf(c.App.Writer, "GAS profile is written to %s\n", args[0])
+	return nil
+}
@@ -0,0 +1,252 @@
+package vm
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/nspcc-dev/neo-go/pkg/compiler"
+	"github.com/nspcc-dev/neo-go/pkg/vm"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"github.com/urfave/cli/v2"
+)
+
+// listContextLines is the number of source lines printed by 'list' command
+// before and after the current one.
+const listContextLines = 5
+
+// errNoDebugInfo is returned from source-level commands when there is no debug
+// info for the current context.
+var errNoDebugInfo = errors.New("no debug info for the current context (use 'loadgo' to load a contract with debug info)")
+
+func getDebugInfoFromContext(app *cli.App) *compiler.DebugInfo {
+	return app.Metadata[debugInfoKey].(*compiler.DebugInfo)
+}
+
+func setDebugInfoInContext(app *cli.App, di *compiler.DebugInfo) {
+	app.Metadata[debugInfoKey] = di
+}
+
+// contextDebugInfo returns debug info for the given VM context if it belongs to
+// the loaded contract and nil otherwise.
+func contextDebugInfo(app *cli.App, ctx *vm.Context) *compiler.DebugInfo {
+	di := getDebugInfoFromContext(app)
+	cs := getContractStateFromContext(app)
+	if di == nil || cs == nil || ctx == nil || !bytes.Equal(ctx.Program(), cs.NEF.Script) {
+		return nil
+	}
+	return di
+}
+
+// sourceLocation returns the source location (file:line) of the given
+// instruction of the context if it's known and an empty string otherwise.
+func sourceLocation(app *cli.App, ctx *vm.Context, ip int) string {
+	di := contextDebugInfo(app, ctx)
+	if di == nil {
+		return ""
+	}
+	_, sp := di.SeqPointByOffset(ip)
+	if sp == nil || sp.Document >= len(di.Documents) {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d", di.Documents[sp.Document], sp.StartLine)
+}
+
+// parseSourceLocation parses file:line string.
+func parseSourceLocation(s string) (string, int, error) {
+	i := strings.LastIndexByte(s, ':')
+	if i <= 0 {
+		return "", 0, fmt.Errorf("%w: <file>:<line> expected, got %s", ErrInvalidParameter, s)
+	}
+	line, err := strconv.Atoi(s[i+1:])
+	if err != nil || line <= 0 {
+		return "", 0, fmt.Errorf("%w: invalid line number in %s", ErrInvalidParameter, s)
+	}
+	return s[:i], line, nil
+}
+
+// getBreakpointParameter parses breakpoint parameter that is either an
+// instruction offset or a source location. For source locations the resolved
+// location is returned as well.
+func getBreakpointParameter(c *cli.Context) (int, string, error) {
+	args := c.Args().Slice()
+	if len(args) != 1 {
+		return 0, "", fmt.Errorf("%w: <ip> or <file>:<line>", ErrMissingParameter)
+	}
+	n, err := strconv.Atoi(args[0])
+	if err == nil {
+		return n, "", nil
+	}
+	if !strings.Contains(args[0], ":") {
+		return 0, "", fmt.Errorf("%w: %w", ErrInvalidParameter, err)
+	}
+	file, line, err := parseSourceLocation(args[0])
+	if err != nil {
+		return 0, "", err
+	}
+	di := contextDebugInfo(c.App, getVMFromContext(c.App).Context())
+	if di == nil {
+		return 0, "", errNoDebugInfo
+	}
+	doc, err := di.DocumentIndex(file)
+	if err != nil {
+		return 0, "", err
+	}
+	n, actual, ok := di.LineOffset(doc, line)
+	if !ok {
+		return 0, "", fmt.Errorf("no code found at %s:%d or below", file, line)
+	}
+	return n, fmt.Sprintf("%s:%d", file, actual), nil
+}
+
+func handleList(c *cli.Context) error {
+	if !checkVMIsReady(c.App) {
+		return nil
+	}
+	var (
+		ctx  = getVMFromContext(c.App).Context()
+		di   = contextDebugInfo(c.App, ctx)
+		doc  string
+		line int
+		cur  int
+	)
+	if di == nil {
+		return errNoDebugInfo
+	}
+	if c.Args().Present() {
+		file, l, err := parseSourceLocation(c.Args().First())
+		if err != nil {
+			return err
+		}
+		i, err := di.DocumentIndex(file)
+		if err != nil {
+			return err
+		}
+		doc, line = di.Documents[i], l
+	} else {
+		if ctx.NextIP() >= ctx.LenInstr() {
+			fmt.Fprintln(c.App.Writer, "execution has finished")
+			return nil
+		}
+		_, sp := di.SeqPointByOffset(ctx.NextIP())
+		if sp == nil || sp.Document >= len(di.Documents) {
+			return fmt.Errorf("no source information for instruction %d", ctx.NextIP())
+		}
+		doc, line, cur = di.Documents[sp.Document], sp.StartLine, sp.StartLine
+	}
+	src, err := os.ReadFile(doc)
+	if err != nil {
+		return fmt.Errorf("failed to read source file: %w", err)
+	}
+	lines := strings.Split(string(src), "\n")
+	if line > len(lines) {
+		return fmt.Errorf("%w: line %d is out of %s range", ErrInvalidParameter, line, doc)
+	}
+	fmt.Fprintf(c.App.Writer, "%s:%d\n", doc, line)
+	for i := max(line-listContextLines, 1); i <= min(line+listContextLines, len(lines)); i++ {
+		var marker = "  "
+		if i == cur {
+			marker = "=>"
+		}
+		fmt.Fprintf(c.App.Writer, "%s %4d\t%s\n", marker, i, strings.TrimRight(lines[i-1], "\r"))
+	}
+	return nil
+}
+
+func handleSourceVars(c *cli.Context) error {
+	if !checkVMIsReady(c.App) {
+		return nil
+	}
+	var (
+		ctx = getVMFromContext(c.App).Context()
+		di  = contextDebugInfo(c.App, ctx)
+	)
+	if di == nil {
+		return errNoDebugInfo
+	}
+	m := di.MethodByOffset(ctx.NextIP())
+	if m == nil {
+		return fmt.Errorf("no method found for instruction %d", ctx.NextIP())
+	}
+	switch c.Command.Name {
+	case "args":
+		var offset int
+		if !m.IsFunction {
+			offset = 1 // Receiver.
+		}
+		for i, p := range m.Parameters {
+			printSourceVar(c, p.Name, p.Type, ctx.ArgumentsSlot(), i+offset)
+		}
+	case "locals":
+		for i, v := range m.Variables {
+			if i >= len(m.VariableSlots) || m.VariableSlots[i] < 0 {
+				continue
+			}
+			name, typ, _ := strings.Cut(v, ",")
+			printSourceVar(c, name, typ, ctx.LocalsSlot(), m.VariableSlots[i])
+		}
+	default:
+		return errors.New("unknown variables kind")
+	}
+	return nil
+}
+
+// printSourceVar prints the variable with the given name and type stored in
+// the i-th element of the slot.
+func printSourceVar(c *cli.Context, name string, typ string, s *vm.Slot, i int) {
+	var val = "<unavailable>"
+	if i < s.Size() {
+		data, err := stackitem.ToJSONWithTypes(s.Get(i))
+		if err != nil {
+			val = fmt.Sprintf("<%s>", err)
+		} else {
+			val = string(data)
+		}
+	}
+	fmt.Fprintf(c.App.Writer, "%s (%s) = %s\n", name, typ, val)
+}
+
+func handleBacktrace(c *cli.Context) error {
+	if !checkVMIsReady(c.App) {
+		return nil
+	}
+	istack := getVMFromContext(c.App).Istack()
+	for i := len(istack) - 1; i >= 0; i-- {
+		var (
+			n   = len(istack) - 1 - i
+			ctx = istack[i]
+			ip  = ctx.IP()
+		)
+		if n == 0 {
+			ip = ctx.NextIP()
+		}
+		di := contextDebugInfo(c.App, ctx)
+		if di != nil {
+			m, sp := di.SeqPointByOffset(ip)
+			if m != nil && sp != nil && sp.Document < len(di.Documents) {
+				fmt.Fprintf(c.App.Writer, "#%d %s.%s at %s:%d (ip %d)\n", n, m.Name.Namespace, m.ID,
+					di.Documents[sp.Document], sp.StartLine, ip)
+				continue
+			}
+		}
+		fmt.Fprintf(c.App.Writer, "#%d %s (ip %d)\n", n, ctx.ScriptHash().StringLE(), ip)
+	}
+	return nil
+}
+
+// locationSuffix returns " at <file>:<line>" suffix for the given instruction
+// of the context if its source location is known and an empty string otherwise.
+func locationSuffix(app *cli.App, ctx *vm.Context, ip int) string {
+	return formatLocation(sourceLocation(app, ctx, ip))
+}
+
+// formatLocation formats non-empty source location as a message suffix.
+func formatLocation(loc string) string {
+	if loc == "" {
+		return ""
+	}
+	return " at " + loc
+}
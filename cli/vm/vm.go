@@ -38,6 +38,7 @@ func startVMPrompt(ctx *cli.Context) error {
 	if cfg.ApplicationConfiguration.DBConfiguration.Type != dbconfig.InMemoryDB {
 		cfg.ApplicationConfiguration.DBConfiguration.LevelDBOptions.ReadOnly = true
 		cfg.ApplicationConfiguration.DBConfiguration.BoltDBOptions.ReadOnly = true
+		cfg.ApplicationConfiguration.DBConfiguration.PebbleDBOptions.ReadOnly = true
 	}
 
 	p, err := NewWithConfig(true, os.Exit, &readline.Config{}, cfg)
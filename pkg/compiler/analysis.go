@@ -20,13 +20,13 @@ var (
 	ErrMissingExportedParamName = errors.New("exported method is not allowed to have unnamed parameter")
 	// ErrInvalidExportedRetCount is returned when exported contract method has invalid return values count.
 	ErrInvalidExportedRetCount = errors.New("exported method is not allowed to have more than one return value")
-	// ErrGenericsUnsuppored is returned when generics-related tokens are encountered.
-	ErrGenericsUnsuppored = errors.New("generics are currently unsupported, please, see the https://github.com/nspcc-dev/neo-go/issues/2376")
+	// ErrGenericsUnsuppored is returned when unsupported generics usage is encountered.
+	ErrGenericsUnsuppored = errors.New("unsupported generics usage")
 )
 
 var (
 	// Go language builtin functions.
-	goBuiltins = []string{"len", "append", "panic", "make", "copy", "recover", "delete", "min", "max"}
+	goBuiltins = []string{"len", "append", "panic", "make", "copy", "recover", "delete", "min", "max", "clear"}
 	// Custom builtin utility functions that contain some meaningful code inside and
 	// require code generation using standard rules, but sometimes (depending on
 	// the expression usage condition) may be optimized at compile time.
@@ -319,6 +319,7 @@ func (c *codegen) analyzeFuncAndGlobalVarUsage() funcUsage {
 		decl      *ast.FuncDecl
 		importMap map[string]string
 		path      string
+		typeArgs  typeArgMap // Type arguments of generic function instance.
 	}
 	// globalVar represents a global variable declaration node with the corresponding package context.
 	type globalVar struct {
@@ -366,14 +367,13 @@ func (c *codegen) analyzeFuncAndGlobalVarUsage() funcUsage {
 				diff[name] = true
 			case *ast.FuncDecl:
 				name := c.getFuncNameFromDecl(pkgPath, n)
+				nodeCache[name] = declPair{n, c.importMap, pkgPath, nil}
 
-				// filter out generic functions
-				err := c.checkGenericsFuncDecl(n, name)
-				if err != nil {
-					c.prog.Err = err
-					return false // Program is invalid.
+				// Generic functions are used only via their instances, they're
+				// never contract methods.
+				if isGenericFunc(n) {
+					return false
 				}
-
 				// exported functions and methods are always assumed to be used
 				if isMain && n.Name.IsExported() || isInitFunc(n) || isDeployFunc(n) {
 					diff[name] = true
@@ -398,16 +398,8 @@ func (c *codegen) analyzeFuncAndGlobalVarUsage() funcUsage {
 						c.prog.Err = fmt.Errorf("%w: %s/%d return values", ErrInvalidExportedRetCount, n.Name, retCnt)
 					}
 				}
-				nodeCache[name] = declPair{n, c.importMap, pkgPath}
 				return false // will be processed in the next stage
 			case *ast.GenDecl:
-				// Filter out generics usage.
-				err := c.checkGenericsGenDecl(n, pkgPath)
-				if err != nil {
-					c.prog.Err = err
-					return false // Program is invalid.
-				}
-
 				// After skipping all funcDecls, we are sure that each value spec
 				// is a globally declared variable or constant. We need to gather global
 				// vars from both main and imported packages.
@@ -485,19 +477,53 @@ func (c *codegen) analyzeFuncAndGlobalVarUsage() funcUsage {
 			c.typeInfo = pkg.TypesInfo
 			c.currPkg = pkg
 			c.importMap = fd.importMap
+			c.typeArgs = fd.typeArgs
+			if fd.typeArgs != nil {
+				if c.instances[fd.decl] == nil {
+					c.instances[fd.decl] = make(map[string]typeArgMap)
+				}
+				c.instances[fd.decl][name] = fd.typeArgs
+			}
 			ast.Inspect(fd.decl, func(node ast.Node) bool {
 				switch n := node.(type) {
 				case *ast.CallExpr:
-					switch t := n.Fun.(type) {
+					var (
+						callee string
+						targs  []types.Type
+					)
+					switch t := c.unwrapInstance(n.Fun).(type) {
 					case *ast.Ident:
-						nextDiff[c.getIdentName(fd.path, t.Name)] = true
+						targs = c.instanceOf(t)
+						callee = c.getIdentName(fd.path, t.Name)
 					case *ast.SelectorExpr:
-						name, _ := c.getFuncNameFromSelector(t)
-						nextDiff[name] = true
+						callee, _ = c.getFuncNameFromSelector(t)
+						if c.typeInfo.Selections[t] != nil {
+							targs = c.recvTypeArgs(t)
+						} else {
+							targs = c.instanceOf(t.Sel)
+						}
+					default:
+						return true
 					}
+					if targs != nil {
+						// Register generic function instance.
+						base, _, _ := strings.Cut(callee, "[")
+						callee = base + instanceSuffix(targs)
+						generic, ok := nodeCache[base]
+						if _, seen := nodeCache[callee]; ok && !seen {
+							genPkg := c.mainPkg
+							if generic.path != "" {
+								genPkg = c.packageCache[generic.path]
+							}
+							generic.typeArgs = newTypeArgMap(generic.decl, genPkg.TypesInfo, targs)
+							nodeCache[callee] = generic
+						}
+					}
+					nextDiff[callee] = true
 				}
 				return true
 			})
+			c.typeArgs = nil
 			usedExpressions = append(usedExpressions, nodeContext{
 				node:      fd.decl.Body,
 				path:      fd.path,
@@ -555,54 +581,6 @@ func (c *codegen) analyzeFuncAndGlobalVarUsage() funcUsage {
 	return usage
 }
 
-// checkGenericsFuncDecl checks whether provided ast.FuncDecl has generic code.
-func (c *codegen) checkGenericsFuncDecl(n *ast.FuncDecl, funcName string) error {
-	var errGenerics error
-
-	// Generic function receiver.
-	if n.Recv != nil {
-		switch t := n.Recv.List[0].Type.(type) {
-		case *ast.StarExpr:
-			switch t.X.(type) {
-			case *ast.IndexExpr:
-				// func (x *Pointer[T]) Load() *T
-				errGenerics = errors.New("generic pointer function receiver")
-			}
-		case *ast.IndexExpr:
-			// func (x Structure[T]) Load() *T
-			errGenerics = errors.New("generic function receiver")
-		}
-	}
-
-	// Generic function parameters type: func SumInts[V int64 | int32](vals []V) V
-	if n.Type.TypeParams != nil {
-		errGenerics = errors.New("function type parameters")
-	}
-
-	if errGenerics != nil {
-		return fmt.Errorf("%w: %s has %s", ErrGenericsUnsuppored, funcName, errGenerics.Error())
-	}
-
-	return nil
-}
-
-// checkGenericsGenDecl checks whether provided ast.GenDecl has generic code.
-func (c *codegen) checkGenericsGenDecl(n *ast.GenDecl, pkgPath string) error {
-	// Generic type declaration:
-	// 	type List[T any] struct
-	// 	type List[T any] interface
-	if n.Tok == token.TYPE {
-		for _, s := range n.Specs {
-			typeSpec := s.(*ast.TypeSpec)
-			if typeSpec.TypeParams != nil {
-				return fmt.Errorf("%w: type %s is generic", ErrGenericsUnsuppored, c.getIdentName(pkgPath, typeSpec.Name.Name))
-			}
-		}
-	}
-
-	return nil
-}
-
 // nodeContext contains ast node with the corresponding import map, type info and package information
 // required to retrieve fully qualified node name (if so).
 type nodeContext struct {
@@ -723,6 +701,27 @@ func isSyscall(fun *funcScope) bool {
 
 const interopPrefix = "github.com/nspcc-dev/neo-go/pkg/interop"
 
+// iteratorPkg is the path of the package providing iterator sequences.
+const iteratorPkg = interopPrefix + "/iterator"
+
+// isIteratorSeqFunc returns true if the given function name (possibly with
+// the instance suffix) denotes iterator.Values or iterator.Pairs. These
+// functions are not compiled, their result is the Iterator itself.
+func isIteratorSeqFunc(name string) bool {
+	name, _, _ = strings.Cut(name, "[")
+	return name == iteratorPkg+".Values" || name == iteratorPkg+".Pairs"
+}
+
+// isIteratorSeq returns true if the given type is iterator.Seq or
+// iterator.Seq2.
+func isIteratorSeq(typ types.Type) bool {
+	named, ok := types.Unalias(typ).(*types.Named)
+	if !ok || named.Obj().Pkg() == nil || named.Obj().Pkg().Path() != iteratorPkg {
+		return false
+	}
+	return named.Obj().Name() == "Seq" || named.Obj().Name() == "Seq2"
+}
+
 func isInteropPath(s string) bool {
 	return strings.HasPrefix(s, interopPrefix)
 }
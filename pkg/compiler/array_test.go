@@ -0,0 +1,291 @@
+package compiler_test
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/compiler"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"github.com/stretchr/testify/require"
+)
+
+var arrayTestCases = []testCase{
+	{
+		"literal",
+		`package foo
+		func Main() int {
+			a := [3]int{1, 2, 3}
+			return a[0] + a[1]*10 + a[2]*100
+		}`,
+		big.NewInt(321),
+	},
+	{
+		"keyed literal",
+		`package foo
+		func Main() int {
+			a := [4]int{1: 2, 3: 4}
+			return a[0] + a[1]*10 + a[2]*100 + a[3]*1000
+		}`,
+		big.NewInt(4020),
+	},
+	{
+		"partial literal",
+		`package foo
+		func Main() int {
+			a := [3]int{1}
+			a[2] = 3
+			return a[0] + a[1]*10 + a[2]*100
+		}`,
+		big.NewInt(301),
+	},
+	{
+		"default value",
+		`package foo
+		func Main() int {
+			var a [3]int
+			a[1] = 2
+			return a[0] + a[1]*10 + a[2]*100
+		}`,
+		big.NewInt(20),
+	},
+	{
+		"long default value",
+		`package foo
+		func Main() int {
+			var a [100]int
+			a[99] = 7
+			return a[0] + a[99] + len(a)
+		}`,
+		big.NewInt(107),
+	},
+	{
+		"default value of other types",
+		`package foo
+		func Main() bool {
+			var (
+				s [2]string
+				b [2]bool
+			)
+			return s[1] == "" && !b[0]
+		}`,
+		true,
+	},
+	{
+		"byte array",
+		`package foo
+		func Main() int {
+			var a [4]byte
+			a[1] = 0xFF
+			return int(a[0]) + int(a[1])
+		}`,
+		big.NewInt(0xFF),
+	},
+	{
+		"len",
+		`package foo
+		func Main() int {
+			var a [5]int
+			return len(a)
+		}`,
+		big.NewInt(5),
+	},
+	{
+		"array constant length",
+		`package foo
+		const n = 3
+		func Main() int {
+			var a [n * 2]int
+			return len(a)
+		}`,
+		big.NewInt(6),
+	},
+	{
+		"nested arrays",
+		`package foo
+		func Main() int {
+			var a [2][3]int
+			a[1][2] = 42
+			b := [2][2]int{{1, 2}, {3, 4}}
+			return a[0][2] + a[1][2] + b[1][0]
+		}`,
+		big.NewInt(45),
+	},
+	{
+		"array of structs",
+		`package foo
+		type pair struct { a, b int }
+		func Main() int {
+			var ps [2]pair
+			ps[1].b = 42
+			return ps[0].b + ps[1].b
+		}`,
+		big.NewInt(42),
+	},
+	{
+		"array struct field",
+		`package foo
+		type holder struct { arr [3]int }
+		func Main() int {
+			var h holder
+			h.arr[1] = 42
+			return h.arr[1] + len(h.arr)
+		}`,
+		big.NewInt(45),
+	},
+	{
+		"copy on assignment",
+		`package foo
+		func Main() int {
+			a := [2]int{1, 2}
+			b := a
+			b[0] = 10
+			var c [2]int
+			c = b
+			c[1] = 20
+			return a[0] + a[1] + b[0] + b[1] + c[0] + c[1]
+		}`,
+		big.NewInt(1 + 2 + 10 + 2 + 10 + 20),
+	},
+	{
+		"copy of nested arrays",
+		`package foo
+		func Main() int {
+			a := [2][2]int{{1, 2}, {3, 4}}
+			b := a
+			b[1][1] = 40
+			return a[1][1] + b[1][1]
+		}`,
+		big.NewInt(44),
+	},
+	{
+		"copy on struct field assignment",
+		`package foo
+		type holder struct { arr [2]int }
+		func Main() int {
+			a := [2]int{1, 2}
+			h := holder{arr: a}
+			h.arr[0] = 10
+			a[1] = 20
+			return a[0] + a[1] + h.arr[0] + h.arr[1]
+		}`,
+		big.NewInt(1 + 20 + 10 + 2),
+	},
+	{
+		"copy on slice element assignment",
+		`package foo
+		func Main() int {
+			a := [2]int{1, 2}
+			s := [][2]int{a}
+			s[0][0] = 10
+			s = append(s, a)
+			a[1] = 20
+			return a[0] + a[1] + s[0][0] + s[1][1]
+		}`,
+		big.NewInt(1 + 20 + 10 + 2),
+	},
+	{
+		"copy on map value assignment",
+		`package foo
+		func Main() int {
+			a := [2]int{1, 2}
+			m := map[int][2]int{1: a}
+			m[2] = a
+			a[0] = 10
+			return a[0] + m[1][0] + m[2][0]
+		}`,
+		big.NewInt(12),
+	},
+	{
+		"pass by value",
+		`package foo
+		func Main() int {
+			a := [2]int{1, 2}
+			modify(a)
+			return a[0] + a[1]
+		}
+		func modify(a [2]int) {
+			a[0] = 10
+		}`,
+		big.NewInt(3),
+	},
+	{
+		"return by value",
+		`package foo
+		var global [2]int
+		func Main() int {
+			a := get()
+			a[0] = 10
+			return a[0] + global[0]
+		}
+		func get() [2]int {
+			return global
+		}`,
+		big.NewInt(10),
+	},
+	{
+		"range copies values",
+		`package foo
+		func Main() int {
+			var (
+				a   = [3][1]int{{1}, {2}, {3}}
+				sum int
+			)
+			for i, v := range a {
+				v[0] *= 10
+				sum += i + v[0]
+			}
+			return sum + a[0][0] + a[1][0] + a[2][0]
+		}`,
+		big.NewInt(0 + 1 + 2 + 60 + 6),
+	},
+	{
+		"equality",
+		`package foo
+		func Main() bool {
+			a := [3]int{1, 2, 3}
+			b := [3]int{1, 2, 3}
+			c := [3]int{1, 2, 4}
+			return a == b && a != c && [2][1]int{{1}, {2}} == [2][1]int{{1}, {2}}
+		}`,
+		true,
+	},
+	{
+		"return array",
+		`package foo
+		func Main() [2]int {
+			return [2]int{1, 2}
+		}`,
+		[]stackitem.Item{stackitem.Make(1), stackitem.Make(2)},
+	},
+}
+
+func TestArrays(t *testing.T) {
+	runTestCases(t, arrayTestCases)
+}
+
+func TestArraySliceExpr(t *testing.T) {
+	src := `package foo
+	func Main() []int {
+		a := [3]int{1, 2, 3}
+		return a[1:]
+	}`
+	_, err := compiler.Compile("foo.go", strings.NewReader(src))
+	require.ErrorContains(t, err, "slicing arrays is not supported")
+}
+
+func TestArrayManifest(t *testing.T) {
+	src := `package foo
+	func Main(a [2]int) [3]string {
+		return [3]string{}
+	}`
+	_, di, err := compiler.CompileWithOptions("foo.go", strings.NewReader(src), nil)
+	require.NoError(t, err)
+	m, err := di.ConvertToManifest(&compiler.Options{Name: "Foo"})
+	require.NoError(t, err)
+	md := m.ABI.GetMethod("main", 1)
+	require.NotNil(t, md)
+	require.Equal(t, smartcontract.ArrayType, md.Parameters[0].Type)
+	require.Equal(t, smartcontract.ArrayType, md.ReturnType)
+}
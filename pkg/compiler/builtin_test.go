@@ -105,3 +105,66 @@ func TestMaxMin(t *testing.T) {
 		}
 	}
 }
+
+func TestClear(t *testing.T) {
+	runTestCases(t, []testCase{
+		{
+			"map",
+			`package foo
+			func Main() int {
+				m := map[int]int{1: 2, 3: 4}
+				clear(m)
+				m[5] = 6
+				return len(m)
+			}`,
+			big.NewInt(1),
+		},
+		{
+			"slice",
+			`package foo
+			func Main() int {
+				s := []int{1, 2, 3}
+				clear(s)
+				return len(s)*100 + s[0] + s[1] + s[2]
+			}`,
+			big.NewInt(300),
+		},
+		{
+			"byte slice",
+			`package foo
+			func Main() []byte {
+				b := []byte{1, 2, 3}
+				clear(b[3:])
+				clear(b)
+				return b
+			}`,
+			[]byte{0, 0, 0},
+		},
+		{
+			"slice of structs",
+			`package foo
+			type pair struct { a, b int }
+			func Main() int {
+				s := []pair{{1, 2}, {3, 4}}
+				clear(s)
+				s[0].a = 5
+				return s[0].a + s[0].b + s[1].a
+			}`,
+			big.NewInt(5),
+		},
+		{
+			"nil",
+			`package foo
+			func Main() int {
+				var (
+					m map[string]int
+					s []string
+				)
+				clear(m)
+				clear(s)
+				return len(m) + len(s)
+			}`,
+			big.NewInt(0),
+		},
+	})
+}
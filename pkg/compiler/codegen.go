@@ -14,6 +14,7 @@ import (
 	"slices"
 	"strings"
 
+	"github.com/nspcc-dev/neo-go/pkg/core/interop/interopnames"
 	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
 	"github.com/nspcc-dev/neo-go/pkg/io"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
@@ -46,6 +47,15 @@ type codegen struct {
 	// A mapping of lambda functions into their scope.
 	lambda map[string]*funcScope
 
+	// instances contains type arguments of all used generic function
+	// instances by instance name for every generic function declaration.
+	instances map[*ast.FuncDecl]map[string]typeArgMap
+	// typeArgs contains type arguments of the generic function instance
+	// being processed, it's nil for non-generic functions.
+	typeArgs typeArgMap
+	// typeCtx is used to deduplicate generic type instances.
+	typeCtx *types.Context
+
 	// reverseOffsetMap maps function offsets to a local variable count.
 	reverseOffsetMap map[int]nameWithLocals
 
@@ -57,8 +67,12 @@ type codegen struct {
 	staticVariables []string
 	// initVariables contains variables local to `_initialize` method.
 	initVariables []string
+	// initVariableSlots contains local slot indexes of initVariables.
+	initVariableSlots []int
 	// deployVariables contains variables local to `_initialize` method.
 	deployVariables []string
+	// deployVariableSlots contains local slot indexes of deployVariables.
+	deployVariableSlots []int
 
 	// A mapping from label's names to their ids.
 	labels map[labelWithType]uint16
@@ -163,6 +177,12 @@ const (
 	varArgument
 )
 
+// maxUnrolledArrayDefault is the maximum length of basic type arrays that are
+// initialized with default values by pushing every element, longer ones are
+// created with NEWARRAY_T and converted to Struct which is more expensive, but
+// shorter.
+const maxUnrolledArrayDefault = 64
+
 // ErrUnsupportedTypeAssertion is returned when type assertion statement is not supported by the compiler.
 var ErrUnsupportedTypeAssertion = errors.New("type assertion with two return values is not supported")
 
@@ -350,11 +370,59 @@ func (c *codegen) emitDefault(t types.Type) {
 		}
 		emit.Int(c.prog.BinWriter, int64(num))
 		emit.Opcodes(c.prog.BinWriter, opcode.PACKSTRUCT)
+	case *types.Array:
+		n := t.Len()
+		switch t.Elem().Underlying().(type) {
+		case *types.Struct, *types.Array:
+			// Every element is a separate struct.
+		default:
+			if n > maxUnrolledArrayDefault {
+				// NEWARRAY_T fills the array with the default values of the
+				// element type, it's only to be converted to Struct.
+				emit.Int(c.prog.BinWriter, n)
+				emit.Instruction(c.prog.BinWriter, opcode.NEWARRAYT, []byte{byte(toNeoType(t.Elem()))})
+				emit.Instruction(c.prog.BinWriter, opcode.CONVERT, []byte{byte(stackitem.StructT)})
+				return
+			}
+		}
+		for range n {
+			c.emitDefault(t.Elem())
+		}
+		emit.Int(c.prog.BinWriter, n)
+		emit.Opcodes(c.prog.BinWriter, opcode.PACKSTRUCT)
 	default:
 		emit.Opcodes(c.prog.BinWriter, opcode.PUSHNULL)
 	}
 }
 
+// emitStructClone replaces the struct on top of the stack with its deep copy.
+func (c *codegen) emitStructClone() {
+	// To clone struct fields we create a new array and append struct to it.
+	// This way even non-pointer struct fields will be copied.
+	emit.Opcodes(c.prog.BinWriter, opcode.NEWARRAY0,
+		opcode.DUP, opcode.ROT, opcode.APPEND,
+		opcode.POPITEM)
+}
+
+// emitArrayCopy copies the array that is on top of the stack (as a result of
+// the given expression evaluation) to preserve Go array value semantics. It
+// does nothing for non-array expressions and for expressions producing new
+// values (literals and function calls).
+func (c *codegen) emitArrayCopy(e ast.Expr) {
+	if !isArray(c.typeOf(e)) {
+		return
+	}
+	switch t := ast.Unparen(e).(type) {
+	case *ast.CompositeLit:
+		return
+	case *ast.CallExpr:
+		if !c.typeAndValueOf(t.Fun).IsType() {
+			return
+		}
+	}
+	c.emitStructClone()
+}
+
 // convertGlobals traverses the AST and only converts global declarations.
 // If we call this in convertFuncDecl, it will load all global variables
 // into the scope of the function.
@@ -545,8 +613,10 @@ func (c *codegen) convertFuncDecl(file ast.Node, decl *ast.FuncDecl, pkg *types.
 
 	if isInit {
 		c.initVariables = append(c.initVariables, f.variables...)
+		c.initVariableSlots = append(c.initVariableSlots, f.variableSlots...)
 	} else if isDeploy {
 		c.deployVariables = append(c.deployVariables, f.variables...)
+		c.deployVariableSlots = append(c.deployVariableSlots, f.variableSlots...)
 	}
 
 	f.rng.End = uint16(c.prog.Len() - 1)
@@ -580,13 +650,6 @@ func (c *codegen) Visit(node ast.Node) ast.Visitor {
 	//     x = 2
 	// )
 	case *ast.GenDecl:
-		// Filter out generics usage.
-		err := c.checkGenericsGenDecl(n, c.currPkg.PkgPath)
-		if err != nil {
-			c.prog.Err = err
-			return nil // Program is invalid.
-		}
-
 		if n.Tok == token.VAR || n.Tok == token.CONST {
 			c.saveSequencePoint(n)
 		}
@@ -619,14 +682,15 @@ func (c *codegen) Visit(node ast.Node) ast.Visitor {
 				multiRet := n.Tok == token.VAR && len(t.Values) != 0 && len(t.Names) != len(t.Values)
 				for _, id := range t.Names {
 					if id.Name != "_" {
+						var slot = -1
 						if c.scope == nil {
 							// it is a global declaration
 							c.newGlobal("", id.Name)
 						} else {
-							c.scope.newLocal(id.Name)
+							slot = c.scope.newLocal(id.Name)
 						}
 						if !multiRet {
-							c.registerDebugVariable(id.Name, t.Type)
+							c.registerDebugVariable(id.Name, t.Type, slot)
 						}
 					}
 				}
@@ -635,6 +699,7 @@ func (c *codegen) Visit(node ast.Node) ast.Visitor {
 						if len(t.Values) != 0 {
 							if i == 0 || !multiRet {
 								ast.Walk(c, t.Values[i])
+								c.emitArrayCopy(t.Values[i])
 							}
 						} else {
 							c.emitDefault(c.typeOf(t.Type))
@@ -684,21 +749,24 @@ func (c *codegen) Visit(node ast.Node) ast.Visitor {
 			switch t := n.Lhs[i].(type) {
 			case *ast.Ident:
 				if n.Tok == token.DEFINE {
-					if !multiRet {
-						c.registerDebugVariable(t.Name, n.Rhs[i])
-					}
+					var slot = -1
 					if t.Name != "_" {
-						c.scope.newLocal(t.Name)
+						slot = c.scope.newLocal(t.Name)
+					}
+					if !multiRet {
+						c.registerDebugVariable(t.Name, n.Rhs[i], slot)
 					}
 				}
 				if !isAssignOp && (i == 0 || !multiRet) {
 					ast.Walk(c, n.Rhs[i])
+					c.emitArrayCopy(n.Rhs[i])
 				}
 				c.emitStoreVar("", t.Name)
 
 			case *ast.SelectorExpr:
 				if !isAssignOp {
 					ast.Walk(c, n.Rhs[i])
+					c.emitArrayCopy(n.Rhs[i])
 				}
 				typ := c.typeOf(t.X)
 				if c.isInvalidType(typ) {
@@ -720,6 +788,7 @@ func (c *codegen) Visit(node ast.Node) ast.Visitor {
 			case *ast.IndexExpr:
 				if !isAssignOp {
 					ast.Walk(c, n.Rhs[i])
+					c.emitArrayCopy(n.Rhs[i])
 				}
 				ast.Walk(c, t.X)
 				ast.Walk(c, t.Index)
@@ -733,6 +802,10 @@ func (c *codegen) Visit(node ast.Node) ast.Visitor {
 			c.prog.Err = errors.New("subslices are supported only for []byte and string")
 			return nil
 		}
+		if isArray(c.typeOf(n.X)) {
+			c.prog.Err = errors.New("slicing arrays is not supported")
+			return nil
+		}
 
 		ast.Walk(c, n.X)
 
@@ -788,6 +861,7 @@ func (c *codegen) Visit(node ast.Node) ast.Visitor {
 			// first result should be on top of the stack
 			for i := len(n.Results) - 1; i >= 0; i-- {
 				ast.Walk(c, n.Results[i])
+				c.emitArrayCopy(n.Results[i])
 			}
 		}
 
@@ -931,6 +1005,10 @@ func (c *codegen) Visit(node ast.Node) ast.Visitor {
 		return nil
 
 	case *ast.Ident:
+		if c.instanceOf(n) != nil {
+			c.prog.Err = fmt.Errorf("%w: generic function %s can only be called", ErrGenericsUnsuppored, n.Name)
+			return nil
+		}
 		if tv := c.typeAndValueOf(n); tv.Value != nil {
 			c.emitLoadConst(tv)
 		} else if n.Name == "nil" {
@@ -947,6 +1025,8 @@ func (c *codegen) Visit(node ast.Node) ast.Visitor {
 			c.convertStruct(n, false)
 		case *types.Map:
 			c.convertMap(n)
+		case *types.Array:
+			c.convertArray(n, typ)
 		default:
 			if tn, ok := t.(*types.Named); ok && isInteropPath(tn.String()) {
 				st, _, _, _ := scAndVMInteropTypeFromExpr(tn, false)
@@ -971,6 +1051,7 @@ func (c *codegen) Visit(node ast.Node) ast.Visitor {
 			}
 			for i := ln - 1; i >= 0; i-- {
 				ast.Walk(c, n.Elts[i])
+				c.emitArrayCopy(n.Elts[i])
 			}
 			emit.Int(c.prog.BinWriter, int64(ln))
 			emit.Opcodes(c.prog.BinWriter, opcode.PACK)
@@ -993,7 +1074,7 @@ func (c *codegen) Visit(node ast.Node) ast.Visitor {
 			isLiteral bool
 		)
 
-		switch fun := n.Fun.(type) {
+		switch fun := c.unwrapInstance(n.Fun).(type) {
 		case *ast.Ident:
 			f, ok = c.getFuncFromIdent(fun)
 			isBuiltin = isGoBuiltin(fun.Name)
@@ -1010,6 +1091,11 @@ func (c *codegen) Visit(node ast.Node) ast.Visitor {
 			}
 		case *ast.SelectorExpr:
 			name, isMethod := c.getFuncNameFromSelector(fun)
+			if isIteratorSeqFunc(name) {
+				// Sequence is the Iterator itself.
+				ast.Walk(c, n.Args[0])
+				return nil
+			}
 
 			f, ok = c.funcs[name]
 			if ok {
@@ -1058,11 +1144,9 @@ func (c *codegen) Visit(node ast.Node) ast.Visitor {
 			typ := c.typeOf(arg)
 			_, ok := typ.Underlying().(*types.Struct)
 			if ok && !isInteropPath(typ.String()) {
-				// To clone struct fields we create a new array and append struct to it.
-				// This way even non-pointer struct fields will be copied.
-				emit.Opcodes(c.prog.BinWriter, opcode.NEWARRAY0,
-					opcode.DUP, opcode.ROT, opcode.APPEND,
-					opcode.POPITEM)
+				c.emitStructClone()
+			} else {
+				c.emitArrayCopy(arg)
 			}
 		}
 		// Do not swap for builtin functions.
@@ -1317,19 +1401,33 @@ func (c *codegen) Visit(node ast.Node) ast.Visitor {
 
 		ast.Walk(c, n.X)
 
-		// Implementation is a bit different for slices and maps:
+		if seq, ok := c.typeOf(n.X).Underlying().(*types.Signature); ok {
+			c.convertRangeOverSeq(n, seq, label, start, end, post)
+			c.currentFor = lastFor
+			c.currentSwitch = lastSwitch
+			return nil
+		}
+
+		// Implementation is a bit different for slices, maps and integers:
 		// For slices, we iterate through indices from 0 to len-1, storing array, len and index on stack.
 		// For maps, we iterate through indices from 0 to len-1, storing map, keyarray, size and index on stack.
+		// For integers, we iterate from 0 to n-1, storing n and index on stack.
 		_, isMap := c.typeOf(n.X).Underlying().(*types.Map)
-		emit.Opcodes(c.prog.BinWriter, opcode.DUP)
-		if isMap {
-			emit.Opcodes(c.prog.BinWriter, opcode.KEYS, opcode.DUP)
+		isInt := isInteger(c.typeOf(n.X))
+		if !isInt {
+			emit.Opcodes(c.prog.BinWriter, opcode.DUP)
+			if isMap {
+				emit.Opcodes(c.prog.BinWriter, opcode.KEYS, opcode.DUP)
+			}
+			emit.Opcodes(c.prog.BinWriter, opcode.SIZE)
 		}
-		emit.Opcodes(c.prog.BinWriter, opcode.SIZE, opcode.PUSH0)
+		emit.Opcodes(c.prog.BinWriter, opcode.PUSH0)
 
 		stackSize := 3 // slice, len(slice), index
 		if isMap {
 			stackSize++ // map, keys, len(keys), index in keys
+		} else if isInt {
+			stackSize-- // n, index
 		}
 		c.pushStackLabel(label, stackSize)
 		c.setLabel(start)
@@ -1337,29 +1435,16 @@ func (c *codegen) Visit(node ast.Node) ast.Visitor {
 		emit.Opcodes(c.prog.BinWriter, opcode.OVER, opcode.OVER)
 		emit.Jmp(c.prog.BinWriter, opcode.JMPLEL, end)
 
+		keyIdent, valIdent, err := rangeIdents(n)
+		if err != nil {
+			c.prog.Err = err
+			return nil
+		}
 		var (
-			haveKey   bool
-			haveVal   bool
-			keyIdent  *ast.Ident
+			haveKey   = keyIdent != nil
+			haveVal   = valIdent != nil
 			keyLoaded bool
-			valIdent  *ast.Ident
 		)
-		if n.Key != nil {
-			keyIdent, haveKey = n.Key.(*ast.Ident)
-			if !haveKey {
-				c.prog.Err = errors.New("only simple identifiers can be used for range loop keys (see #2870)")
-				return nil
-			}
-			haveKey = (keyIdent.Name != "_")
-		}
-		if n.Value != nil {
-			valIdent, haveVal = n.Value.(*ast.Ident)
-			if !haveVal {
-				c.prog.Err = errors.New("only simple identifiers can be used for range loop values (see #2870)")
-				return nil
-			}
-			haveVal = (valIdent.Name != "_")
-		}
 		if haveKey {
 			if isMap {
 				c.rangeLoadKey()
@@ -1387,6 +1472,9 @@ func (c *codegen) Visit(node ast.Node) ast.Visitor {
 					opcode.SWAP, // key should be on top
 					opcode.PICKITEM)
 			}
+			if isArray(c.typeOf(valIdent)) {
+				c.emitStructClone()
+			}
 			if n.Tok == token.DEFINE {
 				c.scope.newLocal(valIdent.Name)
 			}
@@ -1543,6 +1631,99 @@ func (c *codegen) isInvalidType(typ types.Type) bool {
 	return typ == nil || ok && tb.Kind() == types.Invalid
 }
 
+// rangeIdents returns key and value identifiers of the range loop, nil is
+// returned for the missing and blank ones.
+func rangeIdents(n *ast.RangeStmt) (*ast.Ident, *ast.Ident, error) {
+	var keyIdent, valIdent *ast.Ident
+	if n.Key != nil {
+		id, ok := n.Key.(*ast.Ident)
+		if !ok {
+			return nil, nil, errors.New("only simple identifiers can be used for range loop keys (see #2870)")
+		}
+		if id.Name != "_" {
+			keyIdent = id
+		}
+	}
+	if n.Value != nil {
+		id, ok := n.Value.(*ast.Ident)
+		if !ok {
+			return nil, nil, errors.New("only simple identifiers can be used for range loop values (see #2870)")
+		}
+		if id.Name != "_" {
+			valIdent = id
+		}
+	}
+	return keyIdent, valIdent, nil
+}
+
+// convertRangeOverSeq emits a loop over iterator.Seq or iterator.Seq2 sequence
+// which is an Iterator at runtime. Iterator is the only item stored on stack,
+// Seq values are stored in the key variable, Seq2 key-value pairs are split
+// into key and value variables.
+func (c *codegen) convertRangeOverSeq(n *ast.RangeStmt, seq *types.Signature, label string, start, end, post uint16) {
+	if !isIteratorSeq(c.typeOf(n.X)) {
+		c.prog.Err = errors.New("only iterator.Seq and iterator.Seq2 sequences can be used in range-over-func loops")
+		return
+	}
+	keyIdent, valIdent, err := rangeIdents(n)
+	if err != nil {
+		c.prog.Err = err
+		return
+	}
+	var (
+		yield = seq.Params().At(0).Type().Underlying().(*types.Signature)
+		vars  = []*ast.Ident{keyIdent, valIdent}
+	)
+
+	c.pushStackLabel(label, 1)
+	c.setLabel(start)
+
+	emit.Opcodes(c.prog.BinWriter, opcode.DUP)
+	emit.Syscall(c.prog.BinWriter, interopnames.SystemIteratorNext)
+	emit.Jmp(c.prog.BinWriter, opcode.JMPIFNOTL, end)
+
+	if keyIdent != nil || valIdent != nil {
+		emit.Opcodes(c.prog.BinWriter, opcode.DUP)
+		emit.Syscall(c.prog.BinWriter, interopnames.SystemIteratorValue)
+		if yield.Params().Len() == 1 {
+			c.rangeStoreSeqVar(n, keyIdent, yield.Params().At(0).Type())
+		} else {
+			// Key-value pair is an array of two elements.
+			for i, id := range vars {
+				if id == nil {
+					continue
+				}
+				if i == 0 && valIdent != nil {
+					emit.Opcodes(c.prog.BinWriter, opcode.DUP)
+				}
+				emit.Int(c.prog.BinWriter, int64(i))
+				emit.Opcodes(c.prog.BinWriter, opcode.PICKITEM)
+				c.rangeStoreSeqVar(n, id, yield.Params().At(i).Type())
+			}
+		}
+	}
+
+	ast.Walk(c, n.Body)
+
+	c.setLabel(post)
+	emit.Jmp(c.prog.BinWriter, opcode.JMPL, start)
+
+	c.setLabel(end)
+	c.dropStackLabel()
+}
+
+// rangeStoreSeqVar converts the top stack item to the given type (the same
+// way type assertion does) and stores it into the range loop variable.
+func (c *codegen) rangeStoreSeqVar(n *ast.RangeStmt, id *ast.Ident, typ types.Type) {
+	if canConvert(typ.String()) && !types.IsInterface(typ) {
+		c.emitConvert(toNeoType(typ))
+	}
+	if n.Tok == token.DEFINE {
+		c.scope.newLocal(id.Name)
+	}
+	c.emitStoreVar("", id.Name)
+}
+
 func (c *codegen) rangeLoadKey() {
 	emit.Int(c.prog.BinWriter, 2)
 	emit.Opcodes(c.prog.BinWriter,
@@ -1963,6 +2144,31 @@ func (c *codegen) convertBuiltin(expr *ast.CallExpr) {
 		c.emitStoreByIndex(varGlobal, c.exceptionIndex)
 	case "delete":
 		emit.Opcodes(c.prog.BinWriter, opcode.REMOVE)
+	case "clear":
+		// Clearing nil map or slice is a no-op.
+		isNil := c.newLabel()
+		emit.Opcodes(c.prog.BinWriter, opcode.DUP, opcode.ISNULL)
+		emit.Jmp(c.prog.BinWriter, opcode.JMPIFL, isNil)
+		typ := c.typeOf(expr.Args[0])
+		if isMap(typ) {
+			emit.Opcodes(c.prog.BinWriter, opcode.DUP, opcode.CLEARITEMS)
+		} else {
+			// Slice length is preserved, all elements are set to the default value.
+			start := c.newLabel()
+			end := c.newLabel()
+			emit.Opcodes(c.prog.BinWriter, opcode.DUP, opcode.SIZE) // s n
+			c.setLabel(start)
+			emit.Opcodes(c.prog.BinWriter, opcode.DEC, opcode.DUP, opcode.PUSH0) // s i i 0
+			emit.Jmp(c.prog.BinWriter, opcode.JMPLTL, end)
+			emit.Opcodes(c.prog.BinWriter, opcode.OVER, opcode.OVER) // s i s i
+			c.emitDefault(typ.Underlying().(*types.Slice).Elem())
+			emit.Opcodes(c.prog.BinWriter, opcode.SETITEM) // s i
+			emit.Jmp(c.prog.BinWriter, opcode.JMPL, start)
+			c.setLabel(end)
+			emit.Opcodes(c.prog.BinWriter, opcode.DROP)
+		}
+		c.setLabel(isNil)
+		emit.Opcodes(c.prog.BinWriter, opcode.DROP)
 	case "ToHash160":
 		// We can be sure that this is an ast.BasicLit just containing a simple
 		// address string. Note that the string returned from calling Value will
@@ -2088,12 +2294,40 @@ func (c *codegen) convertMap(lit *ast.CompositeLit) {
 	for i := l - 1; i >= 0; i-- {
 		elem := lit.Elts[i].(*ast.KeyValueExpr)
 		ast.Walk(c, elem.Value)
+		c.emitArrayCopy(elem.Value)
 		ast.Walk(c, elem.Key)
 	}
 	emit.Int(c.prog.BinWriter, int64(l))
 	emit.Opcodes(c.prog.BinWriter, opcode.PACKMAP)
 }
 
+// convertArray converts array literal. Arrays are represented by structs, so
+// that they're copied by value just like structs.
+func (c *codegen) convertArray(lit *ast.CompositeLit, typ *types.Array) {
+	var (
+		elems = make([]ast.Expr, typ.Len())
+		index int64
+	)
+	for _, e := range lit.Elts {
+		if kv, ok := e.(*ast.KeyValueExpr); ok {
+			index, _ = constant.Int64Val(constant.ToInt(c.typeAndValueOf(kv.Key).Value))
+			e = kv.Value
+		}
+		elems[index] = e
+		index++
+	}
+	for i := len(elems) - 1; i >= 0; i-- {
+		if elems[i] == nil {
+			c.emitDefault(typ.Elem())
+			continue
+		}
+		ast.Walk(c, elems[i])
+		c.emitArrayCopy(elems[i])
+	}
+	emit.Int(c.prog.BinWriter, int64(len(elems)))
+	emit.Opcodes(c.prog.BinWriter, opcode.PACKSTRUCT)
+}
+
 func (c *codegen) getStruct(typ types.Type) (*types.Struct, bool) {
 	switch t := typ.Underlying().(type) {
 	case *types.Struct:
@@ -2129,6 +2363,7 @@ func (c *codegen) convertStruct(lit *ast.CompositeLit, ptr bool) {
 		if !keyedLit {
 			if len(lit.Elts) > i {
 				ast.Walk(c, lit.Elts[i])
+				c.emitArrayCopy(lit.Elts[i])
 				initialized = true
 			}
 		} else {
@@ -2139,6 +2374,7 @@ func (c *codegen) convertStruct(lit *ast.CompositeLit, ptr bool) {
 
 				if sField.Name() == fieldName {
 					ast.Walk(c, f.Value)
+					c.emitArrayCopy(f.Value)
 					initialized = true
 					break
 				}
@@ -2236,30 +2472,45 @@ func (c *codegen) newFunc(decl *ast.FuncDecl) *funcScope {
 	return f
 }
 
+// isEmitted returns true if the code for the function was generated (i.e. it
+// is used and not inlined).
+func (c *codegen) isEmitted(f *funcScope) bool {
+	return c.l[f.label] >= 0
+}
+
 func (c *codegen) getFuncFromIdent(fun *ast.Ident) (*funcScope, bool) {
 	var pkgName string
 	if len(c.pkgInfoInline) != 0 {
 		pkgName = c.pkgInfoInline[len(c.pkgInfoInline)-1].PkgPath
 	}
 
-	f, ok := c.funcs[c.getIdentName(pkgName, fun.Name)]
+	f, ok := c.funcs[c.getIdentName(pkgName, fun.Name)+instanceSuffix(c.instanceOf(fun))]
 	return f, ok
 }
 
 // getFuncNameFromSelector returns fully-qualified function name from the selector expression.
 // Second return value is true iff this was a method call, not foreign package call.
+// Names of generic function instances include type arguments.
 func (c *codegen) getFuncNameFromSelector(e *ast.SelectorExpr) (string, bool) {
 	if c.typeInfo.Selections[e] != nil {
-		typ := c.typeInfo.Types[e.X].Type.String()
+		var (
+			typ   = c.subst(c.typeInfo.Types[e.X].Type).String()
+			targs = c.recvTypeArgs(e)
+		)
+		if targs != nil {
+			// Use generic type name without type arguments.
+			named, _ := derefNamed(c.subst(c.typeInfo.Types[e.X].Type))
+			typ = named.Obj().Pkg().Path() + "." + named.Obj().Name()
+		}
 		name := c.getIdentName(typ, e.Sel.Name)
 		if name[0] == '*' {
 			name = name[1:]
 		}
-		return name, true
+		return name + instanceSuffix(targs), true
 	}
 
 	ident := e.X.(*ast.Ident)
-	return c.getIdentName(ident.Name, e.Sel.Name), false
+	return c.getIdentName(ident.Name, e.Sel.Name) + instanceSuffix(c.instanceOf(e.Sel)), false
 }
 
 func (c *codegen) newLambda(u uint16, lit *ast.FuncLit) {
@@ -2319,11 +2570,13 @@ func (c *codegen) compile(info *buildInfo, pkg *packages.Package) error {
 				if pkg != c.mainPkg.Types { // not a main package
 					pkgPath = pkg.Path()
 				}
-				name := c.getFuncNameFromDecl(pkgPath, n)
-				if !isInitFunc(n) && !isDeployFunc(n) && funUsage.funcUsed(name) &&
-					(!isInteropPath(pkg.Path()) && !canInline(pkg.Path(), n.Name.Name, false)) {
-					c.convertFuncDecl(f, n, pkg)
-				}
+				c.forEachInstance(n, func() {
+					name := c.getFuncNameFromDecl(pkgPath, n)
+					if !isInitFunc(n) && !isDeployFunc(n) && funUsage.funcUsed(name) &&
+						(!isInteropPath(pkg.Path()) && !canInline(pkg.Path(), n.Name.Name, false)) {
+						c.convertFuncDecl(f, n, pkg)
+					}
+				})
 			}
 		}
 	})
@@ -2338,6 +2591,7 @@ func newCodegen(info *buildInfo, pkg *packages.Package) *codegen {
 		l:                []int{},
 		funcs:            map[string]*funcScope{},
 		lambda:           map[string]*funcScope{},
+		instances:        map[*ast.FuncDecl]map[string]typeArgMap{},
 		reverseOffsetMap: map[int]nameWithLocals{},
 		globals:          map[string]int{},
 		labels:           map[labelWithType]uint16{},
@@ -2355,8 +2609,9 @@ func newCodegen(info *buildInfo, pkg *packages.Package) *codegen {
 	}
 }
 
-// codeGen compiles the program to bytecode.
-func codeGen(info *buildInfo) (*nef.File, *DebugInfo, error) {
+// generate compiles the program and returns the code generator state
+// together with the resulting unoptimized bytecode.
+func generate(info *buildInfo) (*codegen, []byte, error) {
 	if len(info.program) == 0 {
 		return nil, nil, errors.New("empty package")
 	}
@@ -2371,6 +2626,21 @@ func codeGen(info *buildInfo) (*nef.File, *DebugInfo, error) {
 	if err != nil {
 		return nil, nil, err
 	}
+	return c, buf, nil
+}
+
+// codeGen compiles the program to bytecode.
+func codeGen(info *buildInfo) (*nef.File, *DebugInfo, error) {
+	c, buf, err := generate(info)
+	if err != nil {
+		return nil, nil, err
+	}
+	if info.options != nil && info.options.Optimize {
+		buf, err = c.optimize(buf)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to optimize: %w", err)
+		}
+	}
 
 	methods := bitfield.New(len(buf))
 	di := c.emitDebugInfo(buf)
@@ -2392,12 +2662,35 @@ func (c *codegen) resolveFuncDecls(f *ast.File, pkg *types.Package) {
 	for _, decl := range f.Decls {
 		switch n := decl.(type) {
 		case *ast.FuncDecl:
-			fs := c.newFunc(n)
-			fs.file = f
+			c.forEachInstance(n, func() {
+				fs := c.newFunc(n)
+				fs.file = f
+			})
 		}
 	}
 }
 
+// forEachInstance calls fn for every used instance of the given generic
+// function declaration with the instance type arguments set. For non-generic
+// functions fn is called once.
+func (c *codegen) forEachInstance(decl *ast.FuncDecl, fn func()) {
+	if !isGenericFunc(decl) {
+		fn()
+		return
+	}
+	insts := c.instances[decl]
+	names := make([]string, 0, len(insts))
+	for name := range insts {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		c.typeArgs = insts[name]
+		fn()
+	}
+	c.typeArgs = nil
+}
+
 func (c *codegen) writeJumps(b []byte) ([]byte, error) {
 	ctx := vm.NewContext(b)
 	var nopOffsets []int
@@ -2650,6 +2943,8 @@ func toShortForm(op opcode.Opcode) opcode.Opcode {
 		return opcode.CALL
 	case opcode.ENDTRYL:
 		return opcode.ENDTRY
+	case opcode.TRYL:
+		return opcode.TRY
 	default:
 		panic(fmt.Errorf("invalid opcode: %s", op))
 	}
@@ -90,6 +90,10 @@ type Options struct {
 
 	// BindingsFile contains configuration for smart-contract bindings generator.
 	BindingsFile string
+
+	// Optimize enables bytecode optimizations (peephole and dead code
+	// elimination) that reduce the script size and GAS consumption.
+	Optimize bool
 }
 
 // HybridEvent represents the description of event emitted by the contract squashed
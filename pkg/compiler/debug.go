@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"go/ast"
 	"go/types"
+	"path/filepath"
 	"slices"
 	"strconv"
 	"strings"
@@ -68,6 +69,10 @@ type MethodDebugInfo struct {
 	// ReturnTypeSC is a return type to use in manifest.
 	ReturnTypeSC smartcontract.ParamType `json:"-"`
 	Variables    []string                `json:"variables"`
+	// VariableSlots contains local slot indexes of Variables (-1 is used for
+	// variables that don't have a slot). It's only available for freshly
+	// compiled contracts and is not a part of the debug info file.
+	VariableSlots []int `json:"-"`
 	// SeqPoints is a map between source lines and byte-code instruction offsets.
 	SeqPoints []DebugSeqPoint `json:"sequence-points"`
 }
@@ -165,10 +170,11 @@ func (c *codegen) emitDebugInfo(contract []byte) *DebugInfo {
 				Start: 0,
 				End:   uint16(c.initEndOffset),
 			},
-			ReturnType:   "Void",
-			ReturnTypeSC: smartcontract.VoidType,
-			SeqPoints:    c.sequencePoints["init"],
-			Variables:    c.initVariables,
+			ReturnType:    "Void",
+			ReturnTypeSC:  smartcontract.VoidType,
+			SeqPoints:     c.sequencePoints["init"],
+			Variables:     c.initVariables,
+			VariableSlots: c.initVariableSlots,
 		})
 	}
 	if c.deployEndOffset >= 0 {
@@ -196,16 +202,17 @@ func (c *codegen) emitDebugInfo(contract []byte) *DebugInfo {
 					TypeSC: smartcontract.BoolType,
 				},
 			},
-			ReturnType:   "Void",
-			ReturnTypeSC: smartcontract.VoidType,
-			SeqPoints:    c.sequencePoints[manifest.MethodDeploy],
-			Variables:    c.deployVariables,
+			ReturnType:    "Void",
+			ReturnTypeSC:  smartcontract.VoidType,
+			SeqPoints:     c.sequencePoints[manifest.MethodDeploy],
+			Variables:     c.deployVariables,
+			VariableSlots: c.deployVariableSlots,
 		})
 	}
 
 	var fnames = make([]string, 0, len(c.funcs))
 	for name, scope := range c.funcs {
-		if scope.rng.Start == scope.rng.End {
+		if !c.isEmitted(scope) {
 			continue
 		}
 		fnames = append(fnames, name)
@@ -221,16 +228,22 @@ func (c *codegen) emitDebugInfo(contract []byte) *DebugInfo {
 	return d
 }
 
-func (c *codegen) registerDebugVariable(name string, expr ast.Expr) {
+func (c *codegen) registerDebugVariable(name string, expr ast.Expr, slot int) {
 	_, vt, _, _ := c.scAndVMTypeFromExpr(expr, nil)
 	if c.scope == nil {
 		c.staticVariables = append(c.staticVariables, name+","+vt.String())
 		return
 	}
 	c.scope.variables = append(c.scope.variables, name+","+vt.String())
+	c.scope.variableSlots = append(c.scope.variableSlots, slot)
 }
 
 func (c *codegen) methodInfoFromScope(name string, scope *funcScope, exts map[string]binding.ExtendedType) *MethodDebugInfo {
+	// Parameter types of generic function instances are resolved using
+	// instance type arguments.
+	c.typeArgs = scope.typeArgs
+	defer func() { c.typeArgs = nil }()
+
 	ps := scope.decl.Type.Params
 	params := make([]DebugParam, 0, ps.NumFields())
 	for i := range ps.List {
@@ -245,8 +258,7 @@ func (c *codegen) methodInfoFromScope(name string, scope *funcScope, exts map[st
 			})
 		}
 	}
-	ss := strings.Split(name, ".")
-	name = ss[len(ss)-1]
+	name = scope.name
 	r, n := utf8.DecodeRuneInString(name)
 	st, vt, rt, et := c.scAndVMReturnTypeFromScope(scope, exts)
 
@@ -256,7 +268,7 @@ func (c *codegen) methodInfoFromScope(name string, scope *funcScope, exts map[st
 			Name:      string(unicode.ToLower(r)) + name[n:],
 			Namespace: scope.pkg.Name(),
 		},
-		IsExported:         scope.decl.Name.IsExported(),
+		IsExported:         scope.decl.Name.IsExported() && scope.typeArgs == nil,
 		IsFunction:         scope.decl.Recv == nil,
 		Range:              scope.rng,
 		Parameters:         params,
@@ -266,6 +278,7 @@ func (c *codegen) methodInfoFromScope(name string, scope *funcScope, exts map[st
 		ReturnTypeSC:       st,
 		SeqPoints:          c.sequencePoints[name],
 		Variables:          scope.variables,
+		VariableSlots:      scope.variableSlots,
 	}
 }
 
@@ -390,7 +403,7 @@ func (c *codegen) scAndVMTypeFromType(t types.Type, exts map[string]binding.Exte
 		var extName string
 		if isNamed {
 			over.Package = named.Obj().Pkg().Path()
-			over.TypeName = named.Obj().Pkg().Name() + "." + named.Obj().Name()
+			over.TypeName = named.Obj().Pkg().Name() + "." + genericTypeName(named)
 			_ = c.genStructExtended(t, over.TypeName, exts)
 			extName = over.TypeName
 		} else {
@@ -430,6 +443,23 @@ func (c *codegen) scAndVMTypeFromType(t types.Type, exts map[string]binding.Exte
 			over.TypeName = "[]" + over.TypeName
 		}
 		return smartcontract.ArrayType, stackitem.ArrayT, over, et
+	case *types.Array:
+		// Arrays are structs in fact, but they're passed to and returned from
+		// contract methods just like slices.
+		et := &binding.ExtendedType{
+			Base: smartcontract.ArrayType,
+		}
+		vt, _, over, vet := c.scAndVMTypeFromType(t.Elem(), exts)
+		et.Value = vet
+		if et.Value == nil {
+			et.Value = &binding.ExtendedType{
+				Base: vt,
+			}
+		}
+		if over.TypeName != "" {
+			over.TypeName = "[]" + over.TypeName
+		}
+		return smartcontract.ArrayType, stackitem.StructT, over, et
 	default:
 		over.TypeName = "any"
 		return smartcontract.AnyType, stackitem.AnyT, over, nil
@@ -643,3 +673,103 @@ func (di *DebugInfo) ConvertToManifest(o *Options) (*manifest.Manifest, error) {
 	}
 	return result, nil
 }
+
+// MethodByOffset returns the method containing the instruction with the given
+// offset or nil if there is no such method.
+func (di *DebugInfo) MethodByOffset(ip int) *MethodDebugInfo {
+	for i := range di.Methods {
+		if int(di.Methods[i].Range.Start) <= ip && ip <= int(di.Methods[i].Range.End) {
+			return &di.Methods[i]
+		}
+	}
+	return nil
+}
+
+// SeqPointByOffset returns the method and the sequence point of the statement
+// the instruction with the given offset belongs to. If the instruction precedes
+// all statements of the method, the first statement is returned. Sequence
+// point is nil if there are no statements in the method.
+func (di *DebugInfo) SeqPointByOffset(ip int) (*MethodDebugInfo, *DebugSeqPoint) {
+	m := di.MethodByOffset(ip)
+	if m == nil || len(m.SeqPoints) == 0 {
+		return m, nil
+	}
+	var cur, first *DebugSeqPoint
+	for i := range m.SeqPoints {
+		sp := &m.SeqPoints[i]
+		if sp.Opcode <= ip && (cur == nil || sp.Opcode > cur.Opcode) {
+			cur = sp
+		}
+		if first == nil || sp.Opcode < first.Opcode {
+			first = sp
+		}
+	}
+	if cur == nil {
+		cur = first
+	}
+	return m, cur
+}
+
+// DocumentIndex returns the index of the document matching the given file
+// name. Full paths and path suffixes (like base file names) are accepted as
+// long as they're unambiguous.
+func (di *DebugInfo) DocumentIndex(file string) (int, error) {
+	var (
+		res    = -1
+		clean  = filepath.Clean(file)
+		suffix = "/" + filepath.ToSlash(clean)
+	)
+	for i, doc := range di.Documents {
+		if filepath.Clean(doc) == clean {
+			return i, nil
+		}
+		if strings.HasSuffix(filepath.ToSlash(doc), suffix) {
+			if res != -1 {
+				return 0, fmt.Errorf("ambiguous file name %s: matches %s and %s", file, di.Documents[res], doc)
+			}
+			res = i
+		}
+	}
+	if res == -1 {
+		return 0, fmt.Errorf("file %s not found in debug info", file)
+	}
+	return res, nil
+}
+
+// LineOffset returns the offset of the first instruction of the statement
+// starting at the given line of the document with the given index (or at the
+// nearest subsequent line having code) along with the line used. False is
+// returned if there is no code at or below the given line.
+func (di *DebugInfo) LineOffset(doc int, line int) (int, int, bool) {
+	var best *DebugSeqPoint
+	for i := range di.Methods {
+		for j := range di.Methods[i].SeqPoints {
+			sp := &di.Methods[i].SeqPoints[j]
+			if sp.Document != doc || sp.StartLine < line {
+				continue
+			}
+			if best == nil || sp.StartLine < best.StartLine ||
+				(sp.StartLine == best.StartLine && sp.Opcode < best.Opcode) {
+				best = sp
+			}
+		}
+	}
+	if best == nil {
+		return 0, 0, false
+	}
+	return best.Opcode, best.StartLine, true
+}
+
+// VariableSlot returns the local slot index of the i-th variable of the method
+// or -1 if the variable doesn't have a slot. If VariableSlots are not available
+// (like for the debug info read from file), variables are assumed to occupy
+// slots sequentially.
+func (m *MethodDebugInfo) VariableSlot(i int) int {
+	if m.VariableSlots == nil {
+		return i
+	}
+	if i >= len(m.VariableSlots) {
+		return -1
+	}
+	return m.VariableSlots[i]
+}
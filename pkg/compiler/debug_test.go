@@ -106,6 +106,20 @@ func _deploy(data any, isUpdate bool) { x := 1; _ = x }
 		}
 	})
 
+	t.Run("variable slots", func(t *testing.T) {
+		slots := map[string][]int{
+			"Main":                {0, 1},
+			manifest.MethodInit:   {0, 0},
+			manifest.MethodDeploy: {0},
+		}
+		for i := range d.Methods {
+			v, ok := slots[d.Methods[i].ID]
+			if ok {
+				require.Equal(t, v, d.Methods[i].VariableSlots)
+			}
+		}
+	})
+
 	t.Run("static variables", func(t *testing.T) {
 		require.Equal(t, []string{"staticVar,Integer"}, d.StaticVariables)
 	})
@@ -412,3 +426,80 @@ func TestManifestOverload(t *testing.T) {
 		require.Error(t, err)
 	})
 }
+
+func TestDebugInfo_Lookup(t *testing.T) {
+	di := &DebugInfo{
+		Documents: []string{"/path/to/a/main.go", "/path/to/b/main.go", "/path/to/util.go"},
+		Methods: []MethodDebugInfo{
+			{
+				Range: DebugRange{Start: 0, End: 9},
+				SeqPoints: []DebugSeqPoint{
+					{Opcode: 2, Document: 0, StartLine: 5},
+					{Opcode: 6, Document: 0, StartLine: 7},
+				},
+				Variables:     []string{"a,Integer", "b,Integer"},
+				VariableSlots: []int{0, -1},
+			},
+			{
+				Range: DebugRange{Start: 10, End: 15},
+				SeqPoints: []DebugSeqPoint{
+					{Opcode: 12, Document: 2, StartLine: 3},
+				},
+				Variables: []string{"c,Integer"},
+			},
+		},
+	}
+
+	t.Run("MethodByOffset", func(t *testing.T) {
+		require.Equal(t, &di.Methods[0], di.MethodByOffset(9))
+		require.Equal(t, &di.Methods[1], di.MethodByOffset(10))
+		require.Nil(t, di.MethodByOffset(16))
+	})
+	t.Run("SeqPointByOffset", func(t *testing.T) {
+		m, sp := di.SeqPointByOffset(0)
+		require.Equal(t, &di.Methods[0], m)
+		require.Equal(t, &di.Methods[0].SeqPoints[0], sp)
+		_, sp = di.SeqPointByOffset(5)
+		require.Equal(t, &di.Methods[0].SeqPoints[0], sp)
+		_, sp = di.SeqPointByOffset(6)
+		require.Equal(t, &di.Methods[0].SeqPoints[1], sp)
+		m, sp = di.SeqPointByOffset(100)
+		require.Nil(t, m)
+		require.Nil(t, sp)
+	})
+	t.Run("DocumentIndex", func(t *testing.T) {
+		i, err := di.DocumentIndex("/path/to/b/main.go")
+		require.NoError(t, err)
+		require.Equal(t, 1, i)
+		i, err = di.DocumentIndex("a/main.go")
+		require.NoError(t, err)
+		require.Equal(t, 0, i)
+		i, err = di.DocumentIndex("util.go")
+		require.NoError(t, err)
+		require.Equal(t, 2, i)
+		_, err = di.DocumentIndex("main.go")
+		require.ErrorContains(t, err, "ambiguous")
+		_, err = di.DocumentIndex("il.go")
+		require.ErrorContains(t, err, "not found")
+	})
+	t.Run("LineOffset", func(t *testing.T) {
+		off, line, ok := di.LineOffset(0, 5)
+		require.True(t, ok)
+		require.Equal(t, 2, off)
+		require.Equal(t, 5, line)
+		off, line, ok = di.LineOffset(0, 6)
+		require.True(t, ok)
+		require.Equal(t, 6, off)
+		require.Equal(t, 7, line)
+		_, _, ok = di.LineOffset(0, 8)
+		require.False(t, ok)
+		_, _, ok = di.LineOffset(1, 1)
+		require.False(t, ok)
+	})
+	t.Run("VariableSlot", func(t *testing.T) {
+		require.Equal(t, 0, di.Methods[0].VariableSlot(0))
+		require.Equal(t, -1, di.Methods[0].VariableSlot(1))
+		require.Equal(t, -1, di.Methods[0].VariableSlot(2))
+		require.Equal(t, 0, di.Methods[1].VariableSlot(0))
+	})
+}
@@ -740,6 +740,80 @@ var forLoopTestCases = []testCase{
 		`,
 		big.NewInt(10),
 	},
+	{
+		"range over int",
+		`func F%d() int {
+			s := 0
+			for i := range 5 {
+				s += i
+			}
+			return s
+		}
+		`,
+		big.NewInt(10),
+	},
+	{
+		"range over int variable, no key",
+		`func F%d() int {
+			n, s := 3, 0
+			for range n {
+				s++
+				n = 10
+			}
+			return s
+		}
+		`,
+		big.NewInt(3),
+	},
+	{
+		"range over int, change variable",
+		`func F%d() int {
+			var (
+				s int
+				i int64
+			)
+			for i = range int64(4) {
+				s += int(i)
+				i = 10
+			}
+			return s + int(i)
+		}
+		`,
+		big.NewInt(16),
+	},
+	{
+		"range over int, break and continue",
+		`func F%d() int {
+			s := 0
+		outer:
+			for i := range 10 {
+				for j := range i {
+					if j == 1 {
+						continue outer
+					}
+				}
+				if i == 5 {
+					break
+				}
+				s += i
+			}
+			return s
+		}
+		`,
+		big.NewInt(1),
+	},
+	{
+		"range over negative int",
+		`func F%d() int {
+			s := 0
+			for range -1 {
+				s++
+			}
+			return s
+		}
+		`,
+		big.NewInt(0),
+	},
 }
 
 func TestForLoop(t *testing.T) {
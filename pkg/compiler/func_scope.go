@@ -31,6 +31,8 @@ type funcScope struct {
 	rng DebugRange
 	// Variables together with it's type in neo-vm.
 	variables []string
+	// variableSlots contains local slot indexes of variables.
+	variableSlots []int
 
 	// deferStack is a stack containing encountered `defer` statements.
 	deferStack []deferInfo
@@ -48,6 +50,9 @@ type funcScope struct {
 
 	// Local variable counter.
 	i int
+
+	// typeArgs contains type arguments of the generic function instance.
+	typeArgs typeArgMap
 }
 
 type deferInfo struct {
@@ -65,7 +70,7 @@ const (
 func (c *codegen) newFuncScope(decl *ast.FuncDecl, label uint16) *funcScope {
 	var name string
 	if decl.Name != nil {
-		name = decl.Name.Name
+		name = decl.Name.Name + shortInstanceSuffix(c.declTypeArgs(decl))
 	}
 	return &funcScope{
 		name:      name,
@@ -76,6 +81,7 @@ func (c *codegen) newFuncScope(decl *ast.FuncDecl, label uint16) *funcScope {
 		voidCalls: map[*ast.CallExpr]bool{},
 		variables: []string{},
 		i:         -1,
+		typeArgs:  c.typeArgs,
 	}
 }
 
@@ -92,6 +98,9 @@ func (c *codegen) getFuncNameFromDecl(pkgPath string, decl *ast.FuncDecl) string
 			case *ast.IndexExpr:
 				// Generic func declaration receiver: func (x *Pointer[T]) Load() *T
 				name = t.X.(*ast.IndexExpr).X.(*ast.Ident).Name + "." + name
+			case *ast.IndexListExpr:
+				// Generic func declaration receiver: func (x *Pair[K, V]) Load() *V
+				name = t.X.(*ast.IndexListExpr).X.(*ast.Ident).Name + "." + name
 			default:
 				panic(fmt.Errorf("unexpected function `%s` receiver type: %T", name, t.X))
 			}
@@ -103,9 +112,17 @@ func (c *codegen) getFuncNameFromDecl(pkgPath string, decl *ast.FuncDecl) string
 			default:
 				panic(fmt.Errorf("unexpected function `%s` receiver type: %T", name, t.X))
 			}
+		case *ast.IndexListExpr:
+			switch t.X.(type) {
+			case *ast.Ident:
+				// Generic func declaration receiver: func (x Pair[K, V]) Load() *V
+				name = t.X.(*ast.Ident).Name + "." + name
+			default:
+				panic(fmt.Errorf("unexpected function `%s` receiver type: %T", name, t.X))
+			}
 		}
 	}
-	return c.getIdentName(pkgPath, name)
+	return c.getIdentName(pkgPath, name) + instanceSuffix(c.declTypeArgs(decl))
 }
 
 // analyzeVoidCalls checks for functions that are not assigned
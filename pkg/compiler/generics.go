@@ -0,0 +1,338 @@
+package compiler
+
+import (
+	"go/ast"
+	"go/types"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Generic functions and methods of generic types are compiled via
+// monomorphization: every instance used by the program is compiled as a
+// separate function with type parameters replaced by type arguments of this
+// instance. Instances are named after the generic function with type arguments
+// appended, e.g. `pkg.Sum[int]` or `pkg.List.Push[string]`.
+
+// typeArgMap maps type parameters of a generic function to type arguments of
+// its instance.
+type typeArgMap map[*types.TypeParam]types.Type
+
+// typeParamsOf returns type parameters of the given function declaration, it's
+// either a list of function type parameters or a list of type parameters of the
+// method receiver. Nil is returned for non-generic functions and for
+// declarations that are not known to the type checker.
+func typeParamsOf(decl *ast.FuncDecl, info *types.Info) *types.TypeParamList {
+	if decl.Name == nil {
+		return nil
+	}
+	fn, ok := info.Defs[decl.Name].(*types.Func)
+	if !ok {
+		return nil
+	}
+	sig := fn.Type().(*types.Signature)
+	if sig.RecvTypeParams().Len() != 0 {
+		return sig.RecvTypeParams()
+	}
+	return sig.TypeParams()
+}
+
+// isGenericFunc returns true if the given function declaration has type
+// parameters (either its own or its receiver's).
+func isGenericFunc(decl *ast.FuncDecl) bool {
+	if decl.Type.TypeParams != nil {
+		return true
+	}
+	if decl.Recv != nil {
+		t := decl.Recv.List[0].Type
+		if s, ok := t.(*ast.StarExpr); ok {
+			t = s.X
+		}
+		switch t.(type) {
+		case *ast.IndexExpr, *ast.IndexListExpr:
+			return true
+		}
+	}
+	return false
+}
+
+// newTypeArgMap creates type parameters mapping for the instance of the given
+// generic function with the given type arguments.
+func newTypeArgMap(decl *ast.FuncDecl, info *types.Info, targs []types.Type) typeArgMap {
+	tps := typeParamsOf(decl, info)
+	if tps.Len() != len(targs) {
+		return nil
+	}
+	m := make(typeArgMap, len(targs))
+	for i := range targs {
+		m[tps.At(i)] = targs[i]
+	}
+	return m
+}
+
+// instanceSuffix returns the suffix that is appended to the generic function
+// name to get the name of its instance with the given type arguments.
+func instanceSuffix(targs []types.Type) string {
+	if len(targs) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteByte('[')
+	for i, t := range targs {
+		if i != 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(types.TypeString(t, nil))
+	}
+	b.WriteByte(']')
+	return b.String()
+}
+
+// shortInstanceSuffix is the same as instanceSuffix, but it qualifies types
+// by package names instead of package paths, it's used for debug info.
+func shortInstanceSuffix(targs []types.Type) string {
+	if len(targs) == 0 {
+		return ""
+	}
+	names := make([]string, len(targs))
+	for i, t := range targs {
+		names[i] = types.TypeString(t, func(p *types.Package) string { return p.Name() })
+	}
+	return "[" + strings.Join(names, ",") + "]"
+}
+
+// declTypeArgs returns type arguments of the current instance of the given
+// function declaration in the order of its type parameters. Nil is returned
+// if the function is not generic or no instance is being processed.
+func (c *codegen) declTypeArgs(decl *ast.FuncDecl) []types.Type {
+	if c.typeArgs == nil {
+		return nil
+	}
+	tps := typeParamsOf(decl, c.typeInfo)
+	if tps.Len() == 0 {
+		return nil
+	}
+	targs := make([]types.Type, tps.Len())
+	for i := range targs {
+		targs[i] = c.typeArgs[tps.At(i)]
+	}
+	return targs
+}
+
+// instanceOf returns type arguments of the generic function instance denoted
+// by the given identifier (with type parameters of the current instance
+// substituted). Nil is returned if the identifier doesn't denote a generic
+// function instance.
+func (c *codegen) instanceOf(id *ast.Ident) []types.Type {
+	var (
+		inst types.Instance
+		ok   bool
+	)
+	for i := len(c.pkgInfoInline) - 1; i >= 0 && !ok; i-- {
+		inst, ok = c.pkgInfoInline[i].TypesInfo.Instances[id]
+	}
+	if !ok {
+		inst, ok = c.typeInfo.Instances[id]
+	}
+	if !ok {
+		return nil
+	}
+	if _, isSig := inst.Type.(*types.Signature); !isSig {
+		return nil // Generic type instance.
+	}
+	return c.substList(inst.TypeArgs)
+}
+
+// recvTypeArgs returns type arguments of the generic type the given method
+// selector is called on. Nil is returned for non-generic receivers.
+func (c *codegen) recvTypeArgs(e *ast.SelectorExpr) []types.Type {
+	named, ok := derefNamed(c.subst(c.typeInfo.Types[e.X].Type))
+	if !ok || named.TypeArgs().Len() == 0 {
+		return nil
+	}
+	return c.substList(named.TypeArgs())
+}
+
+// unwrapInstance returns the function expression without explicit type
+// arguments, e.g. `Sum` for `Sum[int]`.
+func (c *codegen) unwrapInstance(fun ast.Expr) ast.Expr {
+	var x ast.Expr
+	switch t := fun.(type) {
+	case *ast.IndexExpr:
+		x = t.X
+	case *ast.IndexListExpr:
+		x = t.X
+	default:
+		return fun
+	}
+	var id *ast.Ident
+	switch t := x.(type) {
+	case *ast.Ident:
+		id = t
+	case *ast.SelectorExpr:
+		id = t.Sel
+	default:
+		return fun
+	}
+	if c.instanceOf(id) == nil {
+		return fun // Index expression, e.g. function stored in a slice.
+	}
+	return x
+}
+
+// subst replaces type parameters of the current generic function instance
+// in the given type with type arguments.
+func (c *codegen) subst(t types.Type) types.Type {
+	if t == nil || len(c.typeArgs) == 0 {
+		return t
+	}
+	if c.typeCtx == nil {
+		c.typeCtx = types.NewContext()
+	}
+	return substType(c.typeCtx, t, c.typeArgs)
+}
+
+func (c *codegen) substList(l *types.TypeList) []types.Type {
+	res := make([]types.Type, l.Len())
+	for i := range res {
+		res[i] = c.subst(l.At(i))
+	}
+	return res
+}
+
+// substType replaces type parameters in the given type according to the
+// given mapping. Types that don't refer to type parameters are returned as is.
+func substType(ctx *types.Context, t types.Type, m typeArgMap) types.Type {
+	switch t := t.(type) {
+	case *types.TypeParam:
+		if r, ok := m[t]; ok {
+			return r
+		}
+	case *types.Alias:
+		return substType(ctx, types.Unalias(t), m)
+	case *types.Pointer:
+		if e := substType(ctx, t.Elem(), m); e != t.Elem() {
+			return types.NewPointer(e)
+		}
+	case *types.Slice:
+		if e := substType(ctx, t.Elem(), m); e != t.Elem() {
+			return types.NewSlice(e)
+		}
+	case *types.Array:
+		if e := substType(ctx, t.Elem(), m); e != t.Elem() {
+			return types.NewArray(e, t.Len())
+		}
+	case *types.Map:
+		k, v := substType(ctx, t.Key(), m), substType(ctx, t.Elem(), m)
+		if k != t.Key() || v != t.Elem() {
+			return types.NewMap(k, v)
+		}
+	case *types.Chan:
+		if e := substType(ctx, t.Elem(), m); e != t.Elem() {
+			return types.NewChan(t.Dir(), e)
+		}
+	case *types.Struct:
+		var (
+			changed bool
+			fields  = make([]*types.Var, t.NumFields())
+			tags    = make([]string, t.NumFields())
+		)
+		for i := range fields {
+			f := t.Field(i)
+			ft := substType(ctx, f.Type(), m)
+			if ft != f.Type() {
+				changed = true
+				f = types.NewField(f.Pos(), f.Pkg(), f.Name(), ft, f.Embedded())
+			}
+			fields[i] = f
+			tags[i] = t.Tag(i)
+		}
+		if changed {
+			return types.NewStruct(fields, tags)
+		}
+	case *types.Tuple:
+		if r, changed := substTuple(ctx, t, m); changed {
+			return r
+		}
+	case *types.Signature:
+		params, pChanged := substTuple(ctx, t.Params(), m)
+		results, rChanged := substTuple(ctx, t.Results(), m)
+		if pChanged || rChanged {
+			return types.NewSignatureType(t.Recv(), nil, nil, params, results, t.Variadic())
+		}
+	case *types.Named:
+		targs := t.TypeArgs()
+		if targs.Len() == 0 {
+			break
+		}
+		var (
+			changed bool
+			args    = make([]types.Type, targs.Len())
+		)
+		for i := range args {
+			args[i] = substType(ctx, targs.At(i), m)
+			changed = changed || args[i] != targs.At(i)
+		}
+		if changed {
+			r, err := types.Instantiate(ctx, t.Origin(), args, false)
+			if err == nil {
+				return r
+			}
+		}
+	}
+	return t
+}
+
+func substTuple(ctx *types.Context, t *types.Tuple, m typeArgMap) (*types.Tuple, bool) {
+	if t == nil {
+		return nil, false
+	}
+	var (
+		changed bool
+		vars    = make([]*types.Var, t.Len())
+	)
+	for i := range vars {
+		v := t.At(i)
+		vt := substType(ctx, v.Type(), m)
+		if vt != v.Type() {
+			changed = true
+			v = types.NewParam(v.Pos(), v.Pkg(), v.Name(), vt)
+		}
+		vars[i] = v
+	}
+	if !changed {
+		return t, false
+	}
+	return types.NewTuple(vars...), true
+}
+
+// derefNamed returns named type (possibly under the pointer).
+func derefNamed(t types.Type) (*types.Named, bool) {
+	if p, ok := t.(*types.Pointer); ok {
+		t = p.Elem()
+	}
+	named, ok := types.Unalias(t).(*types.Named)
+	return named, ok
+}
+
+// genericTypeName returns the name of the generic type instance that can be
+// used as a part of Go identifier, e.g. `ListInt` for `List[int]`.
+func genericTypeName(named *types.Named) string {
+	var b strings.Builder
+	b.WriteString(named.Obj().Name())
+	for i := range named.TypeArgs().Len() {
+		s := types.TypeString(named.TypeArgs().At(i), func(*types.Package) string { return "" })
+		s = strings.Map(func(r rune) rune {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				return r
+			}
+			return -1
+		}, s)
+		if s != "" {
+			r, n := utf8.DecodeRuneInString(s)
+			b.WriteRune(unicode.ToUpper(r))
+			b.WriteString(s[n:])
+		}
+	}
+	return b.String()
+}
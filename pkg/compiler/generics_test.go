@@ -1,91 +1,325 @@
 package compiler_test
 
 import (
+	"math/big"
 	"strings"
 	"testing"
 
 	"github.com/nspcc-dev/neo-go/pkg/compiler"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
 	"github.com/stretchr/testify/require"
 )
 
-func TestGenericMethodReceiver(t *testing.T) {
-	t.Run("star expression", func(t *testing.T) {
-		src := `
-		package receiver
-		type Pointer[T any] struct {
-			value T
-		}
-		func (x *Pointer[T]) Load() *T {
-			return &x.value
-		}
-`
-		_, _, err := compiler.CompileWithOptions("foo.go", strings.NewReader(src), nil)
-		require.ErrorIs(t, err, compiler.ErrGenericsUnsuppored)
-	})
-	t.Run("ident expression", func(t *testing.T) {
-		src := `
-		package receiver
-		type Pointer[T any] struct {
-			value T
-		}
-		func (x Pointer[T]) Load() *T {
-			return &x.value
-		}
-`
-		_, _, err := compiler.CompileWithOptions("foo.go", strings.NewReader(src), nil)
-		require.ErrorIs(t, err, compiler.ErrGenericsUnsuppored)
-	})
-}
-
-func TestGenericFuncArgument(t *testing.T) {
-	src := `
-		package sum
-		func SumInts[V int64 | int32 | int16](vals []V) V { // doesn't make sense with NeoVM, but still it's a valid go code.
+var genericsTestCases = []testCase{
+	{
+		"generic function",
+		`package foo
+		func Main() int {
+			return Max(1, 7) + Max[int](5, 3)
+		}
+		func Max[T int | int64](a, b T) T {
+			if a > b {
+				return a
+			}
+			return b
+		}`,
+		big.NewInt(12),
+	},
+	{
+		"type union with ~",
+		`package foo
+		type Number interface {
+			~int | ~int64 | ~uint8
+		}
+		type Amount int
+		func Sum[V Number](vals []V) V {
 			var s V
 			for i := range vals {
 				s += vals[i]
 			}
 			return s
 		}
-`
-	_, _, err := compiler.CompileWithOptions("foo.go", strings.NewReader(src), nil)
-	require.ErrorIs(t, err, compiler.ErrGenericsUnsuppored)
-}
-
-func TestGenericTypeDecl(t *testing.T) {
-	t.Run("global scope", func(t *testing.T) {
-		src := `
-		package sum
+		func Main() int {
+			return int(Sum([]Amount{1, 2, 3})) + int(Sum([]int64{10, 20}))
+		}`,
+		big.NewInt(36),
+	},
+	{
+		"comparable",
+		`package foo
+		func Index[T comparable](s []T, v T) int {
+			for i := range s {
+				if s[i] == v {
+					return i
+				}
+			}
+			return -1
+		}
+		func Main() int {
+			return Index([]string{"a", "b", "c"}, "c")*100 + Index([]int{1, 2, 3}, 2)*10 + Index([]bool{true}, false)
+		}`,
+		big.NewInt(209),
+	},
+	{
+		"different instances",
+		`package foo
+		func Concat[T ~string | ~int](a, b T) T {
+			return a + b
+		}
+		func Main() string {
+			if Concat(1, 2) != 3 {
+				panic("bad int")
+			}
+			return Concat("a", "b")
+		}`,
+		[]byte("ab"),
+	},
+	{
+		"default value of type parameter",
+		`package foo
+		type S struct { A int }
+		func Zero[T any]() T {
+			var z T
+			return z
+		}
+		func Main() int {
+			if Zero[string]() != "" || Zero[bool]() {
+				panic("bad zero")
+			}
+			return Zero[int]() + Zero[S]().A
+		}`,
+		big.NewInt(0),
+	},
+	{
+		"multiple type parameters",
+		`package foo
+		func Keys[K comparable, V any](m map[K]V) []K {
+			var res []K
+			for k := range m {
+				res = append(res, k)
+			}
+			return res
+		}
+		func Main() int {
+			return len(Keys(map[string]int{"a": 1, "b": 2})) + len(Keys(map[int]bool{1: true}))
+		}`,
+		big.NewInt(3),
+	},
+	{
+		"nested generic calls",
+		`package foo
+		func Double[T ~int](v T) T {
+			return Add(v, v)
+		}
+		func Add[T ~int](a, b T) T {
+			return a + b
+		}
+		func Main() int {
+			return Double(21)
+		}`,
+		big.NewInt(42),
+	},
+	{
+		"conversion to type parameter",
+		`package foo
+		func Conv[T ~string | ~int](v int) T {
+			return T(v)
+		}
+		func Main() int {
+			return Conv[int](42)
+		}`,
+		big.NewInt(42),
+	},
+	{
+		"generic struct",
+		`package foo
+		type Pair[K comparable, V any] struct {
+			Key K
+			Val V
+		}
+		func Main() int {
+			p := Pair[string, int]{Key: "a", Val: 42}
+			var q Pair[int, bool]
+			if q.Key != 0 || q.Val || p.Key != "a" {
+				panic("bad pair")
+			}
+			return p.Val
+		}`,
+		big.NewInt(42),
+	},
+	{
+		"generic type methods",
+		`package foo
+		type Stack[T any] struct {
+			items []T
+		}
+		func (s *Stack[T]) Push(v T) {
+			s.items = append(s.items, v)
+		}
+		func (s *Stack[T]) Top() T {
+			return s.items[len(s.items)-1]
+		}
+		func (s Stack[T]) Len() int {
+			return len(s.items)
+		}
+		func Main() int {
+			s := &Stack[int]{}
+			s.Push(1)
+			s.Push(41)
+			ss := &Stack[string]{}
+			ss.Push("x")
+			return s.Top() + s.Len() + ss.Len()*10
+		}`,
+		big.NewInt(53),
+	},
+	{
+		"recursive generic type",
+		`package foo
 		type List[T any] struct {
 			next *List[T]
 			val  T
 		}
+		func (l *List[T]) Prepend(v T) *List[T] {
+			return &List[T]{next: l, val: v}
+		}
+		func (l *List[T]) Len() int {
+			if l == nil {
+				return 0
+			}
+			return 1 + l.next.Len()
+		}
+		func Main() int {
+			var l *List[int]
+			l = l.Prepend(1).Prepend(2).Prepend(3)
+			return l.Len()*10 + l.val
+		}`,
+		big.NewInt(33),
+	},
+	{
+		"generic function with generic type",
+		`package foo
+		type Box[T any] struct {
+			v T
+		}
+		func NewBox[T any](v T) Box[T] {
+			return Box[T]{v: v}
+		}
+		func (b Box[T]) Get() T {
+			return b.v
+		}
+		func Map[T, U any](b Box[T], f func(T) U) Box[U] {
+			return NewBox(f(b.Get()))
+		}
+		func Main() int {
+			b := Map(NewBox("abc"), func(s string) int { return len(s) })
+			return b.Get()
+		}`,
+		big.NewInt(3),
+	},
+	{
+		"constraint method",
+		`package foo
+		type Stringer interface {
+			String() string
+		}
+		type T1 struct{}
+		func (T1) String() string { return "t1" }
+		type T2 int
+		func (t T2) String() string { return "t2" }
+		func Join[S Stringer](ss []S) string {
+			var res string
+			for _, s := range ss {
+				res += s.String()
+			}
+			return res
+		}
+		func Main() string {
+			return Join([]T1{{}, {}}) + Join([]T2{1})
+		}`,
+		[]byte("t1t1t2"),
+	},
+	{
+		"struct value semantics",
+		`package foo
+		type S struct { A int }
+		func Set[T any](p T, f func(T)) {
+			f(p)
+		}
+		func Main() int {
+			s := S{A: 1}
+			Set(s, func(s S) { s.A = 2 })
+			return s.A
+		}`,
+		big.NewInt(1),
+	},
+}
 
-		func Main() any {
-			l := List[int]{}
-			return l
-		}
-`
-		_, _, err := compiler.CompileWithOptions("foo.go", strings.NewReader(src), nil)
-		require.ErrorIs(t, err, compiler.ErrGenericsUnsuppored)
-	})
-	t.Run("local scope", func(t *testing.T) {
-		src := `
-		package sum
-		func Main() any {
-			type (
-				SomeGoodType int
-			
-				List[T any] struct {
-					next *List[T]
-					val  T
-				}
-			)
-			l := List[int]{}
-			return l
-		}
-`
-		_, _, err := compiler.CompileWithOptions("foo.go", strings.NewReader(src), nil)
-		require.ErrorIs(t, err, compiler.ErrGenericsUnsuppored)
-	})
+func TestGenerics(t *testing.T) {
+	runTestCases(t, genericsTestCases)
+}
+
+func TestGenericsImported(t *testing.T) {
+	src := `package foo
+	import "github.com/nspcc-dev/neo-go/pkg/compiler/testdata/generic"
+	func Main() int {
+		s := generic.NewSet[string]()
+		s.Add("a")
+		s.Add("b")
+		s.Add("a")
+		return generic.Sum([]int{1, 2, 3}) + s.Len()*10
+	}`
+	eval(t, src, big.NewInt(26))
+}
+
+func TestGenericFuncValue(t *testing.T) {
+	src := `package foo
+	func Add[T ~int](a, b T) T { return a + b }
+	func Main() int {
+		f := Add[int]
+		return f(1, 2)
+	}`
+	_, _, err := compiler.CompileWithOptions("foo.go", strings.NewReader(src), nil)
+	require.ErrorIs(t, err, compiler.ErrGenericsUnsuppored)
+}
+
+func TestGenericsDebugInfo(t *testing.T) {
+	src := `package foo
+	type Box[T any] struct {
+		V T
+	}
+	func (b Box[T]) Value() T { return b.V }
+	func Get[T any](b Box[T]) T { return b.Value() }
+	func Unused[T any](v T) T { return v }
+	func Main() int {
+		return Get(Box[int]{V: 1})
+	}
+	func Str(b Box[string]) string {
+		return Get(b)
+	}`
+	_, di, err := compiler.CompileWithOptions("foo.go", strings.NewReader(src), nil)
+	require.NoError(t, err)
+
+	methods := make(map[string]bool)
+	for _, m := range di.Methods {
+		methods[m.ID] = m.IsExported
+	}
+	require.Equal(t, map[string]bool{
+		"Main":          true,
+		"Str":           true,
+		"Get[int]":      false,
+		"Get[string]":   false,
+		"Value[int]":    false,
+		"Value[string]": false,
+	}, methods)
+
+	m, err := di.ConvertToManifest(&compiler.Options{Name: "Foo"})
+	require.NoError(t, err)
+	require.Len(t, m.ABI.Methods, 2)
+	require.NotNil(t, m.ABI.GetMethod("main", 0))
+	str := m.ABI.GetMethod("str", 1)
+	require.NotNil(t, str)
+	require.Equal(t, smartcontract.ArrayType, str.Parameters[0].Type)
+
+	require.Contains(t, di.NamedTypes, "foo.BoxString")
+	require.Equal(t, smartcontract.StringType, di.NamedTypes["foo.BoxString"].Fields[0].Base)
 }
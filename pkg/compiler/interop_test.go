@@ -799,3 +799,94 @@ func Main() int {
 
 	c.Invoke(t, big.NewInt(3), "main")
 }
+
+func TestStorageIteratorRange(t *testing.T) {
+	bc, acc := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+
+	src := `package foo
+
+import (
+	"github.com/nspcc-dev/neo-go/pkg/interop/iterator"
+	"github.com/nspcc-dev/neo-go/pkg/interop/storage"
+)
+
+func init() {
+	ctx := storage.GetContext()
+	storage.Put(ctx, "a1", 1)
+	storage.Put(ctx, "a2", 2)
+	storage.Put(ctx, "a3", 3)
+	storage.Put(ctx, "b", 10)
+}
+
+func Values() int {
+	sum := 0
+	for v := range iterator.Values[int](storage.Find(storage.GetReadOnlyContext(), "a", storage.ValuesOnly)) {
+		sum += v
+	}
+	return sum
+}
+
+func Pairs() []string {
+	var res []string
+	for k, v := range iterator.Pairs[string, int](storage.Find(storage.GetReadOnlyContext(), "", storage.None)) {
+		if v == 2 {
+			continue
+		}
+		res = append(res, k)
+		if v == 3 {
+			break
+		}
+	}
+	return res
+}
+
+func KeyValues() int {
+	sum := 0
+	for kv := range iterator.Values[storage.KeyValue](storage.Find(storage.GetReadOnlyContext(), "a", storage.None)) {
+		sum += len(kv.Key)
+	}
+	return sum
+}
+
+func all(prefix string) iterator.Seq2[[]byte, int] {
+	return iterator.Pairs[[]byte, int](storage.Find(storage.GetReadOnlyContext(), prefix, storage.RemovePrefix))
+}
+
+func Keys() []byte {
+	var res []byte
+	for k := range all("a") {
+		res = append(res, k...)
+	}
+	for _, v := range all("b") {
+		res = append(res, byte(v))
+	}
+	for range all("") {
+		res = append(res, 0)
+	}
+	return res
+}
+`
+	ctr := neotest.CompileSource(t, e.CommitteeHash, strings.NewReader(src), &compiler.Options{Name: "SeqIter"})
+	e.DeployContract(t, ctr, nil)
+	c := e.CommitteeInvoker(ctr.Hash)
+
+	c.Invoke(t, 6, "values")
+	c.Invoke(t, stackitem.NewArray([]stackitem.Item{stackitem.Make("a1"), stackitem.Make("a3")}), "pairs")
+	c.Invoke(t, 6, "keyValues")
+	c.Invoke(t, stackitem.NewBuffer([]byte("123\x0a\x00\x00\x00\x00")), "keys")
+}
+
+func TestRangeOverFunc(t *testing.T) {
+	src := `package foo
+	func seq(yield func(int) bool) {}
+	func Main() int {
+		s := 0
+		for v := range seq {
+			s += v
+		}
+		return s
+	}`
+	_, err := compiler.Compile("foo.go", strings.NewReader(src))
+	require.ErrorContains(t, err, "only iterator.Seq and iterator.Seq2 sequences can be used in range-over-func loops")
+}
@@ -0,0 +1,482 @@
+package compiler
+
+import (
+	"cmp"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/interop/interopnames"
+	"github.com/nspcc-dev/neo-go/pkg/core/native/nativehashes"
+	"golang.org/x/tools/go/packages"
+)
+
+// Identifiers of the rules checked by Lint.
+const (
+	// LintMissingWitness is reported for public methods modifying the
+	// storage without prior authorization check.
+	LintMissingWitness = "missing-witness"
+	// LintUncheckedCall is reported for contract calls with ignored result.
+	LintUncheckedCall = "unchecked-call"
+	// LintReentrancy is reported for storage modifications following
+	// token transfers.
+	LintReentrancy = "reentrancy"
+	// LintKeyCollision is reported for storage key prefixes used by
+	// different packages when one of them is a prefix of another.
+	LintKeyCollision = "storage-key-collision"
+	// LintUnprotectedUpdate is reported for public methods updating or
+	// destroying the contract without authorization check.
+	LintUnprotectedUpdate = "unprotected-update"
+)
+
+// LintRule describes the class of issues reported by Lint.
+type LintRule struct {
+	// ID is a unique rule identifier.
+	ID string
+	// Description is a short human-readable description of the rule.
+	Description string
+}
+
+// LintRules contains all rules checked by Lint.
+var LintRules = []LintRule{
+	{LintMissingWitness, "Public method modifies the storage without runtime.CheckWitness check"},
+	{LintUncheckedCall, "Result of the contract call or token transfer is not checked"},
+	{LintReentrancy, "Storage is modified after the token transfer, the callee can re-enter the contract"},
+	{LintKeyCollision, "Storage key prefixes used by different packages overlap"},
+	{LintUnprotectedUpdate, "Contract can be updated or destroyed without authorization check"},
+}
+
+// LintIssue is a potential problem found in the contract.
+type LintIssue struct {
+	// Rule is the identifier of the rule that reported the issue.
+	Rule string
+	// Message describes the issue.
+	Message string
+	// Pos is the position of the issue in the source code.
+	Pos token.Position
+}
+
+// lintEventKind is the kind of the contract action relevant for the linter.
+type lintEventKind byte
+
+const (
+	// lintAuth is a witness or the calling contract check.
+	lintAuth lintEventKind = iota
+	// lintWrite is a storage modification.
+	lintWrite
+	// lintCall is an external contract call.
+	lintCall
+	// lintManage is a contract update or destruction.
+	lintManage
+)
+
+// lintEvent is a contract action performed by the function.
+type lintEvent struct {
+	kind lintEventKind
+	// name is the called method name for lintCall and lintManage events.
+	name string
+	pos  token.Pos
+}
+
+// lintDecl is a function declaration together with its package type
+// information.
+type lintDecl struct {
+	decl *ast.FuncDecl
+	info *types.Info
+}
+
+// keyUse is a constant storage key prefix used in some package.
+type keyUse struct {
+	pkg    string
+	prefix string
+	pos    token.Position
+}
+
+type linter struct {
+	c     *codegen
+	fset  *token.FileSet
+	di    *DebugInfo
+	decls map[*types.Func]lintDecl
+	// events contains actions performed by the function, it has nil
+	// value for functions being processed to break recursion.
+	events map[*types.Func][]lintEvent
+	issues []LintIssue
+}
+
+// Lint compiles the program (see CompileWithOptions for the meaning of
+// parameters) and checks it for common contract security issues. Public
+// methods are taken from the compiled contract ABI, the order of actions
+// they perform is analyzed using type-checked source code of the contract
+// and all of its dependencies including interop packages. Issues are
+// returned sorted by their position, the list is empty if nothing
+// suspicious is found. Lint is heuristic, it can produce false positives and
+// miss actual problems.
+func Lint(name string, r io.Reader, o *Options) ([]LintIssue, error) {
+	ctx, err := getBuildInfo(name, r)
+	if err != nil {
+		return nil, err
+	}
+	ctx.options = o
+	c, buf, err := generate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	l := &linter{
+		c:      c,
+		fset:   ctx.config.Fset,
+		di:     c.emitDebugInfo(buf),
+		decls:  make(map[*types.Func]lintDecl),
+		events: make(map[*types.Func][]lintEvent),
+	}
+	for _, pkgPath := range c.packages {
+		pkg := c.packageCache[pkgPath]
+		for _, f := range pkg.Syntax {
+			for _, d := range f.Decls {
+				if fd, ok := d.(*ast.FuncDecl); ok {
+					if fn, ok := pkg.TypesInfo.Defs[fd.Name].(*types.Func); ok {
+						l.decls[fn] = lintDecl{decl: fd, info: pkg.TypesInfo}
+					}
+				}
+			}
+		}
+	}
+	l.checkMethods()
+	l.checkCalls()
+	l.checkKeys()
+	slices.SortStableFunc(l.issues, func(a, b LintIssue) int {
+		return cmp.Or(cmp.Compare(a.Pos.Filename, b.Pos.Filename),
+			cmp.Compare(a.Pos.Line, b.Pos.Line),
+			cmp.Compare(a.Pos.Column, b.Pos.Column),
+			cmp.Compare(a.Rule, b.Rule))
+	})
+	return l.issues, nil
+}
+
+func (l *linter) report(rule string, pos token.Position, format string, args ...any) {
+	l.issues = append(l.issues, LintIssue{
+		Rule:    rule,
+		Message: fmt.Sprintf(format, args...),
+		Pos:     pos,
+	})
+}
+
+// checkMethods checks the order of actions performed by public contract
+// methods. Methods starting with an underscore can't be called by other
+// contracts, so they're not checked.
+func (l *linter) checkMethods() {
+	scope := l.c.mainPkg.Types.Scope()
+	for _, m := range l.di.Methods {
+		if !m.IsExported || !m.IsFunction || m.Name.Namespace != l.di.MainPkg ||
+			strings.HasPrefix(m.Name.Name, "_") {
+			continue
+		}
+		fn, ok := scope.Lookup(m.ID).(*types.Func)
+		if !ok {
+			continue
+		}
+		var (
+			auth, write bool
+			transfer    *lintEvent
+		)
+		for _, ev := range l.funcEvents(fn) {
+			switch ev.kind {
+			case lintAuth:
+				auth = true
+			case lintCall:
+				if transfer == nil && ev.name == "transfer" {
+					transfer = &ev
+				}
+			case lintManage:
+				if !auth {
+					l.report(LintUnprotectedUpdate, l.fset.Position(ev.pos),
+						"method %s calls management.%s without authorization check", m.ID, ev.name)
+				}
+			case lintWrite:
+				if !auth && !write {
+					l.report(LintMissingWitness, l.fset.Position(ev.pos),
+						"method %s modifies the storage without runtime.CheckWitness check", m.ID)
+				}
+				if transfer != nil {
+					l.report(LintReentrancy, l.fset.Position(ev.pos),
+						"method %s modifies the storage after the transfer at %s, update the state before the transfer",
+						m.ID, l.fset.Position(transfer.pos))
+					transfer = nil
+				}
+				write = true
+			}
+		}
+	}
+}
+
+// funcEvents returns actions performed by the function in their source
+// order including actions of the called functions. Actions of interop
+// functions are attributed to their call site.
+func (l *linter) funcEvents(fn *types.Func) []lintEvent {
+	fn = fn.Origin()
+	if evs, ok := l.events[fn]; ok {
+		return evs
+	}
+	d, ok := l.decls[fn]
+	if !ok || d.decl.Body == nil {
+		return nil
+	}
+	l.events[fn] = nil
+	var (
+		evs   []lintEvent
+		stack []ast.Node
+	)
+	// Call arguments are evaluated before the call, so calls are handled
+	// after their children.
+	ast.Inspect(d.decl.Body, func(n ast.Node) bool {
+		if n != nil {
+			stack = append(stack, n)
+			return true
+		}
+		n, stack = stack[len(stack)-1], stack[:len(stack)-1]
+		if call, ok := n.(*ast.CallExpr); ok {
+			evs = append(evs, l.callEvents(d.info, call)...)
+		}
+		return true
+	})
+	l.events[fn] = evs
+	return evs
+}
+
+// callEvents returns actions performed by the function call.
+func (l *linter) callEvents(info *types.Info, call *ast.CallExpr) []lintEvent {
+	fn := calledFunc(info, call.Fun)
+	if fn == nil || fn.Pkg() == nil {
+		return nil
+	}
+	var (
+		path = fn.Pkg().Path()
+		name = fn.Name()
+	)
+	switch {
+	case path == interopPrefix+"/neogointernal" && strings.HasPrefix(name, "Syscall"):
+		if len(call.Args) == 0 {
+			return nil
+		}
+		switch constString(info, call.Args[0]) {
+		case interopnames.SystemRuntimeCheckWitness, interopnames.SystemRuntimeGetCallingScriptHash:
+			return []lintEvent{{kind: lintAuth, pos: call.Pos()}}
+		case interopnames.SystemStoragePut, interopnames.SystemStorageDelete:
+			return []lintEvent{{kind: lintWrite, pos: call.Pos()}}
+		case interopnames.SystemContractCall:
+			if len(call.Args) > 2 {
+				return []lintEvent{contractCall(info, call.Args[1], call.Args[2], call.Pos())}
+			}
+		}
+		return nil
+	case path == interopPrefix+"/neogointernal" && strings.HasPrefix(name, "CallWithToken"),
+		path == interopPrefix+"/contract" && name == "Call":
+		if len(call.Args) > 1 {
+			return []lintEvent{contractCall(info, call.Args[0], call.Args[1], call.Pos())}
+		}
+		return nil
+	}
+	evs := l.funcEvents(fn)
+	if isInteropPath(path) && len(evs) != 0 {
+		evs = slices.Clone(evs)
+		for i := range evs {
+			evs[i].pos = call.Pos()
+		}
+	}
+	return evs
+}
+
+// contractCall returns an event for the call of the contract method.
+func contractCall(info *types.Info, hash, method ast.Expr, pos token.Pos) lintEvent {
+	m := constString(info, method)
+	// Hashes are usually converted to interop.Hash160.
+	for {
+		conv, ok := ast.Unparen(hash).(*ast.CallExpr)
+		if !ok || len(conv.Args) != 1 || !info.Types[conv.Fun].IsType() {
+			break
+		}
+		hash = conv.Args[0]
+	}
+	if constString(info, hash) == string(nativehashes.ContractManagement.BytesBE()) &&
+		(m == "update" || m == "destroy") {
+		return lintEvent{kind: lintManage, name: m, pos: pos}
+	}
+	return lintEvent{kind: lintCall, name: m, pos: pos}
+}
+
+// calledFunc returns the function called by the expression, it returns nil
+// for builtins, conversions and calls of function values.
+func calledFunc(info *types.Info, fun ast.Expr) *types.Func {
+	switch f := ast.Unparen(fun).(type) {
+	case *ast.Ident:
+		fn, _ := info.Uses[f].(*types.Func)
+		return fn
+	case *ast.SelectorExpr:
+		fn, _ := info.Uses[f.Sel].(*types.Func)
+		return fn
+	case *ast.IndexExpr:
+		return calledFunc(info, f.X)
+	case *ast.IndexListExpr:
+		return calledFunc(info, f.X)
+	}
+	return nil
+}
+
+// constString returns the value of the constant string expression or an
+// empty string if the expression is not a constant string.
+func constString(info *types.Info, e ast.Expr) string {
+	tv, ok := info.Types[e]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return ""
+	}
+	return constant.StringVal(tv.Value)
+}
+
+// lintVoidMethods are the standard methods that don't return anything, so
+// results of their calls are not checked.
+var lintVoidMethods = []string{"onNEP11Payment", "onNEP17Payment", "update", "destroy"}
+
+// checkCalls looks for contract calls and token transfers used as
+// statements, the compiler drops their results.
+func (l *linter) checkCalls() {
+	l.inspect(func(pkg *packages.Package, n ast.Node) {
+		info := pkg.TypesInfo
+		st, ok := n.(*ast.ExprStmt)
+		if !ok {
+			return
+		}
+		call, ok := ast.Unparen(st.X).(*ast.CallExpr)
+		if !ok {
+			return
+		}
+		fn := calledFunc(info, call.Fun)
+		if fn == nil || fn.Pkg() == nil {
+			return
+		}
+		pos := l.fset.Position(call.Pos())
+		if fn.Pkg().Path() == interopPrefix+"/contract" && fn.Name() == "Call" {
+			m := constString(info, call.Args[1])
+			switch {
+			case slices.Contains(lintVoidMethods, m):
+			case m != "":
+				l.report(LintUncheckedCall, pos, "result of contract.Call of %q method is not checked", m)
+			default:
+				l.report(LintUncheckedCall, pos, "result of contract.Call is not checked")
+			}
+			return
+		}
+		res := fn.Signature().Results()
+		if res.Len() != 1 || !types.Identical(res.At(0).Type(), types.Typ[types.Bool]) {
+			return
+		}
+		for _, ev := range l.funcEvents(fn) {
+			if ev.kind == lintCall && ev.name == "transfer" {
+				l.report(LintUncheckedCall, pos, "result of %s.%s transfer call is not checked", fn.Pkg().Name(), fn.Name())
+				return
+			}
+		}
+	})
+}
+
+// inspect calls fn for every node of non-interop packages.
+func (l *linter) inspect(fn func(*packages.Package, ast.Node)) {
+	for _, pkgPath := range l.c.packages {
+		if isInteropPath(pkgPath) {
+			continue
+		}
+		pkg := l.c.packageCache[pkgPath]
+		for _, f := range pkg.Syntax {
+			ast.Inspect(f, func(n ast.Node) bool {
+				fn(pkg, n)
+				return true
+			})
+		}
+	}
+}
+
+// checkKeys looks for constant storage key prefixes used by different
+// packages that overlap with each other.
+func (l *linter) checkKeys() {
+	var (
+		uses []keyUse
+		seen = make(map[keyUse]bool)
+	)
+	l.inspect(func(pkg *packages.Package, n ast.Node) {
+		info := pkg.TypesInfo
+		call, ok := n.(*ast.CallExpr)
+		if !ok || len(call.Args) < 2 {
+			return
+		}
+		fn := calledFunc(info, call.Fun)
+		if fn == nil || fn.Pkg() == nil || fn.Pkg().Path() != interopPrefix+"/storage" {
+			return
+		}
+		switch fn.Name() {
+		case "Get", "Put", "Delete", "Find":
+		default:
+			return
+		}
+		prefix, ok := keyPrefix(info, call.Args[1])
+		if !ok || len(prefix) == 0 {
+			return
+		}
+		u := keyUse{pkg: pkg.PkgPath, prefix: prefix}
+		if !seen[u] {
+			seen[u] = true
+			u.pos = l.fset.Position(call.Args[1].Pos())
+			uses = append(uses, u)
+		}
+	})
+	for i, u := range uses {
+		for _, prev := range uses[:i] {
+			if prev.pkg != u.pkg && (strings.HasPrefix(u.prefix, prev.prefix) || strings.HasPrefix(prev.prefix, u.prefix)) {
+				l.report(LintKeyCollision, u.pos, "storage key prefix %q collides with %q used by %s package at %s",
+					u.prefix, prev.prefix, prev.pkg, prev.pos)
+			}
+		}
+	}
+}
+
+// keyPrefix returns the constant prefix of the storage key expression.
+func keyPrefix(info *types.Info, e ast.Expr) (string, bool) {
+	if tv, ok := info.Types[e]; ok && tv.Value != nil && tv.Value.Kind() == constant.String {
+		return constant.StringVal(tv.Value), true
+	}
+	switch e := ast.Unparen(e).(type) {
+	case *ast.CallExpr:
+		if len(e.Args) == 0 {
+			return "", false
+		}
+		if info.Types[e.Fun].IsType() && len(e.Args) == 1 {
+			return keyPrefix(info, e.Args[0])
+		}
+		if id, ok := ast.Unparen(e.Fun).(*ast.Ident); ok {
+			if b, ok := info.Uses[id].(*types.Builtin); ok && b.Name() == "append" {
+				return keyPrefix(info, e.Args[0])
+			}
+		}
+	case *ast.BinaryExpr:
+		if e.Op == token.ADD {
+			return keyPrefix(info, e.X)
+		}
+	case *ast.CompositeLit:
+		var b []byte
+		for _, el := range e.Elts {
+			tv := info.Types[el]
+			if tv.Value == nil || tv.Value.Kind() != constant.Int {
+				break
+			}
+			v, ok := constant.Int64Val(tv.Value)
+			if !ok {
+				break
+			}
+			b = append(b, byte(v))
+		}
+		return string(b), len(b) != 0
+	}
+	return "", false
+}
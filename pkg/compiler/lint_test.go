@@ -0,0 +1,197 @@
+package compiler_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/compiler"
+	"github.com/stretchr/testify/require"
+)
+
+type lintResult struct {
+	rule string
+	line int
+}
+
+func checkLint(t *testing.T, src string, expected ...lintResult) []compiler.LintIssue {
+	issues, err := compiler.Lint("foo.go", strings.NewReader(src), nil)
+	require.NoError(t, err)
+	actual := make([]lintResult, 0, len(issues))
+	for _, is := range issues {
+		actual = append(actual, lintResult{rule: is.Rule, line: is.Pos.Line})
+	}
+	if expected == nil {
+		expected = []lintResult{}
+	}
+	require.Equal(t, expected, actual)
+	return issues
+}
+
+func TestLintMissingWitness(t *testing.T) {
+	src := `package foo
+	import (
+		"github.com/nspcc-dev/neo-go/pkg/interop"
+		"github.com/nspcc-dev/neo-go/pkg/interop/native/gas"
+		"github.com/nspcc-dev/neo-go/pkg/interop/runtime"
+		"github.com/nspcc-dev/neo-go/pkg/interop/storage"
+		"github.com/nspcc-dev/neo-go/pkg/interop/lib/address"
+	)
+	var owner = address.ToHash160("NbrUYaZgyhSkNoRo9ugRyEMdUZxrhkNaWB")
+	func Put(k, v []byte) {
+		storage.Put(storage.GetContext(), k, v)
+	}
+	func PutChecked(k, v []byte) {
+		if !runtime.CheckWitness(owner) {
+			panic("not an owner")
+		}
+		storage.Put(storage.GetContext(), k, v)
+	}
+	func Delete(k []byte) {
+		del(k)
+	}
+	func del(k []byte) {
+		storage.Delete(storage.GetContext(), k)
+	}
+	func OnNEP17Payment(from interop.Hash160, amount int, data any) {
+		if !runtime.GetCallingScriptHash().Equals(interop.Hash160(gas.Hash)) {
+			panic("only GAS is accepted")
+		}
+		storage.Put(storage.GetContext(), from, amount)
+	}
+	func Get(k []byte) any {
+		return storage.Get(storage.GetReadOnlyContext(), k)
+	}
+	func _deploy(data any, isUpdate bool) {
+		storage.Put(storage.GetContext(), "owner", owner)
+	}`
+	issues := checkLint(t, src,
+		lintResult{compiler.LintMissingWitness, 11},
+		lintResult{compiler.LintMissingWitness, 23})
+	require.Equal(t, "method Put modifies the storage without runtime.CheckWitness check", issues[0].Message)
+	require.Equal(t, "method Delete modifies the storage without runtime.CheckWitness check", issues[1].Message)
+}
+
+func TestLintUncheckedCall(t *testing.T) {
+	src := `package foo
+	import (
+		"github.com/nspcc-dev/neo-go/pkg/interop"
+		"github.com/nspcc-dev/neo-go/pkg/interop/contract"
+		"github.com/nspcc-dev/neo-go/pkg/interop/native/gas"
+		"github.com/nspcc-dev/neo-go/pkg/interop/runtime"
+	)
+	func Call(h interop.Hash160, method string) {
+		contract.Call(h, "foo", contract.All)
+		contract.Call(h, method, contract.All)
+		contract.Call(h, "onNEP17Payment", contract.All, nil, 1, nil)
+		_ = contract.Call(h, "foo", contract.All)
+		if !contract.Call(h, "foo", contract.All).(bool) {
+			panic("failed")
+		}
+	}
+	func Transfer(to interop.Hash160) {
+		gas.Transfer(runtime.GetExecutingScriptHash(), to, 1, nil)
+	}`
+	issues := checkLint(t, src,
+		lintResult{compiler.LintUncheckedCall, 9},
+		lintResult{compiler.LintUncheckedCall, 10},
+		lintResult{compiler.LintUncheckedCall, 18})
+	require.Equal(t, `result of contract.Call of "foo" method is not checked`, issues[0].Message)
+	require.Equal(t, "result of contract.Call is not checked", issues[1].Message)
+	require.Equal(t, "result of gas.Transfer transfer call is not checked", issues[2].Message)
+}
+
+func TestLintReentrancy(t *testing.T) {
+	src := `package foo
+	import (
+		"github.com/nspcc-dev/neo-go/pkg/interop"
+		"github.com/nspcc-dev/neo-go/pkg/interop/contract"
+		"github.com/nspcc-dev/neo-go/pkg/interop/native/gas"
+		"github.com/nspcc-dev/neo-go/pkg/interop/runtime"
+		"github.com/nspcc-dev/neo-go/pkg/interop/storage"
+	)
+	func Withdraw(to interop.Hash160, amount int) {
+		if !runtime.CheckWitness(to) {
+			panic("no witness")
+		}
+		if !gas.Transfer(runtime.GetExecutingScriptHash(), to, amount, nil) {
+			panic("transfer failed")
+		}
+		storage.Delete(storage.GetContext(), to)
+	}
+	func WithdrawToken(token, to interop.Hash160, amount int) {
+		if !runtime.CheckWitness(to) {
+			panic("no witness")
+		}
+		storage.Delete(storage.GetContext(), to)
+		if !contract.Call(token, "transfer", contract.All, runtime.GetExecutingScriptHash(), to, amount, nil).(bool) {
+			panic("transfer failed")
+		}
+	}`
+	issues := checkLint(t, src, lintResult{compiler.LintReentrancy, 16})
+	require.True(t, strings.HasPrefix(issues[0].Message, "method Withdraw modifies the storage after the transfer at "), issues[0].Message)
+	require.Contains(t, issues[0].Message, "foo.go:13:7")
+}
+
+func TestLintUnprotectedUpdate(t *testing.T) {
+	src := `package foo
+	import (
+		"github.com/nspcc-dev/neo-go/pkg/interop"
+		"github.com/nspcc-dev/neo-go/pkg/interop/contract"
+		"github.com/nspcc-dev/neo-go/pkg/interop/native/management"
+		"github.com/nspcc-dev/neo-go/pkg/interop/runtime"
+	)
+	func Update(nef, manifest []byte) {
+		management.Update(nef, manifest)
+	}
+	func UpdateCall(nef, manifest []byte) {
+		contract.Call(interop.Hash160(management.Hash), "update", contract.All, nef, manifest)
+	}
+	func Destroy(owner interop.Hash160) {
+		if !runtime.CheckWitness(owner) {
+			panic("not an owner")
+		}
+		management.Destroy()
+	}`
+	issues := checkLint(t, src,
+		lintResult{compiler.LintUnprotectedUpdate, 9},
+		lintResult{compiler.LintUnprotectedUpdate, 12})
+	require.Equal(t, "method Update calls management.update without authorization check", issues[0].Message)
+}
+
+func TestLintKeyCollision(t *testing.T) {
+	src := `package foo
+	import (
+		"github.com/nspcc-dev/neo-go/pkg/compiler/testdata/lint"
+		"github.com/nspcc-dev/neo-go/pkg/interop/storage"
+	)
+	const prefixBonus = "b"
+	func Bonus(key []byte) int {
+		return storage.Get(storage.GetReadOnlyContext(), []byte(prefixBonus + string(key))).(int)
+	}
+	func Balance(key []byte) int {
+		return lint.Balance(key)
+	}
+	func Total() int {
+		return lint.Total() + storage.Get(storage.GetReadOnlyContext(), "total").(int)
+	}`
+	issues := checkLint(t, src, lintResult{compiler.LintKeyCollision, 8})
+	require.True(t, strings.HasPrefix(issues[0].Message,
+		`storage key prefix "b" collides with "ba" used by github.com/nspcc-dev/neo-go/pkg/compiler/testdata/lint package at `), issues[0].Message)
+}
+
+func TestLintClean(t *testing.T) {
+	src := `package foo
+	import (
+		"github.com/nspcc-dev/neo-go/pkg/interop/runtime"
+		"github.com/nspcc-dev/neo-go/pkg/interop/storage"
+		"github.com/nspcc-dev/neo-go/pkg/interop/lib/address"
+	)
+	var owner = address.ToHash160("NbrUYaZgyhSkNoRo9ugRyEMdUZxrhkNaWB")
+	func SetValue(v int) {
+		if !runtime.CheckWitness(owner) {
+			panic("not an owner")
+		}
+		storage.Put(storage.GetContext(), "value", v)
+	}`
+	checkLint(t, src)
+}
@@ -0,0 +1,654 @@
+package compiler
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/nspcc-dev/neo-go/pkg/vm"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+)
+
+// The optimizer works with the final script (after jump labels are replaced
+// with offsets). The script is decoded into a list of instructions with jump
+// targets represented by instruction indices, so that instructions can be
+// removed and replaced without any offset bookkeeping. Passes are repeated
+// until none of them changes the program, then the script is encoded back
+// with all jumps in the shortest possible form and all offsets (function
+// ranges, sequence points) are remapped.
+
+// noTarget is used for jumps without target (like TRY without catch block).
+const noTarget = -1
+
+// optInstr is an instruction of the optimized program.
+type optInstr struct {
+	op opcode.Opcode
+	// raw contains the original instruction bytes, it's used to encode
+	// non-jump instructions.
+	raw []byte
+	// offset is the instruction offset in the original script.
+	offset int
+	// target is the index of the jump target instruction (catch block for TRY).
+	target int
+	// finally is the index of the finally block for TRY.
+	finally int
+	removed bool
+}
+
+type optimizer struct {
+	prog    []optInstr
+	entries []int
+	// targets is the set of instructions that can be entered not only from
+	// the previous instruction.
+	targets map[int]bool
+}
+
+// optimize performs peephole and dead code elimination passes over the given
+// script and updates function ranges and debug sequence points accordingly.
+func (c *codegen) optimize(b []byte) ([]byte, error) {
+	o, err := newOptimizer(b, c.entryOffsets())
+	if err != nil {
+		return nil, err
+	}
+	for o.threadJumps() || o.peephole() || o.optimizeLocals() || o.removeDeadCode() {
+	}
+	res, offsets, err := o.encode()
+	if err != nil {
+		return nil, err
+	}
+
+	// mapOffset returns the new offset of the first instruction at or after
+	// the given old offset.
+	mapOffset := func(old int) int {
+		i, _ := slices.BinarySearchFunc(o.prog, old, func(in optInstr, off int) int {
+			return in.offset - off
+		})
+		return offsets[i]
+	}
+	mapRange := func(start, end int) (int, int) {
+		return mapOffset(start), mapOffset(end+1) - 1
+	}
+	for _, f := range c.funcs {
+		if !c.isEmitted(f) {
+			continue
+		}
+		start, end := mapRange(int(f.rng.Start), int(f.rng.End))
+		f.rng.Start, f.rng.End = uint16(start), uint16(end)
+	}
+	if c.deployEndOffset >= 0 {
+		_, c.deployEndOffset = mapRange(c.initEndOffset+1, c.deployEndOffset)
+	}
+	if c.initEndOffset > 0 {
+		_, c.initEndOffset = mapRange(0, c.initEndOffset)
+	}
+	for _, seqPoints := range c.sequencePoints {
+		for i := range seqPoints {
+			seqPoints[i].Opcode = mapOffset(seqPoints[i].Opcode)
+		}
+	}
+	return res, nil
+}
+
+// entryOffsets returns offsets of all instructions the script can be entered
+// at: methods, `_initialize` and `_deploy`.
+func (c *codegen) entryOffsets() []int {
+	offs := []int{0}
+	if c.deployEndOffset >= 0 {
+		offs = append(offs, c.initEndOffset+1)
+	}
+	for _, f := range c.funcs {
+		if c.isEmitted(f) {
+			offs = append(offs, int(f.rng.Start))
+		}
+	}
+	return offs
+}
+
+func newOptimizer(b []byte, entries []int) (*optimizer, error) {
+	var (
+		o       = &optimizer{}
+		index   = make(map[int]int)
+		ctx     = vm.NewContext(b)
+		targets [][2]int
+	)
+	for op, param, err := ctx.Next(); ctx.IP() < len(b); op, param, err = ctx.Next() {
+		if err != nil {
+			return nil, fmt.Errorf("can't decode instruction at %d: %w", ctx.IP(), err)
+		}
+		ip := ctx.IP()
+		index[ip] = len(o.prog)
+		o.prog = append(o.prog, optInstr{
+			op:     op,
+			raw:    b[ip:ctx.NextIP()],
+			offset: ip,
+		})
+		t := [2]int{noTarget, noTarget}
+		switch {
+		case isTryOp(op):
+			catch, finally := jumpOffset(op, param), jumpOffset(op, param[len(param)/2:])
+			if catch != 0 {
+				t[0] = ip + catch
+			}
+			if finally != 0 {
+				t[1] = ip + finally
+			}
+		case hasJumpTarget(op):
+			t[0] = ip + jumpOffset(op, param)
+		}
+		targets = append(targets, t)
+	}
+	toIndex := func(off int) (int, error) {
+		if off == noTarget {
+			return noTarget, nil
+		}
+		i, ok := index[off]
+		if !ok {
+			return 0, fmt.Errorf("invalid jump target: %d", off)
+		}
+		return i, nil
+	}
+	var err error
+	for i := range o.prog {
+		if o.prog[i].target, err = toIndex(targets[i][0]); err != nil {
+			return nil, err
+		}
+		if o.prog[i].finally, err = toIndex(targets[i][1]); err != nil {
+			return nil, err
+		}
+	}
+	for _, off := range entries {
+		if i, ok := index[off]; ok {
+			o.entries = append(o.entries, i)
+		}
+	}
+	return o, nil
+}
+
+// next returns the index of the next live instruction or -1 if there is none.
+func (o *optimizer) next(i int) int {
+	return o.resolve(i + 1)
+}
+
+// resolve returns the index of the first live instruction starting from i or
+// -1 if there is none.
+func (o *optimizer) resolve(i int) int {
+	if i < 0 {
+		return -1
+	}
+	for ; i < len(o.prog); i++ {
+		if !o.prog[i].removed {
+			return i
+		}
+	}
+	return -1
+}
+
+// opAt returns the opcode of the first live instruction starting from i or
+// NOP if there is none.
+func (o *optimizer) opAt(i int) opcode.Opcode {
+	if i = o.resolve(i); i < 0 {
+		return opcode.NOP
+	}
+	return o.prog[i].op
+}
+
+// remove removes the i-th instruction, jumps to it now lead to the next one.
+func (o *optimizer) remove(i int) {
+	o.prog[i].removed = true
+	if o.targets[i] {
+		if n := o.next(i); n >= 0 {
+			o.targets[n] = true
+		}
+	}
+}
+
+// replace replaces the i-th instruction with the given one-byte instruction.
+func (o *optimizer) replace(i int, op opcode.Opcode) {
+	o.prog[i].op = op
+	o.prog[i].raw = []byte{byte(op)}
+	o.prog[i].target = noTarget
+}
+
+// collectTargets fills the set of instructions that can be entered not only
+// from the previous one.
+func (o *optimizer) collectTargets() {
+	o.targets = make(map[int]bool)
+	for _, i := range o.entries {
+		if i = o.resolve(i); i >= 0 {
+			o.targets[i] = true
+		}
+	}
+	for i := range o.prog {
+		if o.prog[i].removed {
+			continue
+		}
+		for _, t := range []int{o.prog[i].target, o.prog[i].finally} {
+			if t = o.resolve(t); t >= 0 {
+				o.targets[t] = true
+			}
+		}
+	}
+}
+
+// threadJumps retargets jumps leading to unconditional jumps and replaces
+// unconditional jumps to RET with RET.
+func (o *optimizer) threadJumps() bool {
+	var changed bool
+	for i := range o.prog {
+		in := &o.prog[i]
+		if in.removed || !isJumpOp(in.op) {
+			continue
+		}
+		t := o.resolve(in.target)
+		if t < 0 {
+			continue
+		}
+		for range len(o.prog) { // Protect from infinite loops.
+			if !isUncondJumpOp(o.prog[t].op) {
+				break
+			}
+			next := o.resolve(o.prog[t].target)
+			if next == t {
+				break
+			}
+			t = next
+		}
+		if t != in.target {
+			in.target = t
+			changed = true
+		}
+		if isUncondJumpOp(in.op) && o.prog[t].op == opcode.RET {
+			o.replace(i, opcode.RET)
+			changed = true
+		}
+	}
+	return changed
+}
+
+// peephole performs simple local optimizations, like removing values that
+// are pushed onto the stack only to be dropped or jumps on constant
+// conditions.
+func (o *optimizer) peephole() bool {
+	var changed bool
+	o.collectTargets()
+	for i := o.resolve(0); i >= 0; i = o.next(i) {
+		in := &o.prog[i]
+		j := o.next(i)
+		switch {
+		case in.op == opcode.NOP:
+			o.remove(i)
+			changed = true
+			continue
+		case isUncondJumpOp(in.op) && o.resolve(in.target) == j:
+			o.remove(i)
+			changed = true
+			continue
+		case isCondJumpOp(in.op) && o.resolve(in.target) == j:
+			o.replace(i, opcode.DROP)
+			changed = true
+		}
+		if j < 0 || o.targets[j] {
+			continue
+		}
+		next := &o.prog[j]
+		switch {
+		case isPushOp(in.op) && next.op == opcode.DROP,
+			in.op == opcode.SWAP && next.op == opcode.SWAP:
+			o.remove(i)
+			o.remove(j)
+			changed = true
+		case isCondJumpOp(next.op) && isConstOp(in.op):
+			if constCondJumpTaken(in.op, next.op) {
+				next.op = opcode.JMPL
+			} else {
+				o.remove(j)
+			}
+			o.remove(i)
+			changed = true
+		case isUncondJumpOp(next.op) && isConstOp(in.op) && isCondJumpOp(o.opAt(next.target)):
+			// Boolean expressions are often materialized and checked
+			// right after that, the check can be done at compile time.
+			t := o.resolve(next.target)
+			if constCondJumpTaken(in.op, o.prog[t].op) {
+				next.target = o.prog[t].target
+			} else {
+				next.target = o.next(t)
+			}
+			o.remove(i)
+			changed = true
+		case isCondJumpOp(next.op) && in.op == opcode.NOT:
+			next.op = negateCondJump(next.op)
+			o.remove(i)
+			changed = true
+		}
+	}
+	return changed
+}
+
+// optimizeLocals replaces stores to local variables that are never loaded
+// with DROP and removes store-load pairs for variables that are loaded only
+// once (right after the store). Every INITSLOT starts a new set of local
+// variables, so it's enough to check loads between two INITSLOT instructions.
+func (o *optimizer) optimizeLocals() bool {
+	var (
+		changed bool
+		start   int
+	)
+	o.collectTargets()
+	for start < len(o.prog) {
+		end := start + 1
+		for end < len(o.prog) && (o.prog[end].removed || o.prog[end].op != opcode.INITSLOT) {
+			end++
+		}
+		loads := make(map[int]int)
+		for i := start; i < end; i++ {
+			if n, ok := localIndex(o.prog[i], opcode.LDLOC0, opcode.LDLOC); ok && !o.prog[i].removed {
+				loads[n]++
+			}
+		}
+		for i := start; i < end; i++ {
+			n, ok := localIndex(o.prog[i], opcode.STLOC0, opcode.STLOC)
+			if !ok || o.prog[i].removed {
+				continue
+			}
+			switch j := o.next(i); {
+			case loads[n] == 0:
+				o.replace(i, opcode.DROP)
+				changed = true
+			case loads[n] == 1 && j >= 0 && !o.targets[j]:
+				if m, ok := localIndex(o.prog[j], opcode.LDLOC0, opcode.LDLOC); ok && m == n {
+					// The value is left on the stack, other stores are
+					// unused now.
+					o.remove(i)
+					o.remove(j)
+					loads[n] = 0
+					changed = true
+				}
+			}
+		}
+		start = end
+	}
+	return changed
+}
+
+// removeDeadCode removes instructions that can't be reached from any entry
+// point.
+func (o *optimizer) removeDeadCode() bool {
+	var (
+		reached = make([]bool, len(o.prog))
+		queue   []int
+	)
+	visit := func(i int) {
+		if i = o.resolve(i); i >= 0 && !reached[i] {
+			reached[i] = true
+			queue = append(queue, i)
+		}
+	}
+	for _, i := range o.entries {
+		visit(i)
+	}
+	for len(queue) > 0 {
+		i := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+		in := o.prog[i]
+		if in.target != noTarget {
+			visit(in.target)
+		}
+		if in.finally != noTarget {
+			visit(in.finally)
+		}
+		switch in.op {
+		case opcode.RET, opcode.THROW, opcode.ABORT, opcode.ABORTMSG, opcode.ENDFINALLY,
+			opcode.JMP, opcode.JMPL, opcode.ENDTRY, opcode.ENDTRYL:
+		default:
+			visit(o.next(i))
+		}
+	}
+	var changed bool
+	for i := range o.prog {
+		if !o.prog[i].removed && !reached[i] {
+			o.prog[i].removed = true
+			changed = true
+		}
+	}
+	return changed
+}
+
+// encode encodes the program back into the script using the shortest jump
+// forms possible. It also returns new offsets of all original instructions
+// (removed instructions get the offset of the next live one) with the script
+// length appended.
+func (o *optimizer) encode() ([]byte, []int, error) {
+	var (
+		live  []int
+		long  = make(map[int]bool)
+		pos   = make([]int, len(o.prog)+1)
+		fits  = func(off int) bool { return math.MinInt8 <= off && off <= math.MaxInt8 }
+		relTo = func(i, t int) int {
+			if t == noTarget {
+				return 0
+			}
+			return pos[o.resolve(t)] - pos[i]
+		}
+	)
+	for i := range o.prog {
+		if o.prog[i].removed {
+			continue
+		}
+		live = append(live, i)
+		if hasJumpTarget(o.prog[i].op) || isTryOp(o.prog[i].op) {
+			long[i] = true
+		}
+	}
+	size := func(i int) int {
+		in := o.prog[i]
+		switch {
+		case isTryOp(in.op) && long[i]:
+			return 9
+		case isTryOp(in.op):
+			return 3
+		case hasJumpTarget(in.op) && long[i]:
+			return 5
+		case hasJumpTarget(in.op):
+			return 2
+		default:
+			return len(in.raw)
+		}
+	}
+	for {
+		var p int
+		for _, i := range live {
+			pos[i] = p
+			p += size(i)
+		}
+		var changed bool
+		for _, i := range live {
+			in := o.prog[i]
+			if !long[i] || in.op == opcode.PUSHA {
+				continue
+			}
+			if fits(relTo(i, in.target)) && fits(relTo(i, in.finally)) {
+				long[i] = false
+				changed = true
+			}
+		}
+		if !changed {
+			pos[len(o.prog)] = p
+			break
+		}
+	}
+
+	buf := make([]byte, 0, pos[len(o.prog)])
+	for _, i := range live {
+		in := o.prog[i]
+		if !hasJumpTarget(in.op) && !isTryOp(in.op) {
+			buf = append(buf, in.raw...)
+			continue
+		}
+		op := toLongForm(in.op)
+		if !long[i] {
+			op = toShortForm(op)
+		}
+		buf = append(buf, byte(op))
+		offs := []int{relTo(i, in.target)}
+		if isTryOp(op) {
+			offs = append(offs, relTo(i, in.finally))
+		}
+		for _, off := range offs {
+			if long[i] {
+				buf = binary.LittleEndian.AppendUint32(buf, uint32(int32(off)))
+			} else {
+				buf = append(buf, byte(int8(off)))
+			}
+		}
+	}
+	if len(buf) != pos[len(o.prog)] {
+		return nil, nil, fmt.Errorf("ICE: optimized script length mismatch: %d vs %d", len(buf), pos[len(o.prog)])
+	}
+
+	offsets := make([]int, len(o.prog)+1)
+	offsets[len(o.prog)] = len(buf)
+	for i := len(o.prog) - 1; i >= 0; i-- {
+		if o.prog[i].removed {
+			offsets[i] = offsets[i+1]
+		} else {
+			offsets[i] = pos[i]
+		}
+	}
+	return buf, offsets, nil
+}
+
+// jumpOffset returns the jump offset stored in the given parameter.
+func jumpOffset(op opcode.Opcode, param []byte) int {
+	if isLongJumpOp(op) {
+		return int(int32(binary.LittleEndian.Uint32(param)))
+	}
+	return int(int8(param[0]))
+}
+
+// localIndex returns the index of the local variable accessed by the given
+// LDLOC* (base0 = LDLOC0, base = LDLOC) or STLOC* instruction.
+func localIndex(in optInstr, base0, base opcode.Opcode) (int, bool) {
+	switch {
+	case base0 <= in.op && in.op < base:
+		return int(in.op - base0), true
+	case in.op == base:
+		return int(in.raw[1]), true
+	default:
+		return 0, false
+	}
+}
+
+// hasJumpTarget returns true for instructions with a single offset parameter.
+func hasJumpTarget(op opcode.Opcode) bool {
+	return isJumpOp(op) || op == opcode.CALL || op == opcode.CALLL ||
+		op == opcode.ENDTRY || op == opcode.ENDTRYL || op == opcode.PUSHA
+}
+
+func isTryOp(op opcode.Opcode) bool {
+	return op == opcode.TRY || op == opcode.TRYL
+}
+
+func isLongJumpOp(op opcode.Opcode) bool {
+	switch op {
+	case opcode.JMPL, opcode.JMPIFL, opcode.JMPIFNOTL, opcode.JMPEQL, opcode.JMPNEL,
+		opcode.JMPGTL, opcode.JMPGEL, opcode.JMPLEL, opcode.JMPLTL,
+		opcode.CALLL, opcode.PUSHA, opcode.ENDTRYL, opcode.TRYL:
+		return true
+	default:
+		return false
+	}
+}
+
+// isJumpOp returns true for unconditional and conditional jumps.
+func isJumpOp(op opcode.Opcode) bool {
+	return opcode.JMP <= op && op <= opcode.JMPLEL
+}
+
+func isUncondJumpOp(op opcode.Opcode) bool {
+	return op == opcode.JMP || op == opcode.JMPL
+}
+
+func isCondJumpOp(op opcode.Opcode) bool {
+	switch op {
+	case opcode.JMPIF, opcode.JMPIFL, opcode.JMPIFNOT, opcode.JMPIFNOTL:
+		return true
+	default:
+		return false
+	}
+}
+
+// isConstOp returns true for instructions pushing constant integer or boolean.
+func isConstOp(op opcode.Opcode) bool {
+	return op == opcode.PUSHT || op == opcode.PUSHF || opcode.PUSHM1 <= op && op <= opcode.PUSH16
+}
+
+// constCondJumpTaken returns true if the conditional jump is taken after the
+// given constant push.
+func constCondJumpTaken(push, jmp opcode.Opcode) bool {
+	// NeoVM treats all non-zero integers as true.
+	isTrue := push != opcode.PUSHF && push != opcode.PUSH0
+	return isTrue == (jmp == opcode.JMPIF || jmp == opcode.JMPIFL)
+}
+
+// isPushOp returns true for instructions pushing a single item onto the stack
+// without any other side effects.
+func isPushOp(op opcode.Opcode) bool {
+	switch {
+	case opcode.PUSHINT8 <= op && op <= opcode.PUSHINT256,
+		opcode.PUSHT <= op && op <= opcode.PUSHDATA4 && op != opcode.PUSHA,
+		opcode.PUSHM1 <= op && op <= opcode.PUSH16,
+		opcode.LDSFLD0 <= op && op <= opcode.LDSFLD,
+		opcode.LDLOC0 <= op && op <= opcode.LDLOC,
+		opcode.LDARG0 <= op && op <= opcode.LDARG:
+		return true
+	}
+	switch op {
+	case opcode.DUP, opcode.OVER, opcode.NEWMAP, opcode.NEWARRAY0, opcode.NEWSTRUCT0:
+		return true
+	default:
+		return false
+	}
+}
+
+// negateCondJump returns the opposite conditional jump in the long form.
+func negateCondJump(op opcode.Opcode) opcode.Opcode {
+	switch op {
+	case opcode.JMPIF, opcode.JMPIFL:
+		return opcode.JMPIFNOTL
+	default:
+		return opcode.JMPIFL
+	}
+}
+
+func toLongForm(op opcode.Opcode) opcode.Opcode {
+	switch op {
+	case opcode.JMP:
+		return opcode.JMPL
+	case opcode.JMPIF:
+		return opcode.JMPIFL
+	case opcode.JMPIFNOT:
+		return opcode.JMPIFNOTL
+	case opcode.JMPEQ:
+		return opcode.JMPEQL
+	case opcode.JMPNE:
+		return opcode.JMPNEL
+	case opcode.JMPGT:
+		return opcode.JMPGTL
+	case opcode.JMPGE:
+		return opcode.JMPGEL
+	case opcode.JMPLE:
+		return opcode.JMPLEL
+	case opcode.JMPLT:
+		return opcode.JMPLTL
+	case opcode.CALL:
+		return opcode.CALLL
+	case opcode.ENDTRY:
+		return opcode.ENDTRYL
+	case opcode.TRY:
+		return opcode.TRYL
+	default:
+		return op
+	}
+}
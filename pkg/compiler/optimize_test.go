@@ -0,0 +1,296 @@
+package compiler_test
+
+import (
+	"fmt"
+	"math/big"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/cli/smartcontract"
+	"github.com/nspcc-dev/neo-go/pkg/compiler"
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/neotest"
+	"github.com/nspcc-dev/neo-go/pkg/neotest/chain"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+	"github.com/stretchr/testify/require"
+)
+
+// evalOptimized compiles the source with and without optimizations, checks
+// that the optimized script is not bigger and that its method returns the
+// expected result.
+func evalOptimized(t *testing.T, src string, method string, result any) []byte {
+	plain, _, err := compiler.CompileWithOptions("foo.go", strings.NewReader(src), nil)
+	require.NoError(t, err)
+
+	b, di, err := compiler.CompileWithOptions("foo.go", strings.NewReader(src), &compiler.Options{Optimize: true})
+	require.NoError(t, err)
+	require.LessOrEqual(t, len(b.Script), len(plain.Script))
+
+	v := vm.New()
+	storePlugin := newStoragePlugin()
+	v.GasLimit = -1
+	v.SyscallHandler = storePlugin.syscallHandler
+	storePlugin.info = di
+	invokeMethod(t, method, b.Script, v, di)
+	runAndCheck(t, v, result)
+	return b.Script
+}
+
+func TestOptimizeEquivalence(t *testing.T) {
+	for name, tcases := range map[string][]testCase{
+		"arrays":   arrayTestCases,
+		"generics": genericsTestCases,
+		"maps":     mapTestCases,
+		"nil":      nilTestCases,
+		"numeric":  numericTestCases,
+	} {
+		t.Run(name, func(t *testing.T) {
+			for _, tc := range tcases {
+				t.Run(tc.name, func(t *testing.T) { evalOptimized(t, tc.src, testMainIdent, tc.result) })
+			}
+		})
+	}
+	// These test cases are functions named F%d without package clause.
+	for name, tcases := range map[string][]testCase{
+		"assign":  assignTestCases,
+		"binary":  binaryExprTestCases,
+		"for":     forLoopTestCases,
+		"slices":  sliceTestCases,
+		"structs": structTestCases,
+		"switch":  switchTestCases,
+	} {
+		t.Run(name, func(t *testing.T) {
+			for _, tc := range tcases {
+				t.Run(tc.name, func(t *testing.T) {
+					evalOptimized(t, "package testcase\n"+fmt.Sprintf(tc.src, 0), "F0", tc.result)
+				})
+			}
+		})
+	}
+}
+
+func TestOptimize(t *testing.T) {
+	t.Run("constant condition", func(t *testing.T) {
+		src := `package foo
+		const debug = false
+		func Main() int {
+			if debug {
+				return 1
+			}
+			return 2
+		}`
+		script := evalOptimized(t, src, testMainIdent, big.NewInt(2))
+		require.Equal(t, []byte{byte(opcode.PUSH2), byte(opcode.RET)}, script)
+	})
+	t.Run("unused local", func(t *testing.T) {
+		src := `package foo
+		func Main() int {
+			a := 1
+			a = 2
+			return a
+		}`
+		plain := eval(t, src, big.NewInt(2))
+		script := evalOptimized(t, src, testMainIdent, big.NewInt(2))
+		require.Less(t, len(script), len(plain))
+	})
+	t.Run("dead code after panic", func(t *testing.T) {
+		src := `package foo
+		func Main() int {
+			return f(true)
+		}
+		func f(ok bool) int {
+			if !ok {
+				panic("bad")
+			}
+			for {
+				return 42
+			}
+		}`
+		evalOptimized(t, src, testMainIdent, big.NewInt(42))
+	})
+	t.Run("try-catch", func(t *testing.T) {
+		src := `package foo
+		var a int
+		func Main() int {
+			return h() + a
+		}
+		func h() int {
+			defer func() {
+				if r := recover(); r != nil {
+					a = 3
+				} else {
+					a = 4
+				}
+			}()
+			a = 1
+			panic("msg")
+			return a
+		}`
+		evalOptimized(t, src, testMainIdent, big.NewInt(3))
+	})
+	t.Run("unused function", func(t *testing.T) {
+		src := `package foo
+		func Main() int {
+			return 1
+		}
+		func Unused() int {
+			return 2
+		}`
+		_, di, err := compiler.CompileWithOptions("foo.go", strings.NewReader(src), &compiler.Options{Optimize: true})
+		require.NoError(t, err)
+		require.Len(t, di.Methods, 2)
+		for _, m := range di.Methods {
+			require.LessOrEqual(t, m.Range.Start, m.Range.End, m.ID)
+		}
+	})
+}
+
+func TestOptimizeDebugInfo(t *testing.T) {
+	src := `package foo
+	func Main(a int) int {
+		b := a + 1
+		if b > 10 {
+			return b
+		}
+		return sum(a, b)
+	}
+	func sum(a, b int) int {
+		return a + b
+	}`
+	b, di, err := compiler.CompileWithOptions("foo.go", strings.NewReader(src), &compiler.Options{Optimize: true})
+	require.NoError(t, err)
+
+	ops := make(map[int]bool)
+	ctx := vm.NewContext(b.Script)
+	for {
+		ip := ctx.NextIP()
+		if ip >= len(b.Script) {
+			break
+		}
+		ops[ip] = true
+		_, _, err := ctx.Next()
+		require.NoError(t, err)
+	}
+	for _, m := range di.Methods {
+		require.True(t, ops[int(m.Range.Start)], m.ID)
+		require.Less(t, int(m.Range.End), len(b.Script), m.ID)
+		for _, sp := range m.SeqPoints {
+			require.True(t, sp.Opcode >= int(m.Range.Start) && sp.Opcode <= int(m.Range.End), m.ID)
+			require.True(t, ops[sp.Opcode], "%s: sequence point at %d", m.ID, sp.Opcode)
+		}
+	}
+}
+
+// compileExample compiles the example contract the same way neotest.CompileFile
+// does, but allows to enable optimizations.
+func compileExample(t *testing.T, sender util.Uint160, name string, optimize bool) *neotest.Contract {
+	config.Version = "neotest"
+
+	dir := filepath.Join(examplePath, name)
+	ne, di, err := compiler.CompileWithOptions(dir, nil, &compiler.Options{Optimize: optimize})
+	require.NoError(t, err)
+
+	conf, err := smartcontract.ParseContractConfig(filepath.Join(dir, name+".yml"))
+	require.NoError(t, err)
+
+	o := &compiler.Options{
+		Name:                       conf.Name,
+		ContractEvents:             conf.Events,
+		ContractSupportedStandards: conf.SupportedStandards,
+		SafeMethods:                conf.SafeMethods,
+		Permissions:                []manifest.Permission{*manifest.NewPermission(manifest.PermissionWildcard)},
+	}
+	m, err := compiler.CreateManifest(di, o)
+	require.NoError(t, err)
+
+	return &neotest.Contract{
+		Hash:      state.CreateContractHash(sender, ne.Checksum, m.Name),
+		NEF:       ne,
+		Manifest:  m,
+		DebugInfo: di,
+	}
+}
+
+func TestOptimizeExamples(t *testing.T) {
+	type call struct {
+		method string
+		args   []any
+	}
+	testCases := []struct {
+		name  string
+		setup []call
+		calls []call
+	}{
+		{
+			name: "storage",
+			setup: []call{
+				{"put", []any{[]byte("key1"), []byte("value1")}},
+				{"put", []any{[]byte("key2"), []byte("value2")}},
+			},
+			calls: []call{
+				{"putDefault", []any{[]byte("default")}},
+				{"get", []any{[]byte("key1")}},
+				{"getDefault", nil},
+				{"find", []any{[]byte("key")}},
+				{"delete", []any{[]byte("key2")}},
+			},
+		},
+		{
+			name: "runtime",
+			calls: []call{
+				{"checkWitness", nil},
+				{"log", []any{"message"}},
+				{"notify", []any{"event"}},
+			},
+		},
+		{
+			name: "token",
+			calls: []call{
+				{"symbol", nil},
+				{"decimals", nil},
+				{"totalSupply", nil},
+				{"balanceOf", []any{util.Uint160{1, 2, 3}}},
+				{"transfer", []any{util.Uint160{1, 2, 3}, util.Uint160{3, 2, 1}, 1, nil}},
+			},
+		},
+	}
+	var plainTotal, optTotal int64
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			bc, acc := chain.NewSingle(t)
+			e := neotest.NewExecutor(t, bc, acc, acc)
+			e.DisableCoverage()
+
+			plain := compileExample(t, e.CommitteeHash, tc.name, false)
+			opt := compileExample(t, e.CommitteeHash, tc.name, true)
+			require.Less(t, len(opt.NEF.Script), len(plain.NEF.Script))
+			e.DeployContract(t, plain, nil)
+			e.DeployContract(t, opt, nil)
+
+			plainInv := e.CommitteeInvoker(plain.Hash)
+			optInv := e.CommitteeInvoker(opt.Hash)
+			for _, c := range tc.setup {
+				plainInv.Invoke(t, c.args[0], c.method, c.args...)
+				optInv.Invoke(t, c.args[0], c.method, c.args...)
+			}
+
+			for _, c := range tc.calls {
+				plainVM, err := e.TestInvoke(plainInv.PrepareInvoke(t, c.method, c.args...))
+				require.NoError(t, err, c.method)
+				optVM, err := e.TestInvoke(optInv.PrepareInvoke(t, c.method, c.args...))
+				require.NoError(t, err, c.method)
+
+				require.Equal(t, plainVM.Estack().ToArray(), optVM.Estack().ToArray(), c.method)
+				require.LessOrEqual(t, optVM.GasConsumed(), plainVM.GasConsumed(), c.method)
+				plainTotal += plainVM.GasConsumed()
+				optTotal += optVM.GasConsumed()
+			}
+		})
+	}
+	require.Less(t, optTotal, plainTotal)
+}
@@ -0,0 +1,35 @@
+package generic
+
+// Number is a set of integer types.
+type Number interface {
+	~int | ~int64
+}
+
+// Sum returns the sum of all values.
+func Sum[T Number](vals []T) T {
+	var s T
+	for _, v := range vals {
+		s += v
+	}
+	return s
+}
+
+// Set is a set of comparable values.
+type Set[T comparable] struct {
+	m map[T]bool
+}
+
+// NewSet creates a new empty set.
+func NewSet[T comparable]() *Set[T] {
+	return &Set[T]{m: make(map[T]bool)}
+}
+
+// Add adds value to the set.
+func (s *Set[T]) Add(v T) {
+	s.m[v] = true
+}
+
+// Len returns the number of values in the set.
+func (s *Set[T]) Len() int {
+	return len(s.m)
+}
@@ -0,0 +1,15 @@
+package lint
+
+import "github.com/nspcc-dev/neo-go/pkg/interop/storage"
+
+const prefixBalance = "ba"
+
+// Balance returns the balance stored with the given key.
+func Balance(key []byte) int {
+	return storage.Get(storage.GetReadOnlyContext(), append([]byte(prefixBalance), key...)).(int)
+}
+
+// Total returns the total supply.
+func Total() int {
+	return storage.Get(storage.GetReadOnlyContext(), []byte{0x74, 0x73}).(int)
+}
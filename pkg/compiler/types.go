@@ -8,7 +8,16 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
 )
 
+// typeAndValueOf returns type and value of the given expression. Type
+// parameters of the generic function instance being processed are replaced
+// with the instance type arguments.
 func (c *codegen) typeAndValueOf(e ast.Expr) types.TypeAndValue {
+	tv := c.rawTypeAndValueOf(e)
+	tv.Type = c.subst(tv.Type)
+	return tv
+}
+
+func (c *codegen) rawTypeAndValueOf(e ast.Expr) types.TypeAndValue {
 	for i := len(c.pkgInfoInline) - 1; i >= 0; i-- {
 		if tv, ok := c.pkgInfoInline[i].TypesInfo.Types[e]; ok {
 			return tv
@@ -28,16 +37,19 @@ func (c *codegen) typeAndValueOf(e ast.Expr) types.TypeAndValue {
 	return types.TypeAndValue{}
 }
 
+// typeOf returns type of the given expression. Type parameters of the generic
+// function instance being processed are replaced with the instance type
+// arguments.
 func (c *codegen) typeOf(e ast.Expr) types.Type {
 	for i := len(c.pkgInfoInline) - 1; i >= 0; i-- {
 		if typ := c.pkgInfoInline[i].TypesInfo.TypeOf(e); typ != nil {
-			return typ
+			return c.subst(typ)
 		}
 	}
 	for _, p := range c.packageCache {
 		typ := p.TypesInfo.TypeOf(e)
 		if typ != nil {
-			return typ
+			return c.subst(typ)
 		}
 	}
 	return nil
@@ -69,6 +81,11 @@ func isNumber(typ types.Type) bool {
 	return ok && t.Info()&types.IsNumeric != 0
 }
 
+func isInteger(typ types.Type) bool {
+	t, ok := typ.Underlying().(*types.Basic)
+	return ok && t.Info()&types.IsInteger != 0
+}
+
 func isString(typ types.Type) bool {
 	return isBasicTypeOfKind(typ, types.String)
 }
@@ -83,6 +100,14 @@ func isByteSlice(typ types.Type) bool {
 	return ok && isByte(t.Elem())
 }
 
+func isArray(typ types.Type) bool {
+	if typ == nil {
+		return false
+	}
+	_, ok := typ.Underlying().(*types.Array)
+	return ok
+}
+
 func toNeoType(typ types.Type) stackitem.Type {
 	if typ == nil {
 		return stackitem.AnyT
@@ -102,7 +127,7 @@ func toNeoType(typ types.Type) stackitem.Type {
 		}
 	case *types.Map:
 		return stackitem.MapT
-	case *types.Struct:
+	case *types.Struct, *types.Array:
 		return stackitem.StructT
 	case *types.Slice:
 		if isByte(t.Elem()) {
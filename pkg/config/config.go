@@ -35,6 +35,18 @@ const (
 	// DefaultMaxNEP11Tokens is the default maximum number of resulting NEP11 tokens
 	// that can be traversed by `getnep11balances` JSON-RPC handler.
 	DefaultMaxNEP11Tokens = 100
+	// DefaultMaxScannedNotifications is the default maximum number of index
+	// records scanned by `findnotifications` JSON-RPC handler per request.
+	DefaultMaxScannedNotifications = 10000
+	// DefaultMaxSimulationSteps is the default maximum number of transactions
+	// and scripts accepted by `simulatetransactions` JSON-RPC handler.
+	DefaultMaxSimulationSteps = 64
+	// DefaultMaxTraceSteps is the default maximum number of instructions
+	// returned by `tracetransaction` JSON-RPC handler.
+	DefaultMaxTraceSteps = 100000
+	// DefaultMaxTraceSize is the default maximum estimated size (in bytes) of
+	// instructions data collected by `tracetransaction` JSON-RPC handler.
+	DefaultMaxTraceSize = 64 * 1024 * 1024
 	// DefaultMaxRequestBodyBytes is the default maximum allowed size of HTTP
 	// request body in bytes.
 	DefaultMaxRequestBodyBytes = 5 * 1024 * 1024
@@ -160,6 +172,8 @@ func updateRelativePaths(relativePath string, config *Config) {
 	updatePath(&config.ApplicationConfiguration.LogPath)
 	updatePath(&config.ApplicationConfiguration.DBConfiguration.BoltDBOptions.FilePath)
 	updatePath(&config.ApplicationConfiguration.DBConfiguration.LevelDBOptions.DataDirectoryPath)
+	updatePath(&config.ApplicationConfiguration.DBConfiguration.PebbleDBOptions.DataDirectoryPath)
+	updatePath(&config.ApplicationConfiguration.P2P.BanListPath)
 	updatePath(&config.ApplicationConfiguration.Consensus.UnlockWallet.Path)
 	updatePath(&config.ApplicationConfiguration.P2PNotary.UnlockWallet.Path)
 	updatePath(&config.ApplicationConfiguration.Oracle.UnlockWallet.Path)
@@ -26,6 +26,15 @@ type Ledger struct {
 	RemoveUntraceableBlocks bool `yaml:"RemoveUntraceableBlocks"`
 	// SaveStorageBatch enables storage batch saving before every persist.
 	SaveStorageBatch bool `yaml:"SaveStorageBatch"`
+	// SaveStorageDiffs enables saving contract storage changes made by
+	// every block and transaction.
+	SaveStorageDiffs bool `yaml:"SaveStorageDiffs"`
+	// SaveNotificationIndex enables indexing of contract notifications by
+	// contract hash, event name and block height.
+	SaveNotificationIndex bool `yaml:"SaveNotificationIndex"`
+	// SaveAddressIndex enables indexing of transactions by addresses of their
+	// signers and addresses used in their notifications.
+	SaveAddressIndex bool `yaml:"SaveAddressIndex"`
 	// SkipBlockVerification allows to disable verification of received
 	// blocks (including cryptographic checks).
 	SkipBlockVerification bool `yaml:"SkipBlockVerification"`
@@ -7,6 +7,13 @@ type P2P struct {
 	// Addresses stores the node address list in the form of "[host]:[port][:announcedPort]".
 	Addresses        []string `yaml:"Addresses"`
 	AttemptConnPeers int      `yaml:"AttemptConnPeers"`
+	// BanDuration is the time a misbehaving peer is banned for.
+	BanDuration time.Duration `yaml:"BanDuration"`
+	// BanListPath is the file to persist bans to, bans are kept in memory
+	// only if it's empty.
+	BanListPath string `yaml:"BanListPath"`
+	// BanScore is the misbehavior score that makes the node ban a peer.
+	BanScore int `yaml:"BanScore"`
 	// BroadcastFactor is the factor (0-100) controlling gossip fan-out number optimization.
 	BroadcastFactor int `yaml:"BroadcastFactor"`
 	// BroadcastTxsBatchDelay is a time for txs batch collection before broadcasting them.
@@ -21,6 +21,10 @@ type (
 		MaxNEP11Tokens              int           `yaml:"MaxNEP11Tokens"`
 		MaxRequestBodyBytes         int           `yaml:"MaxRequestBodyBytes"`
 		MaxRequestHeaderBytes       int           `yaml:"MaxRequestHeaderBytes"`
+		MaxScannedNotifications     int           `yaml:"MaxScannedNotifications"`
+		MaxSimulationSteps          int           `yaml:"MaxSimulationSteps"`
+		MaxTraceSize                int           `yaml:"MaxTraceSize"`
+		MaxTraceSteps               int           `yaml:"MaxTraceSteps"`
 		MaxWebSocketClients         int           `yaml:"MaxWebSocketClients"`
 		MaxWebSocketFeeds           int           `yaml:"MaxWebSocketFeeds"`
 		MempoolSubscriptionsEnabled bool          `yaml:"MempoolSubscriptionsEnabled"`
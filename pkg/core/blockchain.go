@@ -201,6 +201,10 @@ type Blockchain struct {
 
 	memPool *mempool.Pool
 
+	// snapshotTxs contains mempool transactions for every chain snapshot
+	// made with Snapshot.
+	snapshotTxs [][]*transaction.Transaction
+
 	// postBlock is a set of callback methods which should be run under the Blockchain lock after new block is persisted.
 	// Block's transactions are passed via mempool.
 	postBlock []func(func(*transaction.Transaction, *mempool.Pool, bool) bool, *mempool.Pool, *block.Block)
@@ -583,6 +587,9 @@ func (bc *Blockchain) init() error {
 			Magic:                      uint32(bc.config.Magic),
 			Value:                      version,
 			SaveInvocations:            bc.config.SaveInvocations,
+			SaveNotificationIndex:      bc.config.Ledger.SaveNotificationIndex,
+			SaveAddressIndex:           bc.config.Ledger.SaveAddressIndex,
+			SaveStorageDiffs:           bc.config.Ledger.SaveStorageDiffs,
 		}
 		bc.dao.PutVersion(ver)
 		bc.dao.Version = ver
@@ -636,6 +643,18 @@ func (bc *Blockchain) init() error {
 		return fmt.Errorf("SaveInvocations setting mismatch (old=%v, new=%v)",
 			ver.SaveInvocations, bc.config.SaveInvocations)
 	}
+	if ver.SaveNotificationIndex != bc.config.Ledger.SaveNotificationIndex {
+		return fmt.Errorf("SaveNotificationIndex setting mismatch (old=%v, new=%v), resynchronization is required",
+			ver.SaveNotificationIndex, bc.config.Ledger.SaveNotificationIndex)
+	}
+	if ver.SaveAddressIndex != bc.config.Ledger.SaveAddressIndex {
+		return fmt.Errorf("SaveAddressIndex setting mismatch (old=%v, new=%v), resynchronization is required",
+			ver.SaveAddressIndex, bc.config.Ledger.SaveAddressIndex)
+	}
+	if ver.SaveStorageDiffs != bc.config.Ledger.SaveStorageDiffs {
+		return fmt.Errorf("SaveStorageDiffs setting mismatch (old=%v, new=%v), resynchronization is required",
+			ver.SaveStorageDiffs, bc.config.Ledger.SaveStorageDiffs)
+	}
 	bc.dao.Version = ver
 	bc.persistent.Version = ver
 
@@ -932,6 +951,71 @@ func (bc *Blockchain) Reset(height uint32) error {
 	return bc.resetStateInternal(height, none)
 }
 
+// Snapshot saves the current chain state (including mempool contents) so that
+// it can be restored later with Revert. It requires the chain to use
+// storage.SnapshotStore and returns the snapshot identifier.
+func (bc *Blockchain) Snapshot() (int, error) {
+	s, ok := bc.store.(*storage.SnapshotStore)
+	if !ok {
+		return 0, errors.New("snapshots require SnapshotStore")
+	}
+	bc.addLock.Lock()
+	defer bc.addLock.Unlock()
+	bc.lock.Lock()
+	defer bc.lock.Unlock()
+
+	if _, err := bc.persist(); err != nil {
+		return 0, fmt.Errorf("failed to persist: %w", err)
+	}
+	id := s.Snapshot()
+	bc.snapshotTxs = append(bc.snapshotTxs[:id], bc.memPool.GetVerifiedTransactions())
+	return id, nil
+}
+
+// Revert restores the chain state saved by Snapshot with the given identifier,
+// dropping all blocks, state changes and mempool transactions added after it.
+// The snapshot can be reverted to again, while all subsequent ones are removed.
+// Subscribers are not notified about the change.
+func (bc *Blockchain) Revert(id int) error {
+	s, ok := bc.store.(*storage.SnapshotStore)
+	if !ok {
+		return errors.New("snapshots require SnapshotStore")
+	}
+	bc.addLock.Lock()
+	defer bc.addLock.Unlock()
+	bc.lock.Lock()
+	defer bc.lock.Unlock()
+
+	if id < 0 || id >= len(bc.snapshotTxs) {
+		return fmt.Errorf("unknown snapshot %d", id)
+	}
+	// Flush all pending changes to drop them with the snapshot layer.
+	if _, err := bc.persist(); err != nil {
+		return fmt.Errorf("failed to persist: %w", err)
+	}
+	if err := s.Revert(id); err != nil {
+		return err
+	}
+	bc.snapshotTxs = bc.snapshotTxs[:id+1]
+
+	height, err := bc.dao.GetCurrentBlockHeight()
+	if err != nil {
+		return fmt.Errorf("failed to retrieve current block height: %w", err)
+	}
+	bc.gcBlockTimes.Purge()
+	if err := bc.stateRoot.Init(height); err != nil {
+		return fmt.Errorf("can't init MPT at height %d: %w", height, err)
+	}
+	if err := bc.resetRAMState(height, true); err != nil {
+		return err
+	}
+	bc.memPool.RemoveStale(func(*transaction.Transaction) bool { return false }, bc)
+	for _, tx := range bc.snapshotTxs[id] {
+		_ = bc.memPool.Add(tx, bc)
+	}
+	return nil
+}
+
 func (bc *Blockchain) resetStateInternal(height uint32, stage stateChangeStage) error {
 	// Cache isn't yet initialized, so retrieve block height right from DAO.
 	currHeight, err := bc.dao.GetCurrentBlockHeight()
@@ -1969,7 +2053,11 @@ func (bc *Blockchain) storeBlock(block *block.Block, txpool *mempool.Pool) error
 		appExecResults = make([]*state.AppExecResult, 0, 2+len(block.Transactions))
 		aerchan        = make(chan *state.AppExecResult, len(block.Transactions)/8) // Tested 8 and 4 with no practical difference, but feel free to test more and tune.
 		aerdone        = make(chan error)
+		storageDiffs   map[util.Uint256]state.StorageDiff
 	)
+	if bc.config.Ledger.SaveStorageDiffs {
+		storageDiffs = make(map[util.Uint256]state.StorageDiff, 1+len(block.Transactions))
+	}
 	go func() {
 		var (
 			kvcache      = aerCache
@@ -2046,6 +2134,9 @@ func (bc *Blockchain) storeBlock(block *block.Block, txpool *mempool.Pool) error
 		err := systemInterop.Exec()
 		var faultException string
 		if !v.HasFailed() {
+			if storageDiffs != nil {
+				storageDiffs[tx.Hash()] = MakeStorageDiff(systemInterop.DAO.Store.GetStorageChanges(), cache.Store)
+			}
 			_, err := systemInterop.DAO.Persist()
 			if err != nil {
 				// Release goroutines, don't care about errors, we already have one.
@@ -2059,6 +2150,9 @@ func (bc *Blockchain) storeBlock(block *block.Block, txpool *mempool.Pool) error
 				zap.Uint32("block", block.Index),
 				zap.Error(err))
 			faultException = err.Error()
+			if storageDiffs != nil {
+				storageDiffs[tx.Hash()] = state.StorageDiff{}
+			}
 		}
 		aer := &state.AppExecResult{
 			Container: tx.Hash(),
@@ -2125,6 +2219,25 @@ func (bc *Blockchain) storeBlock(block *block.Block, txpool *mempool.Pool) error
 	if aererr != nil {
 		return aererr
 	}
+	if storageDiffs != nil {
+		storageDiffs[block.Hash()] = MakeStorageDiff(cache.Store.GetStorageChanges(), bc.dao.Store)
+		for h, diff := range storageDiffs {
+			if err := aerCache.PutStorageDiff(h, diff); err != nil {
+				return fmt.Errorf("failed to store storage diff for %s: %w", h.StringLE(), err)
+			}
+		}
+	}
+	if bc.config.Ledger.SaveNotificationIndex {
+		if err := aerCache.PutNotificationIndex(block.Index, appExecResults); err != nil {
+			return fmt.Errorf("failed to store notification index: %w", err)
+		}
+	}
+	if bc.config.Ledger.SaveAddressIndex {
+		// Transaction results are between OnPersist and PostPersist ones.
+		if err := aerCache.PutAddressIndex(block, appExecResults[1:len(appExecResults)-1]); err != nil {
+			return fmt.Errorf("failed to store address index: %w", err)
+		}
+	}
 
 	bc.lock.Lock()
 	// Wait for a while if we're lagging behind the persistence routine,
@@ -2233,6 +2346,29 @@ func (bc *Blockchain) runPersist(script []byte, block *block.Block, cache *dao.S
 	}, v, nil
 }
 
+// MakeStorageDiff converts the set of storage changes into a diff using prev
+// to retrieve the values before the changes. No-op changes are omitted.
+func MakeStorageDiff(changes map[string][]byte, prev storage.Store) state.StorageDiff {
+	var diff = make(state.StorageDiff, 0, len(changes))
+	for k, v := range changes {
+		old, err := prev.Get([]byte(k))
+		if err != nil {
+			old = nil
+		}
+		if (old == nil && v == nil) || (old != nil && v != nil && bytes.Equal(old, v)) {
+			continue
+		}
+		diff = append(diff, state.StorageChange{
+			ID:  int32(binary.LittleEndian.Uint32([]byte(k[1:5]))),
+			Key: []byte(k[5:]),
+			Old: old,
+			New: bytes.Clone(v),
+		})
+	}
+	diff.Sort()
+	return diff
+}
+
 func (bc *Blockchain) handleNotification(note *state.NotificationEvent, d *dao.Simple,
 	transCache map[util.Uint160]transferData, b *block.Block, h util.Uint256) {
 	if note.Name != "Transfer" {
@@ -2565,6 +2701,30 @@ func (bc *Blockchain) GetAppExecResults(hash util.Uint256, trig trigger.Type) ([
 	return bc.dao.GetAppExecResults(hash, trig)
 }
 
+// GetStorageDiff returns contract storage changes made by the block or
+// transaction with the given hash. It's only available if SaveStorageDiffs
+// setting is enabled.
+func (bc *Blockchain) GetStorageDiff(hash util.Uint256) (state.StorageDiff, error) {
+	return bc.dao.GetStorageDiff(hash)
+}
+
+// SeekNotifications calls f for every notification emitted by the given
+// contract in blocks from start to end (inclusive) and optionally filtered by
+// the event name starting after the given cursor. It's only available if
+// SaveNotificationIndex setting is enabled, see [dao.Simple.SeekNotifications]
+// for details.
+func (bc *Blockchain) SeekNotifications(contract util.Uint160, name *string, start, end uint32, cursor []byte, f func(cursor []byte, index uint32, ev *state.ContainedNotificationEvent) bool) error {
+	return bc.dao.SeekNotifications(contract, name, start, end, cursor, f)
+}
+
+// SeekAddressTransactions calls f for every transaction touching the given
+// account starting from the newest one with block timestamp not exceeding
+// end. It's only available if SaveAddressIndex setting is enabled, see
+// [dao.Simple.PutAddressIndex] for details.
+func (bc *Blockchain) SeekAddressTransactions(acc util.Uint160, end uint64, f func(h util.Uint256, index uint32, timestamp uint64) bool) error {
+	return bc.dao.SeekAddressTransactions(acc, end, f)
+}
+
 // GetStorageItem returns an item from storage.
 func (bc *Blockchain) GetStorageItem(id int32, key []byte) state.StorageItem {
 	return bc.dao.GetStorageItem(id, key)
@@ -3227,6 +3387,67 @@ func (bc *Blockchain) GetTestHistoricVM(t trigger.Type, tx *transaction.Transact
 	if err != nil {
 		return nil, fmt.Errorf("failed to create fake block for height %d: %w", nextBlockHeight, err)
 	}
+	dTrie, err := bc.getHistoricDAO(b)
+	if err != nil {
+		return nil, err
+	}
+	systemInterop := bc.newInteropContext(t, dTrie, b, tx)
+	_ = systemInterop.SpawnVM() // All the other code suppose that the VM is ready.
+	return systemInterop, nil
+}
+
+// GetTxReplayVM returns an interop context with VM set up to re-execute the
+// persisted transaction with the given hash. The VM has the transaction script
+// loaded and its state is exactly the one the transaction was executed in
+// originally: the state of the previous block with the OnPersist and all the
+// preceding transactions of the same block applied. Changes made by the VM
+// are never persisted to the chain.
+func (bc *Blockchain) GetTxReplayVM(hash util.Uint256) (*interop.Context, error) {
+	if bc.config.Ledger.KeepOnlyLatestState {
+		return nil, errors.New("only latest state is supported")
+	}
+	tx, height, err := bc.dao.GetTransaction(hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+	b, err := bc.GetBlock(bc.GetHeaderHash(height))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block %d: %w", height, err)
+	}
+	cache, err := bc.getHistoricDAO(b)
+	if err != nil {
+		return nil, err
+	}
+	_, v, err := bc.runPersist(bc.contracts.GetPersistScript(), b, cache, trigger.OnPersist, nil)
+	if err != nil {
+		return nil, fmt.Errorf("onPersist failed: %w", err)
+	}
+	for _, prev := range b.Transactions {
+		if prev.Hash().Equals(hash) {
+			break
+		}
+		systemInterop := bc.newInteropContext(trigger.Application, cache, b, prev)
+		systemInterop.ReuseVM(v)
+		v.LoadScriptWithFlags(prev.Script, callflag.All)
+		v.GasLimit = prev.SystemFee
+		_ = systemInterop.Exec()
+		if !v.HasFailed() {
+			_, err = systemInterop.DAO.Persist()
+			if err != nil {
+				return nil, fmt.Errorf("failed to persist invocation results of %s: %w", prev.Hash().StringLE(), err)
+			}
+		}
+	}
+	systemInterop := bc.newInteropContext(trigger.Application, cache, b, tx)
+	v = systemInterop.SpawnVM()
+	v.LoadScriptWithFlags(tx.Script, callflag.All)
+	v.GasLimit = tx.SystemFee
+	return systemInterop, nil
+}
+
+// getHistoricDAO returns a DAO backed by the MPT state of the block preceding
+// the given one, it's the state the given block is processed in.
+func (bc *Blockchain) getHistoricDAO(b *block.Block) (*dao.Simple, error) {
 	var mode = mpt.ModeAll
 	if bc.config.Ledger.RemoveUntraceableBlocks {
 		if b.Index < bc.BlockHeight()-bc.GetMaxTraceableBlocks() {
@@ -3247,13 +3468,13 @@ func (bc *Blockchain) GetTestHistoricVM(t trigger.Type, tx *transaction.Transact
 	dTrie.Version = bc.dao.Version
 	// Initialize native cache before passing DAO to interop context constructor, because
 	// the constructor will call BaseExecFee/StoragePrice policy methods on the passed DAO.
-	err = bc.initializeNativeCache(b.Index, dTrie)
+	// The cache must match the state it's backed by, hardforks enabled at b.Index are
+	// initialized by the OnPersist of this block, just like for the regular processing.
+	err = bc.initializeNativeCache(b.Index-1, dTrie)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize native cache backed by historic DAO: %w", err)
 	}
-	systemInterop := bc.newInteropContext(t, dTrie, b, tx)
-	_ = systemInterop.SpawnVM() // All the other code suppose that the VM is ready.
-	return systemInterop, nil
+	return dTrie, nil
 }
 
 // GetFakeNextBlock returns fake block with the specified index and pre-filled Timestamp field.
@@ -145,6 +145,33 @@ func TestBlockchain_StartFromExistingDB(t *testing.T) {
 		require.Error(t, err)
 		require.True(t, strings.Contains(err.Error(), "KeepOnlyLatestState setting mismatch"), err)
 	})
+	t.Run("mismatch SaveNotificationIndex", func(t *testing.T) {
+		ps = newPS(t)
+		_, _, _, err := chain.NewMultiWithCustomConfigAndStoreNoCheck(t, func(c *config.Blockchain) {
+			customConfig(c)
+			c.Ledger.SaveNotificationIndex = true
+		}, ps)
+		require.Error(t, err)
+		require.True(t, strings.Contains(err.Error(), "SaveNotificationIndex setting mismatch"), err)
+	})
+	t.Run("mismatch SaveAddressIndex", func(t *testing.T) {
+		ps = newPS(t)
+		_, _, _, err := chain.NewMultiWithCustomConfigAndStoreNoCheck(t, func(c *config.Blockchain) {
+			customConfig(c)
+			c.Ledger.SaveAddressIndex = true
+		}, ps)
+		require.Error(t, err)
+		require.True(t, strings.Contains(err.Error(), "SaveAddressIndex setting mismatch"), err)
+	})
+	t.Run("mismatch SaveStorageDiffs", func(t *testing.T) {
+		ps = newPS(t)
+		_, _, _, err := chain.NewMultiWithCustomConfigAndStoreNoCheck(t, func(c *config.Blockchain) {
+			customConfig(c)
+			c.Ledger.SaveStorageDiffs = true
+		}, ps)
+		require.Error(t, err)
+		require.True(t, strings.Contains(err.Error(), "SaveStorageDiffs setting mismatch"), err)
+	})
 	t.Run("Magic mismatch", func(t *testing.T) {
 		ps = newPS(t)
 		_, _, _, err := chain.NewMultiWithCustomConfigAndStoreNoCheck(t, func(c *config.Blockchain) {
@@ -3027,3 +3054,87 @@ func TestRuntimeNotifyRefcounting(t *testing.T) {
 	}
 	cInv.InvokeFail(t, "System.Runtime.Notify failed: notification count shouldn't exceed 512", "produceNumerousNotifications", count)
 }
+
+func TestBlockchain_SnapshotRevert(t *testing.T) {
+	bc, acc := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+	gasInv := e.CommitteeInvoker(e.NativeHash(t, nativenames.Gas))
+
+	receiver := random.Uint160()
+	gasInv.Invoke(t, true, "transfer", e.CommitteeHash, receiver, 100, nil)
+	pooled := e.NewTx(t, []neotest.Signer{acc}, e.NativeHash(t, nativenames.Gas), "transfer", e.CommitteeHash, receiver, 1, nil)
+	require.NoError(t, bc.PoolTx(pooled))
+
+	id := e.Snapshot(t)
+	require.Equal(t, 0, id)
+	height := bc.BlockHeight()
+	top := e.TopBlock(t)
+
+	check := func(t *testing.T) {
+		require.Equal(t, height, bc.BlockHeight())
+		require.Equal(t, height, bc.HeaderHeight())
+		require.Equal(t, top.Hash(), bc.CurrentBlockHash())
+		e.CheckGASBalance(t, receiver, big.NewInt(100))
+		require.True(t, bc.GetMemPool().ContainsKey(pooled.Hash()))
+		require.Equal(t, 1, bc.GetMemPool().Count())
+	}
+
+	h := gasInv.Invoke(t, true, "transfer", e.CommitteeHash, receiver, 50, nil)
+	e.GenerateNewBlocks(t, 2)
+	extra := e.NewTx(t, []neotest.Signer{acc}, e.NativeHash(t, nativenames.Gas), "transfer", e.CommitteeHash, receiver, 2, nil)
+	require.NoError(t, bc.PoolTx(extra))
+	e.CheckGASBalance(t, receiver, big.NewInt(150))
+
+	e.Revert(t, id)
+	check(t)
+	_, _, err := bc.GetTransaction(h)
+	require.Error(t, err)
+	require.False(t, bc.GetMemPool().ContainsKey(extra.Hash()))
+
+	// The chain is operational after the revert and can be reverted again.
+	gasInv.Invoke(t, true, "transfer", e.CommitteeHash, receiver, 10, nil)
+	e.CheckGASBalance(t, receiver, big.NewInt(110))
+	require.Equal(t, 1, e.Snapshot(t))
+	e.Revert(t, id)
+	check(t)
+	require.Error(t, bc.Revert(1))
+	require.Error(t, bc.Revert(-1))
+
+	t.Run("unsupported store", func(t *testing.T) {
+		bc, _ := chain.NewSingleWithCustomConfigAndStore(t, nil, storage.NewMemoryStore(), true)
+		_, err := bc.Snapshot()
+		require.Error(t, err)
+		require.Error(t, bc.Revert(0))
+	})
+}
+
+func TestBlockchain_GetTestHistoricVMHardfork(t *testing.T) {
+	const echidnaHeight = 3
+
+	bc, acc := chain.NewSingleWithCustomConfig(t, func(cfg *config.Blockchain) {
+		cfg.Hardforks = map[string]uint32{
+			config.HFAspidochelone.String(): 0,
+			config.HFBasilisk.String():      0,
+			config.HFCockatrice.String():    0,
+			config.HFDomovoi.String():       0,
+			config.HFEchidna.String():       echidnaHeight,
+		}
+	})
+	e := neotest.NewExecutor(t, bc, acc, acc)
+	gasInvoker := e.CommitteeInvoker(nativehashes.GasToken)
+	e.GenerateNewBlocks(t, echidnaHeight-1)
+	h := gasInvoker.Invoke(t, true, "transfer", acc.ScriptHash(), util.Uint160{1, 2, 3}, 1, nil)
+	require.Equal(t, uint32(echidnaHeight), bc.BlockHeight())
+
+	// The state of the block preceding the hardfork one is used.
+	for nextH := uint32(1); nextH <= echidnaHeight+1; nextH++ {
+		ic, err := bc.GetTestHistoricVM(trigger.Application, nil, nextH)
+		require.NoError(t, err, nextH)
+		ic.Finalize()
+	}
+
+	ic, err := bc.GetTxReplayVM(h)
+	require.NoError(t, err)
+	require.NoError(t, ic.Exec())
+	require.Equal(t, vmstate.Halt, ic.VM.State())
+}
@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	iocore "io"
+	"math"
 	"math/big"
 	"sync"
 
@@ -22,6 +23,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/trigger"
 	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"github.com/nspcc-dev/neo-go/pkg/vm/vmstate"
 )
 
 // HasTransaction errors.
@@ -252,6 +254,402 @@ func (dao *Simple) PutTokenTransferLog(acc util.Uint160, start uint64, index uin
 
 // -- end transfer log.
 
+// -- start storage diff.
+
+func (dao *Simple) makeStorageDiffKey(hash util.Uint256) []byte {
+	key := dao.getKeyBuf(1 + util.Uint256Size)
+	key[0] = byte(storage.DataStorageDiff)
+	copy(key[1:], hash.BytesBE())
+	return key
+}
+
+// GetStorageDiff returns contract storage changes made by the block or
+// transaction with the given hash.
+func (dao *Simple) GetStorageDiff(hash util.Uint256) (state.StorageDiff, error) {
+	var diff state.StorageDiff
+	err := dao.GetAndDecode(&diff, dao.makeStorageDiffKey(hash))
+	if err != nil {
+		return nil, err
+	}
+	return diff, nil
+}
+
+// PutStorageDiff saves contract storage changes made by the block or
+// transaction with the given hash.
+func (dao *Simple) PutStorageDiff(hash util.Uint256, diff state.StorageDiff) error {
+	return dao.putWithBuffer(&diff, dao.makeStorageDiffKey(hash), dao.getDataBuf())
+}
+
+// deleteStorageDiff removes storage diff of the block or transaction with the
+// given hash if it's present. It does nothing if storage diffs are not saved.
+func (dao *Simple) deleteStorageDiff(hash util.Uint256) {
+	if !dao.Version.SaveStorageDiffs {
+		return
+	}
+	key := dao.makeStorageDiffKey(hash)
+	if _, err := dao.Store.Get(key); err == nil {
+		dao.Store.Delete(key)
+	}
+}
+
+// -- end storage diff.
+
+// -- start index key list.
+
+// putIndexKeyList saves the list of index record keys added by some block
+// under the given key, it does nothing if the list is empty. Such lists allow
+// to drop index records along with the block.
+func (dao *Simple) putIndexKeyList(key []byte, keys [][]byte) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	buf := dao.getDataBuf()
+	buf.WriteVarUint(uint64(len(keys)))
+	for _, k := range keys {
+		buf.WriteVarBytes(k)
+	}
+	if buf.Err != nil {
+		return buf.Err
+	}
+	dao.Store.Put(key, buf.Bytes())
+	return nil
+}
+
+// deleteIndexKeyList removes all index records from the list saved under the
+// given key along with the list itself if it's present.
+func (dao *Simple) deleteIndexKeyList(key []byte) error {
+	v, err := dao.Store.Get(key)
+	if err != nil {
+		return nil
+	}
+	r := io.NewBinReaderFromBuf(v)
+	n := r.ReadVarUint()
+	for range n {
+		k := r.ReadVarBytes()
+		if r.Err != nil {
+			return fmt.Errorf("%w: bad index key list: %w", ErrInternalDBInconsistency, r.Err)
+		}
+		dao.Store.Delete(k)
+	}
+	dao.Store.Delete(key)
+	return nil
+}
+
+// -- end index key list.
+
+// -- start notification index.
+
+// Notification index record types, this byte follows the
+// storage.IXNotifications prefix.
+const (
+	// notificationIndexEntry is a notification record, its key is the
+	// contract hash (BE) + event name length + event name + block index (BE)
+	// + notification number within the block (BE), its value is the
+	// container hash (BE) + serialized notification item.
+	notificationIndexEntry byte = iota
+	// notificationIndexBlock is a list of all notification record keys
+	// added by the block with the given index (BE), it's used to drop the
+	// index for this block.
+	notificationIndexBlock
+)
+
+// notificationIndexPrefixLen is the length of the notification record key
+// part preceding the event name (prefix, record type, contract hash and name
+// length).
+const notificationIndexPrefixLen = 2 + util.Uint160Size + 1
+
+func makeNotificationIndexPrefix(contract util.Uint160, name *string) []byte {
+	var l = notificationIndexPrefixLen - 1
+	if name != nil {
+		l += 1 + len(*name)
+	}
+	key := make([]byte, l, l+8)
+	key[0] = byte(storage.IXNotifications)
+	key[1] = notificationIndexEntry
+	copy(key[2:], contract.BytesBE())
+	if name != nil {
+		key[notificationIndexPrefixLen-1] = byte(len(*name))
+		copy(key[notificationIndexPrefixLen:], *name)
+	}
+	return key
+}
+
+func (dao *Simple) makeNotificationIndexBlockKey(index uint32) []byte {
+	key := dao.getKeyBuf(2 + 4)
+	key[0] = byte(storage.IXNotifications)
+	key[1] = notificationIndexBlock
+	binary.BigEndian.PutUint32(key[2:], index)
+	return key
+}
+
+// PutNotificationIndex adds notifications emitted by successful executions
+// from the given list of application execution results of the block with the
+// given index to the notification index.
+func (dao *Simple) PutNotificationIndex(index uint32, aers []*state.AppExecResult) error {
+	var (
+		keys [][]byte
+		num  uint32
+		buf  = dao.getDataBuf()
+	)
+	for _, aer := range aers {
+		if aer.VMState != vmstate.Halt {
+			continue
+		}
+		for i := range aer.Events {
+			ev := &aer.Events[i]
+			key := makeNotificationIndexPrefix(ev.ScriptHash, &ev.Name)
+			key = binary.BigEndian.AppendUint32(key, index)
+			key = binary.BigEndian.AppendUint32(key, num)
+			num++
+
+			buf.Reset()
+			buf.WriteBytes(aer.Container.BytesBE())
+			b, err := dao.GetItemCtx().Serialize(ev.Item, false)
+			if err != nil {
+				return err
+			}
+			buf.WriteBytes(b)
+			dao.Store.Put(key, buf.Bytes())
+			keys = append(keys, key)
+		}
+	}
+	return dao.putIndexKeyList(dao.makeNotificationIndexBlockKey(index), keys)
+}
+
+// SeekNotifications calls f for every indexed notification emitted by the
+// given contract in blocks from start to end (inclusive). If name is not nil,
+// only notifications with this name are passed to f. Notifications are
+// ordered by name first (if it's not specified) and then by block index and
+// execution order. If cursor is not nil, iteration starts after the
+// notification it points to. Every notification is passed to f along with its
+// cursor which is only valid until f returns. Iteration stops when f returns
+// false.
+func (dao *Simple) SeekNotifications(contract util.Uint160, name *string, start, end uint32, cursor []byte, f func(cursor []byte, index uint32, ev *state.ContainedNotificationEvent) bool) error {
+	var (
+		err    error
+		stop   bool
+		prefix = makeNotificationIndexPrefix(contract, nil)
+		pos    []byte
+		part   []byte // Name length and name.
+	)
+	if cursor != nil {
+		// The least key following the cursor.
+		pos = append(bytes.Clone(cursor), 0)
+	}
+	if name != nil {
+		part = append([]byte{byte(len(*name))}, *name...)
+	}
+	for {
+		if name == nil {
+			// Find the next event name.
+			part = nil
+			dao.Store.Seek(storage.SeekRange{Prefix: prefix, Start: pos}, func(k, _ []byte) bool {
+				rest := k[len(prefix):]
+				if len(rest) < 1 || len(rest) < 1+int(rest[0]) {
+					err = fmt.Errorf("%w: bad notification index key", ErrInternalDBInconsistency)
+					return false
+				}
+				part = bytes.Clone(rest[:1+int(rest[0])])
+				return false
+			})
+			if err != nil || part == nil {
+				return err
+			}
+		}
+		// Records of this name are ordered by block index, so the search
+		// starts from the first block of the range.
+		if from := binary.BigEndian.AppendUint32(bytes.Clone(part), start); bytes.Compare(pos, from) < 0 {
+			pos = from
+		}
+		dao.Store.Seek(storage.SeekRange{Prefix: prefix, Start: pos}, func(k, v []byte) bool {
+			rest := k[len(prefix):]
+			if !bytes.HasPrefix(rest, part) {
+				return false
+			}
+			if len(rest) != len(part)+8 || len(v) < util.Uint256Size {
+				err = fmt.Errorf("%w: bad notification index record", ErrInternalDBInconsistency)
+				return false
+			}
+			index := binary.BigEndian.Uint32(rest[len(part):])
+			if index > end {
+				return false
+			}
+			ev := &state.ContainedNotificationEvent{
+				NotificationEvent: state.NotificationEvent{
+					ScriptHash: contract,
+					Name:       string(part[1:]),
+				},
+			}
+			ev.Container, err = util.Uint256DecodeBytesBE(v[:util.Uint256Size])
+			if err != nil {
+				return false
+			}
+			r := io.NewBinReaderFromBuf(v[util.Uint256Size:])
+			item := stackitem.DecodeBinary(r)
+			if r.Err != nil {
+				err = r.Err
+				return false
+			}
+			arr, ok := item.Value().([]stackitem.Item)
+			if !ok {
+				err = fmt.Errorf("%w: bad notification item type %s", ErrInternalDBInconsistency, item.Type())
+				return false
+			}
+			ev.Item = stackitem.NewArray(arr)
+			stop = !f(rest, index, ev)
+			return !stop
+		})
+		if err != nil || stop || name != nil {
+			return err
+		}
+		// Skip all the remaining records of this name.
+		pos = binary.BigEndian.AppendUint64(part, math.MaxUint64)
+		pos = append(pos, 0)
+	}
+}
+
+// deleteNotificationIndex removes notification index records of the block
+// with the given index if they're present.
+func (dao *Simple) deleteNotificationIndex(index uint32) error {
+	err := dao.deleteIndexKeyList(dao.makeNotificationIndexBlockKey(index))
+	if err != nil {
+		return fmt.Errorf("notification index of block %d: %w", index, err)
+	}
+	return nil
+}
+
+// -- end notification index.
+
+// -- start address index.
+
+// Address index record types, this byte follows the
+// storage.IXAddressTransactions prefix.
+const (
+	// addressIndexEntry is a transaction record, its key is the account hash
+	// (BE) + block timestamp (BE) + transaction index within the block (BE),
+	// its value is the transaction hash (BE) + block index (LE).
+	addressIndexEntry byte = iota
+	// addressIndexBlock is a list of all transaction record keys added by
+	// the block with the given index (BE), it's used to drop the index for
+	// this block.
+	addressIndexBlock
+)
+
+// addressIndexPrefixLen is the length of the transaction record key part
+// preceding the block timestamp (prefix, record type and account hash).
+const addressIndexPrefixLen = 2 + util.Uint160Size
+
+func makeAddressIndexPrefix(acc util.Uint160) []byte {
+	key := make([]byte, addressIndexPrefixLen, addressIndexPrefixLen+8+4)
+	key[0] = byte(storage.IXAddressTransactions)
+	key[1] = addressIndexEntry
+	copy(key[2:], acc.BytesBE())
+	return key
+}
+
+func (dao *Simple) makeAddressIndexBlockKey(index uint32) []byte {
+	key := dao.getKeyBuf(2 + 4)
+	key[0] = byte(storage.IXAddressTransactions)
+	key[1] = addressIndexBlock
+	binary.BigEndian.PutUint32(key[2:], index)
+	return key
+}
+
+// PutAddressIndex adds transactions of the given block to the address index.
+// Every transaction is indexed by its signers and by all addresses (20-byte
+// strings) found in the top-level arguments of notifications emitted by it if
+// its execution was successful. Application execution results must be given
+// for all block transactions in the same order.
+func (dao *Simple) PutAddressIndex(b *block.Block, aers []*state.AppExecResult) error {
+	if len(aers) != len(b.Transactions) {
+		return fmt.Errorf("%d execution results for %d transactions", len(aers), len(b.Transactions))
+	}
+	var (
+		keys [][]byte
+		accs []util.Uint160
+		seen = make(map[util.Uint160]struct{})
+		val  [util.Uint256Size + 4]byte
+	)
+	add := func(acc util.Uint160) {
+		if _, ok := seen[acc]; !ok {
+			seen[acc] = struct{}{}
+			accs = append(accs, acc)
+		}
+	}
+	binary.LittleEndian.PutUint32(val[util.Uint256Size:], b.Index)
+	for i, tx := range b.Transactions {
+		h := tx.Hash()
+		if aers[i].Container != h {
+			return fmt.Errorf("execution result for %s doesn't match transaction %s", aers[i].Container.StringLE(), h.StringLE())
+		}
+		accs = accs[:0]
+		clear(seen)
+		for _, s := range tx.Signers {
+			add(s.Account)
+		}
+		if aers[i].VMState == vmstate.Halt {
+			for _, ev := range aers[i].Events {
+				for _, it := range ev.Item.Value().([]stackitem.Item) {
+					if t := it.Type(); t != stackitem.ByteArrayT && t != stackitem.BufferT {
+						continue
+					}
+					bs, err := it.TryBytes()
+					if err != nil || len(bs) != util.Uint160Size {
+						continue
+					}
+					add(util.Uint160(bs))
+				}
+			}
+		}
+		copy(val[:], h.BytesBE())
+		for _, acc := range accs {
+			key := makeAddressIndexPrefix(acc)
+			key = binary.BigEndian.AppendUint64(key, b.Timestamp)
+			key = binary.BigEndian.AppendUint32(key, uint32(i))
+			dao.Store.Put(key, val[:])
+			keys = append(keys, key)
+		}
+	}
+	return dao.putIndexKeyList(dao.makeAddressIndexBlockKey(b.Index), keys)
+}
+
+// SeekAddressTransactions calls f for every indexed transaction touching the
+// given account starting from the newest one with block timestamp not
+// exceeding end. Iteration stops when f returns false.
+func (dao *Simple) SeekAddressTransactions(acc util.Uint160, end uint64, f func(h util.Uint256, index uint32, timestamp uint64) bool) error {
+	var (
+		err   error
+		start = binary.BigEndian.AppendUint64(nil, end)
+	)
+	start = binary.BigEndian.AppendUint32(start, math.MaxUint32)
+	dao.Store.Seek(storage.SeekRange{
+		Prefix:    makeAddressIndexPrefix(acc),
+		Start:     start,
+		Backwards: true,
+	}, func(k, v []byte) bool {
+		if len(k) != addressIndexPrefixLen+8+4 || len(v) != util.Uint256Size+4 {
+			err = fmt.Errorf("%w: bad address index record", ErrInternalDBInconsistency)
+			return false
+		}
+		var h util.Uint256
+		copy(h[:], v) // Both are BE.
+		return f(h, binary.LittleEndian.Uint32(v[util.Uint256Size:]), binary.BigEndian.Uint64(k[addressIndexPrefixLen:]))
+	})
+	return err
+}
+
+// deleteAddressIndex removes address index records of the block with the
+// given index if they're present.
+func (dao *Simple) deleteAddressIndex(index uint32) error {
+	err := dao.deleteIndexKeyList(dao.makeAddressIndexBlockKey(index))
+	if err != nil {
+		return fmt.Errorf("address index of block %d: %w", index, err)
+	}
+	return nil
+}
+
+// -- end address index.
+
 // -- start notification event.
 
 func (dao *Simple) makeExecutableKey(hash util.Uint256) []byte {
@@ -459,6 +857,9 @@ type Version struct {
 	Magic                      uint32
 	Value                      string
 	SaveInvocations            bool
+	SaveNotificationIndex      bool
+	SaveAddressIndex           bool
+	SaveStorageDiffs           bool
 }
 
 const (
@@ -467,6 +868,9 @@ const (
 	p2pStateExchangeExtensionsBit
 	keepOnlyLatestStateBit
 	saveInvocationsBit
+	saveNotificationIndexBit
+	saveAddressIndexBit
+	saveStorageDiffsBit
 )
 
 // FromBytes decodes v from a byte-slice.
@@ -495,6 +899,9 @@ func (v *Version) FromBytes(data []byte) error {
 	v.P2PStateExchangeExtensions = data[i+2]&p2pStateExchangeExtensionsBit != 0
 	v.KeepOnlyLatestState = data[i+2]&keepOnlyLatestStateBit != 0
 	v.SaveInvocations = data[i+2]&saveInvocationsBit != 0
+	v.SaveNotificationIndex = data[i+2]&saveNotificationIndexBit != 0
+	v.SaveAddressIndex = data[i+2]&saveAddressIndexBit != 0
+	v.SaveStorageDiffs = data[i+2]&saveStorageDiffsBit != 0
 
 	m := i + 3
 	if len(data) == m+4 {
@@ -521,6 +928,15 @@ func (v *Version) Bytes() []byte {
 	if v.SaveInvocations {
 		mask |= saveInvocationsBit
 	}
+	if v.SaveNotificationIndex {
+		mask |= saveNotificationIndexBit
+	}
+	if v.SaveAddressIndex {
+		mask |= saveAddressIndexBit
+	}
+	if v.SaveStorageDiffs {
+		mask |= saveStorageDiffsBit
+	}
 	res := append([]byte(v.Value), '\x00', byte(v.StoragePrefix), mask)
 	res = binary.LittleEndian.AppendUint32(res, v.Magic)
 	return res
@@ -832,9 +1248,17 @@ func (dao *Simple) DeleteBlock(h util.Uint256) (uint64, error) {
 		return 0, err
 	}
 	dao.Store.Delete(key)
+	dao.deleteStorageDiff(h)
+	if err := dao.deleteNotificationIndex(b.Index); err != nil {
+		return 0, err
+	}
+	if err := dao.deleteAddressIndex(b.Index); err != nil {
+		return 0, err
+	}
 
 	for _, tx := range b.Transactions {
-		copy(key[1:], tx.Hash().BytesBE())
+		dao.deleteStorageDiff(tx.Hash())
+		key = dao.makeExecutableKey(tx.Hash())
 		dao.Store.Delete(key)
 		for _, attr := range tx.GetAttributes(transaction.ConflictsT) {
 			hash := attr.Value.(*transaction.Conflicts).Hash
@@ -1,6 +1,7 @@
 package dao
 
 import (
+	"bytes"
 	"encoding/binary"
 	"testing"
 
@@ -14,6 +15,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
 	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"github.com/nspcc-dev/neo-go/pkg/vm/vmstate"
 	"github.com/stretchr/testify/require"
 )
 
@@ -116,6 +118,249 @@ func TestPutGetBlock(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestPutGetStorageDiff(t *testing.T) {
+	dao := NewSimple(storage.NewMemoryStore(), false)
+	dao.Version.SaveStorageDiffs = true
+	b := &block.Block{
+		Header: block.Header{
+			Script: transaction.Witness{
+				VerificationScript: []byte{byte(opcode.PUSH1)},
+				InvocationScript:   []byte{byte(opcode.NOP)},
+			},
+		},
+		Transactions: []*transaction.Transaction{transaction.New([]byte{byte(opcode.PUSH1)}, 1)},
+	}
+	tx := b.Transactions[0]
+	tx.Signers = []transaction.Signer{{}}
+	tx.Scripts = []transaction.Witness{{}}
+	require.NoError(t, dao.StoreAsBlock(b, nil, nil))
+	require.NoError(t, dao.StoreAsTransaction(tx, 0, nil))
+
+	_, err := dao.GetStorageDiff(b.Hash())
+	require.ErrorIs(t, err, storage.ErrKeyNotFound)
+
+	diff := state.StorageDiff{{ID: 1, Key: []byte{1}, New: []byte{2}}}
+	require.NoError(t, dao.PutStorageDiff(b.Hash(), diff))
+	require.NoError(t, dao.PutStorageDiff(tx.Hash(), state.StorageDiff{}))
+	actual, err := dao.GetStorageDiff(b.Hash())
+	require.NoError(t, err)
+	require.Equal(t, diff, actual)
+	actual, err = dao.GetStorageDiff(tx.Hash())
+	require.NoError(t, err)
+	require.Empty(t, actual)
+
+	_, err = dao.DeleteBlock(b.Hash())
+	require.NoError(t, err)
+	_, err = dao.GetStorageDiff(b.Hash())
+	require.ErrorIs(t, err, storage.ErrKeyNotFound)
+	_, err = dao.GetStorageDiff(tx.Hash())
+	require.ErrorIs(t, err, storage.ErrKeyNotFound)
+}
+
+func TestPutSeekNotificationIndex(t *testing.T) {
+	dao := NewSimple(storage.NewMemoryStore(), false)
+	var (
+		c1, c2 = util.Uint160{1}, util.Uint160{2}
+		txHash = util.Uint256{3}
+		newEv  = func(c util.Uint160, name string, v int64) state.NotificationEvent {
+			return state.NotificationEvent{ScriptHash: c, Name: name, Item: stackitem.NewArray([]stackitem.Item{stackitem.Make(v)})}
+		}
+		putBlock = func(index uint32) util.Uint256 {
+			b := &block.Block{
+				Header: block.Header{
+					Index: index,
+					Script: transaction.Witness{
+						VerificationScript: []byte{byte(opcode.PUSH1)},
+						InvocationScript:   []byte{byte(opcode.NOP)},
+					},
+				},
+			}
+			require.NoError(t, dao.StoreAsBlock(b, nil, nil))
+			return b.Hash()
+		}
+	)
+	h1 := putBlock(1)
+	require.NoError(t, dao.PutNotificationIndex(1, []*state.AppExecResult{{
+		Container: h1,
+		Execution: state.Execution{VMState: vmstate.Halt, Events: []state.NotificationEvent{newEv(c1, "Transfer", 1)}},
+	}, {
+		Container: txHash,
+		Execution: state.Execution{VMState: vmstate.Fault, Events: []state.NotificationEvent{newEv(c1, "Transfer", 2)}},
+	}, {
+		Container: txHash,
+		Execution: state.Execution{VMState: vmstate.Halt, Events: []state.NotificationEvent{newEv(c1, "Mint", 3), newEv(c2, "Transfer", 4), newEv(c1, "Transfer", 5)}},
+	}}))
+	h2 := putBlock(2)
+	require.NoError(t, dao.PutNotificationIndex(2, []*state.AppExecResult{{
+		Container: h2,
+		Execution: state.Execution{VMState: vmstate.Halt, Events: []state.NotificationEvent{newEv(c1, "Transfer", 6)}},
+	}}))
+
+	type found struct {
+		index uint32
+		name  string
+		value int64
+	}
+	seek := func(c util.Uint160, name *string, start, end uint32) []found {
+		var res []found
+		require.NoError(t, dao.SeekNotifications(c, name, start, end, nil, func(_ []byte, index uint32, ev *state.ContainedNotificationEvent) bool {
+			require.Equal(t, c, ev.ScriptHash)
+			v, err := ev.Item.Value().([]stackitem.Item)[0].TryInteger()
+			require.NoError(t, err)
+			res = append(res, found{index, ev.Name, v.Int64()})
+			return true
+		}))
+		return res
+	}
+	transfer := "Transfer"
+	require.Equal(t, []found{{1, "Mint", 3}, {1, "Transfer", 1}, {1, "Transfer", 5}, {2, "Transfer", 6}}, seek(c1, nil, 0, 2))
+	require.Equal(t, []found{{1, "Mint", 3}, {1, "Transfer", 1}, {1, "Transfer", 5}}, seek(c1, nil, 0, 1))
+	require.Equal(t, []found{{2, "Transfer", 6}}, seek(c1, nil, 2, 10))
+	require.Equal(t, []found{{1, "Transfer", 1}, {1, "Transfer", 5}, {2, "Transfer", 6}}, seek(c1, &transfer, 0, 2))
+	require.Equal(t, []found{{2, "Transfer", 6}}, seek(c1, &transfer, 2, 2))
+	require.Equal(t, []found{{1, "Transfer", 4}}, seek(c2, nil, 0, 2))
+	require.Empty(t, seek(c2, &transfer, 2, 2))
+	require.Empty(t, seek(util.Uint160{4}, nil, 0, 2))
+
+	var cnt int
+	require.NoError(t, dao.SeekNotifications(c1, nil, 0, 2, nil, func([]byte, uint32, *state.ContainedNotificationEvent) bool {
+		cnt++
+		return false
+	}))
+	require.Equal(t, 1, cnt)
+
+	// seekByOne retrieves notifications one by one resuming from the cursor.
+	seekByOne := func(c util.Uint160, name *string, start, end uint32) []found {
+		var (
+			res    []found
+			cursor []byte
+		)
+		for {
+			var next []byte
+			require.NoError(t, dao.SeekNotifications(c, name, start, end, cursor, func(cur []byte, index uint32, ev *state.ContainedNotificationEvent) bool {
+				v, err := ev.Item.Value().([]stackitem.Item)[0].TryInteger()
+				require.NoError(t, err)
+				res = append(res, found{index, ev.Name, v.Int64()})
+				next = bytes.Clone(cur)
+				return false
+			}))
+			if next == nil {
+				return res
+			}
+			cursor = next
+		}
+	}
+	require.Equal(t, seek(c1, nil, 0, 2), seekByOne(c1, nil, 0, 2))
+	require.Equal(t, seek(c1, nil, 2, 2), seekByOne(c1, nil, 2, 2))
+	require.Equal(t, seek(c1, &transfer, 0, 2), seekByOne(c1, &transfer, 0, 2))
+
+	// A cursor of some other name only moves the search forward.
+	var mintCursor []byte
+	require.NoError(t, dao.SeekNotifications(c1, nil, 0, 2, nil, func(cur []byte, _ uint32, _ *state.ContainedNotificationEvent) bool {
+		mintCursor = bytes.Clone(cur)
+		return false
+	}))
+	var afterMint []found
+	require.NoError(t, dao.SeekNotifications(c1, &transfer, 0, 2, mintCursor, func(_ []byte, index uint32, ev *state.ContainedNotificationEvent) bool {
+		afterMint = append(afterMint, found{index: index, name: ev.Name})
+		return true
+	}))
+	require.Len(t, afterMint, 3)
+
+	_, err := dao.DeleteBlock(h1)
+	require.NoError(t, err)
+	require.Equal(t, []found{{2, "Transfer", 6}}, seek(c1, nil, 0, 2))
+	require.Empty(t, seek(c2, nil, 0, 2))
+}
+
+func TestPutSeekAddressIndex(t *testing.T) {
+	dao := NewSimple(storage.NewMemoryStore(), false)
+	var (
+		acc1, acc2, acc3 = util.Uint160{1}, util.Uint160{2}, util.Uint160{3}
+		newTx            = func(nonce uint32, signers ...util.Uint160) *transaction.Transaction {
+			tx := transaction.New([]byte{byte(opcode.PUSH1)}, 1)
+			tx.Nonce = nonce
+			for _, s := range signers {
+				tx.Signers = append(tx.Signers, transaction.Signer{Account: s})
+				tx.Scripts = append(tx.Scripts, transaction.Witness{})
+			}
+			return tx
+		}
+		newBlock = func(index uint32, ts uint64, txs ...*transaction.Transaction) *block.Block {
+			b := &block.Block{
+				Header: block.Header{
+					Index:     index,
+					Timestamp: ts,
+					Script: transaction.Witness{
+						VerificationScript: []byte{byte(opcode.PUSH1)},
+						InvocationScript:   []byte{byte(opcode.NOP)},
+					},
+				},
+				Transactions: txs,
+			}
+			require.NoError(t, dao.StoreAsBlock(b, nil, nil))
+			return b
+		}
+		newAer = func(tx *transaction.Transaction, st vmstate.State, args ...stackitem.Item) *state.AppExecResult {
+			return &state.AppExecResult{
+				Container: tx.Hash(),
+				Execution: state.Execution{VMState: st, Events: []state.NotificationEvent{{
+					ScriptHash: util.Uint160{42},
+					Name:       "Transfer",
+					Item:       stackitem.NewArray(args),
+				}}},
+			}
+		}
+	)
+	tx1 := newTx(1, acc1)
+	tx2 := newTx(2, acc1, acc2)
+	tx3 := newTx(3, acc2)
+	b1 := newBlock(1, 1000, tx1, tx2)
+	require.NoError(t, dao.PutAddressIndex(b1, []*state.AppExecResult{
+		newAer(tx1, vmstate.Halt, stackitem.Make(acc1.BytesBE()), stackitem.Make(acc3.BytesBE()), stackitem.Make(1)),
+		newAer(tx2, vmstate.Fault, stackitem.Make(acc3.BytesBE())),
+	}))
+	b2 := newBlock(2, 2000, tx3)
+	require.NoError(t, dao.PutAddressIndex(b2, []*state.AppExecResult{
+		newAer(tx3, vmstate.Halt, stackitem.NewBuffer(acc3.BytesBE()), stackitem.Make([]byte{1, 2, 3})),
+	}))
+	require.Error(t, dao.PutAddressIndex(b2, []*state.AppExecResult{newAer(tx1, vmstate.Halt)}))
+	require.Error(t, dao.PutAddressIndex(b2, nil))
+
+	type found struct {
+		h     util.Uint256
+		index uint32
+		ts    uint64
+	}
+	seek := func(acc util.Uint160, end uint64) []found {
+		var res []found
+		require.NoError(t, dao.SeekAddressTransactions(acc, end, func(h util.Uint256, index uint32, ts uint64) bool {
+			res = append(res, found{h, index, ts})
+			return true
+		}))
+		return res
+	}
+	require.Equal(t, []found{{tx2.Hash(), 1, 1000}, {tx1.Hash(), 1, 1000}}, seek(acc1, 5000))
+	require.Equal(t, []found{{tx3.Hash(), 2, 2000}, {tx2.Hash(), 1, 1000}}, seek(acc2, 5000))
+	require.Equal(t, []found{{tx3.Hash(), 2, 2000}, {tx1.Hash(), 1, 1000}}, seek(acc3, 2000))
+	require.Equal(t, []found{{tx1.Hash(), 1, 1000}}, seek(acc3, 1999))
+	require.Empty(t, seek(acc3, 999))
+	require.Empty(t, seek(util.Uint160{4}, 5000))
+
+	var cnt int
+	require.NoError(t, dao.SeekAddressTransactions(acc1, 5000, func(util.Uint256, uint32, uint64) bool {
+		cnt++
+		return false
+	}))
+	require.Equal(t, 1, cnt)
+
+	_, err := dao.DeleteBlock(b1.Hash())
+	require.NoError(t, err)
+	require.Empty(t, seek(acc1, 5000))
+	require.Equal(t, []found{{tx3.Hash(), 2, 2000}}, seek(acc2, 5000))
+	require.Equal(t, []found{{tx3.Hash(), 2, 2000}}, seek(acc3, 5000))
+}
+
 func TestGetVersion_NoVersion(t *testing.T) {
 	dao := NewSimple(storage.NewMemoryStore(), false)
 	version, err := dao.GetVersion()
@@ -126,10 +371,13 @@ func TestGetVersion_NoVersion(t *testing.T) {
 func TestGetVersion(t *testing.T) {
 	dao := NewSimple(storage.NewMemoryStore(), false)
 	expected := Version{
-		StoragePrefix:     0x42,
-		P2PSigExtensions:  true,
-		StateRootInHeader: true,
-		Value:             "testVersion",
+		StoragePrefix:         0x42,
+		P2PSigExtensions:      true,
+		StateRootInHeader:     true,
+		SaveNotificationIndex: true,
+		SaveAddressIndex:      true,
+		SaveStorageDiffs:      true,
+		Value:                 "testVersion",
 	}
 	dao.PutVersion(expected)
 	actual, err := dao.GetVersion()
@@ -505,6 +505,53 @@ func (m *Management) Update(ic *interop.Context, hash util.Uint160, neff *nef.Fi
 	return &contract, nil
 }
 
+// SetContract puts the contract with the given hash, NEF and manifest into the
+// given DAO bypassing the deployment procedure, it's intended to be used for
+// test invocations with overridden state only. An existing contract keeps its
+// ID, storage and update counter (NEF or manifest can be nil to keep the old
+// one), a new contract gets the next available ID and requires both. It doesn't
+// run _deploy method, doesn't emit notification and doesn't check that the
+// hash corresponds to the NEF and manifest. Native contracts can't be changed.
+func (m *Management) SetContract(ic *interop.Context, hash util.Uint160, neff *nef.File, manif *manifest.Manifest) (*state.Contract, error) {
+	var contract state.Contract
+
+	oldcontract, err := GetContract(ic.DAO, m.ID, hash)
+	if err == nil {
+		if oldcontract.ID < 0 {
+			return nil, errors.New("native contract can't be changed")
+		}
+		contract = *oldcontract // Make a copy, don't ruin (potentially) cached contract.
+	} else {
+		if neff == nil || manif == nil {
+			return nil, errors.New("both NEF and manifest are required for a new contract")
+		}
+		contract.Hash = hash
+		contract.ID, err = m.getNextContractID(ic.DAO)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if neff != nil {
+		contract.NEF = *neff
+	}
+	if manif != nil {
+		err = manif.IsValid(hash, false)
+		if err != nil {
+			return nil, fmt.Errorf("invalid manifest: %w", err)
+		}
+		contract.Manifest = *manif
+	}
+	err = checkScriptAndMethods(ic, contract.NEF.Script, contract.Manifest.ABI.Methods)
+	if err != nil {
+		return nil, err
+	}
+	err = PutContractState(ic.DAO, m.ID, &contract)
+	if err != nil {
+		return nil, err
+	}
+	return &contract, nil
+}
+
 // destroy is an implementation of destroy update method, it's run under
 // VM protections, so it's OK for it to panic instead of returning errors.
 func (m *Management) destroy(ic *interop.Context, sis []stackitem.Item) stackitem.Item {
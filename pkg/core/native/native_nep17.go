@@ -275,6 +275,28 @@ func (c *nep17TokenNative) balanceOfInternal(d *dao.Simple, h util.Uint160) *big
 	return balance
 }
 
+// SetBalance sets the balance of the given account to the specified amount
+// adjusting the total supply (and votes for NEO) accordingly. It doesn't emit
+// notifications and doesn't distribute GAS, it's intended to be used for test
+// invocations with overridden state only.
+func (c *nep17TokenNative) SetBalance(ic *interop.Context, h util.Uint160, amount *big.Int) error {
+	if amount.Sign() < 0 {
+		return errors.New("negative balance")
+	}
+	diff := new(big.Int).Sub(amount, c.balanceOfInternal(ic.DAO, h))
+	if diff.Sign() == 0 {
+		return nil
+	}
+	_, err := c.updateAccBalance(ic, h, diff, nil)
+	if err != nil {
+		return err
+	}
+	buf, supply := c.getTotalSupply(ic.DAO)
+	supply.Add(supply, diff)
+	c.saveTotalSupply(ic.DAO, buf, supply)
+	return nil
+}
+
 func (c *nep17TokenNative) Mint(ic *interop.Context, h util.Uint160, amount *big.Int, callOnPayment bool) {
 	if amount.Sign() == 0 {
 		return
@@ -0,0 +1,114 @@
+package state
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+
+	"github.com/nspcc-dev/neo-go/pkg/io"
+)
+
+// Storage change flags used in binary serialization to distinguish missing
+// values from empty ones.
+const (
+	storageChangeHasOld byte = 1 << iota
+	storageChangeHasNew
+)
+
+// StorageChange is a single contract storage item change.
+type StorageChange struct {
+	// ID is the storage owner contract ID.
+	ID int32
+	// Key is the storage item key (without contract ID).
+	Key []byte
+	// Old is the value before the change, nil if the item was added.
+	Old []byte
+	// New is the value after the change, nil if the item was deleted.
+	New []byte
+}
+
+// StorageDiff is a set of contract storage changes made by a block or a
+// transaction, it's sorted by contract ID and key.
+type StorageDiff []StorageChange
+
+// Sort sorts the diff by contract ID and key.
+func (d StorageDiff) Sort() {
+	slices.SortFunc(d, func(a, b StorageChange) int {
+		if a.ID != b.ID {
+			if a.ID < b.ID {
+				return -1
+			}
+			return 1
+		}
+		return bytes.Compare(a.Key, b.Key)
+	})
+}
+
+// EncodeBinary implements the Serializable interface.
+func (d *StorageDiff) EncodeBinary(w *io.BinWriter) {
+	w.WriteVarUint(uint64(len(*d)))
+	for i := range *d {
+		(*d)[i].EncodeBinary(w)
+	}
+}
+
+// DecodeBinary implements the Serializable interface.
+func (d *StorageDiff) DecodeBinary(r *io.BinReader) {
+	l := r.ReadVarUint()
+	if r.Err != nil {
+		return
+	}
+	if l > uint64(r.Len()) {
+		r.Err = errors.New("invalid storage diff length")
+		return
+	}
+	res := make(StorageDiff, l)
+	for i := range res {
+		res[i].DecodeBinary(r)
+		if r.Err != nil {
+			return
+		}
+	}
+	*d = res
+}
+
+// EncodeBinary implements the Serializable interface.
+func (c *StorageChange) EncodeBinary(w *io.BinWriter) {
+	var flags byte
+	if c.Old != nil {
+		flags |= storageChangeHasOld
+	}
+	if c.New != nil {
+		flags |= storageChangeHasNew
+	}
+	w.WriteU32LE(uint32(c.ID))
+	w.WriteVarBytes(c.Key)
+	w.WriteB(flags)
+	if c.Old != nil {
+		w.WriteVarBytes(c.Old)
+	}
+	if c.New != nil {
+		w.WriteVarBytes(c.New)
+	}
+}
+
+// DecodeBinary implements the Serializable interface.
+func (c *StorageChange) DecodeBinary(r *io.BinReader) {
+	c.ID = int32(r.ReadU32LE())
+	c.Key = r.ReadVarBytes()
+	flags := r.ReadB()
+	if r.Err != nil {
+		return
+	}
+	if flags&^(storageChangeHasOld|storageChangeHasNew) != 0 {
+		r.Err = errors.New("invalid storage change flags")
+		return
+	}
+	c.Old, c.New = nil, nil
+	if flags&storageChangeHasOld != 0 {
+		c.Old = r.ReadVarBytes()
+	}
+	if flags&storageChangeHasNew != 0 {
+		c.New = r.ReadVarBytes()
+	}
+}
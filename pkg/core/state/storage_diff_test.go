@@ -0,0 +1,51 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/internal/testserdes"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStorageDiffEncodeDecode(t *testing.T) {
+	diff := StorageDiff{
+		{ID: -1, Key: []byte{1}, Old: []byte{}, New: []byte{2}},
+		{ID: 1, Key: []byte{}, New: []byte{}},
+		{ID: 2, Key: []byte{3}, Old: []byte{4}},
+	}
+	testserdes.EncodeDecodeBinary(t, &diff, new(StorageDiff))
+
+	empty := StorageDiff{}
+	testserdes.EncodeDecodeBinary(t, &empty, new(StorageDiff))
+
+	t.Run("invalid flags", func(t *testing.T) {
+		w := io.NewBufBinWriter()
+		w.WriteVarUint(1)
+		w.WriteU32LE(1)
+		w.WriteVarBytes([]byte{1})
+		w.WriteB(4)
+		require.Error(t, testserdes.DecodeBinary(w.Bytes(), new(StorageDiff)))
+	})
+	t.Run("invalid length", func(t *testing.T) {
+		w := io.NewBufBinWriter()
+		w.WriteVarUint(100)
+		require.Error(t, testserdes.DecodeBinary(w.Bytes(), new(StorageDiff)))
+	})
+}
+
+func TestStorageDiffSort(t *testing.T) {
+	diff := StorageDiff{
+		{ID: 2, Key: []byte{1}},
+		{ID: -1, Key: []byte{2}},
+		{ID: -1, Key: []byte{1, 2}},
+		{ID: 2, Key: []byte{0}},
+	}
+	diff.Sort()
+	require.Equal(t, StorageDiff{
+		{ID: -1, Key: []byte{1, 2}},
+		{ID: -1, Key: []byte{2}},
+		{ID: 2, Key: []byte{0}},
+		{ID: 2, Key: []byte{1}},
+	}, diff)
+}
@@ -5,11 +5,13 @@ package dbconfig
 
 type (
 	// DBConfiguration describes configuration for DB. Supported types:
-	// [LevelDB], [BoltDB] or [InMemoryDB] (not recommended for production usage).
+	// [LevelDB], [BoltDB], [PebbleDB] or [InMemoryDB] (not recommended for
+	// production usage).
 	DBConfiguration struct {
-		Type           string         `yaml:"Type"`
-		LevelDBOptions LevelDBOptions `yaml:"LevelDBOptions"`
-		BoltDBOptions  BoltDBOptions  `yaml:"BoltDBOptions"`
+		Type            string          `yaml:"Type"`
+		LevelDBOptions  LevelDBOptions  `yaml:"LevelDBOptions"`
+		BoltDBOptions   BoltDBOptions   `yaml:"BoltDBOptions"`
+		PebbleDBOptions PebbleDBOptions `yaml:"PebbleDBOptions"`
 	}
 	// LevelDBOptions configuration for LevelDB.
 	LevelDBOptions struct {
@@ -21,4 +23,18 @@ type (
 		FilePath string `yaml:"FilePath"`
 		ReadOnly bool   `yaml:"ReadOnly"`
 	}
+	// PebbleDBOptions configuration for PebbleDB.
+	PebbleDBOptions struct {
+		DataDirectoryPath string `yaml:"DataDirectoryPath"`
+		ReadOnly          bool   `yaml:"ReadOnly"`
+		// CacheSize is the size of block cache in bytes, 0 means default
+		// (8 MiB).
+		CacheSize int64 `yaml:"CacheSize"`
+		// MemTableSize is the size of a single memtable in bytes, 0 means
+		// default (4 MiB).
+		MemTableSize uint64 `yaml:"MemTableSize"`
+		// MaxOpenFiles is a soft limit on the number of open files, 0 means
+		// default (1000).
+		MaxOpenFiles int `yaml:"MaxOpenFiles"`
+	}
 )
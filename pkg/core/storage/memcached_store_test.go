@@ -279,8 +279,9 @@ func BenchmarkCachedSeek(t *testing.B) {
 		"MemPS": func(t testing.TB) Store {
 			return NewMemoryStore()
 		},
-		"BoltPS":  newBoltStoreForTesting,
-		"LevelPS": newLevelDBForTesting,
+		"BoltPS":   newBoltStoreForTesting,
+		"LevelPS":  newLevelDBForTesting,
+		"PebblePS": newPebbleDBForTesting,
 	}
 	for psName, newPS := range stores {
 		for psCount := 100; psCount <= 10000; psCount *= 10 {
@@ -0,0 +1,159 @@
+package storage
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/cockroachdb/pebble/bloom"
+	"github.com/nspcc-dev/neo-go/pkg/core/storage/dbconfig"
+)
+
+// PebbleDBStore is an LSM-based storage implementation for storing and
+// retrieving blockchain data. It's an alternative to LevelDBStore with better
+// compaction behaviour which is mostly noticeable on big (archival) databases.
+type PebbleDBStore struct {
+	db *pebble.DB
+	// lock serializes writes, SeekGC needs its view of the DB to remain
+	// consistent with the set of keys it deletes.
+	lock sync.Mutex
+}
+
+// NewPebbleDBStore returns a new PebbleDBStore object that will initialize
+// the database found at the given path.
+func NewPebbleDBStore(cfg dbconfig.PebbleDBOptions) (*PebbleDBStore, error) {
+	var opts = &pebble.Options{
+		ReadOnly:         cfg.ReadOnly,
+		ErrorIfNotExists: cfg.ReadOnly,
+		MaxOpenFiles:     cfg.MaxOpenFiles,
+		MemTableSize:     cfg.MemTableSize,
+		Levels:           []pebble.LevelOptions{{FilterPolicy: bloom.FilterPolicy(10)}},
+	}
+	if cfg.CacheSize != 0 {
+		cache := pebble.NewCache(cfg.CacheSize)
+		defer cache.Unref() // DB holds its own reference.
+		opts.Cache = cache
+	}
+	opts.EnsureDefaults()
+	db, err := pebble.Open(cfg.DataDirectoryPath, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open PebbleDB instance: %w", err)
+	}
+	return &PebbleDBStore{db: db}, nil
+}
+
+// Get implements the Store interface.
+func (s *PebbleDBStore) Get(key []byte) ([]byte, error) {
+	value, closer, err := s.db.Get(key)
+	if err != nil {
+		if errors.Is(err, pebble.ErrNotFound) {
+			err = ErrKeyNotFound
+		}
+		return nil, err
+	}
+	// Value is only valid until closer is closed.
+	value = bytes.Clone(value)
+	return value, closer.Close()
+}
+
+// PutChangeSet implements the Store interface.
+func (s *PebbleDBStore) PutChangeSet(puts map[string][]byte, stores map[string][]byte) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var (
+		err   error
+		batch = s.db.NewBatch()
+	)
+	defer batch.Close()
+
+	for _, m := range []map[string][]byte{puts, stores} {
+		for k, v := range m {
+			if v != nil {
+				err = batch.Set([]byte(k), v, nil)
+			} else {
+				err = batch.Delete([]byte(k), nil)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return batch.Commit(pebble.Sync)
+}
+
+// Seek implements the Store interface.
+func (s *PebbleDBStore) Seek(rng SeekRange, f func(k, v []byte) bool) {
+	err := s.seek(rng, f)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// SeekGC implements the Store interface.
+func (s *PebbleDBStore) SeekGC(rng SeekRange, keepCont func(k, v []byte) (bool, bool)) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var (
+		delErr error
+		batch  = s.db.NewBatch()
+	)
+	defer batch.Close()
+
+	err := s.seek(rng, func(k, v []byte) bool {
+		keep, cont := keepCont(k, v)
+		if !keep {
+			delErr = batch.Delete(k, nil)
+			if delErr != nil {
+				return false
+			}
+		}
+		return cont
+	})
+	if err == nil {
+		err = delErr
+	}
+	if err != nil {
+		return err
+	}
+	return batch.Commit(pebble.Sync)
+}
+
+func (s *PebbleDBStore) seek(rng SeekRange, f func(k, v []byte) bool) error {
+	var (
+		next func() bool
+		ok   bool
+		rang = seekRangeToPrefixes(rng)
+	)
+
+	iter, err := s.db.NewIter(&pebble.IterOptions{
+		LowerBound: rang.Start,
+		UpperBound: rang.Limit,
+	})
+	if err != nil {
+		return err
+	}
+
+	if !rng.Backwards {
+		ok = iter.First()
+		next = iter.Next
+	} else {
+		ok = iter.Last()
+		next = iter.Prev
+	}
+
+	for ; ok; ok = next() {
+		if !f(iter.Key(), iter.Value()) {
+			break
+		}
+	}
+	return iter.Close()
+}
+
+// Close implements the Store interface.
+func (s *PebbleDBStore) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/nspcc-dev/neo-go/pkg/core/storage/dbconfig"
+	"github.com/stretchr/testify/require"
+)
+
+func newPebbleDBForTesting(t testing.TB) Store {
+	dir := t.TempDir()
+	opts := dbconfig.PebbleDBOptions{
+		DataDirectoryPath: dir,
+	}
+	newPebbleStore, err := NewPebbleDBStore(opts)
+	require.Nil(t, err, "NewPebbleDBStore error")
+	return newPebbleStore
+}
+
+func TestROPebbleDB(t *testing.T) {
+	dir := t.TempDir()
+	opts := dbconfig.PebbleDBOptions{
+		DataDirectoryPath: dir,
+		ReadOnly:          true,
+	}
+
+	// If DB doesn't exist, then error should be returned.
+	_, err := NewPebbleDBStore(opts)
+	require.Error(t, err)
+
+	// Create the DB and try to open it in RO mode.
+	opts.ReadOnly = false
+	store, err := NewPebbleDBStore(opts)
+	require.NoError(t, err)
+	require.NoError(t, store.PutChangeSet(map[string][]byte{"one": []byte("one")}, nil))
+	require.NoError(t, store.Close())
+	opts.ReadOnly = true
+
+	store, err = NewPebbleDBStore(opts)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, store.Close()) })
+	v, err := store.Get([]byte("one"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("one"), v)
+	// Changes must be prohibited.
+	putErr := store.PutChangeSet(map[string][]byte{"two": []byte("two")}, nil)
+	require.ErrorIs(t, putErr, pebble.ErrReadOnly)
+}
+
+func TestPebbleDBOptions(t *testing.T) {
+	store, err := NewPebbleDBStore(dbconfig.PebbleDBOptions{
+		DataDirectoryPath: t.TempDir(),
+		CacheSize:         1 << 20,
+		MemTableSize:      1 << 20,
+		MaxOpenFiles:      100,
+	})
+	require.NoError(t, err)
+	require.NoError(t, store.PutChangeSet(map[string][]byte{"key": []byte("value")}, nil))
+	v, err := store.Get([]byte("key"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("value"), v)
+	require.NoError(t, store.Close())
+}
@@ -0,0 +1,108 @@
+package storage
+
+import (
+	"fmt"
+	"sync"
+)
+
+// SnapshotStore is a Store wrapper that allows to save the current state of
+// the underlying Store and to revert all changes made after that later. All
+// changes made after the first snapshot are kept in memory (the underlying
+// Store is not changed), so it's mostly useful for tests.
+type SnapshotStore struct {
+	lock   sync.RWMutex
+	base   Store
+	layers []*MemCachedStore
+}
+
+// NewSnapshotStore creates a new SnapshotStore over the given Store.
+func NewSnapshotStore(base Store) *SnapshotStore {
+	return &SnapshotStore{base: base}
+}
+
+// top returns the Store all changes are written to, it's supposed to be
+// called with the lock held.
+func (s *SnapshotStore) top() Store {
+	if len(s.layers) == 0 {
+		return s.base
+	}
+	return s.layers[len(s.layers)-1]
+}
+
+// Snapshot saves the current state of the Store and returns its identifier
+// that can be passed to Revert. Identifiers are sequential, starting from 0.
+func (s *SnapshotStore) Snapshot() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.layers = append(s.layers, NewMemCachedStore(s.top()))
+	return len(s.layers) - 1
+}
+
+// Revert drops all changes made after the specified snapshot. The snapshot
+// itself stays valid and can be reverted to again, while all subsequent
+// snapshots are removed.
+func (s *SnapshotStore) Revert(id int) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if id < 0 || id >= len(s.layers) {
+		return fmt.Errorf("unknown snapshot %d", id)
+	}
+	s.layers = s.layers[:id]
+	s.layers = append(s.layers, NewMemCachedStore(s.top()))
+	return nil
+}
+
+// Get implements the Store interface.
+func (s *SnapshotStore) Get(key []byte) ([]byte, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.top().Get(key)
+}
+
+// PutChangeSet implements the Store interface.
+func (s *SnapshotStore) PutChangeSet(puts map[string][]byte, stor map[string][]byte) error {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.top().PutChangeSet(puts, stor)
+}
+
+// Seek implements the Store interface.
+func (s *SnapshotStore) Seek(rng SeekRange, f func(k, v []byte) bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	s.top().Seek(rng, f)
+}
+
+// SeekGC implements the Store interface. If there are any snapshots, removed
+// items are only deleted from the latest one.
+func (s *SnapshotStore) SeekGC(rng SeekRange, keepCont func(k, v []byte) (bool, bool)) error {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	if len(s.layers) == 0 {
+		return s.base.SeekGC(rng, keepCont)
+	}
+	var (
+		top = s.layers[len(s.layers)-1]
+		del []string
+	)
+	top.Seek(rng, func(k, v []byte) bool {
+		keep, cont := keepCont(k, v)
+		if !keep {
+			del = append(del, string(k))
+		}
+		return cont
+	})
+	for _, k := range del {
+		top.Delete([]byte(k))
+	}
+	return nil
+}
+
+// Close implements the Store interface, it drops all snapshots and closes the
+// underlying Store.
+func (s *SnapshotStore) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.layers = nil
+	return s.base.Close()
+}
@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newSnapshotStoreForTesting(t testing.TB) Store {
+	s := NewSnapshotStore(NewMemoryStore())
+	s.Snapshot()
+	return s
+}
+
+func TestSnapshotStore(t *testing.T) {
+	var (
+		k1 = []byte{byte(STStorage), 1}
+		k2 = []byte{byte(STStorage), 2}
+		k3 = []byte{byte(STStorage), 3}
+	)
+	seek := func(t *testing.T, s Store, expected ...[]byte) {
+		var actual [][]byte
+		s.Seek(SeekRange{Prefix: []byte{byte(STStorage)}}, func(k, _ []byte) bool {
+			actual = append(actual, k)
+			return true
+		})
+		require.Equal(t, expected, actual)
+	}
+
+	base := NewMemoryStore()
+	s := NewSnapshotStore(base)
+	require.Error(t, s.Revert(0))
+
+	require.NoError(t, s.PutChangeSet(nil, map[string][]byte{string(k1): {1}}))
+	require.Equal(t, 0, s.Snapshot())
+	require.NoError(t, s.PutChangeSet(nil, map[string][]byte{string(k1): nil, string(k2): {2}}))
+	require.Equal(t, 1, s.Snapshot())
+	require.NoError(t, s.PutChangeSet(nil, map[string][]byte{string(k3): {3}}))
+	seek(t, s, k2, k3)
+
+	// The underlying store is not changed after the first snapshot.
+	seek(t, base, k1)
+
+	require.NoError(t, s.Revert(1))
+	seek(t, s, k2)
+	_, err := s.Get(k3)
+	require.ErrorIs(t, err, ErrKeyNotFound)
+
+	require.NoError(t, s.Revert(0))
+	seek(t, s, k1)
+	v, err := s.Get(k1)
+	require.NoError(t, err)
+	require.Equal(t, []byte{1}, v)
+	require.Error(t, s.Revert(1))
+
+	// Snapshot can be reverted to several times.
+	require.NoError(t, s.PutChangeSet(nil, map[string][]byte{string(k3): {3}}))
+	seek(t, s, k1, k3)
+	require.NoError(t, s.Revert(0))
+	seek(t, s, k1)
+
+	require.NoError(t, s.Close())
+}
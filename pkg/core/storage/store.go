@@ -18,16 +18,26 @@ const (
 	// DataMPTAux is used to store additional MPT data like height-root
 	// mappings and local/validated heights.
 	DataMPTAux KeyPrefix = 0x04
-	STStorage  KeyPrefix = 0x70
+	// DataStorageDiff is used to store contract storage changes made by blocks
+	// and transactions identified by Uint256 (when enabled).
+	DataStorageDiff KeyPrefix = 0x05
+	STStorage       KeyPrefix = 0x70
 	// STTempStorage is used to store contract storage items during state sync process
 	// in order not to mess up the previous state which has its own items stored by
 	// STStorage prefix. Once state exchange process is completed, all items with
 	// STStorage prefix will be replaced with STTempStorage-prefixed ones.
-	STTempStorage                  KeyPrefix = 0x71
-	STNEP11Transfers               KeyPrefix = 0x72
-	STNEP17Transfers               KeyPrefix = 0x73
-	STTokenTransferInfo            KeyPrefix = 0x74
-	IXHeaderHashList               KeyPrefix = 0x80
+	STTempStorage       KeyPrefix = 0x71
+	STNEP11Transfers    KeyPrefix = 0x72
+	STNEP17Transfers    KeyPrefix = 0x73
+	STTokenTransferInfo KeyPrefix = 0x74
+	IXHeaderHashList    KeyPrefix = 0x80
+	// IXNotifications is used to store the notification index (when enabled),
+	// it allows to search for contract notifications by contract hash, event
+	// name and block height.
+	IXNotifications KeyPrefix = 0x81
+	// IXAddressTransactions is used to store the address index (when
+	// enabled), it allows to search for transactions touching some address.
+	IXAddressTransactions          KeyPrefix = 0x82
 	SYSCurrentBlock                KeyPrefix = 0xc0
 	SYSCurrentHeader               KeyPrefix = 0xc1
 	SYSStateSyncCurrentBlockHeight KeyPrefix = 0xc2
@@ -134,6 +144,8 @@ func NewStore(cfg dbconfig.DBConfiguration) (Store, error) {
 		store = NewMemoryStore()
 	case dbconfig.BoltDB:
 		store, err = NewBoltDBStore(cfg.BoltDBOptions)
+	case dbconfig.PebbleDB:
+		store, err = NewPebbleDBStore(cfg.PebbleDBOptions)
 	default:
 		return nil, fmt.Errorf("unknown storage: %s", cfg.Type)
 	}
@@ -17,8 +17,11 @@ func TestStorageNames(t *testing.T) {
 		BoltDBOptions: dbconfig.BoltDBOptions{
 			FilePath: filepath.Join(tmp, "bolt"),
 		},
+		PebbleDBOptions: dbconfig.PebbleDBOptions{
+			DataDirectoryPath: filepath.Join(tmp, "pebble"),
+		},
 	}
-	for _, name := range []string{dbconfig.BoltDB, dbconfig.LevelDB, dbconfig.InMemoryDB} {
+	for _, name := range []string{dbconfig.BoltDB, dbconfig.LevelDB, dbconfig.PebbleDB, dbconfig.InMemoryDB} {
 		t.Run(name, func(t *testing.T) {
 			cfg.Type = name
 			s, err := NewStore(cfg)
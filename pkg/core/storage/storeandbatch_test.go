@@ -229,6 +229,8 @@ func TestAllDBs(t *testing.T) {
 		{"LevelDB", newLevelDBForTesting},
 		{"MemCached", newMemCachedStoreForTesting},
 		{"Memory", newMemoryStoreForTesting},
+		{"PebbleDB", newPebbleDBForTesting},
+		{"Snapshot", newSnapshotStoreForTesting},
 	}
 	var tests = []dbTestFunction{testStoreGetNonExistent, testStoreSeek,
 		testStoreSeekGC}
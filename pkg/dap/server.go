@@ -0,0 +1,114 @@
+/*
+Package dap implements Debug Adapter Protocol server for smart contracts.
+
+The server compiles (or loads) a contract, runs one of its methods in NeoVM
+using the chain state provided by the caller and maps VM execution to the
+contract source code via debug info sequence points, so that any
+DAP-capable editor can be used to place breakpoints, step through the code
+and inspect variables.
+*/
+package dap
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/google/go-dap"
+	"github.com/nspcc-dev/neo-go/pkg/core/interop"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"go.uber.org/zap"
+)
+
+// threadID is the identifier of the only thread reported to the client.
+const threadID = 1
+
+// DefaultGasLimit is the GAS limit (in GAS fractions) used for debug sessions
+// if it's neither specified in the launch request nor in the Config.
+const DefaultGasLimit = 20_0000_0000
+
+// Config is the debug server configuration.
+type Config struct {
+	// NewContext creates an interop context (bound to some chain state) for
+	// a debug session. The transaction given is a fake one containing the
+	// contract script, its signers are taken from the launch request.
+	NewContext func(tx *transaction.Transaction) (*interop.Context, error)
+	// GasLimit is the default GAS limit (in GAS fractions) for a session,
+	// DefaultGasLimit is used if it's not set.
+	GasLimit int64
+}
+
+// Server is a Debug Adapter Protocol server. It serves one debug session per
+// connection, every session runs a single contract method invocation.
+type Server struct {
+	cfg Config
+	log *zap.Logger
+}
+
+// LaunchArguments are the debugger-specific arguments of the launch request.
+type LaunchArguments struct {
+	// Program is the path to the contract Go source file (or package
+	// directory) or to the contract NEF file (with .nef extension).
+	Program string `json:"program"`
+	// Manifest is the path to the contract manifest. It's only used for NEF
+	// programs and defaults to the NEF file path with .manifest.json
+	// extension instead of .nef.
+	Manifest string `json:"manifest,omitempty"`
+	// DebugInfo is the path to the contract debug info file. It's only used
+	// for NEF programs, source-level debugging is not available without it.
+	DebugInfo string `json:"debugInfo,omitempty"`
+	// Hash is the contract hash used for execution, zero hash is used by
+	// default.
+	Hash util.Uint160 `json:"hash"`
+	// Method is the name of the contract method to run.
+	Method string `json:"method"`
+	// Args are the method parameters.
+	Args []smartcontract.Parameter `json:"args,omitempty"`
+	// Signers are the signers of the fake transaction used for execution.
+	Signers []transaction.Signer `json:"signers,omitempty"`
+	// GasLimit is the maximum amount of GAS (in GAS fractions) that can be
+	// spent by the method, server's default is used if not specified.
+	GasLimit int64 `json:"gasLimit,omitempty"`
+	// StopOnEntry makes the debugger stop at the first instruction of the
+	// method.
+	StopOnEntry bool `json:"stopOnEntry,omitempty"`
+}
+
+// New creates a new debug server with the given configuration.
+func New(cfg Config, log *zap.Logger) *Server {
+	return &Server{
+		cfg: cfg,
+		log: log,
+	}
+}
+
+// ServeConn serves a single debug session over the given connection. It
+// returns when the client disconnects or the connection is closed.
+func (s *Server) ServeConn(conn io.ReadWriter) error {
+	var (
+		r    = bufio.NewReader(conn)
+		sess = newSession(s, conn)
+	)
+	defer sess.close()
+	for !sess.done {
+		msg, err := dap.ReadProtocolMessage(r)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("failed to read message: %w", err)
+		}
+		req, ok := msg.(dap.RequestMessage)
+		if !ok {
+			s.log.Debug("unexpected DAP message", zap.Any("message", msg))
+			continue
+		}
+		if err := sess.handle(req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
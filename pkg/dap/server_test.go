@@ -0,0 +1,382 @@
+package dap_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-dap"
+	"github.com/nspcc-dev/neo-go/pkg/compiler"
+	"github.com/nspcc-dev/neo-go/pkg/core/interop"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	neodap "github.com/nspcc-dev/neo-go/pkg/dap"
+	"github.com/nspcc-dev/neo-go/pkg/neotest/chain"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/trigger"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+const testContract = `package kek
+func Main(a int) int {
+	b := a + 1
+	return double(b)
+}
+func double(x int) int {
+	y := x * 2
+	return y
+}
+func Fail() {
+	panic("oops")
+}
+func Loop() {
+	for {
+	}
+}`
+
+type testClient struct {
+	t    *testing.T
+	conn net.Conn
+	seq  int
+	msgs chan dap.Message
+}
+
+func newTestClient(t *testing.T) (*testClient, string) {
+	bc, _ := chain.NewSingle(t)
+	srv := neodap.New(neodap.Config{
+		NewContext: func(tx *transaction.Transaction) (*interop.Context, error) {
+			return bc.GetTestVM(trigger.Application, tx, nil)
+		},
+		GasLimit: 1_0000_0000,
+	}, zaptest.NewLogger(t))
+
+	srvConn, cliConn := net.Pipe()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.ServeConn(srvConn)
+		_ = srvConn.Close()
+	}()
+	c := &testClient{t: t, conn: cliConn, msgs: make(chan dap.Message, 100)}
+	go func() {
+		r := bufio.NewReader(cliConn)
+		for {
+			msg, err := dap.ReadProtocolMessage(r)
+			if err != nil {
+				close(c.msgs)
+				return
+			}
+			c.msgs <- msg
+		}
+	}()
+	t.Cleanup(func() {
+		_ = cliConn.Close()
+		require.NoError(t, <-errCh)
+	})
+
+	file := filepath.Join(t.TempDir(), "contract.go")
+	require.NoError(t, os.WriteFile(file, []byte(testContract), os.ModePerm))
+	return c, file
+}
+
+func (c *testClient) request(command string, args any) {
+	c.seq++
+	data, err := json.Marshal(map[string]any{
+		"seq":       c.seq,
+		"type":      "request",
+		"command":   command,
+		"arguments": args,
+	})
+	require.NoError(c.t, err)
+	require.NoError(c.t, dap.WriteBaseMessage(c.conn, data))
+}
+
+func (c *testClient) next() dap.Message {
+	select {
+	case msg, ok := <-c.msgs:
+		require.True(c.t, ok, "connection closed")
+		return msg
+	case <-time.After(10 * time.Second):
+		c.t.Fatal("timeout waiting for message")
+	}
+	return nil
+}
+
+// expect reads the next message and checks its type.
+func expect[T dap.Message](c *testClient) T {
+	msg := c.next()
+	res, ok := msg.(T)
+	require.True(c.t, ok, "unexpected message %#v", msg)
+	return res
+}
+
+func (c *testClient) stackTrace() []dap.StackFrame {
+	c.request("stackTrace", dap.StackTraceArguments{ThreadId: 1})
+	return expect[*dap.StackTraceResponse](c).Body.StackFrames
+}
+
+// variables returns the values of the frame scope variables by name.
+func (c *testClient) variables(frame int, scope string) map[string]string {
+	c.request("scopes", dap.ScopesArguments{FrameId: frame})
+	var ref int
+	for _, sc := range expect[*dap.ScopesResponse](c).Body.Scopes {
+		if sc.Name == scope {
+			ref = sc.VariablesReference
+		}
+	}
+	require.NotZero(c.t, ref)
+	c.request("variables", dap.VariablesArguments{VariablesReference: ref})
+	res := make(map[string]string)
+	for _, v := range expect[*dap.VariablesResponse](c).Body.Variables {
+		res[v.Name] = v.Value
+	}
+	return res
+}
+
+func (c *testClient) initialize() {
+	c.request("initialize", dap.InitializeRequestArguments{AdapterID: "neo-go"})
+	resp := expect[*dap.InitializeResponse](c)
+	require.True(c.t, resp.Body.SupportsConfigurationDoneRequest)
+	expect[*dap.InitializedEvent](c)
+}
+
+func TestServer_Breakpoints(t *testing.T) {
+	c, file := newTestClient(t)
+	c.initialize()
+
+	c.request("setBreakpoints", dap.SetBreakpointsArguments{
+		Source:      dap.Source{Path: file},
+		Breakpoints: []dap.SourceBreakpoint{{Line: 8}, {Line: 100}},
+	})
+	bps := expect[*dap.SetBreakpointsResponse](c).Body.Breakpoints
+	require.Len(t, bps, 2)
+	require.False(t, bps[0].Verified)
+
+	c.request("launch", neodap.LaunchArguments{
+		Program: file,
+		Method:  "main",
+		Args:    []smartcontract.Parameter{{Type: smartcontract.IntegerType, Value: big.NewInt(3)}},
+	})
+	expect[*dap.LaunchResponse](c)
+	ev := expect[*dap.BreakpointEvent](c)
+	require.True(t, ev.Body.Breakpoint.Verified)
+	require.Equal(t, 8, ev.Body.Breakpoint.Line)
+	ev = expect[*dap.BreakpointEvent](c)
+	require.False(t, ev.Body.Breakpoint.Verified)
+
+	c.request("configurationDone", nil)
+	expect[*dap.ConfigurationDoneResponse](c)
+	stopped := expect[*dap.StoppedEvent](c)
+	require.Equal(t, "breakpoint", stopped.Body.Reason)
+	require.Equal(t, []int{bps[0].Id}, stopped.Body.HitBreakpointIds)
+
+	frames := c.stackTrace()
+	require.Len(t, frames, 2)
+	require.Equal(t, "kek.double", frames[0].Name)
+	require.Equal(t, 8, frames[0].Line)
+	require.Equal(t, file, frames[0].Source.Path)
+	require.Equal(t, "kek.Main", frames[1].Name)
+	require.Equal(t, 4, frames[1].Line)
+
+	require.Equal(t, map[string]string{"x": "4"}, c.variables(frames[0].Id, "Arguments"))
+	require.Equal(t, map[string]string{"y": "8"}, c.variables(frames[0].Id, "Locals"))
+	require.Equal(t, map[string]string{"a": "3"}, c.variables(frames[1].Id, "Arguments"))
+	require.Equal(t, map[string]string{"b": "4"}, c.variables(frames[1].Id, "Locals"))
+
+	c.request("stepOut", dap.StepOutArguments{ThreadId: 1})
+	expect[*dap.StepOutResponse](c)
+	require.Equal(t, "step", expect[*dap.StoppedEvent](c).Body.Reason)
+	frames = c.stackTrace()
+	require.Len(t, frames, 1)
+	require.Equal(t, 4, frames[0].Line)
+
+	c.request("continue", dap.ContinueArguments{ThreadId: 1})
+	expect[*dap.ContinueResponse](c)
+	out := expect[*dap.OutputEvent](c)
+	require.Contains(t, out.Body.Output, "HALT")
+	require.Contains(t, out.Body.Output, `"value": "8"`)
+	require.Equal(t, 0, expect[*dap.ExitedEvent](c).Body.ExitCode)
+	expect[*dap.TerminatedEvent](c)
+
+	c.request("disconnect", nil)
+	expect[*dap.DisconnectResponse](c)
+}
+
+func TestServer_Stepping(t *testing.T) {
+	c, file := newTestClient(t)
+	c.initialize()
+	c.request("launch", neodap.LaunchArguments{
+		Program:     file,
+		Method:      "main",
+		Args:        []smartcontract.Parameter{{Type: smartcontract.IntegerType, Value: big.NewInt(1)}},
+		StopOnEntry: true,
+	})
+	expect[*dap.LaunchResponse](c)
+	c.request("configurationDone", nil)
+	expect[*dap.ConfigurationDoneResponse](c)
+	require.Equal(t, "entry", expect[*dap.StoppedEvent](c).Body.Reason)
+
+	step := func(command string, line int, depth int) {
+		c.request(command, dap.NextArguments{ThreadId: 1})
+		c.next() // Response.
+		require.Equal(t, "step", expect[*dap.StoppedEvent](c).Body.Reason)
+		frames := c.stackTrace()
+		require.Len(t, frames, depth)
+		require.Equal(t, line, frames[0].Line)
+	}
+	step("next", 4, 1)
+	step("stepIn", 7, 2)
+	step("next", 8, 2)
+	step("next", 4, 1)
+
+	c.request("continue", dap.ContinueArguments{ThreadId: 1})
+	expect[*dap.ContinueResponse](c)
+	require.Contains(t, expect[*dap.OutputEvent](c).Body.Output, `"value": "4"`)
+	require.Equal(t, 0, expect[*dap.ExitedEvent](c).Body.ExitCode)
+	expect[*dap.TerminatedEvent](c)
+}
+
+func TestServer_Exception(t *testing.T) {
+	c, file := newTestClient(t)
+	c.initialize()
+	c.request("configurationDone", nil)
+	expect[*dap.ConfigurationDoneResponse](c)
+	c.request("launch", neodap.LaunchArguments{Program: file, Method: "fail"})
+	expect[*dap.LaunchResponse](c)
+
+	stopped := expect[*dap.StoppedEvent](c)
+	require.Equal(t, "exception", stopped.Body.Reason)
+	require.Contains(t, stopped.Body.Text, "oops")
+	frames := c.stackTrace()
+	require.Len(t, frames, 1)
+	require.Equal(t, "kek.Fail", frames[0].Name)
+	require.Equal(t, 11, frames[0].Line)
+
+	c.request("continue", dap.ContinueArguments{ThreadId: 1})
+	expect[*dap.ContinueResponse](c)
+	require.Contains(t, expect[*dap.OutputEvent](c).Body.Output, "FAULT")
+	require.Equal(t, 1, expect[*dap.ExitedEvent](c).Body.ExitCode)
+	expect[*dap.TerminatedEvent](c)
+}
+
+func TestServer_Pause(t *testing.T) {
+	c, file := newTestClient(t)
+	c.initialize()
+	c.request("launch", neodap.LaunchArguments{Program: file, Method: "loop"})
+	expect[*dap.LaunchResponse](c)
+	c.request("configurationDone", nil)
+	expect[*dap.ConfigurationDoneResponse](c)
+
+	c.request("stackTrace", dap.StackTraceArguments{ThreadId: 1})
+	msg := c.next()
+	if resp, ok := msg.(*dap.ErrorResponse); ok { // Still running.
+		require.Equal(t, "program is running", resp.Message)
+		c.request("pause", dap.PauseArguments{ThreadId: 1})
+		expect[*dap.PauseResponse](c)
+		require.Equal(t, "pause", expect[*dap.StoppedEvent](c).Body.Reason)
+	} else { // GAS is already exhausted (unlikely, but possible).
+		require.Equal(t, "exception", msg.(*dap.StoppedEvent).Body.Reason)
+		expect[*dap.StackTraceResponse](c)
+	}
+	frames := c.stackTrace()
+	require.Len(t, frames, 1)
+	require.Equal(t, "kek.Loop", frames[0].Name)
+
+	c.request("continue", dap.ContinueArguments{ThreadId: 1})
+	expect[*dap.ContinueResponse](c)
+	for {
+		stopped, ok := c.next().(*dap.StoppedEvent)
+		if !ok {
+			continue // Output/exited/terminated after the exception.
+		}
+		require.Equal(t, "exception", stopped.Body.Reason)
+		require.Contains(t, stopped.Body.Text, "gas limit")
+		break
+	}
+}
+
+func TestServer_Terminate(t *testing.T) {
+	c, file := newTestClient(t)
+	c.initialize()
+	c.request("launch", neodap.LaunchArguments{Program: file, Method: "loop"})
+	expect[*dap.LaunchResponse](c)
+	c.request("configurationDone", nil)
+	expect[*dap.ConfigurationDoneResponse](c)
+	c.request("terminate", nil)
+	expect[*dap.TerminateResponse](c)
+	expect[*dap.TerminatedEvent](c)
+	c.request("disconnect", nil)
+	expect[*dap.DisconnectResponse](c)
+}
+
+func TestServer_LaunchErrors(t *testing.T) {
+	c, file := newTestClient(t)
+	c.initialize()
+
+	for _, args := range []neodap.LaunchArguments{
+		{Method: "main"},
+		{Program: file},
+		{Program: file, Method: "unknown"},
+		{Program: file + ".missing", Method: "main"},
+		{Program: file + ".nef", Method: "main"},
+	} {
+		c.request("launch", args)
+		resp := expect[*dap.ErrorResponse](c)
+		require.False(t, resp.Success)
+		require.NotEmpty(t, resp.Message)
+	}
+	c.request("stepBack", nil)
+	require.False(t, expect[*dap.ErrorResponse](c).Success)
+}
+
+func TestServer_NEF(t *testing.T) {
+	c, file := newTestClient(t)
+	ne, di, err := compiler.CompileWithOptions(file, nil, nil)
+	require.NoError(t, err)
+	m, err := di.ConvertToManifest(&compiler.Options{Name: "kek"})
+	require.NoError(t, err)
+
+	var (
+		dir      = filepath.Dir(file)
+		nefFile  = filepath.Join(dir, "contract.nef")
+		diFile   = filepath.Join(dir, "contract.debug.json")
+		manifest = filepath.Join(dir, "contract.manifest.json")
+	)
+	rawNEF, err := ne.Bytes()
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(nefFile, rawNEF, os.ModePerm))
+	rawDI, err := json.Marshal(di)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(diFile, rawDI, os.ModePerm))
+	rawManifest, err := json.Marshal(m)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(manifest, rawManifest, os.ModePerm))
+
+	c.initialize()
+	c.request("setBreakpoints", dap.SetBreakpointsArguments{
+		Source: dap.Source{Path: file},
+		Lines:  []int{8},
+	})
+	expect[*dap.SetBreakpointsResponse](c)
+	c.request("launch", neodap.LaunchArguments{
+		Program:   nefFile,
+		DebugInfo: diFile,
+		Method:    "main",
+		Args:      []smartcontract.Parameter{{Type: smartcontract.IntegerType, Value: big.NewInt(5)}},
+	})
+	expect[*dap.LaunchResponse](c)
+	require.True(t, expect[*dap.BreakpointEvent](c).Body.Breakpoint.Verified)
+	c.request("configurationDone", nil)
+	expect[*dap.ConfigurationDoneResponse](c)
+	require.Equal(t, "breakpoint", expect[*dap.StoppedEvent](c).Body.Reason)
+
+	frames := c.stackTrace()
+	require.Len(t, frames, 2)
+	require.Equal(t, 8, frames[0].Line)
+	require.Equal(t, map[string]string{"x": "6"}, c.variables(frames[0].Id, "Arguments"))
+	require.Equal(t, map[string]string{"y": "12"}, c.variables(frames[0].Id, "Locals"))
+}
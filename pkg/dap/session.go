@@ -0,0 +1,938 @@
+package dap
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/google/go-dap"
+	"github.com/nspcc-dev/neo-go/pkg/compiler"
+	"github.com/nspcc-dev/neo-go/pkg/core/interop"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/nef"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"go.uber.org/zap"
+)
+
+// stepMode defines the condition execution is paused at.
+type stepMode byte
+
+const (
+	// modeContinue runs until breakpoint or the end of execution.
+	modeContinue stepMode = iota
+	// modeNext runs until the next statement of the current function.
+	modeNext
+	// modeStepIn runs until the next statement of any function.
+	modeStepIn
+	// modeStepOut runs until the current function returns.
+	modeStepOut
+)
+
+// breakpoint is a source breakpoint requested by the client.
+type breakpoint struct {
+	id       int
+	line     int
+	offset   int
+	verified bool
+	message  string
+}
+
+// session is a single debug session state. The VM is run in a separate
+// goroutine, so all of the state is protected by the mutex that is released
+// by the VM goroutine between instructions.
+type session struct {
+	srv *Server
+	w   io.Writer
+	mtx sync.Mutex
+	// wg tracks the VM goroutine.
+	wg  sync.WaitGroup
+	seq int
+	// err is the first connection write error.
+	err error
+	// done is set when the client disconnects.
+	done bool
+
+	launched    bool
+	configured  bool
+	started     bool
+	finished    bool
+	stopOnEntry bool
+	// running is set while the VM goroutine executes the program.
+	running bool
+	// pauseReq and abortReq ask the VM goroutine to stop execution with
+	// or without notifying the client.
+	pauseReq bool
+	abortReq bool
+
+	// breakpoints are source breakpoints by document path.
+	breakpoints map[string][]*breakpoint
+	// bpOffsets maps instruction offsets to breakpoint IDs.
+	bpOffsets map[int]int
+	lastBPID  int
+
+	ic   *interop.Context
+	hash util.Uint160
+	di   *compiler.DebugInfo
+	// statements maps statement start offsets to sequence points.
+	statements map[int]*compiler.DebugSeqPoint
+	// vmErr is the VM execution error.
+	vmErr error
+
+	// refs are variable containers of the current stop, the reference of
+	// the container is its index plus one.
+	refs []any
+}
+
+// scopeKind is a kind of variables scope of a stack frame.
+type scopeKind byte
+
+const (
+	scopeArguments scopeKind = iota
+	scopeLocals
+	scopeStatics
+	scopeEvaluationStack
+)
+
+// scopeRef is a variables scope of the stack frame.
+type scopeRef struct {
+	ctx  *vm.Context
+	kind scopeKind
+}
+
+func newSession(srv *Server, w io.Writer) *session {
+	return &session{
+		srv:         srv,
+		w:           w,
+		breakpoints: make(map[string][]*breakpoint),
+		bpOffsets:   make(map[int]int),
+	}
+}
+
+// close stops the execution and releases session resources.
+func (s *session) close() {
+	s.mtx.Lock()
+	s.abortReq = true
+	s.mtx.Unlock()
+	s.wg.Wait()
+	if s.ic != nil {
+		s.ic.Finalize()
+	}
+}
+
+// handle processes a single client request. It only returns an error if the
+// connection is broken.
+func (s *session) handle(req dap.RequestMessage) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	var r = req.GetRequest()
+	if s.running {
+		switch req.(type) {
+		case *dap.StackTraceRequest, *dap.ScopesRequest, *dap.VariablesRequest,
+			*dap.ContinueRequest, *dap.NextRequest, *dap.StepInRequest, *dap.StepOutRequest:
+			s.sendError(r, errors.New("program is running"))
+			return s.err
+		}
+	}
+	switch req := req.(type) {
+	case *dap.InitializeRequest:
+		s.send(&dap.InitializeResponse{
+			Response: s.newResponse(r),
+			Body: dap.Capabilities{
+				SupportsConfigurationDoneRequest: true,
+				SupportTerminateDebuggee:         true,
+			},
+		})
+		s.send(&dap.InitializedEvent{Event: s.newEvent("initialized")})
+	case *dap.LaunchRequest:
+		if s.launched {
+			s.sendError(r, errors.New("program is already launched"))
+			break
+		}
+		var args LaunchArguments
+		err := json.Unmarshal(req.Arguments, &args)
+		if err == nil {
+			err = s.launch(&args)
+		}
+		if err != nil {
+			s.sendError(r, err)
+			break
+		}
+		s.send(&dap.LaunchResponse{Response: s.newResponse(r)})
+		s.updateBreakpoints(true)
+		if s.configured {
+			s.start()
+		}
+	case *dap.SetBreakpointsRequest:
+		s.setBreakpoints(req)
+	case *dap.SetExceptionBreakpointsRequest:
+		s.send(&dap.SetExceptionBreakpointsResponse{Response: s.newResponse(r)})
+	case *dap.ConfigurationDoneRequest:
+		s.send(&dap.ConfigurationDoneResponse{Response: s.newResponse(r)})
+		s.configured = true
+		if s.launched {
+			s.start()
+		}
+	case *dap.ThreadsRequest:
+		s.send(&dap.ThreadsResponse{
+			Response: s.newResponse(r),
+			Body:     dap.ThreadsResponseBody{Threads: []dap.Thread{{Id: threadID, Name: "main"}}},
+		})
+	case *dap.StackTraceRequest:
+		s.stackTrace(req)
+	case *dap.ScopesRequest:
+		s.scopes(req)
+	case *dap.VariablesRequest:
+		s.variables(req)
+	case *dap.ContinueRequest:
+		s.send(&dap.ContinueResponse{
+			Response: s.newResponse(r),
+			Body:     dap.ContinueResponseBody{AllThreadsContinued: true},
+		})
+		s.resume(modeContinue)
+	case *dap.NextRequest:
+		s.send(&dap.NextResponse{Response: s.newResponse(r)})
+		s.resume(modeNext)
+	case *dap.StepInRequest:
+		s.send(&dap.StepInResponse{Response: s.newResponse(r)})
+		s.resume(modeStepIn)
+	case *dap.StepOutRequest:
+		s.send(&dap.StepOutResponse{Response: s.newResponse(r)})
+		s.resume(modeStepOut)
+	case *dap.PauseRequest:
+		s.send(&dap.PauseResponse{Response: s.newResponse(r)})
+		if s.running {
+			s.pauseReq = true
+		}
+	case *dap.TerminateRequest:
+		s.send(&dap.TerminateResponse{Response: s.newResponse(r)})
+		s.abortReq = s.running
+		if !s.finished {
+			s.finished = true
+			s.send(&dap.TerminatedEvent{Event: s.newEvent("terminated")})
+		}
+	case *dap.DisconnectRequest:
+		s.send(&dap.DisconnectResponse{Response: s.newResponse(r)})
+		s.abortReq = s.running
+		s.done = true
+	default:
+		s.sendError(r, fmt.Errorf("unsupported request: %s", r.Command))
+	}
+	return s.err
+}
+
+// launch prepares the program for execution.
+func (s *session) launch(args *LaunchArguments) error {
+	if args.Program == "" {
+		return errors.New("program is not specified")
+	}
+	if args.Method == "" {
+		return errors.New("method is not specified")
+	}
+	var (
+		ne  *nef.File
+		m   *manifest.Manifest
+		di  *compiler.DebugInfo
+		err error
+	)
+	if strings.HasSuffix(args.Program, ".nef") {
+		ne, m, di, err = loadNEF(args)
+	} else {
+		ne, m, di, err = compileGo(args.Program)
+	}
+	if err != nil {
+		return err
+	}
+	md := m.ABI.GetMethod(args.Method, len(args.Args))
+	if md == nil {
+		return fmt.Errorf("method %s with %d parameters is not found", args.Method, len(args.Args))
+	}
+	params := make([]stackitem.Item, len(args.Args))
+	for i := range args.Args {
+		params[i], err = args.Args[i].ToStackItem()
+		if err != nil {
+			return fmt.Errorf("failed to convert parameter #%d to stackitem: %w", i, err)
+		}
+	}
+	ic, err := s.srv.cfg.NewContext(&transaction.Transaction{
+		Script:  ne.Script,
+		Signers: args.Signers,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create interop context: %w", err)
+	}
+	var initOff = -1
+	if initMD := m.ABI.GetMethod(manifest.MethodInit, 0); initMD != nil {
+		initOff = initMD.Offset
+	}
+	v := ic.VM
+	v.GasLimit = args.GasLimit
+	if v.GasLimit <= 0 {
+		v.GasLimit = s.srv.cfg.GasLimit
+	}
+	if v.GasLimit <= 0 {
+		v.GasLimit = DefaultGasLimit
+	}
+	v.LoadNEFMethod(ne, m, util.Uint160{}, args.Hash, callflag.All, md.ReturnType != smartcontract.VoidType, md.Offset, initOff, nil)
+	for i := len(params) - 1; i >= 0; i-- {
+		v.Estack().PushVal(params[i])
+	}
+
+	// Zero hash is replaced with the script hash by VM.
+	s.ic, s.hash, s.di = ic, v.Context().ScriptHash(), di
+	s.stopOnEntry = args.StopOnEntry
+	s.statements = make(map[int]*compiler.DebugSeqPoint)
+	if di != nil {
+		for i := range di.Documents {
+			if abs, err := filepath.Abs(di.Documents[i]); err == nil {
+				di.Documents[i] = abs
+			}
+		}
+		for i := range di.Methods {
+			for j := range di.Methods[i].SeqPoints {
+				sp := &di.Methods[i].SeqPoints[j]
+				if _, ok := s.statements[sp.Opcode]; !ok {
+					s.statements[sp.Opcode] = sp
+				}
+			}
+		}
+	}
+	s.launched = true
+	return nil
+}
+
+// compileGo compiles Go contract with debug info.
+func compileGo(program string) (*nef.File, *manifest.Manifest, *compiler.DebugInfo, error) {
+	name := strings.TrimSuffix(filepath.Base(program), ".go")
+	ne, di, err := compiler.CompileWithOptions(program, nil, &compiler.Options{Name: name})
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to compile: %w", err)
+	}
+	// Don't perform checks, just load.
+	m, err := di.ConvertToManifest(&compiler.Options{Name: name})
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("can't create manifest: %w", err)
+	}
+	return ne, m, di, nil
+}
+
+// loadNEF reads NEF file along with its manifest and (optionally) debug info.
+func loadNEF(args *LaunchArguments) (*nef.File, *manifest.Manifest, *compiler.DebugInfo, error) {
+	b, err := os.ReadFile(args.Program)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	ne, err := nef.FileFromBytes(b)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to read NEF file: %w", err)
+	}
+	manifestFile := args.Manifest
+	if manifestFile == "" {
+		manifestFile = strings.TrimSuffix(args.Program, ".nef") + ".manifest.json"
+	}
+	b, err = os.ReadFile(manifestFile)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	m := new(manifest.Manifest)
+	if err := json.Unmarshal(b, m); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to unmarshal manifest: %w", err)
+	}
+	if args.DebugInfo == "" {
+		return &ne, m, nil, nil
+	}
+	b, err = os.ReadFile(args.DebugInfo)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	di := new(compiler.DebugInfo)
+	if err := json.Unmarshal(b, di); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to unmarshal debug info: %w", err)
+	}
+	return &ne, m, di, nil
+}
+
+// setBreakpoints replaces breakpoints of the source.
+func (s *session) setBreakpoints(req *dap.SetBreakpointsRequest) {
+	var (
+		path  = req.Arguments.Source.Path
+		lines = req.Arguments.Lines
+		bps   = make([]*breakpoint, 0, len(req.Arguments.Breakpoints))
+	)
+	if len(req.Arguments.Breakpoints) != 0 {
+		lines = make([]int, len(req.Arguments.Breakpoints))
+		for i := range req.Arguments.Breakpoints {
+			lines[i] = req.Arguments.Breakpoints[i].Line
+		}
+	}
+	for _, l := range lines {
+		s.lastBPID++
+		bps = append(bps, &breakpoint{id: s.lastBPID, line: l})
+	}
+	s.breakpoints[path] = bps
+	s.updateBreakpoints(false)
+
+	res := make([]dap.Breakpoint, len(bps))
+	for i, bp := range bps {
+		res[i] = bp.toDAP(path)
+	}
+	s.send(&dap.SetBreakpointsResponse{
+		Response: s.newResponse(&req.Request),
+		Body:     dap.SetBreakpointsResponseBody{Breakpoints: res},
+	})
+}
+
+// updateBreakpoints resolves source breakpoints into instruction offsets and
+// optionally notifies the client about breakpoint changes.
+func (s *session) updateBreakpoints(notify bool) {
+	clear(s.bpOffsets)
+	for path, bps := range s.breakpoints {
+		var (
+			doc    int
+			docErr error
+		)
+		if s.di != nil {
+			doc, docErr = s.di.DocumentIndex(path)
+		}
+		for _, bp := range bps {
+			bp.verified = false
+			switch {
+			case !s.launched:
+				bp.message = "program is not launched yet"
+			case s.di == nil:
+				bp.message = "no debug info"
+			case docErr != nil:
+				bp.message = docErr.Error()
+			default:
+				off, line, ok := s.di.LineOffset(doc, bp.line)
+				if !ok {
+					bp.message = "no code found at this line or below"
+					break
+				}
+				bp.verified, bp.message, bp.line, bp.offset = true, "", line, off
+				if _, ok := s.bpOffsets[off]; !ok {
+					s.bpOffsets[off] = bp.id
+				}
+			}
+			if notify {
+				s.send(&dap.BreakpointEvent{
+					Event: s.newEvent("breakpoint"),
+					Body:  dap.BreakpointEventBody{Reason: "changed", Breakpoint: bp.toDAP(path)},
+				})
+			}
+		}
+	}
+}
+
+// toDAP converts breakpoint to DAP representation.
+func (bp *breakpoint) toDAP(path string) dap.Breakpoint {
+	return dap.Breakpoint{
+		Id:       bp.id,
+		Verified: bp.verified,
+		Message:  bp.message,
+		Source:   &dap.Source{Name: filepath.Base(path), Path: path},
+		Line:     bp.line,
+	}
+}
+
+// start starts program execution once it's launched and configured.
+func (s *session) start() {
+	if s.started {
+		return
+	}
+	s.started = true
+	if s.stopOnEntry {
+		s.stop("entry", "", 0)
+		return
+	}
+	if id, ok := s.breakpointAt(s.ic.VM.Context()); ok {
+		s.stop("breakpoint", "", id)
+		return
+	}
+	s.resume(modeContinue)
+}
+
+// resume continues program execution in a separate goroutine until the
+// condition specified by mode is met or execution ends.
+func (s *session) resume(mode stepMode) {
+	if !s.started || s.finished || s.running {
+		return
+	}
+	v := s.ic.VM
+	if s.vmErr != nil || v.HasStopped() {
+		s.finish()
+		return
+	}
+	var (
+		depth   = len(v.Istack())
+		startSP *compiler.DebugSeqPoint
+	)
+	if ctx := v.Context(); s.hasDebugInfo(ctx) {
+		_, startSP = s.di.SeqPointByOffset(ctx.NextIP())
+	}
+	s.running = true
+	s.wg.Add(1)
+	go s.run(mode, depth, startSP)
+}
+
+// run executes VM instructions one by one until the condition specified by
+// mode is met, execution ends or the client asks to stop. The session lock
+// is released between instructions, so requests can be served meanwhile.
+func (s *session) run(mode stepMode, depth int, startSP *compiler.DebugSeqPoint) {
+	defer s.wg.Done()
+	s.mtx.Lock()
+	defer func() {
+		s.running, s.pauseReq, s.abortReq = false, false, false
+		s.mtx.Unlock()
+	}()
+	v := s.ic.VM
+	for {
+		if s.abortReq {
+			return
+		}
+		if s.pauseReq {
+			s.stop("pause", "", 0)
+			return
+		}
+		if err := v.Step(); err != nil {
+			s.vmErr = err
+			s.stop("exception", err.Error(), 0)
+			return
+		}
+		if v.HasStopped() {
+			s.finish()
+			return
+		}
+		var (
+			ctx = v.Context()
+			d   = len(v.Istack())
+		)
+		if id, ok := s.breakpointAt(ctx); ok {
+			s.stop("breakpoint", "", id)
+			return
+		}
+		var stop bool
+		switch mode {
+		case modeNext:
+			stop = d < depth || (d == depth && s.atStatement(ctx, startSP))
+		case modeStepIn:
+			prev := startSP
+			if d != depth {
+				prev = nil
+			}
+			stop = d < depth || s.atStatement(ctx, prev)
+		case modeStepOut:
+			stop = d < depth
+		}
+		if stop {
+			s.stop("step", "", 0)
+			return
+		}
+		s.mtx.Unlock()
+		s.mtx.Lock()
+	}
+}
+
+// hasDebugInfo checks whether the context belongs to the debugged contract
+// with debug info available.
+func (s *session) hasDebugInfo(ctx *vm.Context) bool {
+	return s.di != nil && ctx != nil && ctx.ScriptHash() == s.hash
+}
+
+// breakpointAt returns the ID of the breakpoint placed at the next
+// instruction of the context if there is any.
+func (s *session) breakpointAt(ctx *vm.Context) (int, bool) {
+	if !s.hasDebugInfo(ctx) {
+		return 0, false
+	}
+	id, ok := s.bpOffsets[ctx.NextIP()]
+	return id, ok
+}
+
+// atStatement checks whether the next instruction of the context starts a
+// statement different from the given one.
+func (s *session) atStatement(ctx *vm.Context, prev *compiler.DebugSeqPoint) bool {
+	if !s.hasDebugInfo(ctx) {
+		return false
+	}
+	sp, ok := s.statements[ctx.NextIP()]
+	return ok && sp != prev
+}
+
+// stop notifies the client that execution has stopped.
+func (s *session) stop(reason string, text string, bpID int) {
+	s.refs = s.refs[:0]
+	ev := &dap.StoppedEvent{
+		Event: s.newEvent("stopped"),
+		Body: dap.StoppedEventBody{
+			Reason:            reason,
+			Text:              text,
+			ThreadId:          threadID,
+			AllThreadsStopped: true,
+		},
+	}
+	if bpID != 0 {
+		ev.Body.HitBreakpointIds = []int{bpID}
+	}
+	s.send(ev)
+}
+
+// finish reports execution results and terminates the session.
+func (s *session) finish() {
+	var (
+		v        = s.ic.VM
+		exitCode int
+		out      strings.Builder
+	)
+	s.finished = true
+	s.refs = s.refs[:0]
+	if s.vmErr != nil {
+		exitCode = 1
+		fmt.Fprintf(&out, "FAULT: %s\n", s.vmErr)
+	} else {
+		fmt.Fprintf(&out, "HALT\nResult stack: %s\n", v.DumpEStack())
+	}
+	fmt.Fprintf(&out, "GAS consumed: %d\n", v.GasConsumed())
+	for _, ntf := range s.ic.Notifications {
+		data, err := stackitem.ToJSONWithTypes(ntf.Item)
+		if err != nil {
+			data = []byte(err.Error())
+		}
+		fmt.Fprintf(&out, "Notification %s from %s: %s\n", ntf.Name, ntf.ScriptHash.StringLE(), data)
+	}
+	s.send(&dap.OutputEvent{
+		Event: s.newEvent("output"),
+		Body:  dap.OutputEventBody{Category: "console", Output: out.String()},
+	})
+	s.send(&dap.ExitedEvent{
+		Event: s.newEvent("exited"),
+		Body:  dap.ExitedEventBody{ExitCode: exitCode},
+	})
+	s.send(&dap.TerminatedEvent{Event: s.newEvent("terminated")})
+}
+
+// frames returns the current invocation stack starting from the top.
+func (s *session) frames() []*vm.Context {
+	if !s.started || s.finished {
+		return nil
+	}
+	istack := s.ic.VM.Istack()
+	res := make([]*vm.Context, 0, len(istack))
+	for i := len(istack) - 1; i >= 0; i-- {
+		res = append(res, istack[i])
+	}
+	return res
+}
+
+// frameIP returns the instruction pointer of the frame, it's the next
+// instruction for the top frame (unless it has failed) and the current (call
+// or failed) instruction for others.
+func (s *session) frameIP(ctx *vm.Context, top bool) int {
+	if top && s.vmErr == nil {
+		return ctx.NextIP()
+	}
+	return ctx.IP()
+}
+
+func (s *session) stackTrace(req *dap.StackTraceRequest) {
+	var (
+		frames = s.frames()
+		start  = min(req.Arguments.StartFrame, len(frames))
+		end    = len(frames)
+		res    = make([]dap.StackFrame, 0, len(frames))
+	)
+	if req.Arguments.Levels > 0 {
+		end = min(start+req.Arguments.Levels, end)
+	}
+	for i := start; i < end; i++ {
+		var (
+			ctx = frames[i]
+			ip  = s.frameIP(ctx, i == 0)
+			f   = dap.StackFrame{
+				Id:                          i + 1,
+				Name:                        ctx.ScriptHash().StringLE(),
+				InstructionPointerReference: strconv.Itoa(ip),
+			}
+		)
+		if s.hasDebugInfo(ctx) {
+			m, sp := s.di.SeqPointByOffset(ip)
+			if m != nil {
+				f.Name = m.Name.Namespace + "." + m.ID
+			}
+			if sp != nil && sp.Document < len(s.di.Documents) {
+				doc := s.di.Documents[sp.Document]
+				f.Source = &dap.Source{Name: filepath.Base(doc), Path: doc}
+				f.Line, f.Column, f.EndLine, f.EndColumn = sp.StartLine, sp.StartCol, sp.EndLine, sp.EndCol
+			}
+		}
+		if f.Source == nil {
+			f.PresentationHint = "subtle"
+		}
+		res = append(res, f)
+	}
+	s.send(&dap.StackTraceResponse{
+		Response: s.newResponse(&req.Request),
+		Body:     dap.StackTraceResponseBody{StackFrames: res, TotalFrames: len(frames)},
+	})
+}
+
+func (s *session) scopes(req *dap.ScopesRequest) {
+	frames := s.frames()
+	i := req.Arguments.FrameId - 1
+	if i < 0 || i >= len(frames) {
+		s.sendError(&req.Request, fmt.Errorf("unknown frame %d", req.Arguments.FrameId))
+		return
+	}
+	var (
+		ctx = frames[i]
+		res = make([]dap.Scope, 0, 4)
+	)
+	for _, sc := range []struct {
+		name string
+		hint string
+		kind scopeKind
+	}{
+		{"Arguments", "arguments", scopeArguments},
+		{"Locals", "locals", scopeLocals},
+		{"Statics", "", scopeStatics},
+		{"Evaluation stack", "", scopeEvaluationStack},
+	} {
+		res = append(res, dap.Scope{
+			Name:               sc.name,
+			PresentationHint:   sc.hint,
+			VariablesReference: s.addRef(scopeRef{ctx: ctx, kind: sc.kind}),
+		})
+	}
+	s.send(&dap.ScopesResponse{
+		Response: s.newResponse(&req.Request),
+		Body:     dap.ScopesResponseBody{Scopes: res},
+	})
+}
+
+func (s *session) variables(req *dap.VariablesRequest) {
+	i := req.Arguments.VariablesReference - 1
+	if i < 0 || i >= len(s.refs) {
+		s.sendError(&req.Request, fmt.Errorf("unknown variables reference %d", req.Arguments.VariablesReference))
+		return
+	}
+	var res []dap.Variable
+	switch ref := s.refs[i].(type) {
+	case scopeRef:
+		res = s.scopeVariables(ref)
+	case *stackitem.Array:
+		res = s.listVariables(ref.Value().([]stackitem.Item))
+	case *stackitem.Struct:
+		res = s.listVariables(ref.Value().([]stackitem.Item))
+	case *stackitem.Map:
+		for _, e := range ref.Value().([]stackitem.MapElement) {
+			res = append(res, s.variable(formatItem(e.Key), "", e.Value))
+		}
+	}
+	if res == nil {
+		res = []dap.Variable{}
+	}
+	s.send(&dap.VariablesResponse{
+		Response: s.newResponse(&req.Request),
+		Body:     dap.VariablesResponseBody{Variables: res},
+	})
+}
+
+// scopeVariables returns variables of the frame scope named according to
+// debug info if it's available.
+func (s *session) scopeVariables(ref scopeRef) []dap.Variable {
+	var (
+		res  []dap.Variable
+		ctx  = ref.ctx
+		m    *compiler.MethodDebugInfo
+		seen = make(map[int]bool)
+	)
+	if s.hasDebugInfo(ctx) {
+		m = s.di.MethodByOffset(s.frameIP(ctx, ctx == s.ic.VM.Context()))
+	}
+	// named adds a variable stored in the i-th element of the slot.
+	named := func(slot *vm.Slot, i int, name string, typ string) {
+		var item stackitem.Item
+		if i >= 0 && i < slot.Size() {
+			item = slot.Get(i)
+			seen[i] = true
+		}
+		res = append(res, s.variable(name, typ, item))
+	}
+	// rest adds unnamed slot elements.
+	rest := func(slot *vm.Slot) {
+		for i := range slot.Size() {
+			if !seen[i] {
+				res = append(res, s.variable("#"+strconv.Itoa(i), "", slot.Get(i)))
+			}
+		}
+	}
+	switch ref.kind {
+	case scopeArguments:
+		slot := ctx.ArgumentsSlot()
+		if m != nil {
+			offset := max(slot.Size()-len(m.Parameters), 0) // Receiver.
+			for i, p := range m.Parameters {
+				named(slot, i+offset, p.Name, p.Type)
+			}
+		}
+		rest(slot)
+	case scopeLocals:
+		slot := ctx.LocalsSlot()
+		if m != nil {
+			for i, v := range m.Variables {
+				if n := m.VariableSlot(i); n >= 0 {
+					name, typ, _ := strings.Cut(v, ",")
+					named(slot, n, name, typ)
+				}
+			}
+		} else {
+			rest(slot)
+		}
+	case scopeStatics:
+		slot := ctx.StaticsSlot()
+		if s.hasDebugInfo(ctx) {
+			for i, v := range s.di.StaticVariables {
+				name, typ, _ := strings.Cut(v, ",")
+				named(slot, i, name, typ)
+			}
+		}
+		rest(slot)
+	case scopeEvaluationStack:
+		items := ctx.Estack().ToArray()
+		for i := len(items) - 1; i >= 0; i-- {
+			res = append(res, s.variable("#"+strconv.Itoa(len(items)-1-i), "", items[i]))
+		}
+	}
+	return res
+}
+
+// listVariables returns variables for the elements of array or struct.
+func (s *session) listVariables(items []stackitem.Item) []dap.Variable {
+	res := make([]dap.Variable, len(items))
+	for i := range items {
+		res[i] = s.variable("["+strconv.Itoa(i)+"]", "", items[i])
+	}
+	return res
+}
+
+// variable converts stack item to DAP variable, the type is taken from the
+// item itself if not specified. Compound items get a reference to their
+// elements.
+func (s *session) variable(name string, typ string, item stackitem.Item) dap.Variable {
+	res := dap.Variable{
+		Name:  name,
+		Value: formatItem(item),
+		Type:  typ,
+	}
+	if item == nil {
+		return res
+	}
+	if res.Type == "" {
+		res.Type = item.Type().String()
+	}
+	switch it := item.(type) {
+	case *stackitem.Array, *stackitem.Struct:
+		if n := len(it.Value().([]stackitem.Item)); n != 0 {
+			res.VariablesReference = s.addRef(it)
+			res.IndexedVariables = n
+		}
+	case *stackitem.Map:
+		if n := it.Len(); n != 0 {
+			res.VariablesReference = s.addRef(it)
+			res.NamedVariables = n
+		}
+	}
+	return res
+}
+
+// addRef registers variables container and returns its reference.
+func (s *session) addRef(ref any) int {
+	s.refs = append(s.refs, ref)
+	return len(s.refs)
+}
+
+// formatItem returns a short human-readable representation of the item.
+func formatItem(item stackitem.Item) string {
+	switch it := item.(type) {
+	case nil:
+		return "<unavailable>"
+	case stackitem.Null:
+		return "null"
+	case *stackitem.BigInteger:
+		return it.Big().String()
+	case stackitem.Bool:
+		return strconv.FormatBool(bool(it))
+	case *stackitem.ByteArray, *stackitem.Buffer:
+		b, _ := it.TryBytes()
+		return formatBytes(b)
+	case *stackitem.Array, *stackitem.Struct:
+		return fmt.Sprintf("%s[%d]", it.Type(), len(it.Value().([]stackitem.Item)))
+	case *stackitem.Map:
+		return fmt.Sprintf("%s[%d]", it.Type(), it.Len())
+	case *stackitem.Pointer:
+		return fmt.Sprintf("%s(%d)", it.Type(), it.Position())
+	default:
+		return it.Type().String()
+	}
+}
+
+// formatBytes returns quoted string for printable UTF-8 data and hex
+// otherwise.
+func formatBytes(b []byte) string {
+	if len(b) == 0 {
+		return `""`
+	}
+	if s := string(b); strconv.CanBackquote(s) {
+		return strconv.Quote(s)
+	}
+	return fmt.Sprintf("0x%x", b)
+}
+
+func (s *session) newResponse(req *dap.Request) dap.Response {
+	s.seq++
+	return dap.Response{
+		ProtocolMessage: dap.ProtocolMessage{Seq: s.seq, Type: "response"},
+		Command:         req.Command,
+		RequestSeq:      req.Seq,
+		Success:         true,
+	}
+}
+
+func (s *session) newEvent(name string) dap.Event {
+	s.seq++
+	return dap.Event{
+		ProtocolMessage: dap.ProtocolMessage{Seq: s.seq, Type: "event"},
+		Event:           name,
+	}
+}
+
+// sendError sends error response to the request.
+func (s *session) sendError(req *dap.Request, err error) {
+	resp := &dap.ErrorResponse{Response: s.newResponse(req)}
+	resp.Success = false
+	resp.Message = err.Error()
+	resp.Body.Error = &dap.ErrorMessage{Format: err.Error(), ShowUser: true}
+	s.send(resp)
+}
+
+// send writes the message to the client, connection errors are saved to be
+// returned from the request handler.
+func (s *session) send(msg dap.Message) {
+	if s.err != nil {
+		return
+	}
+	if err := dap.WriteProtocolMessage(s.w, msg); err != nil {
+		s.err = fmt.Errorf("failed to write message: %w", err)
+		s.srv.log.Debug("failed to send DAP message", zap.Error(err))
+	}
+}
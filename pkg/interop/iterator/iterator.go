@@ -29,3 +29,53 @@ func Next(it Iterator) bool {
 func Value(it Iterator) any {
 	return neogointernal.Syscall1("System.Iterator.Value", it)
 }
+
+// Seq is a sequence of iterator values. It's an iter.Seq-compatible function
+// type that can be used in range-over-func loops, but in contracts it's just
+// an Iterator, so it can only be obtained from Values.
+type Seq[V any] func(yield func(V) bool)
+
+// Seq2 is a sequence of iterator key-value pairs. It's an iter.Seq2-compatible
+// function type that can be used in range-over-func loops, but in contracts
+// it's just an Iterator, so it can only be obtained from Pairs.
+type Seq2[K, V any] func(yield func(K, V) bool)
+
+// Values returns a sequence of values of the given iterator, each value is
+// type-asserted to V. It allows to range over iterators:
+//
+//	for v := range iterator.Values[int](it) {
+//		...
+//	}
+//
+// The loop is compiled into Next/Value calls, Values itself doesn't emit any
+// code.
+func Values[V any](it Iterator) Seq[V] {
+	return func(yield func(V) bool) {
+		for Next(it) {
+			if !yield(Value(it).(V)) {
+				return
+			}
+		}
+	}
+}
+
+// Pairs returns a sequence of key-value pairs of the given iterator. It's
+// suitable for map iterators and for storage iterators returning key-value
+// pairs (see [storage.KeyValue]):
+//
+//	for k, v := range iterator.Pairs[[]byte, int](storage.Find(ctx, prefix, storage.DeserializeValues)) {
+//		...
+//	}
+//
+// The loop is compiled into Next/Value calls, Pairs itself doesn't emit any
+// code.
+func Pairs[K, V any](it Iterator) Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for Next(it) {
+			kv := Value(it).([]any)
+			if !yield(kv[0].(K), kv[1].(V)) {
+				return
+			}
+		}
+	}
+}
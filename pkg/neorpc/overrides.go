@@ -0,0 +1,88 @@
+package neorpc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+)
+
+type (
+	// StateOverrides is a set of chain state changes applied before test
+	// invocation (invokefunction, invokescript and their historic variants are
+	// supported, it's a NeoGo extension). Changes are only visible to the
+	// invocation and are never persisted. Contracts are changed first, then
+	// storage items and then balances.
+	StateOverrides struct {
+		Contracts []ContractOverride `json:"contracts,omitempty"`
+		Storage   []StorageOverride  `json:"storage,omitempty"`
+		Balances  []BalanceOverride  `json:"balances,omitempty"`
+	}
+
+	// ContractOverride replaces NEF and/or manifest of an existing contract
+	// (keeping its ID and storage) or creates a new contract with the given
+	// hash (both NEF and manifest are required then). Native contracts can't
+	// be changed.
+	ContractOverride struct {
+		Hash util.Uint160 `json:"hash"`
+		// NEF is a serialized NEF file.
+		NEF      []byte             `json:"nef,omitempty"`
+		Manifest *manifest.Manifest `json:"manifest,omitempty"`
+	}
+
+	// StorageOverride puts the contract storage item or deletes it if the
+	// value is nil.
+	StorageOverride struct {
+		Contract util.Uint160 `json:"contract"`
+		Key      []byte       `json:"key"`
+		Value    []byte       `json:"value"`
+	}
+
+	// BalanceOverride sets NEO or GAS balance of the account, the total
+	// supply is adjusted accordingly.
+	BalanceOverride struct {
+		Asset   util.Uint160
+		Account util.Uint160
+		Amount  *big.Int
+	}
+)
+
+// balanceOverrideAux is an auxiliary struct for BalanceOverride JSON
+// marshalling, the amount is a string.
+type balanceOverrideAux struct {
+	Asset   util.Uint160 `json:"asset"`
+	Account util.Uint160 `json:"account"`
+	Amount  string       `json:"amount"`
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (b BalanceOverride) MarshalJSON() ([]byte, error) {
+	if b.Amount == nil {
+		return nil, errors.New("nil amount")
+	}
+	return json.Marshal(&balanceOverrideAux{
+		Asset:   b.Asset,
+		Account: b.Account,
+		Amount:  b.Amount.String(),
+	})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (b *BalanceOverride) UnmarshalJSON(data []byte) error {
+	aux := new(balanceOverrideAux)
+	err := json.Unmarshal(data, aux)
+	if err != nil {
+		return err
+	}
+	amount, ok := new(big.Int).SetString(aux.Amount, 10)
+	if !ok {
+		return fmt.Errorf("invalid amount %q", aux.Amount)
+	}
+	b.Asset = aux.Asset
+	b.Account = aux.Account
+	b.Amount = amount
+	return nil
+}
@@ -0,0 +1,39 @@
+package neorpc
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/internal/testserdes"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateOverridesMarshalUnmarshalJSON(t *testing.T) {
+	o := &StateOverrides{
+		Contracts: []ContractOverride{
+			{Hash: util.Uint160{1}, NEF: []byte{1, 2, 3}},
+			{Hash: util.Uint160{2}, Manifest: manifest.NewManifest("Test")},
+		},
+		Storage: []StorageOverride{
+			{Contract: util.Uint160{1}, Key: []byte{1}, Value: []byte{2}},
+			{Contract: util.Uint160{1}, Key: []byte{3}},
+		},
+		Balances: []BalanceOverride{
+			{Asset: util.Uint160{3}, Account: util.Uint160{4}, Amount: big.NewInt(100500)},
+		},
+	}
+	testserdes.MarshalUnmarshalJSON(t, o, new(StateOverrides))
+
+	data, err := json.Marshal(o.Balances[0])
+	require.NoError(t, err)
+	require.JSONEq(t, `{"asset":"0x0000000000000000000000000000000000000003","account":"0x0000000000000000000000000000000000000004","amount":"100500"}`, string(data))
+
+	_, err = json.Marshal(BalanceOverride{})
+	require.Error(t, err)
+
+	var b BalanceOverride
+	require.Error(t, json.Unmarshal([]byte(`{"asset":"0x0000000000000000000000000000000000000003","account":"0x0000000000000000000000000000000000000004","amount":"1.5"}`), &b))
+}
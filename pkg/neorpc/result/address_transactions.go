@@ -0,0 +1,19 @@
+package result
+
+import (
+	"github.com/nspcc-dev/neo-go/pkg/util"
+)
+
+// AddressTransactions is a result for the getaddresstransactions RPC call.
+type AddressTransactions struct {
+	Address      string               `json:"address"`
+	Transactions []AddressTransaction `json:"transactions"`
+}
+
+// AddressTransaction is a transaction touching some address along with the
+// index and timestamp of the block it's included in.
+type AddressTransaction struct {
+	TxHash    util.Uint256 `json:"txhash"`
+	Index     uint32       `json:"blockindex"`
+	Timestamp uint64       `json:"timestamp"`
+}
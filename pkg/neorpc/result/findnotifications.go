@@ -0,0 +1,64 @@
+package result
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+)
+
+// FindNotifications represents the result of `findnotifications` RPC handler.
+type FindNotifications struct {
+	Results []IndexedNotification `json:"results"`
+	// Next is the cursor of the last scanned notification, it's set if the
+	// result is truncated and can be used to retrieve the next page.
+	Next      []byte `json:"next,omitempty"`
+	Truncated bool   `json:"truncated"`
+}
+
+// IndexedNotification is a notification with the index of the block it was
+// emitted in.
+type IndexedNotification struct {
+	BlockIndex uint32
+	state.ContainedNotificationEvent
+}
+
+// indexedNotificationAux is an auxiliary struct for JSON marshalling.
+type indexedNotificationAux struct {
+	BlockIndex uint32 `json:"blockindex"`
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (n IndexedNotification) MarshalJSON() ([]byte, error) {
+	idx, err := json.Marshal(&indexedNotificationAux{
+		BlockIndex: n.BlockIndex,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal block index: %w", err)
+	}
+	ev, err := json.Marshal(&n.ContainedNotificationEvent)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal notification: %w", err)
+	}
+
+	if idx[len(idx)-1] != '}' || ev[0] != '{' {
+		return nil, errors.New("can't merge internal jsons")
+	}
+	idx[len(idx)-1] = ','
+	idx = append(idx, ev[1:]...)
+	return idx, nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (n *IndexedNotification) UnmarshalJSON(data []byte) error {
+	aux := new(indexedNotificationAux)
+	if err := json.Unmarshal(data, aux); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, &n.ContainedNotificationEvent); err != nil {
+		return err
+	}
+	n.BlockIndex = aux.BlockIndex
+	return nil
+}
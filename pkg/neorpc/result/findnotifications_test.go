@@ -0,0 +1,39 @@
+package result
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/internal/testserdes"
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindNotifications_MarshalUnmarshalJSON(t *testing.T) {
+	res := &FindNotifications{
+		Results: []IndexedNotification{{
+			BlockIndex: 5,
+			ContainedNotificationEvent: state.ContainedNotificationEvent{
+				Container: util.Uint256{1, 2, 3},
+				NotificationEvent: state.NotificationEvent{
+					ScriptHash: util.Uint160{4, 5, 6},
+					Name:       "Transfer",
+					Item:       stackitem.NewArray([]stackitem.Item{stackitem.Make(42)}),
+				},
+			},
+		}},
+		Next:      []byte{1, 2},
+		Truncated: true,
+	}
+	testserdes.MarshalUnmarshalJSON(t, res, new(FindNotifications))
+
+	data, err := json.Marshal(res.Results[0])
+	require.NoError(t, err)
+	var m map[string]any
+	require.NoError(t, json.Unmarshal(data, &m))
+	require.Equal(t, float64(5), m["blockindex"])
+	require.Equal(t, "0x"+res.Results[0].Container.StringLE(), m["container"])
+	require.Equal(t, "Transfer", m["eventname"])
+}
@@ -13,6 +13,9 @@ type (
 		Unconnected Peers `json:"unconnected"`
 		Connected   Peers `json:"connected"`
 		Bad         Peers `json:"bad"`
+		// Banned is a list of peers temporarily banned for misbehavior.
+		// It's a NeoGo extension, so it's omitted if empty.
+		Banned []BannedPeer `json:"banned,omitempty"`
 	}
 
 	// Peers represents a slice of peers.
@@ -25,6 +28,14 @@ type (
 		UserAgent       string `json:"useragent,omitempty"`
 		LastKnownHeight uint32 `json:"lastknownheight,omitempty"`
 	}
+
+	// BannedPeer represents a banned peer.
+	BannedPeer struct {
+		Address string `json:"address"`
+		// Until is the ban expiration time in milliseconds since the
+		// Unix epoch.
+		Until uint64 `json:"until"`
+	}
 )
 
 // NewGetPeers creates a new GetPeers structure.
@@ -51,6 +62,16 @@ func (g *GetPeers) AddBad(addrs []string) {
 	g.Bad.addPeers(addrs)
 }
 
+// AddBanned adds a set of banned peers to the banned peers slice.
+func (g *GetPeers) AddBanned(bans []network.BanInfo) {
+	for _, b := range bans {
+		g.Banned = append(g.Banned, BannedPeer{
+			Address: b.Address,
+			Until:   uint64(b.Until.UnixMilli()),
+		})
+	}
+}
+
 // addPeers adds a set of peers to the given peer slice.
 func (p *Peers) addPeers(addrs []string) {
 	for i := range addrs {
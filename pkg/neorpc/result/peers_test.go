@@ -3,6 +3,7 @@ package result
 import (
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/nspcc-dev/neo-go/pkg/network"
 	"github.com/stretchr/testify/require"
@@ -35,10 +36,17 @@ func TestGetPeers(t *testing.T) {
 	require.Equal(t, uint32(200), gp.Connected[1].LastKnownHeight)
 	require.Equal(t, "127.0.0.1", gp.Bad[0].Address)
 	require.Equal(t, uint16(20333), gp.Bad[0].Port)
+	require.Nil(t, gp.Banned)
+
+	gp.AddBanned([]network.BanInfo{{Address: "10.0.0.1", Until: time.UnixMilli(1700000000000)}})
+	require.Equal(t, []BannedPeer{{Address: "10.0.0.1", Until: 1700000000000}}, gp.Banned)
+	data, err := json.Marshal(gp)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"banned":[{"address":"10.0.0.1","until":1700000000000}]`)
 
 	gps := GetPeers{}
 	newPeerFormat := `{"unconnected": [{"address": "20.109.188.128","port": 10333},{"address": "27.188.182.47","port": 10333}],"connected": [{"address": "54.227.43.72","port": 10333},{"address": "157.90.177.38","port": 10333}],"bad": [{"address": "5.226.142.226","port": 10333},{"address": "54.208.117.178","port": 10333}]}`
-	err := json.Unmarshal([]byte(newPeerFormat), &gps)
+	err = json.Unmarshal([]byte(newPeerFormat), &gps)
 	require.NoError(t, err)
 	badIntFormat := `{"unconnected": [{"address": "20.109.188.128","port": 65536}],"connected": [],"bad": []}`
 	err = json.Unmarshal([]byte(badIntFormat), &gps)
@@ -0,0 +1,28 @@
+package result
+
+import (
+	"github.com/nspcc-dev/neo-go/pkg/util"
+)
+
+// Simulation is a result of the `simulatetransactions` call, it contains
+// results of all simulated transactions in the order of execution.
+type Simulation struct {
+	// GasConsumed is the total amount of GAS consumed by all transactions.
+	GasConsumed  int64                  `json:"gasconsumed,string"`
+	Transactions []SimulatedTransaction `json:"transactions"`
+}
+
+// SimulatedTransaction is a result of a single simulated transaction.
+type SimulatedTransaction struct {
+	// Hash is the transaction hash, for script-based steps it's the hash of
+	// the transaction made from the script and signers.
+	Hash   util.Uint256 `json:"hash"`
+	Result Invoke       `json:"result"`
+	// StorageChanges contains contract storage changes made by the
+	// transaction, it's empty for FAULTed transactions since their changes
+	// are discarded.
+	StorageChanges []StorageChange `json:"storagechanges"`
+	// CumulativeGasConsumed is the amount of GAS consumed by this and all
+	// preceding transactions.
+	CumulativeGasConsumed int64 `json:"cumulativegasconsumed,string"`
+}
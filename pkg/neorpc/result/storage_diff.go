@@ -0,0 +1,29 @@
+package result
+
+import (
+	"github.com/nspcc-dev/neo-go/pkg/util"
+)
+
+// StorageDiff is a result of the `getstoragediff` call, it contains contract
+// storage changes made by a block or a transaction.
+type StorageDiff struct {
+	// Hash is the block or transaction hash.
+	Hash    util.Uint256    `json:"hash"`
+	Added   []StorageChange `json:"added"`
+	Changed []StorageChange `json:"changed"`
+	Deleted []StorageChange `json:"deleted"`
+}
+
+// StorageChange is a single contract storage item change.
+type StorageChange struct {
+	// ID is the storage owner contract ID.
+	ID int32 `json:"id"`
+	// Contract is the storage owner contract hash, it's omitted if the
+	// contract is destroyed.
+	Contract *util.Uint160 `json:"contract,omitempty"`
+	Key      []byte        `json:"key"`
+	// OldValue is the value before the change, it's null for added items.
+	OldValue []byte `json:"oldvalue"`
+	// Value is the value after the change, it's null for deleted items.
+	Value []byte `json:"value"`
+}
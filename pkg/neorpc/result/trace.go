@@ -0,0 +1,133 @@
+package result
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+)
+
+// ExecutionTrace is a result of the `tracetransaction` call, it contains
+// per-instruction trace of the transaction re-executed against the state it
+// was originally executed in.
+type ExecutionTrace struct {
+	TxHash         util.Uint256 `json:"txid"`
+	State          string       `json:"vmstate"`
+	GasConsumed    int64        `json:"gasconsumed,string"`
+	FaultException string       `json:"exception,omitempty"`
+	Steps          []TraceStep  `json:"steps"`
+	// Truncated is set if only a part of executed instructions is returned
+	// because of the node limits.
+	Truncated bool `json:"truncated"`
+}
+
+// TraceStep is a single executed instruction.
+type TraceStep struct {
+	// Contract is the hash of the script the instruction belongs to.
+	Contract util.Uint160
+	// IP is the instruction offset in the script.
+	IP int
+	// Opcode is the instruction opcode.
+	Opcode opcode.Opcode
+	// GasConsumed is the amount of GAS consumed by the instruction itself
+	// (including syscall and storage fees, but excluding instructions of
+	// contracts called by it).
+	GasConsumed int64
+	// Stack is the evaluation stack of the current context before the
+	// instruction execution (top item first), it's only filled if requested.
+	Stack []stackitem.Item
+	// Storage contains contract storage accesses made by the instruction,
+	// it's only filled if requested.
+	Storage []StorageAccess
+}
+
+// StorageAccess is a single contract storage read or write.
+type StorageAccess struct {
+	// Operation is either "get", "put" or "delete".
+	Operation string `json:"op"`
+	// ID is the storage owner contract ID.
+	ID    int32  `json:"id"`
+	Key   []byte `json:"key"`
+	Value []byte `json:"value,omitempty"`
+}
+
+type traceStepAux struct {
+	Contract    util.Uint160      `json:"contract"`
+	IP          int               `json:"ip"`
+	Opcode      string            `json:"opcode"`
+	GasConsumed int64             `json:"gasconsumed,string"`
+	Stack       []json.RawMessage `json:"stack,omitempty"`
+	Storage     []StorageAccess   `json:"storage,omitempty"`
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (s TraceStep) MarshalJSON() ([]byte, error) {
+	aux := traceStepAux{
+		Contract:    s.Contract,
+		IP:          s.IP,
+		Opcode:      s.Opcode.String(),
+		GasConsumed: s.GasConsumed,
+		Storage:     s.Storage,
+	}
+	if s.Stack != nil {
+		aux.Stack = make([]json.RawMessage, len(s.Stack))
+		for i := range s.Stack {
+			data, err := stackitem.ToJSONWithTypes(s.Stack[i])
+			if err != nil {
+				// Items that can't be serialized (too big, too deep or
+				// recursive) are replaced with Null, the rest of the trace
+				// is still useful.
+				data, err = json.Marshal(traceItemError{
+					Type:  stackitem.AnyT.String(),
+					Error: fmt.Sprintf("json error: %s item: %v", s.Stack[i].Type(), err),
+				})
+				if err != nil {
+					return nil, err
+				}
+			}
+			aux.Stack[i] = data
+		}
+	}
+	return json.Marshal(aux)
+}
+
+// traceItemError is a placeholder for stack items that can't be marshaled to
+// JSON, it's decoded as Null.
+type traceItemError struct {
+	Type  string `json:"type"`
+	Error string `json:"error"`
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (s *TraceStep) UnmarshalJSON(data []byte) error {
+	var aux traceStepAux
+	err := json.Unmarshal(data, &aux)
+	if err != nil {
+		return err
+	}
+	op, err := opcode.FromString(aux.Opcode)
+	if err != nil {
+		return err
+	}
+	var stack []stackitem.Item
+	if aux.Stack != nil {
+		stack = make([]stackitem.Item, len(aux.Stack))
+		for i := range aux.Stack {
+			stack[i], err = stackitem.FromJSONWithTypes(aux.Stack[i])
+			if err != nil {
+				return fmt.Errorf("failed to unmarshal stack item %d: %w", i, err)
+			}
+		}
+	}
+	*s = TraceStep{
+		Contract:    aux.Contract,
+		IP:          aux.IP,
+		Opcode:      op,
+		GasConsumed: aux.GasConsumed,
+		Stack:       stack,
+		Storage:     aux.Storage,
+	}
+	return nil
+}
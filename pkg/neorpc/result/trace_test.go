@@ -0,0 +1,48 @@
+package result
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTraceStep_MarshalJSON(t *testing.T) {
+	recursive := stackitem.NewArray(nil)
+	recursive.Append(recursive)
+	step := TraceStep{
+		Contract:    util.Uint160{1, 2, 3},
+		IP:          5,
+		Opcode:      opcode.PUSH1,
+		GasConsumed: 30,
+		Stack: []stackitem.Item{
+			stackitem.Make(1),
+			recursive,
+			stackitem.NewByteArray(make([]byte, stackitem.MaxSize)),
+			stackitem.Make("ok"),
+		},
+	}
+	data, err := json.Marshal(step)
+	require.NoError(t, err)
+
+	var aux struct {
+		Stack []map[string]any `json:"stack"`
+	}
+	require.NoError(t, json.Unmarshal(data, &aux))
+	require.Len(t, aux.Stack, 4)
+	require.Equal(t, "Integer", aux.Stack[0]["type"])
+	require.Equal(t, "Any", aux.Stack[1]["type"])
+	require.Contains(t, aux.Stack[1]["error"], "Array item")
+	require.Equal(t, "Any", aux.Stack[2]["type"])
+	require.Contains(t, aux.Stack[2]["error"], "ByteString item")
+	require.Equal(t, "ByteString", aux.Stack[3]["type"])
+
+	var actual TraceStep
+	require.NoError(t, json.Unmarshal(data, &actual))
+	require.Equal(t, []stackitem.Item{stackitem.Make(1), stackitem.Null{}, stackitem.Null{}, stackitem.NewByteArray([]byte("ok"))}, actual.Stack)
+	require.Equal(t, step.Contract, actual.Contract)
+	require.Equal(t, step.IP, actual.IP)
+}
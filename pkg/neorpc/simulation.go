@@ -0,0 +1,75 @@
+package neorpc
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+)
+
+// SimulationStep is a single step of the simulatetransactions call (a NeoGo
+// extension). It's either a complete transaction (witnesses are not checked,
+// only script and signers matter for the execution) or a script with the set
+// of signers.
+type SimulationStep struct {
+	Transaction *transaction.Transaction
+	Script      []byte
+	Signers     []SignerWithWitness
+}
+
+// simulationStepAux is an auxiliary struct for SimulationStep JSON
+// marshalling, it's used for script-based steps.
+type simulationStepAux struct {
+	Script  []byte              `json:"script"`
+	Signers []SignerWithWitness `json:"signers,omitempty"`
+}
+
+// MarshalJSON implements the json.Marshaler interface. Transaction is
+// marshalled as a base64-encoded string, script with signers is marshalled
+// as an object.
+func (s SimulationStep) MarshalJSON() ([]byte, error) {
+	if s.Transaction != nil {
+		return json.Marshal(s.Transaction.Bytes())
+	}
+	if len(s.Script) == 0 {
+		return nil, errors.New("empty script")
+	}
+	return json.Marshal(&simulationStepAux{
+		Script:  s.Script,
+		Signers: s.Signers,
+	})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (s *SimulationStep) UnmarshalJSON(data []byte) error {
+	var str string
+	if json.Unmarshal(data, &str) == nil {
+		b, err := base64.StdEncoding.DecodeString(str)
+		if err != nil {
+			return err
+		}
+		tx, err := transaction.NewTransactionFromBytes(b)
+		if err != nil {
+			return err
+		}
+		*s = SimulationStep{Transaction: tx}
+		return nil
+	}
+	aux := new(simulationStepAux)
+	err := json.Unmarshal(data, aux)
+	if err != nil {
+		return err
+	}
+	if len(aux.Script) == 0 {
+		return errors.New("empty script")
+	}
+	if len(aux.Signers) > transaction.MaxAttributes {
+		return errors.New("too many signers")
+	}
+	*s = SimulationStep{
+		Script:  aux.Script,
+		Signers: aux.Signers,
+	}
+	return nil
+}
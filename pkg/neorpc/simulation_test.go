@@ -0,0 +1,51 @@
+package neorpc
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/internal/testserdes"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulationStepMarshalUnmarshalJSON(t *testing.T) {
+	t.Run("script", func(t *testing.T) {
+		s := &SimulationStep{
+			Script: []byte{1, 2, 3},
+			Signers: []SignerWithWitness{{
+				Signer: transaction.Signer{
+					Account: util.Uint160{1, 2, 3},
+					Scopes:  transaction.CalledByEntry,
+				},
+			}},
+		}
+		testserdes.MarshalUnmarshalJSON(t, s, new(SimulationStep))
+	})
+	t.Run("transaction", func(t *testing.T) {
+		tx := transaction.New([]byte{1, 2, 3}, 100)
+		tx.Signers = []transaction.Signer{{Account: util.Uint160{1, 2, 3}}}
+		tx.Scripts = []transaction.Witness{{InvocationScript: []byte{}, VerificationScript: []byte{}}}
+
+		data, err := json.Marshal(SimulationStep{Transaction: tx})
+		require.NoError(t, err)
+		require.Equal(t, byte('"'), data[0])
+
+		var actual SimulationStep
+		require.NoError(t, json.Unmarshal(data, &actual))
+		require.NotNil(t, actual.Transaction)
+		require.Equal(t, tx.Hash(), actual.Transaction.Hash())
+		require.Nil(t, actual.Script)
+	})
+	t.Run("errors", func(t *testing.T) {
+		_, err := json.Marshal(SimulationStep{})
+		require.Error(t, err)
+
+		var s SimulationStep
+		require.Error(t, json.Unmarshal([]byte(`"not a base64"`), &s))
+		require.Error(t, json.Unmarshal([]byte(`"AQID"`), &s))
+		require.Error(t, json.Unmarshal([]byte(`{"signers":[]}`), &s))
+		require.Error(t, json.Unmarshal([]byte(`42`), &s))
+	})
+}
@@ -10,13 +10,11 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
 	"github.com/nspcc-dev/neo-go/pkg/core"
 	"github.com/nspcc-dev/neo-go/pkg/core/block"
-	"github.com/nspcc-dev/neo-go/pkg/core/fee"
 	"github.com/nspcc-dev/neo-go/pkg/core/native/nativenames"
 	"github.com/nspcc-dev/neo-go/pkg/core/state"
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
-	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/neotest/internal/harness"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
-	"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/trigger"
 	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/nspcc-dev/neo-go/pkg/vm"
@@ -34,22 +32,29 @@ type Executor struct {
 	CommitteeHash util.Uint160
 	// collectCoverage is true if coverage is being collected when running this executor.
 	collectCoverage bool
+	// profileGas is true if GAS profile is being collected when running this executor.
+	profileGas bool
 }
 
 // NewExecutor creates a new executor instance from the provided blockchain and committee.
-// By default coverage collection is enabled, but only when `go test` is running with coverage enabled.
+// By default coverage collection is enabled, but only when `go test` is running with coverage enabled
+// or NEOTEST_COVER_REPORT/NEOTEST_COVER_DIR environment variable is set.
 // Use DisableCoverage and EnableCoverage to stop coverage collection for this executor when not desired.
+// GAS profiling is enabled by default when NEOTEST_GAS_PROFILE environment variable is set, use
+// DisableGasProfiling and EnableGasProfiling to control it.
 func NewExecutor(t testing.TB, bc *core.Blockchain, validator, committee Signer) *Executor {
 	checkMultiSigner(t, validator)
 	checkMultiSigner(t, committee)
 
-	return &Executor{
+	e := &Executor{
 		Chain:           bc,
 		Validator:       validator,
 		Committee:       committee,
 		CommitteeHash:   committee.ScriptHash(),
 		collectCoverage: isCoverageEnabled(t),
 	}
+	e.EnableGasProfiling(t)
+	return e
 }
 
 // TopBlock returns the block with the highest index.
@@ -174,8 +179,13 @@ func (e *Executor) DeployContractCheckFAULT(t testing.TB, c *Contract, data any,
 	e.CheckFault(t, tx.Hash(), errMessage)
 }
 
-// trackCoverage switches on coverage tracking for provided script if `go test` is running with coverage enabled.
+// trackCoverage switches on coverage tracking for provided script if `go test` is running with coverage enabled
+// or neotest coverage report is requested.
+// It also registers the script in GAS profiler if profiling is enabled.
 func (e *Executor) trackCoverage(t testing.TB, c *Contract) {
+	if e.profileGas {
+		addScriptToGasProfile(c)
+	}
 	if e.collectCoverage {
 		addScriptToCoverage(c)
 		t.Cleanup(func() {
@@ -307,61 +317,23 @@ func (e *Executor) AddSystemFee(tx *transaction.Transaction, sysFee int64) {
 		tx.SystemFee = sysFee
 		return
 	}
-	v, _ := e.TestInvoke(tx) // ignore error to support failing transactions
+	v, _ := e.testInvoke(tx, false) // ignore error to support failing transactions
 	tx.SystemFee = v.GasConsumed()
 }
 
 // AddNetworkFee adds network fee to the transaction.
 func AddNetworkFee(t testing.TB, bc *core.Blockchain, tx *transaction.Transaction, signers ...Signer) {
-	baseFee := bc.GetBaseExecFee()
-	size := io.GetVarSize(tx)
+	sgrs := make([]harness.Signer, 0, len(signers))
 	for _, sgr := range signers {
-		csgr, ok := sgr.(SingleSigner)
-		if ok && csgr.Account().Contract.InvocationBuilder != nil {
-			sc, err := csgr.Account().Contract.InvocationBuilder(tx)
-			require.NoError(t, err)
-
-			txCopy := *tx
-			ic, err := bc.GetTestVM(trigger.Verification, &txCopy, nil)
-			require.NoError(t, err)
-
-			ic.UseSigners(tx.Signers)
-			ic.VM.GasLimit = bc.GetMaxVerificationGAS()
-
-			require.NoError(t, bc.InitVerificationContext(ic, csgr.ScriptHash(), &transaction.Witness{InvocationScript: sc, VerificationScript: csgr.Script()}))
-			require.NoError(t, ic.VM.Run())
-
-			tx.NetworkFee += ic.VM.GasConsumed()
-			size += io.GetVarSize(sc) + io.GetVarSize(csgr.Script())
-		} else {
-			netFee, sizeDelta := fee.Calculate(baseFee, sgr.Script())
-			tx.NetworkFee += netFee
-			size += sizeDelta
-		}
+		sgrs = append(sgrs, sgr)
 	}
-	tx.NetworkFee += int64(size)*bc.FeePerByte() + bc.CalculateAttributesFee(tx)
+	require.NoError(t, harness.AddNetworkFee(bc, tx, sgrs...))
 }
 
 // NewUnsignedBlock creates a new unsigned block from txs.
 func (e *Executor) NewUnsignedBlock(t testing.TB, txs ...*transaction.Transaction) *block.Block {
-	lastBlock := e.TopBlock(t)
-	b := &block.Block{
-		Header: block.Header{
-			NextConsensus: e.Validator.ScriptHash(),
-			Script: transaction.Witness{
-				VerificationScript: e.Validator.Script(),
-			},
-			Timestamp: lastBlock.Timestamp + 1,
-		},
-		Transactions: txs,
-	}
-	if e.Chain.GetConfig().StateRootInHeader {
-		b.StateRootEnabled = true
-		b.PrevStateRoot = e.Chain.GetStateModule().CurrentLocalStateRoot()
-	}
-	b.PrevHash = lastBlock.Hash()
-	b.Index = e.Chain.BlockHeight() + 1
-	b.RebuildMerkleRoot()
+	b, err := harness.NewUnsignedBlock(e.Chain, e.Validator, txs...)
+	require.NoError(t, err)
 	return b
 }
 
@@ -370,6 +342,7 @@ func (e *Executor) AddNewBlock(t testing.TB, txs ...*transaction.Transaction) *b
 	b := e.NewUnsignedBlock(t, txs...)
 	e.SignBlock(b)
 	require.NoError(t, e.Chain.AddBlock(b))
+	e.profileBlock(t, b.Transactions)
 	return b
 }
 
@@ -382,11 +355,26 @@ func (e *Executor) GenerateNewBlocks(t testing.TB, count int) []*block.Block {
 	return blocks
 }
 
+// Snapshot saves the current chain state and returns its identifier to be used
+// with Revert. It allows to share some common (expensive) setup between
+// several test cases. The chain must use storage.SnapshotStore, which is the
+// default for chains created by the chain package.
+func (e *Executor) Snapshot(t testing.TB) int {
+	id, err := e.Chain.Snapshot()
+	require.NoError(t, err)
+	return id
+}
+
+// Revert restores the chain state saved with Snapshot, all blocks and
+// transactions added after it are dropped. The same snapshot can be reverted
+// to several times.
+func (e *Executor) Revert(t testing.TB, id int) {
+	require.NoError(t, e.Chain.Revert(id))
+}
+
 // SignBlock add validators signature to b.
 func (e *Executor) SignBlock(b *block.Block) *block.Block {
-	invoc := e.Validator.SignHashable(uint32(e.Chain.GetConfig().Magic), b)
-	b.Script.InvocationScript = invoc
-	return b
+	return harness.SignBlock(e.Chain, e.Validator, b)
 }
 
 // AddBlockCheckHalt is a convenient wrapper over AddBlock and CheckHalt.
@@ -400,30 +388,29 @@ func (e *Executor) AddBlockCheckHalt(t testing.TB, txs ...*transaction.Transacti
 
 // TestInvoke creates a test VM with a dummy block and executes a transaction in it.
 func (e *Executor) TestInvoke(tx *transaction.Transaction) (*vm.VM, error) {
-	lastBlock, err := e.Chain.GetBlock(e.Chain.GetHeaderHash(e.Chain.BlockHeight()))
+	return e.testInvoke(tx, true)
+}
+
+// testInvoke is the same as TestInvoke, but allows to exclude the invocation
+// from GAS profile (which is used for fee estimation that is followed by the
+// real execution).
+func (e *Executor) testInvoke(tx *transaction.Transaction, profile bool) (*vm.VM, error) {
+	ic, err := harness.NewTestVM(e.Chain, tx)
 	if err != nil {
 		return nil, err
 	}
-	b := &block.Block{
-		Header: block.Header{
-			Index:     e.Chain.BlockHeight() + 1,
-			Timestamp: lastBlock.Timestamp + 1,
-		},
-	}
-
-	// `GetTestVM` as well as `Run` can use a transaction hash which will set a cached value.
-	// This is unwanted behavior, so we explicitly copy the transaction to perform execution.
-	ttx := *tx
-	ic, _ := e.Chain.GetTestVM(trigger.Application, &ttx, b)
 
-	if e.collectCoverage {
-		ic.VM.SetOnExecHook(coverageHook)
+	var flush = func() {}
+	if profile {
+		flush = e.setExecHooks(ic.VM)
+	} else if e.collectCoverage {
+		ic.VM.SetOnExecHook(newCoverageHook())
 	}
 
 	defer ic.Finalize()
 
-	ic.VM.LoadWithFlags(tx.Script, callflag.All)
 	err = ic.VM.Run()
+	flush()
 	return ic.VM, err
 }
 
@@ -460,3 +447,27 @@ func (e *Executor) EnableCoverage(t testing.TB) {
 func (e *Executor) DisableCoverage() {
 	e.collectCoverage = false
 }
+
+// EnableGasProfiling enables GAS profiling for this executor, but only when
+// NEOTEST_GAS_PROFILE environment variable is set. Test invocations and
+// transactions of the blocks added to the chain (replayed after the block
+// acceptance, which requires historic states to be kept) are profiled. Profile is
+// written to the file specified by this variable once the tests are completed,
+// see RunWithGasProfile.
+func (e *Executor) EnableGasProfiling(t testing.TB) {
+	if e.profileGas || !isGasProfilingEnabled() {
+		return
+	}
+	e.profileGas = true
+	addNativesToGasProfile(e.Chain)
+	acquireGasProfile()
+	t.Cleanup(func() {
+		releaseGasProfile(t)
+	})
+}
+
+// DisableGasProfiling disables GAS profiling for this executor until enabled
+// explicitly through EnableGasProfiling.
+func (e *Executor) DisableGasProfiling() {
+	e.profileGas = false
+}
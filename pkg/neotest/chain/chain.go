@@ -1,10 +1,8 @@
 package chain
 
 import (
-	"encoding/hex"
 	"slices"
 	"testing"
-	"time"
 
 	"github.com/nspcc-dev/neo-go/pkg/config"
 	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
@@ -13,6 +11,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/core/storage"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
 	"github.com/nspcc-dev/neo-go/pkg/neotest"
+	"github.com/nspcc-dev/neo-go/pkg/neotest/internal/harness"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
 	"github.com/nspcc-dev/neo-go/pkg/wallet"
 	"github.com/stretchr/testify/require"
@@ -23,14 +22,14 @@ import (
 const (
 	// MaxTraceableBlocks is the default MaxTraceableBlocks setting used for test chains.
 	// We don't need a lot of traceable blocks for tests.
-	MaxTraceableBlocks = 1000
+	MaxTraceableBlocks = harness.MaxTraceableBlocks
 
 	// TimePerBlock is the default TimePerBlock setting used for test chains (1s).
 	// Usually blocks are created by tests bypassing this setting.
-	TimePerBlock = time.Second
+	TimePerBlock = harness.TimePerBlock
 )
 
-const singleValidatorWIF = "KxyjQ8eUa4FHt3Gvioyt1Wz29cTUrE4eTqX3yFSk1YFCsPL8uNsY"
+const singleValidatorWIF = harness.SingleValidatorWIF
 
 // committeeWIFs is a list of unencrypted WIFs sorted by the public key.
 var committeeWIFs = []string{
@@ -62,13 +61,16 @@ var (
 // Options contains parameters to customize parameters of the test chain.
 type Options struct {
 	// Logger allows to customize logging performed by the test chain.
-	// If Logger is not set, zaptest.Logger will be used with default configuration.
+	// If Logger is not set, zaptest.Logger will be used with default configuration
+	// (logging is disabled for fuzz tests since testing.F can't be used to
+	// log from the fuzz function).
 	Logger *zap.Logger
 	// BlockchainConfigHook function is sort of visitor pattern for blockchain configuration.
 	// It takes in the default configuration as an argument and can perform any adjustments in it.
 	BlockchainConfigHook func(*config.Blockchain)
 	// Store allows to customize storage for blockchain data.
-	// If Store is not set, MemoryStore is used by default.
+	// If Store is not set, MemoryStore wrapped into SnapshotStore is used by
+	// default (so that Snapshot and Revert can be used with the chain).
 	Store storage.Store
 	// If SkipRun is false, then the blockchain will be started (if its' construction
 	// has succeeded) and will be registered for cleanup when the test completes.
@@ -81,17 +83,12 @@ type Options struct {
 }
 
 func init() {
-	committeeAcc, _ = wallet.NewAccountFromWIF(singleValidatorWIF)
-	pubs := keys.PublicKeys{committeeAcc.PublicKey()}
-	err := committeeAcc.ConvertMultisig(1, pubs)
-	if err != nil {
-		panic(err)
-	}
+	committeeAcc = harness.SingleValidator()
 
 	mc := smartcontract.GetMajorityHonestNodeCount(len(committeeWIFs))
 	mv := smartcontract.GetDefaultHonestNodeCount(4)
 	accs := make([]*wallet.Account, len(committeeWIFs))
-	pubs = make(keys.PublicKeys, len(accs))
+	pubs := make(keys.PublicKeys, len(accs))
 	for i := range committeeWIFs {
 		accs[i], _ = wallet.NewAccountFromWIF(committeeWIFs[i])
 		pubs[i] = accs[i].PublicKey()
@@ -142,8 +139,8 @@ func init() {
 // NewSingle creates a new blockchain instance with a single validator and
 // setups cleanup functions. The configuration used is with netmode.UnitTestNet
 // magic and TimePerBlock/MaxTraceableBlocks options defined by constants in
-// this package. MemoryStore is used as the backend storage, so all of the chain
-// contents is always in RAM. The Signer returned is the validator (and the committee at
+// this package. MemoryStore (wrapped into SnapshotStore) is used as the backend
+// storage, so all of the chain contents is always in RAM. The Signer returned is the validator (and the committee at
 // the same time).
 func NewSingle(t testing.TB) (*core.Blockchain, neotest.Signer) {
 	return NewSingleWithCustomConfig(t, nil)
@@ -175,32 +172,19 @@ func NewSingleWithOptions(t testing.TB, options *Options) (*core.Blockchain, neo
 		options = &Options{}
 	}
 
-	cfg := config.Blockchain{
-		ProtocolConfiguration: config.ProtocolConfiguration{
-			Magic:                       netmode.UnitTestNet,
-			MaxTraceableBlocks:          MaxTraceableBlocks,
-			MaxValidUntilBlockIncrement: MaxTraceableBlocks / 2,
-			TimePerBlock:                TimePerBlock,
-			Genesis: config.Genesis{
-				TimePerBlock: TimePerBlock,
-			},
-			StandbyCommittee:   []string{hex.EncodeToString(committeeAcc.PublicKey().Bytes())},
-			ValidatorsCount:    1,
-			VerifyTransactions: true,
-		},
-	}
+	cfg := harness.SingleConfig(committeeAcc.PublicKey())
 	if options.BlockchainConfigHook != nil {
 		options.BlockchainConfigHook(&cfg)
 	}
 
 	store := options.Store
 	if store == nil {
-		store = storage.NewMemoryStore()
+		store = storage.NewSnapshotStore(storage.NewMemoryStore())
 	}
 
 	logger := options.Logger
 	if logger == nil {
-		logger = zaptest.NewLogger(t)
+		logger = newLogger(t)
 	}
 
 	bc, err := core.NewBlockchain(store, cfg, logger, options.NewNatives)
@@ -277,12 +261,12 @@ func NewMultiWithOptionsNoCheck(t testing.TB, options *Options) (*core.Blockchai
 
 	store := options.Store
 	if store == nil {
-		store = storage.NewMemoryStore()
+		store = storage.NewSnapshotStore(storage.NewMemoryStore())
 	}
 
 	logger := options.Logger
 	if logger == nil {
-		logger = zaptest.NewLogger(t)
+		logger = newLogger(t)
 	}
 
 	bc, err := core.NewBlockchain(store, bcfg, logger)
@@ -292,3 +276,11 @@ func NewMultiWithOptionsNoCheck(t testing.TB, options *Options) (*core.Blockchai
 	}
 	return bc, neotest.NewMultiSigner(multiValidatorAcc...), neotest.NewMultiSigner(multiCommitteeAcc...), err
 }
+
+// newLogger returns the default logger for the test chain.
+func newLogger(t testing.TB) *zap.Logger {
+	if _, ok := t.(*testing.F); ok {
+		return zap.NewNop()
+	}
+	return zaptest.NewLogger(t)
+}
@@ -3,5 +3,18 @@ Package chain contains functions creating new test blockchain instances.
 Different configurations can be used, but all chains created here use
 well-known keys. Most of the time, a single-node chain is the best choice to use
 unless you specifically need multiple validators and a large committee.
+
+NewSingleFork creates a single-node chain forked from the state of some remote
+network at the given height. Deployed contracts, their storage and GAS balances
+are lazily retrieved from the remote RPC node (that should keep historical
+states), so contracts can be tested against the real network data:
+
+	c, err := rpcclient.New(context.Background(), "http://localhost:10332", rpcclient.Options{})
+	require.NoError(t, err)
+	bc, acc := chain.NewSingleFork(t, c, 5000000)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+
+Changes made by test transactions are kept locally and never affect the remote
+node.
 */
 package chain
@@ -0,0 +1,329 @@
+package chain
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/core"
+	"github.com/nspcc-dev/neo-go/pkg/core/mpt"
+	"github.com/nspcc-dev/neo-go/pkg/core/native/nativehashes"
+	"github.com/nspcc-dev/neo-go/pkg/core/native/nativeids"
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/core/storage"
+	"github.com/nspcc-dev/neo-go/pkg/encoding/bigint"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc/result"
+	"github.com/nspcc-dev/neo-go/pkg/neotest"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+// ForkClient is a set of RPC methods used to fetch the state of the remote
+// chain for a forked test chain. It's implemented by rpcclient.Client.
+type ForkClient interface {
+	GetStateRootByHeight(height uint32) (*state.MPTRoot, error)
+	GetProof(stateroot util.Uint256, historicalContractHash util.Uint160, historicalKey []byte) (*result.ProofWithKey, error)
+	FindStates(stateroot util.Uint256, historicalContractHash util.Uint160, historicalPrefix []byte, start []byte, maxCount *int) (result.FindStates, error)
+}
+
+// Management contract storage keys used by the fork store, see native
+// ContractManagement implementation.
+const (
+	prefixContractHash = 12
+	keyNextAvailableID = 15
+)
+
+// forkStore is a storage.Store that keeps all data in memory and falls back to
+// the remote node state at the given root for contract storage items missing
+// locally. Storage of deployed contracts as well as ContractManagement and
+// GasToken storage is forked, other native contracts are local-only, so the
+// test chain keeps its own committee and validators. Remote items are fetched
+// once and are stored locally, local changes always take precedence over them.
+type forkStore struct {
+	t      testing.TB
+	remote ForkClient
+	root   util.Uint256
+	local  *storage.MemoryStore
+
+	lock    sync.Mutex
+	enabled bool
+	lastID  int32
+	deleted map[string]struct{}
+	fetched map[string]struct{}
+	pulled  []string
+	hashes  map[int32]util.Uint160
+}
+
+// NewSingleFork creates a new blockchain instance with a single validator that
+// uses the state of the remote chain at the given height. It works the same way
+// as NewSingle, but contracts deployed on the remote chain, their storage and
+// GAS balances are available to the test chain. Remote data is retrieved
+// lazily with getproof (verified against the state root) and findstates RPC
+// calls, so the remote node must keep historical states
+// (KeepOnlyLatestState disabled). Native contracts (except for
+// ContractManagement and GasToken) are not forked, the committee and
+// validators are the same as for NewSingle.
+func NewSingleFork(t testing.TB, remote ForkClient, height uint32) (*core.Blockchain, neotest.Signer) {
+	return NewSingleForkWithOptions(t, remote, height, nil)
+}
+
+// NewSingleForkWithOptions is similar to NewSingleFork, but allows to specify
+// additional options. Custom Store can't be used for the forked chain.
+func NewSingleForkWithOptions(t testing.TB, remote ForkClient, height uint32, options *Options) (*core.Blockchain, neotest.Signer) {
+	var opts Options
+	if options != nil {
+		opts = *options
+	}
+	require.Nil(t, opts.Store, "custom store can't be used with a forked chain")
+
+	root, err := remote.GetStateRootByHeight(height)
+	require.NoError(t, err)
+	st := &forkStore{
+		t:       t,
+		remote:  remote,
+		root:    root.Root,
+		local:   storage.NewMemoryStore(),
+		deleted: make(map[string]struct{}),
+		fetched: make(map[string]struct{}),
+		hashes: map[int32]util.Uint160{
+			nativeids.ContractManagement: nativehashes.ContractManagement,
+			nativeids.GasToken:           nativehashes.GasToken,
+		},
+	}
+
+	// Genesis block is created locally without remote data, then the
+	// chain is reopened with the fork enabled to initialize native
+	// contract caches (the list of deployed contracts in particular)
+	// from the merged state.
+	run := !opts.SkipRun
+	opts.Store = storage.NewSnapshotStore(st)
+	opts.SkipRun = true
+	bc, _ := NewSingleWithOptions(t, &opts)
+	go bc.Run()
+	bc.Close()
+	require.NoError(t, st.enable())
+
+	opts.SkipRun = !run
+	return NewSingleWithOptions(t, &opts)
+}
+
+// enable turns on remote data retrieval. Contract IDs are taken from the
+// remote chain, so that contracts deployed locally don't conflict with the
+// remote ones.
+func (s *forkStore) enable() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	key := makeForkKey(nativeids.ContractManagement, []byte{keyNextAvailableID})
+	v, err := s.fetch(key)
+	if err != nil {
+		return err
+	}
+	if v == nil {
+		return errors.New("remote next available contract ID is missing")
+	}
+	s.lastID = int32(bigint.FromBytes(v).Int64()) - 1
+	s.fetched[string(key)] = struct{}{}
+	s.enabled = true
+	return s.local.PutChangeSet(nil, map[string][]byte{string(key): v})
+}
+
+// makeForkKey creates a storage key for the given contract ID and item key.
+func makeForkKey(id int32, key []byte) []byte {
+	k := make([]byte, 5+len(key))
+	k[0] = byte(storage.STStorage)
+	binary.LittleEndian.PutUint32(k[1:], uint32(id))
+	copy(k[5:], key)
+	return k
+}
+
+// forkedID returns contract ID for the storage key if this key belongs to the
+// forked contract.
+func (s *forkStore) forkedID(key []byte) (int32, bool) {
+	if !s.enabled || len(key) < 5 || storage.KeyPrefix(key[0]) != storage.STStorage {
+		return 0, false
+	}
+	id := int32(binary.LittleEndian.Uint32(key[1:]))
+	return id, (id > 0 && id <= s.lastID) || id == nativeids.ContractManagement || id == nativeids.GasToken
+}
+
+// isPulled checks whether all remote items with the given key prefix are
+// already stored locally.
+func (s *forkStore) isPulled(key []byte) bool {
+	for _, p := range s.pulled {
+		if strings.HasPrefix(string(key), p) {
+			return true
+		}
+	}
+	return false
+}
+
+// contractHash returns the hash of the forked contract with the given ID, zero
+// hash is returned for contracts missing on the remote chain.
+func (s *forkStore) contractHash(id int32) (util.Uint160, error) {
+	if h, ok := s.hashes[id]; ok {
+		return h, nil
+	}
+	hashKey := make([]byte, 5)
+	hashKey[0] = prefixContractHash
+	binary.BigEndian.PutUint32(hashKey[1:], uint32(id))
+	v, err := s.get(makeForkKey(nativeids.ContractManagement, hashKey))
+	if errors.Is(err, storage.ErrKeyNotFound) {
+		return util.Uint160{}, nil
+	}
+	if err != nil {
+		return util.Uint160{}, err
+	}
+	h, err := util.Uint160DecodeBytesBE(v)
+	if err != nil {
+		return util.Uint160{}, fmt.Errorf("invalid hash of contract %d: %w", id, err)
+	}
+	s.hashes[id] = h
+	return h, nil
+}
+
+// fetch retrieves the value of the storage item from the remote node and
+// verifies it against the state root. It returns nil if there is no such item.
+func (s *forkStore) fetch(key []byte) ([]byte, error) {
+	id := int32(binary.LittleEndian.Uint32(key[1:]))
+	h, err := s.contractHash(id)
+	if err != nil || h.Equals(util.Uint160{}) {
+		return nil, err
+	}
+	p, err := s.remote.GetProof(s.root, h, key[5:])
+	if err != nil {
+		if errors.Is(err, neorpc.ErrUnknownStorageItem) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get proof for %x: %w", key, err)
+	}
+	if !bytes.Equal(p.Key, key[1:]) {
+		return nil, fmt.Errorf("proof key mismatch: expected %x, got %x", key[1:], p.Key)
+	}
+	v, ok := mpt.VerifyProof(s.root, p.Key, p.Proof)
+	if !ok {
+		return nil, fmt.Errorf("invalid proof for %x", key)
+	}
+	return v, nil
+}
+
+// pull stores all remote items with the given prefix locally unless they're
+// changed locally.
+func (s *forkStore) pull(prefix []byte) error {
+	id, ok := s.forkedID(prefix)
+	if !ok || s.isPulled(prefix) {
+		return nil
+	}
+	h, err := s.contractHash(id)
+	if err != nil {
+		return err
+	}
+	if !h.Equals(util.Uint160{}) {
+		var start []byte
+		for {
+			res, err := s.remote.FindStates(s.root, h, prefix[5:], start, nil)
+			if err != nil {
+				return fmt.Errorf("failed to find states for %x: %w", prefix, err)
+			}
+			puts := make(map[string][]byte, len(res.Results))
+			for _, kv := range res.Results {
+				k := string(makeForkKey(id, kv.Key))
+				if _, ok := s.deleted[k]; ok {
+					continue
+				}
+				if _, err := s.local.Get([]byte(k)); err == nil {
+					continue
+				}
+				puts[k] = kv.Value
+			}
+			if err := s.local.PutChangeSet(nil, puts); err != nil {
+				return err
+			}
+			if !res.Truncated || len(res.Results) == 0 {
+				break
+			}
+			start = res.Results[len(res.Results)-1].Key
+		}
+	}
+	s.pulled = append(s.pulled, string(prefix))
+	return nil
+}
+
+// get is an internal unlocked implementation of Get.
+func (s *forkStore) get(key []byte) ([]byte, error) {
+	v, err := s.local.Get(key)
+	if !errors.Is(err, storage.ErrKeyNotFound) {
+		return v, err
+	}
+	if _, ok := s.forkedID(key); !ok || s.isPulled(key) {
+		return nil, storage.ErrKeyNotFound
+	}
+	if _, ok := s.deleted[string(key)]; ok {
+		return nil, storage.ErrKeyNotFound
+	}
+	if _, ok := s.fetched[string(key)]; ok {
+		return nil, storage.ErrKeyNotFound
+	}
+	v, err = s.fetch(key)
+	if err != nil {
+		return nil, err
+	}
+	s.fetched[string(key)] = struct{}{}
+	if v == nil {
+		return nil, storage.ErrKeyNotFound
+	}
+	return v, s.local.PutChangeSet(nil, map[string][]byte{string(key): v})
+}
+
+// Get implements the storage.Store interface.
+func (s *forkStore) Get(key []byte) ([]byte, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	v, err := s.get(key)
+	if err != nil && !errors.Is(err, storage.ErrKeyNotFound) {
+		s.t.Errorf("forked chain: %s", err)
+	}
+	return v, err
+}
+
+// PutChangeSet implements the storage.Store interface.
+func (s *forkStore) PutChangeSet(puts map[string][]byte, stor map[string][]byte) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for k, v := range stor {
+		if v == nil {
+			s.deleted[k] = struct{}{}
+		} else {
+			delete(s.deleted, k)
+		}
+	}
+	return s.local.PutChangeSet(puts, stor)
+}
+
+// Seek implements the storage.Store interface. All remote items matching the
+// prefix are retrieved before iterating over the local ones.
+func (s *forkStore) Seek(rng storage.SeekRange, f func(k, v []byte) bool) {
+	s.lock.Lock()
+	err := s.pull(rng.Prefix)
+	s.lock.Unlock()
+	if err != nil {
+		s.t.Errorf("forked chain: %s", err)
+	}
+	s.local.Seek(rng, f)
+}
+
+// SeekGC implements the storage.Store interface.
+func (s *forkStore) SeekGC(rng storage.SeekRange, keep func(k, v []byte) (bool, bool)) error {
+	return s.local.SeekGC(rng, keep)
+}
+
+// Close implements the storage.Store interface. It's a no-op, local data
+// is kept in memory to be reused when the chain is reopened.
+func (s *forkStore) Close() error {
+	return nil
+}
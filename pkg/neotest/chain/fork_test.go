@@ -0,0 +1,136 @@
+package chain
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/internal/random"
+	"github.com/nspcc-dev/neo-go/pkg/compiler"
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/core"
+	"github.com/nspcc-dev/neo-go/pkg/neotest"
+	"github.com/nspcc-dev/neo-go/pkg/network"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient"
+	"github.com/nspcc-dev/neo-go/pkg/services/rpcsrv"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+const forkTestContract = `package storage
+import (
+	"github.com/nspcc-dev/neo-go/pkg/interop/iterator"
+	"github.com/nspcc-dev/neo-go/pkg/interop/storage"
+)
+func Put(k, v []byte) {
+	storage.Put(storage.GetContext(), k, v)
+}
+func Delete(k []byte) {
+	storage.Delete(storage.GetContext(), k)
+}
+func Get(k []byte) any {
+	return storage.Get(storage.GetReadOnlyContext(), k)
+}
+func Count(prefix []byte) int {
+	var n int
+	it := storage.Find(storage.GetReadOnlyContext(), prefix, storage.KeysOnly)
+	for iterator.Next(it) {
+		n++
+	}
+	return n
+}`
+
+// newTestRPCClient starts RPC server for the given chain and returns a client
+// connected to it.
+func newTestRPCClient(t *testing.T, bc *core.Blockchain) *rpcclient.Client {
+	cfg := config.Config{
+		ProtocolConfiguration: bc.GetConfig().ProtocolConfiguration,
+		ApplicationConfiguration: config.ApplicationConfiguration{
+			RPC: config.RPC{
+				BasicService: config.BasicService{
+					Enabled:   true,
+					Addresses: []string{"localhost:0"},
+				},
+				MaxFindResultItems: 2, // Check pagination.
+			},
+		},
+	}
+	logger := zaptest.NewLogger(t)
+	serverConfig, err := network.NewServerConfig(cfg)
+	require.NoError(t, err)
+	netSrv, err := network.NewServer(serverConfig, bc, bc.GetStateSyncModule(), logger)
+	require.NoError(t, err)
+	rpcSrv := rpcsrv.New(bc, cfg.ApplicationConfiguration.RPC, netSrv, nil, logger, make(chan error, 2))
+	rpcSrv.Start()
+	t.Cleanup(rpcSrv.Shutdown)
+
+	c, err := rpcclient.New(context.Background(), "http://"+rpcSrv.Addresses()[0], rpcclient.Options{})
+	require.NoError(t, err)
+	t.Cleanup(c.Close)
+	return c
+}
+
+func TestNewSingleFork(t *testing.T) {
+	rbc, racc := NewSingle(t)
+	re := neotest.NewExecutor(t, rbc, racc, racc)
+	ctr := neotest.CompileSource(t, re.CommitteeHash, strings.NewReader(forkTestContract), &compiler.Options{Name: "storage"})
+	re.DeployContract(t, ctr, nil)
+
+	rinv := re.CommitteeInvoker(ctr.Hash)
+	for _, k := range []string{"k1", "k2", "k3", "k4", "k5", "x"} {
+		rinv.Invoke(t, stackitem.Null{}, "put", k, "v"+k)
+	}
+	receiver := random.Uint160()
+	re.CommitteeInvoker(re.NativeHash(t, "GasToken")).Invoke(t, true, "transfer", re.CommitteeHash, receiver, 12345, nil)
+	height := rbc.BlockHeight()
+
+	// Changes made after the fork height must not be visible.
+	rinv.Invoke(t, stackitem.Null{}, "put", "k1", "changed")
+	rinv.Invoke(t, stackitem.Null{}, "put", "k6", "vk6")
+
+	bc, acc := NewSingleFork(t, newTestRPCClient(t, rbc), height)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+
+	cs := bc.GetContractState(ctr.Hash)
+	require.NotNil(t, cs)
+	require.Equal(t, ctr.Hash, cs.Hash)
+
+	inv := e.CommitteeInvoker(ctr.Hash)
+	inv.Invoke(t, stackitem.NewByteArray([]byte("vk1")), "get", "k1")
+	inv.Invoke(t, stackitem.Null{}, "get", "k6")
+	inv.Invoke(t, 5, "count", "k")
+
+	inv.Invoke(t, stackitem.Null{}, "put", "k1", "local")
+	inv.Invoke(t, stackitem.Null{}, "delete", "k2")
+	inv.Invoke(t, stackitem.Null{}, "put", "k7", "vk7")
+	inv.Invoke(t, stackitem.NewByteArray([]byte("local")), "get", "k1")
+	inv.Invoke(t, stackitem.Null{}, "get", "k2")
+	inv.Invoke(t, 5, "count", "k")
+	inv.Invoke(t, stackitem.NewByteArray([]byte("vx")), "get", "x")
+
+	gas := e.CommitteeInvoker(e.NativeHash(t, "GasToken"))
+	gas.Invoke(t, 12345, "balanceOf", receiver)
+
+	id := e.Snapshot(t)
+	inv.Invoke(t, stackitem.Null{}, "delete", "x")
+	inv.Invoke(t, stackitem.Null{}, "get", "x")
+	e.Revert(t, id)
+	inv.Invoke(t, stackitem.NewByteArray([]byte("vx")), "get", "x")
+
+	// Locally deployed contracts don't conflict with the remote ones.
+	local := neotest.CompileSource(t, e.CommitteeHash, strings.NewReader(strings.Replace(forkTestContract, "n++", "n += 2", 1)), &compiler.Options{Name: "local"})
+	e.DeployContract(t, local, nil)
+	lcs := bc.GetContractState(local.Hash)
+	require.NotNil(t, lcs)
+	require.Equal(t, cs.ID+1, lcs.ID)
+	linv := e.CommitteeInvoker(local.Hash)
+	linv.Invoke(t, stackitem.Null{}, "put", "k1", "v")
+	linv.Invoke(t, 2, "count", "k")
+	inv.Invoke(t, stackitem.NewByteArray([]byte("local")), "get", "k1")
+
+	// The original chain is not affected.
+	rinv.Invoke(t, stackitem.NewByteArray([]byte("changed")), "get", "k1")
+	rinv.Invoke(t, big.NewInt(6), "count", "k")
+}
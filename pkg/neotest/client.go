@@ -63,12 +63,11 @@ func (c *ContractInvoker) TestInvokeScript(t testing.TB, script []byte, signers
 	}
 	t.Cleanup(ic.Finalize)
 
-	if c.collectCoverage {
-		ic.VM.SetOnExecHook(coverageHook)
-	}
+	flush := c.setExecHooks(ic.VM)
 
 	ic.VM.LoadWithFlags(tx.Script, callflag.All)
 	err = ic.VM.Run()
+	flush()
 	return ic.VM.Estack(), err
 }
 
@@ -82,12 +81,11 @@ func (c *ContractInvoker) TestInvoke(t testing.TB, method string, args ...any) (
 	}
 	t.Cleanup(ic.Finalize)
 
-	if c.collectCoverage {
-		ic.VM.SetOnExecHook(coverageHook)
-	}
+	flush := c.setExecHooks(ic.VM)
 
 	ic.VM.LoadWithFlags(tx.Script, callflag.All)
 	err = ic.VM.Run()
+	flush()
 	return ic.VM.Estack(), err
 }
 
@@ -2,10 +2,13 @@ package neotest
 
 import (
 	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
 	"flag"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"slices"
 	"strconv"
 	"sync"
@@ -19,14 +22,25 @@ import (
 
 const (
 	// goCoverProfileFlag specifies the name of `go test` command flag `coverprofile`
-	// that tells it where to save coverage data. Neotest coverage can be enabled
-	// only when this flag is set.
+	// that tells it where to save coverage data. Neotest coverage is enabled
+	// when this flag is set or when neotestCoverReport or neotestCoverDir
+	// environment variable is set.
 	goCoverProfileFlag = "test.coverprofile"
 	// goCoverModeFlag specifies the name of `go test` command flag `covermode` that
 	// specifies the coverage calculation mode.
 	goCoverModeFlag = "test.covermode"
 	// disableNeotestCover is name of the environmental variable used to explicitly disable neotest coverage.
 	disableNeotestCover = "DISABLE_NEOTEST_COVER"
+	// neotestCoverReport is the name of the environment variable specifying
+	// the file coverage report in neotestCoverFormat format is written to.
+	neotestCoverReport = "NEOTEST_COVER_REPORT"
+	// neotestCoverFormat is the name of the environment variable specifying
+	// the format of neotestCoverReport, LCOV is used by default.
+	neotestCoverFormat = "NEOTEST_COVER_FORMAT"
+	// neotestCoverDir is the name of the environment variable specifying the
+	// directory every test package writes its LCOV coverage report to, these
+	// reports can be combined with MergeCoverage.
+	neotestCoverDir = "NEOTEST_COVER_DIR"
 )
 
 const (
@@ -47,11 +61,24 @@ var (
 	coverProfile = ""
 	// coverMode is the mode of go coverage collection.
 	coverMode = goCoverModeSet
+	// coverReport specifies the file coverage report in coverFormat is
+	// written to, unless empty.
+	coverReport = ""
+	// coverFormat is the format of coverReport.
+	coverFormat = CoverageFormatLCOV
+	// coverDir specifies the directory package LCOV report is written to,
+	// unless empty.
+	coverDir = ""
 )
 
 type scriptRawCoverage struct {
 	debugInfo      *compiler.DebugInfo
 	offsetsVisited []int
+	// jumps contains conditional jumps of the script mapped by their offsets.
+	jumps map[int]conditionalJump
+	// jumpHits contains the number of times every conditional jump was
+	// taken and not taken.
+	jumpHits map[int][2]uint
 }
 
 type coverBlock struct {
@@ -101,9 +128,20 @@ func isCoverageEnabled(t testing.TB) bool {
 		}
 	})
 
-	coverageEnabled = !disabledByEnvironment && goToolCoverageEnabled
+	coverReport = os.Getenv(neotestCoverReport)
+	coverDir = os.Getenv(neotestCoverDir)
+	if v := os.Getenv(neotestCoverFormat); v != "" {
+		coverFormat = v
+	}
+	switch coverFormat {
+	case CoverageFormatLCOV, CoverageFormatCobertura, CoverageFormatHTML:
+	default:
+		t.Fatalf("coverage: unknown format '%s' specified by '%s'", coverFormat, neotestCoverFormat)
+	}
+
+	coverageEnabled = !disabledByEnvironment && (goToolCoverageEnabled || coverReport != "" || coverDir != "")
 
-	if coverageEnabled {
+	if coverageEnabled && goToolCoverageEnabled {
 		if coverMode != goCoverModeSet {
 			t.Fatalf("coverage: only '%s' cover mode is currently supported (#3587), got '%s'", goCoverModeSet, coverMode)
 		}
@@ -118,23 +156,103 @@ func isCoverageEnabled(t testing.TB) bool {
 	return coverageEnabled
 }
 
-var coverageHook vm.OnExecHook = func(scriptHash util.Uint160, offset int, opcode opcode.Opcode) {
-	coverageLock.Lock()
-	defer coverageLock.Unlock()
-	if cov, ok := rawCoverage[scriptHash]; ok {
+// newCoverageHook returns a hook collecting coverage data for a single VM.
+// Conditional jump is accounted as taken or not taken when the next
+// instruction of the same script is executed.
+func newCoverageHook() vm.OnExecHook {
+	var lastJumps = make(map[util.Uint160]int)
+	return func(scriptHash util.Uint160, offset int, opcode opcode.Opcode) {
+		coverageLock.Lock()
+		defer coverageLock.Unlock()
+		cov, ok := rawCoverage[scriptHash]
+		if !ok {
+			return
+		}
 		cov.offsetsVisited = append(cov.offsetsVisited, offset)
+		if jmp, ok := lastJumps[scriptHash]; ok {
+			hits := cov.jumpHits[jmp]
+			if offset == cov.jumps[jmp].target {
+				hits[0]++
+			} else {
+				hits[1]++
+			}
+			cov.jumpHits[jmp] = hits
+			delete(lastJumps, scriptHash)
+		}
+		if _, ok := cov.jumps[offset]; ok {
+			lastJumps[scriptHash] = offset
+		}
 	}
 }
 
 func reportCoverage(t testing.TB) {
 	coverageLock.Lock()
 	defer coverageLock.Unlock()
-	f, err := os.Create(coverProfile)
+	if coverProfile != "" {
+		writeCoverageFile(t, coverProfile, func(w io.Writer) error {
+			writeCoverageReport(w)
+			return nil
+		})
+	}
+	if coverReport == "" && coverDir == "" {
+		return
+	}
+	r := buildCoverageReport()
+	if coverDir != "" {
+		if err := os.MkdirAll(coverDir, 0o755); err != nil {
+			t.Fatalf("coverage: can't create directory '%s': %v", coverDir, err)
+		}
+		writeCoverageFile(t, filepath.Join(coverDir, packageCoverageName()), func(w io.Writer) error {
+			return writeLCOV(w, r)
+		})
+	}
+	if coverReport != "" {
+		writeCoverageFile(t, coverReport, func(w io.Writer) error {
+			return writeCoverage(w, coverFormat, r)
+		})
+	}
+}
+
+// writeCoverageFile writes coverage data to the file.
+func writeCoverageFile(t testing.TB, name string, write func(io.Writer) error) {
+	if err := writeFileAtomic(name, write); err != nil {
+		t.Fatalf("coverage: can't write coverage report to '%s': %v", name, err)
+	}
+}
+
+// writeFileAtomic writes data to a temporary file which then replaces the
+// target one, so the file is never seen incomplete.
+func writeFileAtomic(name string, write func(io.Writer) error) error {
+	f, err := os.CreateTemp(filepath.Dir(name), filepath.Base(name)+".*.tmp")
 	if err != nil {
-		t.Fatalf("coverage: can't create file '%s' to write coverage report", coverProfile)
+		return err
+	}
+	err = f.Chmod(0o644)
+	if err == nil {
+		err = write(f)
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), name)
 	}
-	defer f.Close()
-	writeCoverageReport(f)
+	if err != nil {
+		_ = os.Remove(f.Name())
+	}
+	return err
+}
+
+// packageCoverageName returns the name of the LCOV report file for the
+// current test package. Tests are executed in the package directory, so it's
+// derived from the working directory.
+func packageCoverageName() string {
+	wd, err := os.Getwd()
+	if err != nil {
+		wd = strconv.Itoa(os.Getpid())
+	}
+	h := sha256.Sum256([]byte(wd))
+	return filepath.Base(wd) + "-" + hex.EncodeToString(h[:4]) + ".lcov"
 }
 
 func writeCoverageReport(w io.Writer) {
@@ -239,6 +357,13 @@ func addScriptToCoverage(c *Contract) {
 	coverageLock.Lock()
 	defer coverageLock.Unlock()
 	if _, ok := rawCoverage[c.Hash]; !ok {
-		rawCoverage[c.Hash] = &scriptRawCoverage{debugInfo: c.DebugInfo}
+		cov := &scriptRawCoverage{
+			debugInfo: c.DebugInfo,
+			jumpHits:  make(map[int][2]uint),
+		}
+		if c.NEF != nil {
+			cov.jumps = conditionalJumps(c.NEF.Script, c.DebugInfo)
+		}
+		rawCoverage[c.Hash] = cov
 	}
 }
@@ -0,0 +1,683 @@
+package neotest
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"encoding/binary"
+	"encoding/xml"
+	"fmt"
+	"html/template"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/compiler"
+	"github.com/nspcc-dev/neo-go/pkg/vm"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+)
+
+// Coverage report formats.
+const (
+	// CoverageFormatLCOV is the LCOV tracefile format, it's also used to
+	// merge coverage data, see MergeCoverage.
+	CoverageFormatLCOV = "lcov"
+	// CoverageFormatCobertura is the Cobertura XML format.
+	CoverageFormatCobertura = "cobertura"
+	// CoverageFormatHTML is a standalone HTML page with annotated sources.
+	CoverageFormatHTML = "html"
+)
+
+// coverageReport contains line, branch and function coverage data of every
+// document.
+type coverageReport map[documentName]*documentCoverage
+
+type documentCoverage struct {
+	// lines maps line number to the number of times it was executed.
+	lines map[uint]uint
+	// branches maps line number to the number of times every branch of
+	// this line was taken. Every conditional jump has two branches: the
+	// jump itself and the fallthrough.
+	branches map[uint][]uint
+	// functions maps function name to its coverage data.
+	functions map[string]*functionCoverage
+}
+
+type functionCoverage struct {
+	line uint
+	hits uint
+}
+
+// document returns coverage data of the document creating it if needed.
+func (r coverageReport) document(name documentName) *documentCoverage {
+	d, ok := r[name]
+	if !ok {
+		d = &documentCoverage{
+			lines:     make(map[uint]uint),
+			branches:  make(map[uint][]uint),
+			functions: make(map[string]*functionCoverage),
+		}
+		r[name] = d
+	}
+	return d
+}
+
+// merge adds hits from other report to r.
+func (r coverageReport) merge(other coverageReport) {
+	for name, od := range other {
+		d := r.document(name)
+		for l, hits := range od.lines {
+			d.lines[l] += hits
+		}
+		for l, hits := range od.branches {
+			d.branches[l] = addHits(d.branches[l], hits)
+		}
+		for n, f := range od.functions {
+			if df, ok := d.functions[n]; ok {
+				df.hits += f.hits
+			} else {
+				d.functions[n] = &functionCoverage{line: f.line, hits: f.hits}
+			}
+		}
+	}
+}
+
+// addHits sums hits element-wise.
+func addHits(a, b []uint) []uint {
+	if len(a) < len(b) {
+		a = append(a, make([]uint, len(b)-len(a))...)
+	}
+	for i := range b {
+		a[i] += b[i]
+	}
+	return a
+}
+
+// summary returns the number of lines and branches and the number of covered
+// ones.
+func (d *documentCoverage) summary() (int, int, int, int) {
+	var lines, linesHit, branches, branchesHit int
+	for _, hits := range d.lines {
+		lines++
+		if hits > 0 {
+			linesHit++
+		}
+	}
+	for _, hits := range d.branches {
+		for _, h := range hits {
+			branches++
+			if h > 0 {
+				branchesHit++
+			}
+		}
+	}
+	return lines, linesHit, branches, branchesHit
+}
+
+// conditionalJump is a conditional jump instruction of the script that
+// corresponds to a source code branch.
+type conditionalJump struct {
+	// target is the jump target offset.
+	target int
+	// document and line specify the location branch is attributed to, it's
+	// the beginning of the code executed when jump is not taken (the body
+	// of if statement or loop), since the compiler doesn't emit sequence
+	// points for conditions.
+	document int
+	line     uint
+}
+
+// conditionalJumps returns conditional jumps of the script that correspond to
+// source code branches mapped by their offsets. A jump is a source code branch
+// if the jump target and the next instruction lead to different sequence
+// points, jumps generated by the compiler inside expressions are ignored this
+// way.
+func conditionalJumps(script []byte, di *compiler.DebugInfo) map[int]conditionalJump {
+	var (
+		res = make(map[int]conditionalJump)
+		ctx = vm.NewContext(script)
+	)
+	for {
+		op, param, err := ctx.Next()
+		if err != nil || ctx.IP() >= len(script) {
+			break
+		}
+		if op < opcode.JMPIF || op > opcode.JMPLEL {
+			continue
+		}
+		var offset int
+		if len(param) == 4 {
+			offset = int(int32(binary.LittleEndian.Uint32(param)))
+		} else {
+			offset = int(int8(param[0]))
+		}
+		m := methodAt(di, ctx.IP())
+		if m == nil {
+			continue
+		}
+		var (
+			target    = ctx.IP() + offset
+			tp, tok   = nextSeqPoint(m, target)
+			fp, fok   = nextSeqPoint(m, ctx.NextIP())
+			sameBlock = tok == fok && (!tok || tp.Opcode == fp.Opcode)
+		)
+		if sameBlock || !fok {
+			continue
+		}
+		res[ctx.IP()] = conditionalJump{
+			target:   target,
+			document: fp.Document,
+			line:     uint(fp.StartLine),
+		}
+	}
+	return res
+}
+
+// methodAt returns the method the given offset belongs to.
+func methodAt(di *compiler.DebugInfo, offset int) *compiler.MethodDebugInfo {
+	for i := range di.Methods {
+		if offset >= int(di.Methods[i].Range.Start) && offset <= int(di.Methods[i].Range.End) {
+			return &di.Methods[i]
+		}
+	}
+	return nil
+}
+
+// nextSeqPoint returns the first sequence point of the method located at or
+// after the given offset.
+func nextSeqPoint(m *compiler.MethodDebugInfo, offset int) (compiler.DebugSeqPoint, bool) {
+	var (
+		res   compiler.DebugSeqPoint
+		found bool
+	)
+	for _, p := range m.SeqPoints {
+		if p.Opcode >= offset && (!found || p.Opcode < res.Opcode) {
+			res, found = p, true
+		}
+	}
+	return res, found
+}
+
+// report returns coverage data collected for the script.
+func (c *scriptRawCoverage) report() coverageReport {
+	var (
+		res    = make(coverageReport)
+		di     = c.debugInfo
+		counts = make(map[int]uint)
+	)
+	for _, offset := range c.offsetsVisited {
+		counts[offset]++
+	}
+	for _, m := range di.Methods {
+		if len(m.SeqPoints) == 0 {
+			continue
+		}
+		first := m.SeqPoints[0]
+		res.document(di.Documents[first.Document]).functions[m.ID] = &functionCoverage{
+			line: uint(first.StartLine),
+			hits: counts[int(m.Range.Start)],
+		}
+		for _, p := range m.SeqPoints {
+			d := res.document(di.Documents[p.Document])
+			for l := uint(p.StartLine); l <= uint(p.EndLine); l++ {
+				d.lines[l] = max(d.lines[l], counts[p.Opcode])
+			}
+		}
+	}
+	for _, offset := range slices.Sorted(maps.Keys(c.jumps)) {
+		var (
+			j    = c.jumps[offset]
+			d    = res.document(di.Documents[j.document])
+			hits = c.jumpHits[offset]
+		)
+		d.branches[j.line] = append(d.branches[j.line], hits[0], hits[1])
+	}
+	return res
+}
+
+// buildCoverageReport returns coverage data of all scripts, it's supposed to
+// be called with coverageLock held.
+func buildCoverageReport() coverageReport {
+	res := make(coverageReport)
+	for _, cov := range rawCoverage {
+		res.merge(cov.report())
+	}
+	return res
+}
+
+// writeCoverage writes coverage report in the specified format.
+func writeCoverage(w io.Writer, format string, r coverageReport) error {
+	switch format {
+	case CoverageFormatLCOV:
+		return writeLCOV(w, r)
+	case CoverageFormatCobertura:
+		return writeCobertura(w, r)
+	case CoverageFormatHTML:
+		return writeHTML(w, r)
+	default:
+		return fmt.Errorf("unknown coverage format: %s", format)
+	}
+}
+
+// MergeCoverage merges coverage reports in LCOV format (produced by neotest
+// for separate test packages, for example) and writes the result to w in the
+// specified format (one of CoverageFormat* constants).
+func MergeCoverage(w io.Writer, format string, reports ...io.Reader) error {
+	res := make(coverageReport)
+	for i, rd := range reports {
+		r, err := parseLCOV(rd)
+		if err != nil {
+			return fmt.Errorf("report %d: %w", i, err)
+		}
+		res.merge(r)
+	}
+	return writeCoverage(w, format, res)
+}
+
+func writeLCOV(w io.Writer, r coverageReport) error {
+	var buf bytes.Buffer
+	for _, name := range slices.Sorted(maps.Keys(r)) {
+		d := r[name]
+		fmt.Fprintf(&buf, "TN:\nSF:%s\n", name)
+
+		fns := slices.SortedFunc(maps.Keys(d.functions), func(a, b string) int {
+			return cmp.Or(cmp.Compare(d.functions[a].line, d.functions[b].line), cmp.Compare(a, b))
+		})
+		var fnHit int
+		for _, fn := range fns {
+			fmt.Fprintf(&buf, "FN:%d,%s\n", d.functions[fn].line, fn)
+		}
+		for _, fn := range fns {
+			fmt.Fprintf(&buf, "FNDA:%d,%s\n", d.functions[fn].hits, fn)
+			if d.functions[fn].hits > 0 {
+				fnHit++
+			}
+		}
+		fmt.Fprintf(&buf, "FNF:%d\nFNH:%d\n", len(fns), fnHit)
+
+		lines, linesHit, branches, branchesHit := d.summary()
+		for _, l := range slices.Sorted(maps.Keys(d.branches)) {
+			for i, hits := range d.branches[l] {
+				taken := strconv.FormatUint(uint64(hits), 10)
+				if d.lines[l] == 0 {
+					taken = "-"
+				}
+				fmt.Fprintf(&buf, "BRDA:%d,%d,%d,%s\n", l, i/2, i%2, taken)
+			}
+		}
+		fmt.Fprintf(&buf, "BRF:%d\nBRH:%d\n", branches, branchesHit)
+		for _, l := range slices.Sorted(maps.Keys(d.lines)) {
+			fmt.Fprintf(&buf, "DA:%d,%d\n", l, d.lines[l])
+		}
+		fmt.Fprintf(&buf, "LF:%d\nLH:%d\nend_of_record\n", lines, linesHit)
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// parseLCOV reads LCOV tracefile. Branches of every line are stored in the
+// order of their appearance.
+func parseLCOV(rd io.Reader) (coverageReport, error) {
+	var (
+		res = make(coverageReport)
+		d   *documentCoverage
+		sc  = bufio.NewScanner(rd)
+		n   int
+	)
+	sc.Buffer(nil, 1024*1024)
+	for sc.Scan() {
+		n++
+		line := strings.TrimSpace(sc.Text())
+		key, val, _ := strings.Cut(line, ":")
+		if key == "SF" {
+			d = res.document(val)
+			continue
+		}
+		if key == "end_of_record" {
+			d = nil
+			continue
+		}
+		if d == nil {
+			continue
+		}
+		fields := strings.Split(val, ",")
+		var err error
+		switch key {
+		case "DA":
+			var l, hits uint64
+			if len(fields) < 2 {
+				err = fmt.Errorf("invalid DA record")
+				break
+			}
+			if l, err = strconv.ParseUint(fields[0], 10, 32); err != nil {
+				break
+			}
+			if hits, err = strconv.ParseUint(fields[1], 10, 64); err != nil {
+				break
+			}
+			d.lines[uint(l)] += uint(hits)
+		case "BRDA":
+			var l, hits uint64
+			if len(fields) != 4 {
+				err = fmt.Errorf("invalid BRDA record")
+				break
+			}
+			if l, err = strconv.ParseUint(fields[0], 10, 32); err != nil {
+				break
+			}
+			if fields[3] != "-" {
+				if hits, err = strconv.ParseUint(fields[3], 10, 64); err != nil {
+					break
+				}
+			}
+			d.branches[uint(l)] = append(d.branches[uint(l)], uint(hits))
+		case "FN":
+			var l uint64
+			if len(fields) < 2 {
+				err = fmt.Errorf("invalid FN record")
+				break
+			}
+			if l, err = strconv.ParseUint(fields[0], 10, 32); err != nil {
+				break
+			}
+			name := strings.Join(fields[1:], ",")
+			if f, ok := d.functions[name]; ok {
+				f.line = uint(l)
+			} else {
+				d.functions[name] = &functionCoverage{line: uint(l)}
+			}
+		case "FNDA":
+			var hits uint64
+			if len(fields) < 2 {
+				err = fmt.Errorf("invalid FNDA record")
+				break
+			}
+			if hits, err = strconv.ParseUint(fields[0], 10, 64); err != nil {
+				break
+			}
+			name := strings.Join(fields[1:], ",")
+			if f, ok := d.functions[name]; ok {
+				f.hits += uint(hits)
+			} else {
+				d.functions[name] = &functionCoverage{hits: uint(hits)}
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+	}
+	return res, sc.Err()
+}
+
+type (
+	coberturaCoverage struct {
+		XMLName         xml.Name           `xml:"coverage"`
+		LineRate        string             `xml:"line-rate,attr"`
+		BranchRate      string             `xml:"branch-rate,attr"`
+		LinesCovered    int                `xml:"lines-covered,attr"`
+		LinesValid      int                `xml:"lines-valid,attr"`
+		BranchesCovered int                `xml:"branches-covered,attr"`
+		BranchesValid   int                `xml:"branches-valid,attr"`
+		Complexity      string             `xml:"complexity,attr"`
+		Version         string             `xml:"version,attr"`
+		Timestamp       int64              `xml:"timestamp,attr"`
+		Packages        []coberturaPackage `xml:"packages>package"`
+	}
+
+	coberturaPackage struct {
+		Name       string           `xml:"name,attr"`
+		LineRate   string           `xml:"line-rate,attr"`
+		BranchRate string           `xml:"branch-rate,attr"`
+		Complexity string           `xml:"complexity,attr"`
+		Classes    []coberturaClass `xml:"classes>class"`
+	}
+
+	coberturaClass struct {
+		Name       string            `xml:"name,attr"`
+		Filename   string            `xml:"filename,attr"`
+		LineRate   string            `xml:"line-rate,attr"`
+		BranchRate string            `xml:"branch-rate,attr"`
+		Complexity string            `xml:"complexity,attr"`
+		Methods    []coberturaMethod `xml:"methods>method"`
+		Lines      []coberturaLine   `xml:"lines>line"`
+	}
+
+	coberturaMethod struct {
+		Name       string          `xml:"name,attr"`
+		Signature  string          `xml:"signature,attr"`
+		LineRate   string          `xml:"line-rate,attr"`
+		BranchRate string          `xml:"branch-rate,attr"`
+		Lines      []coberturaLine `xml:"lines>line"`
+	}
+
+	coberturaLine struct {
+		Number            uint   `xml:"number,attr"`
+		Hits              uint   `xml:"hits,attr"`
+		Branch            bool   `xml:"branch,attr"`
+		ConditionCoverage string `xml:"condition-coverage,attr,omitempty"`
+	}
+)
+
+// coverageRate returns the ratio of covered items formatted for Cobertura
+// report.
+func coverageRate(hit, total int) string {
+	if total == 0 {
+		return "0"
+	}
+	return strconv.FormatFloat(float64(hit)/float64(total), 'f', 4, 64)
+}
+
+func writeCobertura(w io.Writer, r coverageReport) error {
+	var (
+		res      = coberturaCoverage{Complexity: "0", Timestamp: time.Now().Unix()}
+		packages = make(map[string]*coberturaPackage)
+		pkgStats = make(map[string][4]int)
+	)
+	for _, name := range slices.Sorted(maps.Keys(r)) {
+		var (
+			d                                      = r[name]
+			lines, linesHit, branches, branchesHit = d.summary()
+			dir                                    = filepath.ToSlash(filepath.Dir(name))
+			base                                   = filepath.Base(name)
+			class                                  = coberturaClass{
+				Name:       strings.TrimSuffix(base, filepath.Ext(base)),
+				Filename:   filepath.ToSlash(name),
+				LineRate:   coverageRate(linesHit, lines),
+				BranchRate: coverageRate(branchesHit, branches),
+				Complexity: "0",
+				Methods:    []coberturaMethod{},
+			}
+		)
+		for _, l := range slices.Sorted(maps.Keys(d.lines)) {
+			line := coberturaLine{Number: l, Hits: d.lines[l]}
+			if hits := d.branches[l]; len(hits) != 0 {
+				var taken int
+				for _, h := range hits {
+					if h > 0 {
+						taken++
+					}
+				}
+				line.Branch = true
+				line.ConditionCoverage = fmt.Sprintf("%d%% (%d/%d)", taken*100/len(hits), taken, len(hits))
+			}
+			class.Lines = append(class.Lines, line)
+		}
+		for _, fn := range slices.Sorted(maps.Keys(d.functions)) {
+			f := d.functions[fn]
+			var hit int
+			if f.hits > 0 {
+				hit = 1
+			}
+			class.Methods = append(class.Methods, coberturaMethod{
+				Name:       fn,
+				LineRate:   coverageRate(hit, 1),
+				BranchRate: "0",
+				Lines:      []coberturaLine{{Number: f.line, Hits: f.hits}},
+			})
+		}
+		p, ok := packages[dir]
+		if !ok {
+			p = &coberturaPackage{Name: dir, Complexity: "0"}
+			packages[dir] = p
+		}
+		p.Classes = append(p.Classes, class)
+		st := pkgStats[dir]
+		pkgStats[dir] = [4]int{st[0] + lines, st[1] + linesHit, st[2] + branches, st[3] + branchesHit}
+		res.LinesValid += lines
+		res.LinesCovered += linesHit
+		res.BranchesValid += branches
+		res.BranchesCovered += branchesHit
+	}
+	for _, dir := range slices.Sorted(maps.Keys(packages)) {
+		p, st := packages[dir], pkgStats[dir]
+		p.LineRate = coverageRate(st[1], st[0])
+		p.BranchRate = coverageRate(st[3], st[2])
+		res.Packages = append(res.Packages, *p)
+	}
+	res.LineRate = coverageRate(res.LinesCovered, res.LinesValid)
+	res.BranchRate = coverageRate(res.BranchesCovered, res.BranchesValid)
+
+	data, err := xml.MarshalIndent(res, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, xml.Header+string(data)+"\n")
+	return err
+}
+
+type (
+	htmlReport struct {
+		Files []htmlFile
+		htmlSummary
+	}
+
+	htmlSummary struct {
+		Lines, LinesHit, Branches, BranchesHit int
+	}
+
+	htmlFile struct {
+		Name     string
+		NoSource bool
+		Source   []htmlLine
+		htmlSummary
+	}
+
+	htmlLine struct {
+		Number   uint
+		Source   string
+		Hits     string
+		Class    string
+		Branches string
+	}
+)
+
+// Percent returns the percentage of covered items formatted for HTML report.
+func (s htmlSummary) Percent(hit, total int) string {
+	if total == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", float64(hit)*100/float64(total))
+}
+
+var htmlTemplate = template.Must(template.New("coverage").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Contract coverage</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table.summary { border-collapse: collapse; margin-bottom: 2em; }
+table.summary td, table.summary th { border: 1px solid #ccc; padding: 0.3em 0.8em; text-align: right; }
+table.summary td:first-child, table.summary th:first-child { text-align: left; }
+table.source { border-collapse: collapse; font-family: monospace; width: 100%; margin-bottom: 2em; }
+table.source td { padding: 0 0.5em; white-space: pre; vertical-align: top; }
+table.source td.num, table.source td.hits, table.source td.branches { text-align: right; color: #666; width: 1%; }
+tr.hit td.src { background: #dfd; }
+tr.miss td.src { background: #fdd; }
+tr.partial td.src { background: #ffc; }
+</style>
+</head>
+<body>
+<h1>Contract coverage</h1>
+<table class="summary">
+<tr><th>File</th><th>Lines</th><th>Branches</th></tr>
+{{- range $i, $f := .Files}}
+<tr><td><a href="#file{{$i}}">{{$f.Name}}</a></td><td>{{$f.Percent $f.LinesHit $f.Lines}} ({{$f.LinesHit}}/{{$f.Lines}})</td><td>{{$f.Percent $f.BranchesHit $f.Branches}} ({{$f.BranchesHit}}/{{$f.Branches}})</td></tr>
+{{- end}}
+<tr><th>Total</th><th>{{.Percent .LinesHit .Lines}} ({{.LinesHit}}/{{.Lines}})</th><th>{{.Percent .BranchesHit .Branches}} ({{.BranchesHit}}/{{.Branches}})</th></tr>
+</table>
+{{- range $i, $f := .Files}}
+<h2 id="file{{$i}}">{{$f.Name}}</h2>
+{{- if $f.NoSource}}
+<p>Source code is not available.</p>
+{{- end}}
+<table class="source">
+{{- range $f.Source}}
+<tr{{with .Class}} class="{{.}}"{{end}}><td class="num">{{.Number}}</td><td class="hits">{{.Hits}}</td><td class="branches">{{.Branches}}</td><td class="src">{{.Source}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+</body>
+</html>
+`))
+
+func writeHTML(w io.Writer, r coverageReport) error {
+	var res htmlReport
+	for _, name := range slices.Sorted(maps.Keys(r)) {
+		d := r[name]
+		f := htmlFile{Name: name}
+		f.Lines, f.LinesHit, f.Branches, f.BranchesHit = d.summary()
+
+		var src []string
+		data, err := os.ReadFile(name)
+		if err == nil {
+			src = strings.Split(strings.TrimSuffix(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n"), "\n")
+		} else {
+			f.NoSource = true
+			if len(d.lines) != 0 {
+				src = make([]string, slices.Max(slices.Collect(maps.Keys(d.lines))))
+			}
+		}
+		for i, s := range src {
+			var (
+				l           = uint(i + 1)
+				hits, isHit = d.lines[l]
+				line        = htmlLine{Number: l, Source: s}
+			)
+			if f.NoSource && !isHit {
+				continue
+			}
+			if isHit {
+				line.Hits = strconv.FormatUint(uint64(hits), 10)
+				line.Class = "miss"
+				if hits > 0 {
+					line.Class = "hit"
+				}
+			}
+			if bs := d.branches[l]; len(bs) != 0 {
+				var taken int
+				for _, h := range bs {
+					if h > 0 {
+						taken++
+					}
+				}
+				line.Branches = fmt.Sprintf("%d/%d", taken, len(bs))
+				if hits > 0 && taken < len(bs) {
+					line.Class = "partial"
+				}
+			}
+			f.Source = append(f.Source, line)
+		}
+		res.Files = append(res.Files, f)
+		res.Lines += f.Lines
+		res.LinesHit += f.LinesHit
+		res.Branches += f.Branches
+		res.BranchesHit += f.BranchesHit
+	}
+	return htmlTemplate.Execute(w, res)
+}
@@ -0,0 +1,139 @@
+package neotest
+
+import (
+	"bytes"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm"
+	"github.com/stretchr/testify/require"
+)
+
+const coverageTestContract = `package foo
+
+func Check(a int) int {
+	if a > 10 {
+		return 1
+	}
+	return 2
+}
+
+func Unused() int {
+	return 3
+}
+`
+
+func TestCoverageReport(t *testing.T) {
+	tmp := t.TempDir()
+	srcPath := filepath.Join(tmp, "foo.go")
+	cfgPath := filepath.Join(tmp, "foo.yml")
+	require.NoError(t, os.WriteFile(srcPath, []byte(coverageTestContract), 0o644))
+	require.NoError(t, os.WriteFile(cfgPath, []byte("name: foo\n"), 0o644))
+
+	c := CompileFile(t, util.Uint160{1, 2, 3}, srcPath, cfgPath)
+	cov := &scriptRawCoverage{
+		debugInfo: c.DebugInfo,
+		jumps:     conditionalJumps(c.NEF.Script, c.DebugInfo),
+		jumpHits:  make(map[int][2]uint),
+	}
+	require.Len(t, cov.jumps, 1)
+
+	coverageLock.Lock()
+	rawCoverage[c.Hash] = cov
+	coverageLock.Unlock()
+	t.Cleanup(func() {
+		coverageLock.Lock()
+		delete(rawCoverage, c.Hash)
+		coverageLock.Unlock()
+	})
+
+	var offset int
+	for _, m := range c.DebugInfo.Methods {
+		if m.Name.Name == "check" {
+			offset = int(m.Range.Start)
+		}
+	}
+	for _, arg := range []int64{1, 2, 20} {
+		v := vm.New()
+		v.SetOnExecHook(newCoverageHook())
+		v.LoadScriptWithHash(c.NEF.Script, c.Hash, callflag.All)
+		v.Context().Jump(offset)
+		v.Estack().PushVal(arg)
+		require.NoError(t, v.Run())
+	}
+
+	r := cov.report()
+	require.Len(t, r, 1)
+	d := r[srcPath]
+	require.NotNil(t, d)
+	require.Len(t, d.lines, 3)
+	require.Equal(t, uint(1), d.lines[5])
+	require.Equal(t, uint(2), d.lines[7])
+	require.Equal(t, uint(0), d.lines[11])
+	require.Equal(t, []uint{2, 1}, d.branches[5])
+	require.Equal(t, uint(3), d.functions["Check"].hits)
+	require.Equal(t, uint(0), d.functions["Unused"].hits)
+
+	t.Run("lcov", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, writeLCOV(&buf, r))
+		out := buf.String()
+		require.True(t, strings.HasPrefix(out, "TN:\nSF:"+srcPath+"\n"))
+		require.Contains(t, out, "FNDA:3,Check\n")
+		require.Contains(t, out, "FNF:2\nFNH:1\n")
+		require.Contains(t, out, "BRDA:5,0,0,2\nBRDA:5,0,1,1\nBRF:2\nBRH:2\n")
+		require.Contains(t, out, "DA:11,0\n")
+		require.True(t, strings.HasSuffix(out, "end_of_record\n"))
+
+		parsed, err := parseLCOV(strings.NewReader(out))
+		require.NoError(t, err)
+		require.Equal(t, r, parsed)
+	})
+
+	t.Run("merge", func(t *testing.T) {
+		var lcov bytes.Buffer
+		require.NoError(t, writeLCOV(&lcov, r))
+
+		var buf bytes.Buffer
+		require.NoError(t, MergeCoverage(&buf, CoverageFormatLCOV,
+			bytes.NewReader(lcov.Bytes()), bytes.NewReader(lcov.Bytes())))
+		merged, err := parseLCOV(&buf)
+		require.NoError(t, err)
+		d := merged[srcPath]
+		require.Equal(t, uint(2), d.lines[5])
+		require.Equal(t, []uint{4, 2}, d.branches[5])
+		require.Equal(t, uint(6), d.functions["Check"].hits)
+
+		require.Error(t, MergeCoverage(&buf, "go", bytes.NewReader(lcov.Bytes())))
+		require.Error(t, MergeCoverage(&buf, CoverageFormatLCOV, strings.NewReader("SF:a.go\nDA:x,1\n")))
+	})
+
+	t.Run("cobertura", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, writeCobertura(&buf, r))
+		var res coberturaCoverage
+		require.NoError(t, xml.Unmarshal(buf.Bytes(), &res))
+		require.Equal(t, 2, res.BranchesValid)
+		require.Equal(t, 2, res.BranchesCovered)
+		require.Len(t, res.Packages, 1)
+		require.Len(t, res.Packages[0].Classes, 1)
+		cls := res.Packages[0].Classes[0]
+		require.Equal(t, "foo", cls.Name)
+		require.Len(t, cls.Methods, 2)
+		require.Contains(t, cls.Lines, coberturaLine{Number: 5, Hits: 1, Branch: true, ConditionCoverage: "100% (2/2)"})
+	})
+
+	t.Run("html", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, writeHTML(&buf, r))
+		out := buf.String()
+		require.Contains(t, out, `<tr class="hit"><td class="num">5</td><td class="hits">1</td><td class="branches">2/2</td><td class="src">		return 1</td></tr>`)
+		require.Contains(t, out, `<tr><td class="num">4</td><td class="hits"></td><td class="branches"></td><td class="src">	if a &gt; 10 {</td></tr>`)
+		require.Contains(t, out, `<tr class="miss"><td class="num">11</td><td class="hits">0</td>`)
+	})
+}
@@ -16,6 +16,19 @@ Higher-order methods provided in Executor and ContractInvoker hide the details
 of transaction creation for the most part, but there are lower-level methods as
 well that can be used for specific tasks.
 
+Executor can save the chain state with Snapshot and restore it with Revert, this
+allows to perform some expensive setup once and share it between several test
+cases (table-driven tests in particular), every one of them starts with the same
+chain state:
+
+	id := e.Snapshot(t)
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e.Revert(t, id)
+			...
+		})
+	}
+
 It's recommended to have a separate folder/package for tests, because having
 them in the same package with the smart contract iself can lead to unxpected
 results if smart contract has any init() functions. If that's the case they
@@ -29,5 +42,72 @@ itself will not be saved because it will be replaced with contracts coverage ins
 In case `go test` coverage is wanted DISABLE_NEOTEST_COVER=1 variable can be set.
 Coverage is gathered by capturing VM instructions during test contract execution and
 mapping them to the contract source code using the DebugInfo information.
+
+Coverage reports in other formats can be requested with NEOTEST_COVER_REPORT
+environment variable set to the name of the file to write report to (which
+works without `go test` coverage enabled). NEOTEST_COVER_FORMAT specifies the
+report format: "lcov" (default), "cobertura" or "html" (a standalone page with
+per-line hit counts of the contract sources). Besides line and function coverage
+these reports contain branch coverage: every conditional jump to a different
+sequence point (like if statements and loop conditions) is accounted as taken
+or not taken. Since there are no sequence points for conditions, branches are
+attributed to the first line of the code executed when the jump is not taken.
+
+Every test binary rewrites report files, so to get the coverage of multiple test
+packages set NEOTEST_COVER_DIR to a directory every package writes its own LCOV
+report to and merge them after testing with MergeCoverage or mergecoverage
+script from the scripts directory of neo-go repository (the directory should be
+cleaned before testing, reports of packages not tested are merged as well
+otherwise):
+
+	rm -rf cover
+	NEOTEST_COVER_DIR=$PWD/cover go test ./...
+	go -C /path/to/neo-go/scripts run ./mergecoverage --format cobertura --out $PWD/cover.xml $PWD/cover
+
+GAS profiling is enabled when NEOTEST_GAS_PROFILE environment variable is set to
+the name of the file to write profile to. GAS spent by every instruction executed
+(including syscall and native contract call prices) is then accounted per contract
+function and source code line and saved in pprof format, so it can be analyzed
+with `go tool pprof` (including flame graphs of its web interface):
+
+	NEOTEST_GAS_PROFILE=$PWD/gas.pprof go test ./tests
+	go tool pprof -http=:8080 gas.pprof
+
+Use an absolute path since tests are executed in the package directory and profile
+one package at a time since every test binary rewrites the file. The profile is
+written once all tests are completed if they're run via RunWithGasProfile from
+TestMain, otherwise it's rewritten after every top-level test using neotest:
+
+	func TestMain(m *testing.M) {
+		os.Exit(neotest.RunWithGasProfile(m))
+	}
+
+Test invocations and transactions added to the chain are profiled, the latter are
+replayed after the block acceptance, so the chain should keep historic states
+(KeepOnlyLatestState disabled, which is the default). Profiling can be disabled
+for any Executor by using EnableGasProfiling and DisableGasProfiling.
+
+Contract methods can be fuzzed with Go native fuzzing using Fuzzer. It decodes
+fuzzing input into a sequence of method calls with arguments generated for the
+parameter types of the contract ABI, executes them in separate blocks and
+checks the given invariants after every call. The chain state is reverted after
+every input, so the chain must support snapshots (which is the default for the
+chain package). Failing inputs are minimized and saved by `go test` as usual:
+
+	func FuzzToken(f *testing.F) {
+		bc, acc := chain.NewSingle(f)
+		e := neotest.NewExecutor(f, bc, acc, acc)
+		c := neotest.CompileFile(f, e.CommitteeHash, "../token", "../token/token.yml")
+		e.DeployContract(f, c, nil)
+
+		z := neotest.NewFuzzer(f, e.CommitteeInvoker(c.Hash), neotest.NoFault("invalid amount"),
+			func(t testing.TB, c *neotest.ContractInvoker, call neotest.FuzzCall, res *state.AppExecResult) {
+				// Check total supply conservation here.
+			})
+		z.Accounts = []neotest.Signer{e.Committee, e.NewAccount(f)}
+		z.Fuzz(f)
+	}
+
+	go test -fuzz FuzzToken ./tests
 */
 package neotest
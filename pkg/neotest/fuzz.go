@@ -0,0 +1,369 @@
+package neotest
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/trigger"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"github.com/nspcc-dev/neo-go/pkg/vm/vmstate"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	// defaultFuzzMaxCalls is the default maximum number of method calls
+	// generated from a single fuzzing input.
+	defaultFuzzMaxCalls = 4
+	// fuzzMaxLength is the maximum length of generated byte arrays and
+	// strings.
+	fuzzMaxLength = 64
+	// fuzzMaxElements is the maximum number of generated array and map
+	// elements.
+	fuzzMaxElements = 4
+	// fuzzMaxDepth is the maximum nesting level of generated arrays and maps.
+	fuzzMaxDepth = 2
+)
+
+// FuzzCall is a contract method call generated by Fuzzer.
+type FuzzCall struct {
+	// Method is the name of the method called.
+	Method string
+	// Args contains method arguments.
+	Args []stackitem.Item
+	// Signers are transaction signers.
+	Signers []Signer
+}
+
+// Invariant is a condition checked by Fuzzer after every method call. It's
+// given the contract invoker (to check the chain state) along with the call
+// and its execution result and is expected to fail the test if the condition
+// is violated.
+type Invariant func(t testing.TB, c *ContractInvoker, call FuzzCall, res *state.AppExecResult)
+
+// Fuzzer generates contract method calls based on the contract ABI from
+// arbitrary input and checks user-supplied invariants after every call. It's
+// intended to be used with Go native fuzzing (see Fuzz), but it can also run
+// any given input (see Run) for property-based tests. Every input is decoded
+// into a sequence of calls deterministically, so inputs found by the fuzzing
+// engine (and minimized by it) can be reproduced as regular tests.
+type Fuzzer struct {
+	*ContractInvoker
+	// Methods contains ABI methods to call, it's initialized with all
+	// contract methods except for the ones starting with underscore
+	// (like _deploy) and verify.
+	Methods []manifest.Method
+	// Accounts contains signers one of which is used for every call, they
+	// are also used as Hash160 arguments. Invoker signers (or committee if
+	// there are none) are used for calls if it's empty.
+	Accounts []Signer
+	// MaxCalls is the maximum number of calls generated from a single input.
+	MaxCalls int
+	// Invariants are checked after every call. FAULTed calls are not
+	// treated as errors by default, use NoFault invariant to check for them.
+	Invariants []Invariant
+
+	// snapshot is the chain state every run starts from, it's valid if
+	// snapshotted is set.
+	snapshot    int
+	snapshotted bool
+}
+
+// NewFuzzer creates a Fuzzer for the contract of the given invoker with the
+// given invariants. The contract must be deployed.
+func NewFuzzer(t testing.TB, c *ContractInvoker, invariants ...Invariant) *Fuzzer {
+	cs := c.Chain.GetContractState(c.Hash)
+	require.NotNil(t, cs, "contract %s is not deployed", c.Hash.StringLE())
+
+	var methods []manifest.Method
+	for _, m := range cs.Manifest.ABI.Methods {
+		if strings.HasPrefix(m.Name, "_") || m.Name == manifest.MethodVerify {
+			continue
+		}
+		methods = append(methods, m)
+	}
+	return &Fuzzer{
+		ContractInvoker: c,
+		Methods:         methods,
+		MaxCalls:        defaultFuzzMaxCalls,
+		Invariants:      invariants,
+	}
+}
+
+// NoFault returns an invariant that fails the test if a call FAULTs with an
+// exception not containing any of the given substrings, so that expected
+// failures (like argument checks) can be filtered out.
+func NoFault(allowed ...string) Invariant {
+	return func(t testing.TB, _ *ContractInvoker, call FuzzCall, res *state.AppExecResult) {
+		if res.VMState != vmstate.Fault {
+			return
+		}
+		for _, s := range allowed {
+			if strings.Contains(res.FaultException, s) {
+				return
+			}
+		}
+		t.Fatalf("unexpected FAULT of %s: %s", call, res.FaultException)
+	}
+}
+
+// Fuzz runs fuzzing of the contract with f, the chain state is reverted to
+// the initial one after every input. Seeds are inputs added to the seed
+// corpus, a few default ones are used if none are given.
+func (z *Fuzzer) Fuzz(f *testing.F, seeds ...[]byte) {
+	if len(seeds) == 0 {
+		seeds = [][]byte{{}, {0, 0, 1}, {3, 1, 2, 0xff, 3, 4, 5, 6, 7, 8}}
+	}
+	for _, s := range seeds {
+		f.Add(s)
+	}
+	z.saveState(f)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		z.Run(t, data)
+	})
+}
+
+// Run decodes calls from data, executes them one by one in separate blocks and
+// checks invariants after every call. The chain state is reverted after the
+// run to the one saved by the first Run (or Fuzz) call of this Fuzzer, so
+// every run starts from the same state.
+func (z *Fuzzer) Run(t testing.TB, data []byte) {
+	calls := z.Calls(data)
+	z.saveState(t)
+	defer z.Revert(t, z.snapshot)
+
+	for i, call := range calls {
+		tx := z.NewTx(t, call.Signers, z.Hash, call.Method, fuzzArgs(call.Args)...)
+		z.AddNewBlock(t, tx)
+		aer, err := z.Chain.GetAppExecResults(tx.Hash(), trigger.Application)
+		require.NoError(t, err)
+		t.Logf("call %d: %s: %s", i, call, aer[0].VMState)
+		for _, inv := range z.Invariants {
+			inv(t, z.ContractInvoker, call, &aer[0])
+		}
+	}
+}
+
+// saveState takes a snapshot of the chain state if it's not taken yet. A
+// single snapshot is reused for all runs since every snapshot taken adds
+// another storage layer.
+func (z *Fuzzer) saveState(t testing.TB) {
+	if !z.snapshotted {
+		z.snapshot = z.Snapshot(t)
+		z.snapshotted = true
+	}
+}
+
+// Calls returns method calls decoded from data. Missing data is treated as
+// zeroes, so any input is valid.
+func (z *Fuzzer) Calls(data []byte) []FuzzCall {
+	if len(z.Methods) == 0 {
+		return nil
+	}
+	var (
+		r     = &fuzzReader{data: data}
+		n     = int(r.byte())%max(z.MaxCalls, 1) + 1
+		calls = make([]FuzzCall, 0, n)
+	)
+	for range n {
+		m := z.Methods[int(r.byte())%len(z.Methods)]
+		call := FuzzCall{
+			Method:  m.Name,
+			Args:    make([]stackitem.Item, 0, len(m.Parameters)),
+			Signers: z.Signers,
+		}
+		if len(z.Accounts) != 0 {
+			call.Signers = []Signer{z.Accounts[int(r.byte())%len(z.Accounts)]}
+		} else if len(call.Signers) == 0 {
+			call.Signers = []Signer{z.Committee}
+		}
+		for _, p := range m.Parameters {
+			call.Args = append(call.Args, z.arg(r, p.Type, 0))
+		}
+		calls = append(calls, call)
+	}
+	return calls
+}
+
+// String implements the fmt.Stringer interface.
+func (c FuzzCall) String() string {
+	var sb strings.Builder
+	sb.WriteString(c.Method)
+	sb.WriteByte('(')
+	for i, a := range c.Args {
+		if i != 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(fuzzItemString(a))
+	}
+	sb.WriteByte(')')
+	for i, s := range c.Signers {
+		if i == 0 {
+			sb.WriteString(" by ")
+		} else {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(s.ScriptHash().StringLE())
+	}
+	return sb.String()
+}
+
+// fuzzItemString returns human-readable representation of the argument.
+func fuzzItemString(item stackitem.Item) string {
+	switch it := item.(type) {
+	case stackitem.Null:
+		return "null"
+	case *stackitem.ByteArray:
+		return fmt.Sprintf("0x%x", it.Value())
+	case *stackitem.Array:
+		var s = make([]string, 0, it.Len())
+		for _, e := range it.Value().([]stackitem.Item) {
+			s = append(s, fuzzItemString(e))
+		}
+		return "[" + strings.Join(s, ", ") + "]"
+	case *stackitem.Map:
+		var s = make([]string, 0, it.Len())
+		for _, e := range it.Value().([]stackitem.MapElement) {
+			s = append(s, fuzzItemString(e.Key)+": "+fuzzItemString(e.Value))
+		}
+		return "{" + strings.Join(s, ", ") + "}"
+	default:
+		return fmt.Sprint(item.Value())
+	}
+}
+
+// fuzzArgs converts stack items to invocation arguments.
+func fuzzArgs(items []stackitem.Item) []any {
+	var args = make([]any, len(items))
+	for i := range items {
+		args[i] = items[i]
+	}
+	return args
+}
+
+// hashes returns Hash160 values likely to be meaningful for the contract.
+func (z *Fuzzer) hashes() []util.Uint160 {
+	var res = []util.Uint160{z.Hash, z.CommitteeHash}
+	for _, s := range z.Accounts {
+		res = append(res, s.ScriptHash())
+	}
+	for _, s := range z.Signers {
+		res = append(res, s.ScriptHash())
+	}
+	return res
+}
+
+// arg generates an argument of the given type.
+func (z *Fuzzer) arg(r *fuzzReader, typ smartcontract.ParamType, depth int) stackitem.Item {
+	switch typ {
+	case smartcontract.BoolType:
+		return stackitem.NewBool(r.byte()&1 != 0)
+	case smartcontract.IntegerType:
+		return stackitem.NewBigInteger(r.integer())
+	case smartcontract.ByteArrayType, smartcontract.StringType:
+		return stackitem.NewByteArray(r.bytes(int(r.byte()) % (fuzzMaxLength + 1)))
+	case smartcontract.Hash160Type:
+		hashes := z.hashes()
+		// Known hashes are used most of the time.
+		if sel := int(r.byte()); sel < 192 {
+			return stackitem.NewByteArray(hashes[sel%len(hashes)].BytesBE())
+		}
+		return stackitem.NewByteArray(r.bytes(util.Uint160Size))
+	case smartcontract.Hash256Type:
+		return stackitem.NewByteArray(r.bytes(util.Uint256Size))
+	case smartcontract.PublicKeyType:
+		return stackitem.NewByteArray(append([]byte{2 + r.byte()&1}, r.bytes(32)...))
+	case smartcontract.SignatureType:
+		return stackitem.NewByteArray(r.bytes(64))
+	case smartcontract.ArrayType:
+		if depth >= fuzzMaxDepth {
+			return stackitem.NewArray([]stackitem.Item{})
+		}
+		var items = make([]stackitem.Item, int(r.byte())%(fuzzMaxElements+1))
+		for i := range items {
+			items[i] = z.arg(r, smartcontract.AnyType, depth+1)
+		}
+		return stackitem.NewArray(items)
+	case smartcontract.MapType:
+		var m = stackitem.NewMap()
+		if depth >= fuzzMaxDepth {
+			return m
+		}
+		for range int(r.byte()) % (fuzzMaxElements + 1) {
+			var key stackitem.Item
+			if r.byte()&1 == 0 {
+				key = stackitem.NewBigInteger(r.integer())
+			} else {
+				key = stackitem.NewByteArray(r.bytes(int(r.byte()) % (fuzzMaxLength + 1)))
+			}
+			m.Add(key, z.arg(r, smartcontract.AnyType, depth+1))
+		}
+		return m
+	case smartcontract.AnyType:
+		switch r.byte() % 6 {
+		case 0:
+			return stackitem.Null{}
+		case 1:
+			return z.arg(r, smartcontract.BoolType, depth)
+		case 2:
+			return z.arg(r, smartcontract.IntegerType, depth)
+		case 3:
+			return z.arg(r, smartcontract.ByteArrayType, depth)
+		case 4:
+			return z.arg(r, smartcontract.Hash160Type, depth)
+		default:
+			return z.arg(r, smartcontract.ArrayType, depth)
+		}
+	default:
+		return stackitem.Null{}
+	}
+}
+
+// fuzzReader reads fuzzing input returning zeroes when it's exhausted.
+type fuzzReader struct {
+	data []byte
+}
+
+func (r *fuzzReader) byte() byte {
+	if len(r.data) == 0 {
+		return 0
+	}
+	b := r.data[0]
+	r.data = r.data[1:]
+	return b
+}
+
+func (r *fuzzReader) bytes(n int) []byte {
+	var res = make([]byte, n)
+	m := copy(res, r.data)
+	r.data = r.data[m:]
+	return res
+}
+
+// integer returns either a small number, a boundary value or an arbitrary
+// 64-bit number.
+func (r *fuzzReader) integer() *big.Int {
+	switch sel := r.byte(); sel % 4 {
+	case 0, 1:
+		return big.NewInt(int64(int8(r.byte())))
+	case 2:
+		bounds := []*big.Int{
+			big.NewInt(0),
+			big.NewInt(-1),
+			big.NewInt(math.MaxInt64),
+			big.NewInt(math.MinInt64),
+			new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 255), big.NewInt(1)),
+			new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), 255)),
+		}
+		return bounds[int(r.byte())%len(bounds)]
+	default:
+		return big.NewInt(int64(binary.LittleEndian.Uint64(r.bytes(8))))
+	}
+}
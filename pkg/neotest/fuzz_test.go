@@ -0,0 +1,132 @@
+package neotest_test
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/compiler"
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/neotest"
+	"github.com/nspcc-dev/neo-go/pkg/neotest/chain"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"github.com/nspcc-dev/neo-go/pkg/vm/vmstate"
+	"github.com/stretchr/testify/require"
+)
+
+const fuzzTokenContract = `package token
+
+import (
+	"github.com/nspcc-dev/neo-go/pkg/interop"
+	"github.com/nspcc-dev/neo-go/pkg/interop/runtime"
+	"github.com/nspcc-dev/neo-go/pkg/interop/storage"
+)
+
+const supply = 1000
+
+func _deploy(data any, isUpdate bool) {
+	if !isUpdate {
+		storage.Put(storage.GetContext(), data.(interop.Hash160), supply)
+	}
+}
+
+func balance(ctx storage.Context, account interop.Hash160) int {
+	v := storage.Get(ctx, account)
+	if v == nil {
+		return 0
+	}
+	return v.(int)
+}
+
+func TotalSupply() int {
+	return supply
+}
+
+func BalanceOf(account interop.Hash160) int {
+	return balance(storage.GetReadOnlyContext(), account)
+}
+
+func Transfer(from, to interop.Hash160, amount int) bool {
+	if amount < 0 {
+		panic("invalid amount")
+	}
+	if !runtime.CheckWitness(from) {
+		return false
+	}
+	ctx := storage.GetContext()
+	fromBalance := balance(ctx, from)
+	if fromBalance < amount {
+		return false
+	}
+	storage.Put(ctx, from, fromBalance-amount)
+	storage.Put(ctx, to, balance(ctx, to)+amount)
+	return true
+}
+`
+
+func newFuzzer(t testing.TB) *neotest.Fuzzer {
+	bc, acc := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+	c := neotest.CompileSource(t, e.CommitteeHash, strings.NewReader(fuzzTokenContract), &compiler.Options{Name: "token"})
+	e.DeployContract(t, c, e.CommitteeHash)
+
+	z := neotest.NewFuzzer(t, e.CommitteeInvoker(c.Hash), neotest.NoFault("invalid amount"))
+	z.Accounts = []neotest.Signer{e.Committee, e.NewAccount(t)}
+	z.Invariants = append(z.Invariants, func(t testing.TB, c *neotest.ContractInvoker, call neotest.FuzzCall, res *state.AppExecResult) {
+		var total int64
+		for _, acc := range z.Accounts {
+			stack, err := c.TestInvoke(t, "balanceOf", acc.ScriptHash())
+			require.NoError(t, err)
+			total += stack.Pop().BigInt().Int64()
+		}
+		require.Equal(t, int64(1000), total, "supply is not conserved after %s", call)
+	})
+	return z
+}
+
+func FuzzToken(f *testing.F) {
+	newFuzzer(f).Fuzz(f)
+}
+
+func TestFuzzer(t *testing.T) {
+	z := newFuzzer(t)
+	require.Len(t, z.Methods, 3)
+
+	t.Run("calls", func(t *testing.T) {
+		require.Len(t, z.Calls(nil), 1)
+		require.Equal(t, z.Calls([]byte{3, 1, 2, 3}), z.Calls([]byte{3, 1, 2, 3}))
+		require.Len(t, z.Calls([]byte{3}), 4)
+
+		z.MaxCalls = 2
+		require.Len(t, z.Calls([]byte{3}), 2)
+		z.MaxCalls = 4
+
+		for _, call := range z.Calls([]byte{3, 0, 0, 1, 0, 2, 1, 2, 0xff, 3, 4, 5}) {
+			m := z.ContractInvoker.Chain.GetContractState(z.Hash).Manifest.ABI.GetMethod(call.Method, -1)
+			require.NotNil(t, m)
+			require.Len(t, call.Args, len(m.Parameters))
+			require.Len(t, call.Signers, 1)
+		}
+	})
+
+	t.Run("revert", func(t *testing.T) {
+		height := z.Chain.BlockHeight()
+		z.Run(t, []byte{3, 2, 0, 0, 1, 2, 0, 0, 0x10, 2, 1, 0, 1, 2, 3, 4})
+		require.Equal(t, height, z.Chain.BlockHeight())
+		stack, err := z.TestInvoke(t, "balanceOf", z.Committee.ScriptHash())
+		require.NoError(t, err)
+		require.Equal(t, big.NewInt(1000), stack.Pop().BigInt())
+
+		// Runs don't nest snapshots.
+		id := z.Snapshot(t)
+		z.Run(t, []byte{1, 2, 0, 0, 1})
+		require.Equal(t, id, z.Snapshot(t))
+	})
+
+	t.Run("fault", func(t *testing.T) {
+		call := neotest.FuzzCall{Method: "transfer", Args: []stackitem.Item{stackitem.Make(-1)}}
+		res := &state.AppExecResult{Execution: state.Execution{VMState: vmstate.Fault, FaultException: "at instruction 1: invalid amount"}}
+		neotest.NoFault("invalid amount")(t, nil, call, res)
+		require.Equal(t, "transfer(-1)", call.String())
+	})
+}
@@ -0,0 +1,169 @@
+package neotest
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/core"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm"
+	"github.com/nspcc-dev/neo-go/pkg/vm/gasprofile"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+)
+
+// neotestGasProfile is the name of the environment variable specifying the
+// file GAS profile is written to. Profiling is enabled only when it's set.
+const neotestGasProfile = "NEOTEST_GAS_PROFILE"
+
+var (
+	// gasProfileLock protects all vars below from concurrent modification
+	// when tests are run in parallel.
+	gasProfileLock sync.Mutex
+	// gasProfileChecked is true if the environment variable was checked at
+	// any point.
+	gasProfileChecked bool
+	// gasProfilePath specifies the file GAS profile is written to, profiling
+	// is disabled if it's empty.
+	gasProfilePath string
+	// gasProfileUsers is the number of executors collecting GAS profile.
+	gasProfileUsers int
+	// gasProfileMain is true if tests are run via RunWithGasProfile, the
+	// profile is written once after all of them then.
+	gasProfileMain bool
+	// gasProfiler collects GAS profiling data of all tests.
+	gasProfiler = gasprofile.New()
+)
+
+func isGasProfilingEnabled() bool {
+	gasProfileLock.Lock()
+	defer gasProfileLock.Unlock()
+
+	if !gasProfileChecked {
+		gasProfilePath = os.Getenv(neotestGasProfile)
+		gasProfileChecked = true
+	}
+	return gasProfilePath != ""
+}
+
+// addNativesToGasProfile registers all native contracts of the chain in the
+// profiler.
+func addNativesToGasProfile(bc *core.Blockchain) {
+	for _, cs := range bc.GetNatives() {
+		gasProfiler.AddContract(cs.Hash, &cs.Manifest, nil)
+	}
+}
+
+func addScriptToGasProfile(c *Contract) {
+	if c.Hash.Equals(util.Uint160{}) {
+		return
+	}
+	gasProfiler.AddContract(c.Hash, c.Manifest, c.DebugInfo)
+}
+
+// acquireGasProfile registers an executor collecting GAS profile.
+func acquireGasProfile() {
+	gasProfileLock.Lock()
+	gasProfileUsers++
+	gasProfileLock.Unlock()
+}
+
+// releaseGasProfile unregisters an executor collecting GAS profile and writes
+// the profile if it was the last one and RunWithGasProfile is not used.
+func releaseGasProfile(t testing.TB) {
+	gasProfileLock.Lock()
+	defer gasProfileLock.Unlock()
+
+	gasProfileUsers--
+	if gasProfileUsers > 0 || gasProfileMain {
+		return
+	}
+	if err := writeGasProfile(); err != nil {
+		t.Errorf("gas profile: %v", err)
+	}
+}
+
+// writeGasProfile writes the profile to gasProfilePath, it must be called
+// with gasProfileLock held.
+func writeGasProfile() error {
+	if err := writeFileAtomic(gasProfilePath, gasProfiler.Write); err != nil {
+		return fmt.Errorf("can't write profile to '%s': %w", gasProfilePath, err)
+	}
+	return nil
+}
+
+// RunWithGasProfile runs the tests and writes GAS profile (if enabled with
+// NEOTEST_GAS_PROFILE environment variable) once all of them are completed.
+// It's intended to be used from TestMain:
+//
+//	func TestMain(m *testing.M) {
+//		os.Exit(neotest.RunWithGasProfile(m))
+//	}
+//
+// Without it the profile is written every time there are no more executors
+// collecting it, that is after every top-level test using neotest.
+func RunWithGasProfile(m *testing.M) int {
+	gasProfileLock.Lock()
+	gasProfileMain = true
+	gasProfileLock.Unlock()
+
+	code := m.Run()
+	if !isGasProfilingEnabled() {
+		return code
+	}
+	gasProfileLock.Lock()
+	defer gasProfileLock.Unlock()
+	if err := writeGasProfile(); err != nil {
+		fmt.Fprintf(os.Stderr, "gas profile: %v\n", err)
+		if code == 0 {
+			code = 1
+		}
+	}
+	return code
+}
+
+// profileBlock replays transactions of the given persisted block with GAS
+// profiling enabled, it's a no-op if profiling is disabled for this
+// executor. Replaying requires historic states to be kept by the chain.
+func (e *Executor) profileBlock(t testing.TB, txs []*transaction.Transaction) {
+	if !e.profileGas || e.Chain.GetConfig().Ledger.KeepOnlyLatestState {
+		return
+	}
+	for _, tx := range txs {
+		ic, err := e.Chain.GetTxReplayVM(tx.Hash())
+		if err != nil {
+			t.Fatalf("gas profile: can't replay transaction %s: %v", tx.Hash().StringLE(), err)
+		}
+		tracer := gasProfiler.Attach(ic.VM)
+		_ = ic.Exec()
+		tracer.Flush()
+	}
+}
+
+// setExecHooks sets OnExecHook of the given VM according to the executor
+// coverage and GAS profiling settings. The function returned must be called
+// after the VM execution is finished.
+func (e *Executor) setExecHooks(v *vm.VM) func() {
+	var tracer *gasprofile.Tracer
+	if e.profileGas {
+		tracer = gasProfiler.NewTracer(v)
+	}
+	switch {
+	case e.collectCoverage && tracer != nil:
+		coverageHook := newCoverageHook()
+		v.SetOnExecHook(func(scriptHash util.Uint160, offset int, op opcode.Opcode) {
+			coverageHook(scriptHash, offset, op)
+			tracer.Hook(scriptHash, offset, op)
+		})
+	case e.collectCoverage:
+		v.SetOnExecHook(newCoverageHook())
+	case tracer != nil:
+		v.SetOnExecHook(tracer.Hook)
+	}
+	if tracer == nil {
+		return func() {}
+	}
+	return tracer.Flush
+}
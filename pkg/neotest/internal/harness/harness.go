@@ -0,0 +1,221 @@
+/*
+Package harness contains test chain configuration and block/transaction
+creation code shared by neotest packages. It doesn't depend on testing
+package, so it can be used by the code that is built into regular binaries
+(like scenario runner of the CLI).
+*/
+package harness
+
+import (
+	"encoding/hex"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
+	"github.com/nspcc-dev/neo-go/pkg/core"
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/core/fee"
+	"github.com/nspcc-dev/neo-go/pkg/core/interop"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/trigger"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+	"github.com/nspcc-dev/neo-go/pkg/wallet"
+)
+
+const (
+	// MaxTraceableBlocks is the MaxTraceableBlocks setting of the single
+	// validator chain.
+	MaxTraceableBlocks = 1000
+
+	// TimePerBlock is the TimePerBlock setting of the single validator chain.
+	TimePerBlock = time.Second
+
+	// SingleValidatorWIF is the key of the single validator chain validator
+	// that is also the only committee member.
+	SingleValidatorWIF = "KxyjQ8eUa4FHt3Gvioyt1Wz29cTUrE4eTqX3yFSk1YFCsPL8uNsY"
+)
+
+// Signer is a transaction and block signer, neotest.Signer implements it.
+type Signer interface {
+	Script() []byte
+	ScriptHash() util.Uint160
+	SignHashable(uint32, hash.Hashable) []byte
+	SignTx(netmode.Magic, *transaction.Transaction) error
+}
+
+// accountSigner is a Signer backed by a single account, network fee of its
+// witness is calculated via test invocation if the account contract has
+// InvocationBuilder.
+type accountSigner interface {
+	Signer
+	Account() *wallet.Account
+}
+
+// AccountSigner is a Signer using a single account key (simple-signature or
+// 1-of-1 multisignature account).
+type AccountSigner wallet.Account
+
+// SingleValidator returns a new 1-of-1 multisignature account of the single
+// validator chain validator (and committee).
+func SingleValidator() *wallet.Account {
+	acc, err := wallet.NewAccountFromWIF(SingleValidatorWIF)
+	if err != nil {
+		panic(err)
+	}
+	err = acc.ConvertMultisig(1, keys.PublicKeys{acc.PublicKey()})
+	if err != nil {
+		panic(err)
+	}
+	return acc
+}
+
+// SingleConfig returns the configuration of a chain with a single validator
+// having the given key.
+func SingleConfig(validator *keys.PublicKey) config.Blockchain {
+	return config.Blockchain{
+		ProtocolConfiguration: config.ProtocolConfiguration{
+			Magic:                       netmode.UnitTestNet,
+			MaxTraceableBlocks:          MaxTraceableBlocks,
+			MaxValidUntilBlockIncrement: MaxTraceableBlocks / 2,
+			TimePerBlock:                TimePerBlock,
+			Genesis: config.Genesis{
+				TimePerBlock: TimePerBlock,
+			},
+			StandbyCommittee:   []string{hex.EncodeToString(validator.Bytes())},
+			ValidatorsCount:    1,
+			VerifyTransactions: true,
+		},
+	}
+}
+
+// Script implements Signer interface.
+func (s *AccountSigner) Script() []byte {
+	return (*wallet.Account)(s).Contract.Script
+}
+
+// ScriptHash implements Signer interface.
+func (s *AccountSigner) ScriptHash() util.Uint160 {
+	return (*wallet.Account)(s).Contract.ScriptHash()
+}
+
+// SignHashable implements Signer interface.
+func (s *AccountSigner) SignHashable(magic uint32, item hash.Hashable) []byte {
+	return append([]byte{byte(opcode.PUSHDATA1), keys.SignatureLen},
+		(*wallet.Account)(s).SignHashable(netmode.Magic(magic), item)...)
+}
+
+// SignTx implements Signer interface.
+func (s *AccountSigner) SignTx(magic netmode.Magic, tx *transaction.Transaction) error {
+	return (*wallet.Account)(s).SignTx(magic, tx)
+}
+
+// Account returns the underlying account.
+func (s *AccountSigner) Account() *wallet.Account {
+	return (*wallet.Account)(s)
+}
+
+// AddNetworkFee adds network fee for the given signers witnesses to the
+// transaction.
+func AddNetworkFee(bc *core.Blockchain, tx *transaction.Transaction, signers ...Signer) error {
+	baseFee := bc.GetBaseExecFee()
+	size := io.GetVarSize(tx)
+	for _, sgr := range signers {
+		csgr, ok := sgr.(accountSigner)
+		if ok && csgr.Account().Contract.InvocationBuilder != nil {
+			sc, err := csgr.Account().Contract.InvocationBuilder(tx)
+			if err != nil {
+				return err
+			}
+
+			txCopy := *tx
+			ic, err := bc.GetTestVM(trigger.Verification, &txCopy, nil)
+			if err != nil {
+				return err
+			}
+
+			ic.UseSigners(tx.Signers)
+			ic.VM.GasLimit = bc.GetMaxVerificationGAS()
+
+			err = bc.InitVerificationContext(ic, csgr.ScriptHash(), &transaction.Witness{InvocationScript: sc, VerificationScript: csgr.Script()})
+			if err != nil {
+				return err
+			}
+			if err := ic.VM.Run(); err != nil {
+				return err
+			}
+
+			tx.NetworkFee += ic.VM.GasConsumed()
+			size += io.GetVarSize(sc) + io.GetVarSize(csgr.Script())
+		} else {
+			netFee, sizeDelta := fee.Calculate(baseFee, sgr.Script())
+			tx.NetworkFee += netFee
+			size += sizeDelta
+		}
+	}
+	tx.NetworkFee += int64(size)*bc.FeePerByte() + bc.CalculateAttributesFee(tx)
+	return nil
+}
+
+// NewUnsignedBlock creates a new unsigned block with the given transactions
+// on top of the chain, validator is the next consensus of the block.
+func NewUnsignedBlock(bc *core.Blockchain, validator Signer, txs ...*transaction.Transaction) (*block.Block, error) {
+	lastBlock, err := bc.GetBlock(bc.GetHeaderHash(bc.BlockHeight()))
+	if err != nil {
+		return nil, err
+	}
+	b := &block.Block{
+		Header: block.Header{
+			NextConsensus: validator.ScriptHash(),
+			Script: transaction.Witness{
+				VerificationScript: validator.Script(),
+			},
+			Timestamp: lastBlock.Timestamp + 1,
+		},
+		Transactions: txs,
+	}
+	if bc.GetConfig().StateRootInHeader {
+		b.StateRootEnabled = true
+		b.PrevStateRoot = bc.GetStateModule().CurrentLocalStateRoot()
+	}
+	b.PrevHash = lastBlock.Hash()
+	b.Index = bc.BlockHeight() + 1
+	b.RebuildMerkleRoot()
+	return b, nil
+}
+
+// SignBlock adds validator signature to the block.
+func SignBlock(bc *core.Blockchain, validator Signer, b *block.Block) *block.Block {
+	b.Script.InvocationScript = validator.SignHashable(uint32(bc.GetConfig().Magic), b)
+	return b
+}
+
+// NewTestVM returns an interop context with VM set up to run the transaction
+// script on top of the chain with a dummy next block. The VM is not started,
+// so execution hooks can be set before running it.
+func NewTestVM(bc *core.Blockchain, tx *transaction.Transaction) (*interop.Context, error) {
+	lastBlock, err := bc.GetBlock(bc.GetHeaderHash(bc.BlockHeight()))
+	if err != nil {
+		return nil, err
+	}
+	b := &block.Block{
+		Header: block.Header{
+			Index:     bc.BlockHeight() + 1,
+			Timestamp: lastBlock.Timestamp + 1,
+		},
+	}
+
+	// `GetTestVM` as well as `Run` can use a transaction hash which will set a cached value.
+	// This is unwanted behavior, so we explicitly copy the transaction to perform execution.
+	ttx := *tx
+	ic, err := bc.GetTestVM(trigger.Application, &ttx, b)
+	if err != nil {
+		return nil, err
+	}
+	ic.VM.LoadWithFlags(tx.Script, callflag.All)
+	return ic, nil
+}
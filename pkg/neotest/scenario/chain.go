@@ -0,0 +1,207 @@
+package scenario
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/nspcc-dev/neo-go/pkg/core"
+	"github.com/nspcc-dev/neo-go/pkg/core/native/nativenames"
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/core/storage"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/neotest/internal/harness"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/nef"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/trigger"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"github.com/nspcc-dev/neo-go/pkg/vm/vmstate"
+	"github.com/nspcc-dev/neo-go/pkg/wallet"
+	"go.uber.org/zap"
+)
+
+// testChain is a single validator in-memory chain (the same one
+// neotest/chain.NewSingle creates) with blocks and transactions created and
+// signed on demand.
+type testChain struct {
+	bc *core.Blockchain
+	// committee is the 1-of-1 multisignature account of the validator that
+	// is also the only committee member.
+	committee *wallet.Account
+	nonce     uint32
+}
+
+// newTestChain creates and starts a new chain, it must be closed after use.
+func newTestChain() (*testChain, error) {
+	acc := harness.SingleValidator()
+	cfg := harness.SingleConfig(acc.PublicKey())
+	bc, err := core.NewBlockchain(storage.NewSnapshotStore(storage.NewMemoryStore()), cfg, zap.NewNop())
+	if err != nil {
+		return nil, err
+	}
+	go bc.Run()
+	return &testChain{bc: bc, committee: acc}, nil
+}
+
+// close stops the chain.
+func (c *testChain) close() {
+	c.bc.Close()
+}
+
+// newTx creates a transaction with the given script signed by the signers
+// (with Global scope), system fee is determined by the test invocation.
+func (c *testChain) newTx(script []byte, signers ...*wallet.Account) (*transaction.Transaction, error) {
+	c.nonce++
+	tx := transaction.New(script, 0)
+	tx.Nonce = c.nonce
+	tx.ValidUntilBlock = c.bc.BlockHeight() + 1
+	sgrs := make([]harness.Signer, 0, len(signers))
+	for _, acc := range signers {
+		tx.Signers = append(tx.Signers, transaction.Signer{
+			Account: acc.ScriptHash(),
+			Scopes:  transaction.Global,
+		})
+		sgrs = append(sgrs, (*harness.AccountSigner)(acc))
+	}
+	if err := harness.AddNetworkFee(c.bc, tx, sgrs...); err != nil {
+		return nil, err
+	}
+
+	// Failing transactions are also accepted, so the result is ignored.
+	res, err := c.run(tx)
+	if err != nil {
+		return nil, err
+	}
+	tx.SystemFee = res.GasConsumed
+
+	for _, sgr := range sgrs {
+		if err := sgr.SignTx(c.bc.GetConfig().Magic, tx); err != nil {
+			return nil, err
+		}
+	}
+	return tx, nil
+}
+
+// run executes the transaction script in a test VM on top of the current
+// state.
+func (c *testChain) run(tx *transaction.Transaction) (*state.Execution, error) {
+	ic, err := harness.NewTestVM(c.bc, tx)
+	if err != nil {
+		return nil, err
+	}
+	defer ic.Finalize()
+
+	res := &state.Execution{
+		Trigger: trigger.Application,
+	}
+	if err := ic.VM.Run(); err != nil {
+		res.FaultException = err.Error()
+	}
+	res.VMState = ic.VM.State()
+	res.GasConsumed = ic.VM.GasConsumed()
+	res.Stack = ic.VM.Estack().ToArray()
+	return res, nil
+}
+
+// call invokes the contract method in a test VM and returns the result
+// stack, an error is returned if the invocation FAULTs.
+func (c *testChain) call(h util.Uint160, method string, args ...any) ([]stackitem.Item, error) {
+	script, err := smartcontract.CreateCallScript(h, method, args...)
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.run(transaction.New(script, 0))
+	if err != nil {
+		return nil, err
+	}
+	if res.VMState != vmstate.Halt {
+		return nil, errors.New(res.FaultException)
+	}
+	return res.Stack, nil
+}
+
+// addBlock creates a new block with the given transactions and adds it to
+// the chain.
+func (c *testChain) addBlock(txs ...*transaction.Transaction) error {
+	validator := (*harness.AccountSigner)(c.committee)
+	b, err := harness.NewUnsignedBlock(c.bc, validator, txs...)
+	if err != nil {
+		return err
+	}
+	return c.bc.AddBlock(harness.SignBlock(c.bc, validator, b))
+}
+
+// invoke adds a block with the transaction invoking the contract method and
+// returns its execution result.
+func (c *testChain) invoke(signers []*wallet.Account, h util.Uint160, method string, args ...any) (*state.AppExecResult, error) {
+	script, err := smartcontract.CreateCallScript(h, method, args...)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := c.newTx(script, signers...)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.addBlock(tx); err != nil {
+		return nil, err
+	}
+	aer, err := c.bc.GetAppExecResults(tx.Hash(), trigger.Application)
+	if err != nil {
+		return nil, err
+	}
+	return &aer[0], nil
+}
+
+// invokeHalt is the same as invoke, but fails if the transaction is not
+// HALTed.
+func (c *testChain) invokeHalt(signers []*wallet.Account, h util.Uint160, method string, args ...any) error {
+	aer, err := c.invoke(signers, h, method, args...)
+	if err != nil {
+		return err
+	}
+	if aer.VMState != vmstate.Halt {
+		return fmt.Errorf("%s failed: %s", method, aer.FaultException)
+	}
+	return nil
+}
+
+// newAccount creates a new account holding the given amount of GAS.
+func (c *testChain) newAccount(gas int64) (*wallet.Account, error) {
+	acc, err := wallet.NewAccount()
+	if err != nil {
+		return nil, err
+	}
+	gasHash, err := c.bc.GetNativeContractScriptHash(nativenames.Gas)
+	if err != nil {
+		return nil, err
+	}
+	err = c.invokeHalt([]*wallet.Account{c.committee}, gasHash, "transfer",
+		c.committee.ScriptHash(), acc.ScriptHash(), gas, nil)
+	if err != nil {
+		return nil, fmt.Errorf("can't transfer GAS: %w", err)
+	}
+	return acc, nil
+}
+
+// deploy deploys the contract by the given account and returns its hash.
+func (c *testChain) deploy(deployer *wallet.Account, ne *nef.File, m *manifest.Manifest, data any) (util.Uint160, error) {
+	h := state.CreateContractHash(deployer.ScriptHash(), ne.Checksum, m.Name)
+	if err := m.IsValid(h, true); err != nil {
+		return util.Uint160{}, fmt.Errorf("invalid manifest: %w", err)
+	}
+	rawNEF, err := ne.Bytes()
+	if err != nil {
+		return util.Uint160{}, err
+	}
+	rawManifest, err := json.Marshal(m)
+	if err != nil {
+		return util.Uint160{}, err
+	}
+	err = c.invokeHalt([]*wallet.Account{deployer}, c.bc.ManagementContractHash(), "deploy", rawNEF, rawManifest, data)
+	if err != nil {
+		return util.Uint160{}, err
+	}
+	return h, nil
+}
@@ -0,0 +1,525 @@
+package scenario
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
+	"github.com/nspcc-dev/neo-go/pkg/encoding/fixedn"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/nef"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"github.com/nspcc-dev/neo-go/pkg/vm/vmstate"
+	"github.com/nspcc-dev/neo-go/pkg/wallet"
+)
+
+// Result is the result of a single scenario test.
+type Result struct {
+	Name     string
+	Duration time.Duration
+	// Failure contains the failure description, it's empty if the test
+	// has passed.
+	Failure string
+}
+
+// placeholderRe matches ${name} placeholders.
+var placeholderRe = regexp.MustCompile(`\$\{([^}]*)\}`)
+
+// runner keeps the scenario chain and named entities.
+type runner struct {
+	c         *testChain
+	accounts  map[string]*wallet.Account
+	contracts map[string]util.Uint160
+}
+
+// Run executes all scenario tests on a new test chain and returns their
+// results. An error is returned if the chain can't be set up.
+func (s *Scenario) Run() ([]Result, error) {
+	c, err := newTestChain()
+	if err != nil {
+		return nil, fmt.Errorf("can't create chain: %w", err)
+	}
+	defer c.close()
+
+	r, err := s.setup(c)
+	if err != nil {
+		return nil, err
+	}
+	snapshot, err := c.bc.Snapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	var res = make([]Result, 0, len(s.Tests))
+	for _, test := range s.Tests {
+		var (
+			start = time.Now()
+			err   = r.test(&test)
+			tr    = Result{Name: test.Name, Duration: time.Since(start)}
+		)
+		if err != nil {
+			tr.Failure = err.Error()
+		}
+		res = append(res, tr)
+		if err := c.bc.Revert(snapshot); err != nil {
+			return res, fmt.Errorf("can't revert chain state: %w", err)
+		}
+	}
+	return res, nil
+}
+
+// setup creates accounts and deploys contracts.
+func (s *Scenario) setup(c *testChain) (*runner, error) {
+	r := &runner{
+		c:         c,
+		accounts:  make(map[string]*wallet.Account),
+		contracts: make(map[string]util.Uint160),
+	}
+	r.accounts[Committee] = c.committee
+	for _, a := range s.Accounts {
+		if _, ok := r.accounts[a.Name]; ok {
+			return nil, fmt.Errorf("duplicate account %s", a.Name)
+		}
+		amount := fixedn.Fixed8FromInt64(100)
+		if a.GAS != "" {
+			var err error
+			if amount, err = fixedn.Fixed8FromString(a.GAS); err != nil {
+				return nil, fmt.Errorf("invalid GAS amount of account %s: %w", a.Name, err)
+			}
+		}
+		acc, err := c.newAccount(int64(amount))
+		if err != nil {
+			return nil, fmt.Errorf("can't create account %s: %w", a.Name, err)
+		}
+		r.accounts[a.Name] = acc
+	}
+	for _, ct := range s.Contracts {
+		if _, ok := r.contracts[ct.Name]; ok {
+			return nil, fmt.Errorf("duplicate contract %s", ct.Name)
+		}
+		h, err := r.deploy(&ct)
+		if err != nil {
+			return nil, fmt.Errorf("can't deploy contract %s: %w", ct.Name, err)
+		}
+		r.contracts[ct.Name] = h
+	}
+	return r, nil
+}
+
+func (r *runner) deploy(ct *Contract) (util.Uint160, error) {
+	deployer, err := r.signer(ct.Deployer)
+	if err != nil {
+		return util.Uint160{}, err
+	}
+	var data any
+	if ct.Data != nil {
+		if data, err = r.item(ct.Data); err != nil {
+			return util.Uint160{}, err
+		}
+	}
+	rawNEF, err := os.ReadFile(ct.NEF)
+	if err != nil {
+		return util.Uint160{}, err
+	}
+	ne, err := nef.FileFromBytes(rawNEF)
+	if err != nil {
+		return util.Uint160{}, fmt.Errorf("invalid NEF file %s: %w", ct.NEF, err)
+	}
+	rawManifest, err := os.ReadFile(ct.Manifest)
+	if err != nil {
+		return util.Uint160{}, err
+	}
+	m := new(manifest.Manifest)
+	if err := json.Unmarshal(rawManifest, m); err != nil {
+		return util.Uint160{}, fmt.Errorf("invalid manifest file %s: %w", ct.Manifest, err)
+	}
+	return r.c.deploy(deployer, &ne, m, data)
+}
+
+// signer returns the account with the given name, committee is returned for
+// an empty one.
+func (r *runner) signer(name string) (*wallet.Account, error) {
+	if name == "" {
+		name = Committee
+	}
+	s, ok := r.accounts[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown account %s", name)
+	}
+	return s, nil
+}
+
+// hash returns the script hash of the account or contract with the given
+// name, native contract name, address or hex-encoded LE hash.
+func (r *runner) hash(name string) (util.Uint160, error) {
+	if s, ok := r.accounts[name]; ok {
+		return s.ScriptHash(), nil
+	}
+	if h, ok := r.contracts[name]; ok {
+		return h, nil
+	}
+	if h, err := r.c.bc.GetNativeContractScriptHash(name); err == nil {
+		return h, nil
+	}
+	if h, err := address.StringToUint160(name); err == nil {
+		return h, nil
+	}
+	if h, err := util.Uint160DecodeStringLE(strings.TrimPrefix(name, "0x")); err == nil {
+		return h, nil
+	}
+	return util.Uint160{}, fmt.Errorf("unknown account or contract %s", name)
+}
+
+// expand replaces ${name} placeholders with addresses.
+func (r *runner) expand(s string) (string, error) {
+	var err error
+	res := placeholderRe.ReplaceAllStringFunc(s, func(p string) string {
+		h, hErr := r.hash(p[2 : len(p)-1])
+		if hErr != nil {
+			err = hErr
+		}
+		return address.Uint160ToString(h)
+	})
+	return res, err
+}
+
+// param converts YAML value to the smartcontract parameter.
+func (r *runner) param(v any) (smartcontract.Parameter, error) {
+	switch v := v.(type) {
+	case nil:
+		return smartcontract.Parameter{Type: smartcontract.AnyType}, nil
+	case []any:
+		var arr = make([]smartcontract.Parameter, 0, len(v))
+		for _, e := range v {
+			p, err := r.param(e)
+			if err != nil {
+				return smartcontract.Parameter{}, err
+			}
+			arr = append(arr, p)
+		}
+		return smartcontract.Parameter{Type: smartcontract.ArrayType, Value: arr}, nil
+	case string, int, uint64, float64, bool:
+		s, err := r.expand(fmt.Sprint(v))
+		if err != nil {
+			return smartcontract.Parameter{}, err
+		}
+		p, err := smartcontract.NewParameterFromString(s)
+		if err != nil {
+			return smartcontract.Parameter{}, fmt.Errorf("invalid value %v: %w", v, err)
+		}
+		return *p, nil
+	default:
+		return smartcontract.Parameter{}, fmt.Errorf("unsupported value %v", v)
+	}
+}
+
+// item converts YAML value to the stack item.
+func (r *runner) item(v any) (stackitem.Item, error) {
+	p, err := r.param(v)
+	if err != nil {
+		return nil, err
+	}
+	it, err := p.ToStackItem()
+	if err != nil {
+		return nil, fmt.Errorf("invalid value %v: %w", v, err)
+	}
+	return it, nil
+}
+
+// items converts YAML values to stack items.
+func (r *runner) items(vs []any) ([]stackitem.Item, error) {
+	var res = make([]stackitem.Item, 0, len(vs))
+	for _, v := range vs {
+		it, err := r.item(v)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, it)
+	}
+	return res, nil
+}
+
+// test executes test steps until the first failure.
+func (r *runner) test(test *Test) (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = fmt.Errorf("panic: %v", rec)
+		}
+	}()
+	for i := range test.Steps {
+		if err := r.step(i, &test.Steps[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// step executes a single test step.
+func (r *runner) step(i int, st *Step) error {
+	name := st.Name
+	if name == "" {
+		name = fmt.Sprintf("#%d", i)
+	}
+	var err error
+	switch {
+	case st.Balance != nil:
+		err = r.checkBalance(st.Balance)
+	case st.Storage != nil:
+		err = r.checkStorage(st.Storage)
+	default:
+		err = r.invoke(st)
+	}
+	if err != nil {
+		return fmt.Errorf("step %s: %w", name, err)
+	}
+	return nil
+}
+
+func (r *runner) invoke(st *Step) error {
+	h, err := r.hash(st.Contract)
+	if err != nil {
+		return err
+	}
+	var signers = make([]*wallet.Account, 0, len(st.Signers))
+	for _, name := range st.Signers {
+		s, err := r.signer(name)
+		if err != nil {
+			return err
+		}
+		signers = append(signers, s)
+	}
+	if len(signers) == 0 {
+		signers = append(signers, r.c.committee)
+	}
+	items, err := r.items(st.Args)
+	if err != nil {
+		return err
+	}
+	var args = make([]any, 0, len(items))
+	for _, it := range items {
+		args = append(args, it)
+	}
+	aer, err := r.c.invoke(signers, h, st.Method, args...)
+	if err != nil {
+		return err
+	}
+
+	expState := vmstate.Halt
+	if st.Expect.State != "" {
+		if expState, err = vmstate.FromString(strings.ToUpper(st.Expect.State)); err != nil {
+			return fmt.Errorf("invalid expected state: %w", err)
+		}
+	}
+	if aer.VMState != expState {
+		return fmt.Errorf("%s returned %s, expected %s (exception: %s)", st.Method, aer.VMState, expState, aer.FaultException)
+	}
+	if st.Expect.Exception != "" && !strings.Contains(aer.FaultException, st.Expect.Exception) {
+		return fmt.Errorf("%s failed with %q, expected %q", st.Method, aer.FaultException, st.Expect.Exception)
+	}
+	if st.Expect.Stack != nil {
+		expected, err := r.items(st.Expect.Stack)
+		if err != nil {
+			return err
+		}
+		if !equalItemLists(expected, aer.Stack) {
+			return fmt.Errorf("%s returned %s, expected %s", st.Method, itemsString(aer.Stack), itemsString(expected))
+		}
+	}
+	if st.Expect.Events == nil {
+		return nil
+	}
+	if len(aer.Events) != len(st.Expect.Events) {
+		return fmt.Errorf("%s emitted %d events, expected %d", st.Method, len(aer.Events), len(st.Expect.Events))
+	}
+	for i, exp := range st.Expect.Events {
+		var (
+			ev    = aer.Events[i]
+			evH   = h
+			evArr = ev.Item.Value().([]stackitem.Item)
+		)
+		if exp.Contract != "" {
+			if evH, err = r.hash(exp.Contract); err != nil {
+				return err
+			}
+		}
+		if ev.ScriptHash != evH || ev.Name != exp.Name {
+			return fmt.Errorf("event #%d is %s from %s, expected %s from %s", i, ev.Name, ev.ScriptHash.StringLE(), exp.Name, evH.StringLE())
+		}
+		if exp.Args == nil {
+			continue
+		}
+		expected, err := r.items(exp.Args)
+		if err != nil {
+			return err
+		}
+		if !equalItemLists(expected, evArr) {
+			return fmt.Errorf("event #%d %s has arguments %s, expected %s", i, ev.Name, itemsString(evArr), itemsString(expected))
+		}
+	}
+	return nil
+}
+
+// callInt invokes the contract method returning an integer.
+func (r *runner) callInt(h util.Uint160, method string, args ...any) (int64, error) {
+	stack, err := r.c.call(h, method, args...)
+	if err != nil {
+		return 0, err
+	}
+	if len(stack) != 1 {
+		return 0, fmt.Errorf("%d values returned", len(stack))
+	}
+	v, err := stack[0].TryInteger()
+	if err != nil {
+		return 0, err
+	}
+	if !v.IsInt64() {
+		return 0, errors.New("value is too big")
+	}
+	return v.Int64(), nil
+}
+
+func (r *runner) checkBalance(b *BalanceCheck) error {
+	h, err := r.hash(b.Token)
+	if err != nil {
+		return err
+	}
+	acc, err := r.hash(b.Account)
+	if err != nil {
+		return err
+	}
+	decimals, err := r.callInt(h, "decimals")
+	if err != nil {
+		return fmt.Errorf("failed to get decimals of %s: %w", b.Token, err)
+	}
+	expected, err := fixedn.FromString(b.Amount, int(decimals))
+	if err != nil {
+		return fmt.Errorf("invalid amount %s: %w", b.Amount, err)
+	}
+	stack, err := r.c.call(h, "balanceOf", acc)
+	if err != nil {
+		return fmt.Errorf("failed to get %s balance of %s: %w", b.Token, b.Account, err)
+	}
+	if len(stack) != 1 {
+		return fmt.Errorf("invalid %s balance of %s: %d values returned", b.Token, b.Account, len(stack))
+	}
+	actual, err := stack[0].TryInteger()
+	if err != nil {
+		return fmt.Errorf("invalid %s balance of %s: %w", b.Token, b.Account, err)
+	}
+	if actual.Cmp(expected) != 0 {
+		return fmt.Errorf("%s balance of %s is %s, expected %s", b.Token, b.Account,
+			fixedn.ToString(actual, int(decimals)), fixedn.ToString(expected, int(decimals)))
+	}
+	return nil
+}
+
+func (r *runner) checkStorage(s *StorageCheck) error {
+	h, err := r.hash(s.Contract)
+	if err != nil {
+		return err
+	}
+	cs := r.c.bc.GetContractState(h)
+	if cs == nil {
+		return fmt.Errorf("contract %s is not deployed", s.Contract)
+	}
+	keyItem, err := r.item(s.Key)
+	if err != nil {
+		return err
+	}
+	key, err := keyItem.TryBytes()
+	if err != nil {
+		return fmt.Errorf("invalid key %v: %w", s.Key, err)
+	}
+	actual := r.c.bc.GetStorageItem(cs.ID, key)
+	if s.Value == nil {
+		if actual != nil {
+			return fmt.Errorf("storage item %x of %s is %x, expected to be missing", key, s.Contract, []byte(actual))
+		}
+		return nil
+	}
+	valueItem, err := r.item(s.Value)
+	if err != nil {
+		return err
+	}
+	expected, err := valueItem.TryBytes()
+	if err != nil {
+		return fmt.Errorf("invalid value %v: %w", s.Value, err)
+	}
+	if actual == nil {
+		return fmt.Errorf("storage item %x of %s is missing, expected %x", key, s.Contract, expected)
+	}
+	if !bytes.Equal(actual, expected) {
+		return fmt.Errorf("storage item %x of %s is %x, expected %x", key, s.Contract, []byte(actual), expected)
+	}
+	return nil
+}
+
+// equalItemLists compares expected and actual stack items.
+func equalItemLists(expected, actual []stackitem.Item) bool {
+	if len(expected) != len(actual) {
+		return false
+	}
+	for i := range expected {
+		if !equalItems(expected[i], actual[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// equalItems compares expected and actual stack items deeply. Arrays and
+// structs as well as byte strings and buffers are treated as equal types.
+func equalItems(expected, actual stackitem.Item) bool {
+	switch e := expected.(type) {
+	case *stackitem.Array, *stackitem.Struct:
+		switch actual.(type) {
+		case *stackitem.Array, *stackitem.Struct:
+			return equalItemLists(e.Value().([]stackitem.Item), actual.Value().([]stackitem.Item))
+		}
+		return false
+	case *stackitem.Map:
+		a, ok := actual.(*stackitem.Map)
+		if !ok || a.Len() != e.Len() {
+			return false
+		}
+		for _, el := range e.Value().([]stackitem.MapElement) {
+			i := a.Index(el.Key)
+			if i < 0 || !equalItems(el.Value, a.Value().([]stackitem.MapElement)[i].Value) {
+				return false
+			}
+		}
+		return true
+	case *stackitem.ByteArray, *stackitem.Buffer:
+		switch actual.(type) {
+		case *stackitem.ByteArray, *stackitem.Buffer:
+			eb, _ := e.TryBytes()
+			ab, _ := actual.TryBytes()
+			return bytes.Equal(eb, ab)
+		}
+		return false
+	default:
+		return expected.Type() == actual.Type() && expected.Equals(actual)
+	}
+}
+
+// itemsString returns JSON representation of stack items for error messages.
+func itemsString(items []stackitem.Item) string {
+	var res = make([]string, 0, len(items))
+	for _, it := range items {
+		b, err := stackitem.ToJSONWithTypes(it)
+		if err != nil {
+			res = append(res, it.String())
+			continue
+		}
+		res = append(res, string(b))
+	}
+	return "[" + strings.Join(res, ", ") + "]"
+}
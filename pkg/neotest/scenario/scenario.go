@@ -0,0 +1,191 @@
+/*
+Package scenario implements declarative contract test scenarios. A scenario is
+a YAML file describing accounts and contracts (NEF and manifest pairs) to be
+deployed on a fresh single-node in-memory chain along with a set of tests. Every
+test is a sequence of steps: contract invocations with expected results and
+events, NEP-17 balance checks and contract storage checks. Tests are
+independent, each of them starts from the state right after the accounts
+creation and contracts deployment:
+
+	name: Token
+	accounts:
+	  - name: alice
+	  - name: bob
+	    gas: "10"
+	contracts:
+	  - name: token
+	    nef: token.nef
+	    manifest: token.manifest.json
+	    deployer: alice
+	tests:
+	  - name: transfer
+	    steps:
+	      - contract: token
+	        method: transfer
+	        args: ["${alice}", "${bob}", 10, nil]
+	        signers: [alice]
+	        expect:
+	          stack: [true]
+	          events:
+	            - name: Transfer
+	              args: ["${alice}", "${bob}", 10]
+	      - balance: {token: token, account: bob, amount: "10"}
+	      - storage: {contract: token, key: "string:totalSupply", value: 1000}
+
+Arguments and expected values use the same syntax as the CLI contract
+invocation commands (see cmdargs), YAML sequences are arrays. ${name} in any of
+them is replaced with the address of the account or contract named so (native
+contracts can be referenced by their names). Accounts hold 100 GAS by default,
+the committee account (also available as "committee") is used as the deployer
+and signer by default.
+*/
+package scenario
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Committee is the name of the predefined committee account.
+const Committee = "committee"
+
+// Scenario is a set of tests executed on a test chain with the given
+// accounts and contracts.
+type Scenario struct {
+	Name      string     `yaml:"name"`
+	Accounts  []Account  `yaml:"accounts"`
+	Contracts []Contract `yaml:"contracts"`
+	Tests     []Test     `yaml:"tests"`
+}
+
+// Account is a test account funded with GAS.
+type Account struct {
+	Name string `yaml:"name"`
+	// GAS is the amount of GAS transferred to the account, 100 by default.
+	GAS string `yaml:"gas"`
+}
+
+// Contract is a contract deployed for the scenario.
+type Contract struct {
+	Name string `yaml:"name"`
+	// NEF and Manifest are paths to the contract files, relative to the
+	// scenario file.
+	NEF      string `yaml:"nef"`
+	Manifest string `yaml:"manifest"`
+	// Deployer is the name of the deploying account, committee by default.
+	Deployer string `yaml:"deployer"`
+	// Data is an optional _deploy method argument.
+	Data any `yaml:"data"`
+}
+
+// Test is a named sequence of steps.
+type Test struct {
+	Name  string `yaml:"name"`
+	Steps []Step `yaml:"steps"`
+}
+
+// Step is either a contract invocation (if Method is set) or a balance or
+// storage check.
+type Step struct {
+	Name     string        `yaml:"name"`
+	Contract string        `yaml:"contract"`
+	Method   string        `yaml:"method"`
+	Args     []any         `yaml:"args"`
+	Signers  []string      `yaml:"signers"`
+	Expect   Expectation   `yaml:"expect"`
+	Balance  *BalanceCheck `yaml:"balance"`
+	Storage  *StorageCheck `yaml:"storage"`
+}
+
+// Expectation describes the expected invocation result.
+type Expectation struct {
+	// State is the expected VM state, HALT by default.
+	State string `yaml:"state"`
+	// Exception is a substring of the expected FAULT exception.
+	Exception string `yaml:"exception"`
+	// Stack contains expected result stack items (not checked if nil).
+	Stack []any `yaml:"stack"`
+	// Events contains all expected notifications (not checked if nil).
+	Events []Event `yaml:"events"`
+}
+
+// Event is an expected notification.
+type Event struct {
+	// Contract is the name of the emitting contract, the invoked one by
+	// default.
+	Contract string `yaml:"contract"`
+	Name     string `yaml:"name"`
+	// Args contains expected notification arguments (not checked if nil).
+	Args []any `yaml:"args"`
+}
+
+// BalanceCheck checks NEP-17 token balance of the account.
+type BalanceCheck struct {
+	Token   string `yaml:"token"`
+	Account string `yaml:"account"`
+	// Amount is a decimal amount with token decimals taken into account.
+	Amount string `yaml:"amount"`
+}
+
+// StorageCheck checks the value of the contract storage item.
+type StorageCheck struct {
+	Contract string `yaml:"contract"`
+	Key      any    `yaml:"key"`
+	// Value is the expected item value, nil means the item must be missing.
+	Value any `yaml:"value"`
+}
+
+// Load reads the scenario from the file, paths to contract files are made
+// relative to the current directory.
+func Load(path string) (*Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var s Scenario
+	if err := yaml.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("invalid scenario %s: %w", path, err)
+	}
+	if s.Name == "" {
+		s.Name = filepath.Base(path)
+	}
+	dir := filepath.Dir(path)
+	for i := range s.Contracts {
+		c := &s.Contracts[i]
+		if c.Name == "" || c.NEF == "" || c.Manifest == "" {
+			return nil, fmt.Errorf("invalid scenario %s: contract #%d must have name, nef and manifest", path, i)
+		}
+		if !filepath.IsAbs(c.NEF) {
+			c.NEF = filepath.Join(dir, c.NEF)
+		}
+		if !filepath.IsAbs(c.Manifest) {
+			c.Manifest = filepath.Join(dir, c.Manifest)
+		}
+	}
+	for i, a := range s.Accounts {
+		if a.Name == "" || a.Name == Committee {
+			return nil, fmt.Errorf("invalid scenario %s: invalid name of account #%d", path, i)
+		}
+	}
+	for _, t := range s.Tests {
+		for j, st := range t.Steps {
+			var kinds int
+			if st.Method != "" {
+				kinds++
+			}
+			if st.Balance != nil {
+				kinds++
+			}
+			if st.Storage != nil {
+				kinds++
+			}
+			if kinds != 1 {
+				return nil, fmt.Errorf("invalid scenario %s: step #%d of test %q must have exactly one of method, balance or storage", path, j, t.Name)
+			}
+		}
+	}
+	return &s, nil
+}
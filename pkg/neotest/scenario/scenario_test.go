@@ -0,0 +1,183 @@
+package scenario
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/compiler"
+	"github.com/nspcc-dev/neo-go/pkg/neotest"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+const tokenContract = `package token
+
+import (
+	"github.com/nspcc-dev/neo-go/pkg/interop"
+	"github.com/nspcc-dev/neo-go/pkg/interop/runtime"
+	"github.com/nspcc-dev/neo-go/pkg/interop/storage"
+)
+
+func _deploy(data any, isUpdate bool) {
+	if !isUpdate {
+		ctx := storage.GetContext()
+		storage.Put(ctx, "totalSupply", 1000)
+		storage.Put(ctx, data.(interop.Hash160), 1000)
+	}
+}
+
+func Decimals() int {
+	return 2
+}
+
+func BalanceOf(account interop.Hash160) int {
+	return balance(storage.GetReadOnlyContext(), account)
+}
+
+func balance(ctx storage.Context, account interop.Hash160) int {
+	v := storage.Get(ctx, account)
+	if v == nil {
+		return 0
+	}
+	return v.(int)
+}
+
+func Transfer(from, to interop.Hash160, amount int, data any) bool {
+	if amount < 0 {
+		panic("invalid amount")
+	}
+	if !runtime.CheckWitness(from) {
+		return false
+	}
+	ctx := storage.GetContext()
+	fromBalance := balance(ctx, from)
+	if fromBalance < amount {
+		return false
+	}
+	storage.Put(ctx, from, fromBalance-amount)
+	storage.Put(ctx, to, balance(ctx, to)+amount)
+	runtime.Notify("Transfer", from, to, amount)
+	return true
+}
+`
+
+const tokenScenario = `name: Token
+accounts:
+  - name: alice
+  - name: bob
+    gas: "10"
+contracts:
+  - name: token
+    nef: token.nef
+    manifest: token.manifest.json
+    deployer: alice
+    data: "${alice}"
+tests:
+  - name: transfer
+    steps:
+      - contract: token
+        method: transfer
+        args: ["${alice}", "${bob}", 10, nil]
+        signers: [alice]
+        expect:
+          stack: [true]
+          events:
+            - name: Transfer
+              args: ["${alice}", "${bob}", 10]
+      - balance: {token: token, account: bob, amount: "0.1"}
+      - balance: {token: GasToken, account: bob, amount: "10"}
+      - storage: {contract: token, key: "string:totalSupply", value: 1000}
+  - name: independent
+    steps:
+      - balance: {token: token, account: bob, amount: "0"}
+      - name: no witness
+        contract: token
+        method: transfer
+        args: ["${alice}", "${bob}", 10, nil]
+        signers: [bob]
+        expect:
+          stack: [false]
+          events: []
+  - name: fault
+    steps:
+      - contract: token
+        method: transfer
+        args: ["${alice}", "${bob}", -1, nil]
+        expect:
+          state: fault
+          exception: invalid amount
+  - name: failing
+    steps:
+      - name: wrong result
+        contract: token
+        method: balanceOf
+        args: ["${alice}"]
+        expect:
+          stack: [1]
+      - balance: {token: token, account: alice, amount: "1"}
+  - name: missing storage
+    steps:
+      - storage: {contract: token, key: "string:unknown", value: 1}
+`
+
+func TestScenario(t *testing.T) {
+	dir := t.TempDir()
+	c := neotest.CompileSource(t, util.Uint160{}, strings.NewReader(tokenContract), &compiler.Options{
+		Name: "token",
+		ContractEvents: []compiler.HybridEvent{{
+			Name: "Transfer",
+			Parameters: []compiler.HybridParameter{
+				{Parameter: manifest.NewParameter("from", smartcontract.Hash160Type)},
+				{Parameter: manifest.NewParameter("to", smartcontract.Hash160Type)},
+				{Parameter: manifest.NewParameter("amount", smartcontract.IntegerType)},
+			},
+		}},
+	})
+	rawNEF, err := c.NEF.Bytes()
+	require.NoError(t, err)
+	rawManifest, err := json.Marshal(c.Manifest)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "token.nef"), rawNEF, 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "token.manifest.json"), rawManifest, 0o644))
+
+	path := filepath.Join(dir, "token.yml")
+	require.NoError(t, os.WriteFile(path, []byte(tokenScenario), 0o644))
+
+	s, err := Load(path)
+	require.NoError(t, err)
+	require.Equal(t, "Token", s.Name)
+	require.Equal(t, filepath.Join(dir, "token.nef"), s.Contracts[0].NEF)
+
+	res, err := s.Run()
+	require.NoError(t, err)
+	require.Len(t, res, 5)
+	for _, r := range res[:3] {
+		require.Empty(t, r.Failure, r.Name)
+	}
+	require.Equal(t, `step wrong result: balanceOf returned [{"type":"Integer","value":"1000"}], expected [{"type":"Integer","value":"1"}]`, res[3].Failure)
+	require.Equal(t, `step #0: storage item 756e6b6e6f776e of token is missing, expected 01`, res[4].Failure)
+
+	t.Run("invalid", func(t *testing.T) {
+		for name, src := range map[string]string{
+			"step kind":    "tests:\n  - steps:\n      - contract: token\n",
+			"contract":     "contracts:\n  - name: token\n",
+			"account name": "accounts:\n  - name: committee\n",
+			"yaml":         "tests: 1",
+		} {
+			require.NoError(t, os.WriteFile(path, []byte(src), 0o644))
+			_, err := Load(path)
+			require.Error(t, err, name)
+		}
+	})
+
+	t.Run("setup failure", func(t *testing.T) {
+		s := &Scenario{Contracts: []Contract{{Name: "token", NEF: filepath.Join(dir, "missing.nef"), Manifest: filepath.Join(dir, "token.manifest.json")}}}
+		_, err := s.Run()
+		require.ErrorContains(t, err, "can't deploy contract token: open "+filepath.Join(dir, "missing.nef"))
+	})
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
 	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
 	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/neotest/internal/harness"
 	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/nspcc-dev/neo-go/pkg/vm"
 	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
@@ -47,9 +48,6 @@ type MultiSigner interface {
 	Single(n int) SingleSigner
 }
 
-// signer represents a simple-signature signer.
-type signer wallet.Account
-
 // multiSigner represents a single multi-signature signer consisting of the provided accounts.
 type multiSigner struct {
 	accounts []*wallet.Account
@@ -62,33 +60,7 @@ func NewSingleSigner(acc *wallet.Account) SingleSigner {
 	if !vm.IsSignatureContract(acc.Contract.Script) {
 		panic("account must have simple-signature verification script")
 	}
-	return (*signer)(acc)
-}
-
-// Script implements Signer interface.
-func (s *signer) Script() []byte {
-	return (*wallet.Account)(s).Contract.Script
-}
-
-// ScriptHash implements Signer interface.
-func (s *signer) ScriptHash() util.Uint160 {
-	return (*wallet.Account)(s).Contract.ScriptHash()
-}
-
-// SignHashable implements Signer interface.
-func (s *signer) SignHashable(magic uint32, item hash.Hashable) []byte {
-	return append([]byte{byte(opcode.PUSHDATA1), keys.SignatureLen},
-		(*wallet.Account)(s).SignHashable(netmode.Magic(magic), item)...)
-}
-
-// SignTx implements Signer interface.
-func (s *signer) SignTx(magic netmode.Magic, tx *transaction.Transaction) error {
-	return (*wallet.Account)(s).SignTx(magic, tx)
-}
-
-// Account implements SingleSigner interface.
-func (s *signer) Account() *wallet.Account {
-	return (*wallet.Account)(s)
+	return (*harness.AccountSigner)(acc)
 }
 
 // NewMultiSigner returns a multi-signature signer for the provided account.
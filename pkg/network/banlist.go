@@ -0,0 +1,278 @@
+package network
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultBanScore    = 100
+	defaultBanDuration = 24 * time.Hour
+	// scoreDecayInterval is the time it takes for a misbehavior score to
+	// decrease by one point, so that rare accidental errors of honest peers
+	// don't accumulate into a ban.
+	scoreDecayInterval = time.Minute
+)
+
+// misbehaviorKind is a type of peer misbehavior with the associated score.
+type misbehaviorKind byte
+
+const (
+	// malformedMessage is a message that can't be decoded.
+	malformedMessage misbehaviorKind = iota
+	// invalidInventory is an inventory of unknown type or an empty one.
+	invalidInventory
+	// invalidBlock is a block that contradicts our chain.
+	invalidBlock
+	// invalidPayload is an extensible payload rejected by the pool.
+	invalidPayload
+	// protocolViolation is a message that is not expected in the current
+	// peer state or node configuration.
+	protocolViolation
+)
+
+var misbehaviorInfo = [...]struct {
+	name  string
+	score int
+}{
+	malformedMessage:  {"malformed_message", 50},
+	invalidInventory:  {"invalid_inventory", 20},
+	invalidBlock:      {"invalid_block", 50},
+	invalidPayload:    {"invalid_payload", 10},
+	protocolViolation: {"protocol_violation", 20},
+}
+
+// errBanned is returned when the peer gets banned or when a connection to
+// the banned peer is attempted.
+var errBanned = errors.New("peer is banned")
+
+// misbehavior is an error that is returned from message handlers when the
+// peer doesn't follow the protocol. It's used to accumulate peer score.
+type misbehavior struct {
+	kind misbehaviorKind
+	err  error
+}
+
+// BanInfo represents a banned peer.
+type BanInfo struct {
+	// Address is the IP address of the banned peer (without port).
+	Address string
+	// Until is the ban expiration time.
+	Until time.Time
+}
+
+// banList keeps track of peer misbehavior scores and bans. Scores and bans
+// are accounted per host, so reconnecting doesn't help the peer.
+type banList struct {
+	lock   sync.Mutex
+	path   string
+	scores map[string]peerScore
+	bans   map[string]time.Time
+	// scoresPruned is the last time fully decayed scores were removed.
+	scoresPruned time.Time
+}
+
+type peerScore struct {
+	score   int
+	updated time.Time
+}
+
+func newMisbehavior(kind misbehaviorKind, err error) error {
+	return &misbehavior{kind: kind, err: err}
+}
+
+// Error implements the error interface.
+func (m *misbehavior) Error() string {
+	return m.err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (m *misbehavior) Unwrap() error {
+	return m.err
+}
+
+// newBanList creates a new banList, it loads bans from the file if the path
+// is not empty and the file exists.
+func newBanList(path string) (*banList, error) {
+	var b = &banList{
+		path:   path,
+		scores: make(map[string]peerScore),
+		bans:   make(map[string]time.Time),
+	}
+	if path == "" {
+		return b, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return b, nil
+		}
+		return nil, fmt.Errorf("failed to read ban list: %w", err)
+	}
+	err = json.Unmarshal(data, &b.bans)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ban list: %w", err)
+	}
+	b.prune(time.Now())
+	updatePeersBannedMetric(len(b.bans))
+	return b, nil
+}
+
+// addScore adds the given score to the host and returns its resulting score.
+func (b *banList) addScore(host string, score int, now time.Time) int {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if now.Sub(b.scoresPruned) >= scoreDecayInterval {
+		b.pruneScores(now)
+	}
+	var ps = b.scores[host]
+	ps.score = ps.decayed(now) + score
+	ps.updated = now
+	b.scores[host] = ps
+	return ps.score
+}
+
+// decayed returns the score decreased according to the time passed since the
+// last update.
+func (ps peerScore) decayed(now time.Time) int {
+	if ps.updated.IsZero() {
+		return ps.score
+	}
+	return max(ps.score-int(now.Sub(ps.updated)/scoreDecayInterval), 0)
+}
+
+// pruneScores removes fully decayed scores, so that the map doesn't grow
+// with every address that ever misbehaved. It must be called under the lock.
+func (b *banList) pruneScores(now time.Time) {
+	for host, ps := range b.scores {
+		if ps.decayed(now) == 0 {
+			delete(b.scores, host)
+		}
+	}
+	b.scoresPruned = now
+}
+
+// ban bans the host until the given time and saves the list if needed.
+func (b *banList) ban(host string, until time.Time) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	delete(b.scores, host)
+	b.bans[host] = until
+	b.prune(time.Now())
+	updatePeersBannedMetric(len(b.bans))
+	return b.save()
+}
+
+// isBanned checks whether the host is banned at the moment.
+func (b *banList) isBanned(host string, now time.Time) bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	until, ok := b.bans[host]
+	return ok && now.Before(until)
+}
+
+// list returns all active bans.
+func (b *banList) list(now time.Time) []BanInfo {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	res := make([]BanInfo, 0, len(b.bans))
+	for host, until := range b.bans {
+		if now.Before(until) {
+			res = append(res, BanInfo{Address: host, Until: until})
+		}
+	}
+	return res
+}
+
+// prune removes expired bans, it must be called under the lock.
+func (b *banList) prune(now time.Time) {
+	for host, until := range b.bans {
+		if !now.Before(until) {
+			delete(b.bans, host)
+		}
+	}
+}
+
+// save writes the ban list to the file if the path is configured, it must be
+// called under the lock.
+func (b *banList) save() error {
+	if b.path == "" {
+		return nil
+	}
+	data, err := json.Marshal(b.bans)
+	if err != nil {
+		return err
+	}
+	if err = io.MakeDirForFile(b.path, "ban list"); err != nil {
+		return err
+	}
+	tmp := b.path + ".tmp"
+	if err = os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, b.path)
+}
+
+// hostOf returns the host part of the given address or the address itself if
+// it can't be split.
+func hostOf(addr net.Addr) string {
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return addr.String()
+	}
+	return host
+}
+
+// isBanned checks whether the given remote address is banned.
+func (s *Server) isBanned(addr net.Addr) bool {
+	return s.bans.isBanned(hostOf(addr), time.Now())
+}
+
+// BannedPeers returns a list of currently banned peers.
+func (s *Server) BannedPeers() []BanInfo {
+	return s.bans.list(time.Now())
+}
+
+// penalize increases misbehavior score of the peer if the error given is a
+// misbehavior and bans it if the score reaches the configured limit. It
+// returns true if the peer is banned.
+func (s *Server) penalize(p Peer, err error) bool {
+	var m *misbehavior
+	if !errors.As(err, &m) {
+		return false
+	}
+	var (
+		info  = misbehaviorInfo[m.kind]
+		host  = hostOf(p.RemoteAddr())
+		now   = time.Now()
+		score = s.bans.addScore(host, info.score, now)
+	)
+	addMisbehaviorMetric(info.name)
+	if score < s.BanScore {
+		s.log.Debug("peer misbehavior",
+			zap.Stringer("addr", p.RemoteAddr()),
+			zap.String("kind", info.name),
+			zap.Int("score", score),
+			zap.Error(err))
+		return false
+	}
+	until := now.Add(s.BanDuration)
+	s.log.Warn("banning peer",
+		zap.String("host", host),
+		zap.String("kind", info.name),
+		zap.Int("score", score),
+		zap.Time("until", until),
+		zap.Error(err))
+	if saveErr := s.bans.ban(host, until); saveErr != nil {
+		s.log.Error("failed to save ban list", zap.Error(saveErr))
+	}
+	return true
+}
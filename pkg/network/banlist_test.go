@@ -0,0 +1,104 @@
+package network
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/network/payload"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBanListScore(t *testing.T) {
+	b, err := newBanList("")
+	require.NoError(t, err)
+
+	now := time.Now()
+	require.Equal(t, 20, b.addScore("1.2.3.4", 20, now))
+	require.Equal(t, 40, b.addScore("1.2.3.4", 20, now))
+	require.Equal(t, 10, b.addScore("4.3.2.1", 10, now))
+
+	// Score decays over time, but never goes below zero.
+	require.Equal(t, 45, b.addScore("1.2.3.4", 10, now.Add(5*scoreDecayInterval)))
+	require.Len(t, b.scores, 2)
+
+	// Fully decayed scores are removed.
+	require.Equal(t, 10, b.addScore("1.2.3.4", 10, now.Add(time.Hour)))
+	require.Len(t, b.scores, 1)
+	require.Contains(t, b.scores, "1.2.3.4")
+}
+
+func TestBanListPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sub", "bans.json")
+	b, err := newBanList(path)
+	require.NoError(t, err)
+	require.Empty(t, b.list(time.Now()))
+
+	now := time.Now()
+	require.NoError(t, b.ban("1.2.3.4", now.Add(time.Hour)))
+	require.NoError(t, b.ban("4.3.2.1", now.Add(2*time.Hour)))
+	require.True(t, b.isBanned("1.2.3.4", now))
+	require.False(t, b.isBanned("1.2.3.4", now.Add(time.Hour)))
+	require.False(t, b.isBanned("1.1.1.1", now))
+
+	b, err = newBanList(path)
+	require.NoError(t, err)
+	require.True(t, b.isBanned("1.2.3.4", now))
+	require.True(t, b.isBanned("4.3.2.1", now))
+	require.ElementsMatch(t, []string{"1.2.3.4", "4.3.2.1"}, banAddrs(b.list(now)))
+	require.Equal(t, []string{"4.3.2.1"}, banAddrs(b.list(now.Add(90*time.Minute))))
+
+	t.Run("expired", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(path, []byte(`{"1.2.3.4":"2000-01-01T00:00:00Z"}`), 0o644))
+		b, err := newBanList(path)
+		require.NoError(t, err)
+		require.Empty(t, b.bans)
+	})
+	t.Run("corrupted", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(path, []byte(`not a json`), 0o644))
+		_, err := newBanList(path)
+		require.Error(t, err)
+	})
+}
+
+func banAddrs(bans []BanInfo) []string {
+	var res = make([]string, 0, len(bans))
+	for _, b := range bans {
+		res = append(res, b.Address)
+	}
+	return res
+}
+
+func TestServerPenalize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bans.json")
+	s := newTestServer(t, ServerConfig{BanScore: 40, BanListPath: path})
+	require.Equal(t, defaultBanDuration, s.BanDuration)
+	p := newLocalPeer(t, s)
+
+	require.False(t, s.penalize(p, errors.New("not a misbehavior")))
+	require.False(t, s.isBanned(p.RemoteAddr()))
+
+	p.handshaked.Store(true)
+	err := s.handleMessage(p, NewMessage(CMDVersion, &payload.Version{}))
+	require.Error(t, err)
+	var m *misbehavior
+	require.ErrorAs(t, err, &m)
+	require.Equal(t, protocolViolation, m.kind)
+
+	require.False(t, s.penalize(p, err))
+	require.False(t, s.isBanned(p.RemoteAddr()))
+	require.Empty(t, s.BannedPeers())
+
+	require.True(t, s.penalize(p, err))
+	require.True(t, s.isBanned(p.RemoteAddr()))
+	bans := s.BannedPeers()
+	require.Equal(t, 1, len(bans))
+	require.Equal(t, "0.0.0.0", bans[0].Address)
+	require.WithinDuration(t, time.Now().Add(defaultBanDuration), bans[0].Until, time.Minute)
+
+	// Bans survive server restarts.
+	s = newTestServer(t, ServerConfig{BanListPath: path})
+	require.True(t, s.isBanned(p.RemoteAddr()))
+}
@@ -24,6 +24,20 @@ var (
 			Namespace: "neogo",
 		},
 	)
+	peersBanned = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Help:      "Number of banned peers",
+			Name:      "peers_banned",
+			Namespace: "neogo",
+		},
+	)
+	peerMisbehavior = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Help:      "Number of peer misbehavior cases",
+			Name:      "peer_misbehavior_total",
+			Namespace: "neogo",
+		},
+		[]string{"reason"})
 	serverID = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Help:      "network server ID",
@@ -63,6 +77,8 @@ func init() {
 	prometheus.MustRegister(
 		estimatedNetworkSize,
 		peersConnected,
+		peersBanned,
+		peerMisbehavior,
 		serverID,
 		poolCount,
 		blockQueueLength,
@@ -101,6 +117,14 @@ func updatePeersConnectedMetric(pConnected int) {
 	peersConnected.Set(float64(pConnected))
 }
 
+func updatePeersBannedMetric(pBanned int) {
+	peersBanned.Set(float64(pBanned))
+}
+
+func addMisbehaviorMetric(reason string) {
+	peerMisbehavior.WithLabelValues(reason).Inc()
+}
+
 func setSeverID(id string) {
 	serverID.WithLabelValues(id).Add(1)
 }
@@ -140,6 +140,8 @@ type (
 		lock  sync.RWMutex
 		peers map[Peer]bool
 
+		bans *banList
+
 		// lastRequestedBlock contains a height of the last requested block.
 		lastRequestedBlock atomic.Uint32
 		// lastRequestedHeader contains a height of the last requested header.
@@ -309,6 +311,25 @@ func newServerFromConstructors(config ServerConfig, chain Ledger, stSync StateSy
 		s.PingInterval = defaultPingInterval
 	}
 
+	if s.BanScore <= 0 {
+		s.log.Info("bad BanScore configured, using the default value",
+			zap.Int("configured", s.BanScore),
+			zap.Int("actual", defaultBanScore))
+		s.BanScore = defaultBanScore
+	}
+
+	if s.BanDuration <= 0 {
+		s.log.Info("bad BanDuration configured, using the default value",
+			zap.Duration("configured", s.BanDuration),
+			zap.Duration("actual", defaultBanDuration))
+		s.BanDuration = defaultBanDuration
+	}
+
+	s.bans, err = newBanList(s.BanListPath)
+	if err != nil {
+		return nil, err
+	}
+
 	if len(s.ServerConfig.Addresses) == 0 {
 		return nil, errors.New("no bind addresses configured")
 	}
@@ -630,7 +651,7 @@ func (s *Server) run() {
 			if s.peers[drop.peer] {
 				delete(s.peers, drop.peer)
 				s.lock.Unlock()
-				if errors.Is(drop.reason, errInvalidInvType) || errors.Is(drop.reason, errStateMismatch) || errors.Is(drop.reason, errBlocksRequestFailed) {
+				if errors.Is(drop.reason, errInvalidInvType) || errors.Is(drop.reason, errStateMismatch) || errors.Is(drop.reason, errBlocksRequestFailed) || errors.Is(drop.reason, errBanned) {
 					s.log.Warn("peer disconnected",
 						zap.Stringer("addr", drop.peer.RemoteAddr()),
 						zap.Error(drop.reason),
@@ -899,6 +920,12 @@ func (s *Server) handleBlockCmd(p Peer, block *block.Block) error {
 	if s.blockFetcher.IsActive() || s.config.NeoFSStateSyncExtensions && s.stateSync.IsActive() {
 		return nil
 	}
+	if block.Index <= s.chain.HeaderHeight() {
+		h := s.chain.GetHeaderHash(block.Index)
+		if !h.Equals(util.Uint256{}) && !h.Equals(block.Hash()) {
+			return newMisbehavior(invalidBlock, fmt.Errorf("block %d %s conflicts with the known header %s", block.Index, block.Hash().StringLE(), h.StringLE()))
+		}
+	}
 	if s.stateSync.IsActive() {
 		return s.bSyncQueue.Put(block)
 	}
@@ -1110,7 +1137,7 @@ func addMessageToPacket(batch *io.BufBinWriter, msg *Message, send func([]byte)
 // handleGetMPTDataCmd processes the received MPT inventory.
 func (s *Server) handleGetMPTDataCmd(p Peer, inv *payload.MPTInventory) error {
 	if !s.config.P2PStateExchangeExtensions {
-		return errors.New("GetMPTDataCMD was received, but P2PStateExchangeExtensions are disabled")
+		return newMisbehavior(protocolViolation, errors.New("GetMPTDataCMD was received, but P2PStateExchangeExtensions are disabled"))
 	}
 	// Even if s.config.KeepOnlyLatestState enabled, we'll keep latest P1 and P2 MPT states.
 	resp := payload.MPTData{}
@@ -1148,7 +1175,7 @@ func (s *Server) handleGetMPTDataCmd(p Peer, inv *payload.MPTInventory) error {
 
 func (s *Server) handleMPTDataCmd(p Peer, data *payload.MPTData) error {
 	if !s.config.P2PStateExchangeExtensions {
-		return errors.New("MPTDataCMD was received, but P2PStateExchangeExtensions are disabled")
+		return newMisbehavior(protocolViolation, errors.New("MPTDataCMD was received, but P2PStateExchangeExtensions are disabled"))
 	}
 	return s.stateSync.AddMPTNodes(data.Nodes)
 }
@@ -1265,7 +1292,7 @@ func (s *Server) handleExtensibleCmd(e *payload.Extensible) error {
 	}
 	ok, err := s.extensiblePool.Add(e)
 	if err != nil {
-		return err
+		return newMisbehavior(invalidPayload, err)
 	}
 	if !ok { // payload is already in cache
 		return nil
@@ -1356,7 +1383,7 @@ drainloop:
 // handleP2PNotaryRequestCmd process the received P2PNotaryRequest payload.
 func (s *Server) handleP2PNotaryRequestCmd(r *payload.P2PNotaryRequest) error {
 	if !s.chain.P2PSigExtensionsEnabled() {
-		return errors.New("P2PNotaryRequestCMD was received, but P2PSignatureExtensions are disabled")
+		return newMisbehavior(protocolViolation, errors.New("P2PNotaryRequestCMD was received, but P2PSignatureExtensions are disabled"))
 	}
 	// It's OK for it to fail for various reasons like request already existing
 	// in the pool.
@@ -1416,7 +1443,7 @@ func (s *Server) broadcastP2PNotaryRequestPayload(_ *transaction.Transaction, da
 // handleAddrCmd will process the received addresses.
 func (s *Server) handleAddrCmd(p Peer, addrs *payload.AddressList) error {
 	if !p.CanProcessAddr() {
-		return errors.New("unexpected addr received")
+		return newMisbehavior(protocolViolation, errors.New("unexpected addr received"))
 	}
 	for _, a := range addrs.Addrs {
 		addr, err := a.GetTCPAddress()
@@ -1510,7 +1537,7 @@ func (s *Server) handleMessage(peer Peer, msg *Message) error {
 	if peer.Handshaked() {
 		if inv, ok := msg.Payload.(*payload.Inventory); ok {
 			if !inv.Type.Valid(s.chain.P2PSigExtensionsEnabled()) || len(inv.Hashes) == 0 {
-				return fmt.Errorf("%w: %s", errInvalidInvType, inv.Type.String())
+				return newMisbehavior(invalidInventory, fmt.Errorf("%w: %s", errInvalidInvType, inv.Type.String()))
 			}
 		}
 		switch msg.Command {
@@ -1566,7 +1593,7 @@ func (s *Server) handleMessage(peer Peer, msg *Message) error {
 			pong := msg.Payload.(*payload.Ping)
 			return s.handlePong(peer, pong)
 		case CMDVersion, CMDVerack:
-			return fmt.Errorf("received '%s' after the handshake", msg.Command.String())
+			return newMisbehavior(protocolViolation, fmt.Errorf("received '%s' after the handshake", msg.Command.String()))
 		default:
 		}
 	} else {
@@ -1581,7 +1608,7 @@ func (s *Server) handleMessage(peer Peer, msg *Message) error {
 			}
 			go peer.StartProtocol()
 		default:
-			return fmt.Errorf("received '%s' during handshake", msg.Command.String())
+			return newMisbehavior(protocolViolation, fmt.Errorf("received '%s' during handshake", msg.Command.String()))
 		}
 	}
 	return nil
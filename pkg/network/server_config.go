@@ -85,6 +85,15 @@ type (
 		// broadcasting them.
 		BroadcastTxsBatchDelay time.Duration
 
+		// BanScore is the misbehavior score that makes the server ban a
+		// peer.
+		BanScore int
+		// BanDuration is the time a misbehaving peer is banned for.
+		BanDuration time.Duration
+		// BanListPath is the file bans are persisted to, it's not used
+		// if empty.
+		BanListPath string
+
 		NeoFSBlockFetcherCfg config.NeoFSBlockFetcher
 		// NeoFSStateFetcherCfg is the statefetcher module configuration.
 		NeoFSStateFetcherCfg config.NeoFSStateFetcher
@@ -121,6 +130,9 @@ func NewServerConfig(cfg config.Config) (ServerConfig, error) {
 		ExtensiblePoolSize:     appConfig.P2P.ExtensiblePoolSize,
 		BroadcastFactor:        appConfig.P2P.BroadcastFactor,
 		BroadcastTxsBatchDelay: appConfig.P2P.BroadcastTxsBatchDelay,
+		BanScore:               appConfig.P2P.BanScore,
+		BanDuration:            appConfig.P2P.BanDuration,
+		BanListPath:            appConfig.P2P.BanListPath,
 		NeoFSBlockFetcherCfg:   appConfig.NeoFSBlockFetcher,
 		NeoFSStateFetcherCfg:   appConfig.NeoFSStateFetcher,
 	}
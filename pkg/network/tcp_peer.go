@@ -175,6 +175,14 @@ func (p *TCPPeer) handleConn() {
 				p.server.log.Warn("not all headers were processed")
 				r.Err = nil
 			} else if err != nil {
+				// Connection errors are stored in the reader, anything
+				// else is a broken message.
+				if r.Err == nil {
+					err = newMisbehavior(malformedMessage, err)
+					if p.server.penalize(p, err) {
+						err = fmt.Errorf("%w: %w", errBanned, err)
+					}
+				}
 				break
 			}
 			select {
@@ -196,6 +204,9 @@ func (p *TCPPeer) handleIncoming() {
 			if p.Handshaked() {
 				err = fmt.Errorf("handling %s message: %w", msg.Command.String(), err)
 			}
+			if p.server.penalize(p, err) {
+				err = fmt.Errorf("%w: %w", errBanned, err)
+			}
 			break
 		}
 	}
@@ -50,6 +50,10 @@ func (t *TCPTransport) Dial(addr string, timeout time.Duration) (AddressablePeer
 	if err != nil {
 		return nil, err
 	}
+	if t.server.isBanned(conn.RemoteAddr()) {
+		conn.Close()
+		return nil, errBanned
+	}
 	p := NewTCPPeer(conn, addr, t.server)
 	go p.handleConn()
 	return p, nil
@@ -86,6 +90,11 @@ func (t *TCPTransport) Accept() {
 			t.log.Warn("TCP accept error", zap.Stringer("address", l.Addr()), zap.Error(err))
 			continue
 		}
+		if t.server.isBanned(conn.RemoteAddr()) {
+			t.log.Debug("rejecting connection from banned peer", zap.Stringer("address", conn.RemoteAddr()))
+			conn.Close()
+			continue
+		}
 		p := NewTCPPeer(conn, "", t.server)
 		go p.handleConn()
 	}
@@ -12,5 +12,9 @@ func TestRPCInvokerRPCClientCompat(t *testing.T) {
 	_ = invoker.RPCInvoke(&rpcclient.WSClient{})
 	_ = invoker.RPCInvokeHistoric(&rpcclient.Client{})
 	_ = invoker.RPCInvokeHistoric(&rpcclient.WSClient{})
+	_ = invoker.RPCInvokeWithOverrides(&rpcclient.Client{})
+	_ = invoker.RPCInvokeWithOverrides(&rpcclient.WSClient{})
+	_ = invoker.RPCInvokeHistoricWithOverrides(&rpcclient.Client{})
+	_ = invoker.RPCInvokeHistoricWithOverrides(&rpcclient.WSClient{})
 	_ = invoker.RPCSessions(&rpcclient.WSClient{})
 }
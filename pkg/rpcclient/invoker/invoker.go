@@ -15,6 +15,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc"
 	"github.com/nspcc-dev/neo-go/pkg/neorpc/result"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
 	"github.com/nspcc-dev/neo-go/pkg/util"
@@ -57,6 +58,31 @@ type RPCInvokeHistoric interface {
 	InvokeScriptWithState(stateroot util.Uint256, script []byte, signers []transaction.Signer) (*result.Invoke, error)
 }
 
+// RPCInvokeWithOverrides is a set of RPC methods needed to execute things at
+// the current blockchain height with overridden chain state (NeoGo extension).
+type RPCInvokeWithOverrides interface {
+	RPCSessions
+
+	InvokeFunctionWithOverrides(contract util.Uint160, operation string, params []smartcontract.Parameter, signers []transaction.Signer, overrides *neorpc.StateOverrides) (*result.Invoke, error)
+	InvokeScriptWithOverrides(script []byte, signers []transaction.Signer, overrides *neorpc.StateOverrides) (*result.Invoke, error)
+}
+
+// RPCInvokeHistoricWithOverrides is a set of RPC methods needed to execute
+// things at some fixed point in blockchain's life with overridden chain state
+// (NeoGo extension).
+type RPCInvokeHistoricWithOverrides interface {
+	RPCSessions
+
+	InvokeFunctionAtHeightWithOverrides(height uint32, contract util.Uint160, operation string, params []smartcontract.Parameter, signers []transaction.Signer, overrides *neorpc.StateOverrides) (*result.Invoke, error)
+	InvokeFunctionWithStateAndOverrides(stateroot util.Uint256, contract util.Uint160, operation string, params []smartcontract.Parameter, signers []transaction.Signer, overrides *neorpc.StateOverrides) (*result.Invoke, error)
+	InvokeScriptAtHeightWithOverrides(height uint32, script []byte, signers []transaction.Signer, overrides *neorpc.StateOverrides) (*result.Invoke, error)
+	InvokeScriptWithStateAndOverrides(stateroot util.Uint256, script []byte, signers []transaction.Signer, overrides *neorpc.StateOverrides) (*result.Invoke, error)
+}
+
+// ErrOverridesVerify is returned from Verify for invokers with state overrides,
+// they're not supported for verification.
+var ErrOverridesVerify = errors.New("state overrides are not supported for verification")
+
 // Invoker allows to test-execute things using RPC client. Its API simplifies
 // reusing the same signers list for a series of invocations and at the
 // same time uses regular Go types for call parameters. It doesn't do anything with
@@ -82,6 +108,39 @@ func New(client RPCInvoke, signers []transaction.Signer) *Invoker {
 	return &Invoker{client, signers}
 }
 
+// NewWithOverrides creates an Invoker to test-execute things at the current
+// blockchain height with the chain state changed by the given overrides (which
+// are only visible to these invocations). Verify is not supported by such
+// Invoker.
+func NewWithOverrides(client RPCInvokeWithOverrides, signers []transaction.Signer, overrides *neorpc.StateOverrides) *Invoker {
+	return New(&overridesConverter{
+		client:    client,
+		overrides: overrides,
+	}, signers)
+}
+
+// NewHistoricAtHeightWithOverrides creates an Invoker to test-execute things at
+// some given height with the chain state changed by the given overrides.
+// Verify is not supported by such Invoker.
+func NewHistoricAtHeightWithOverrides(height uint32, client RPCInvokeHistoricWithOverrides, signers []transaction.Signer, overrides *neorpc.StateOverrides) *Invoker {
+	return New(&historicOverridesConverter{
+		client:    client,
+		height:    &height,
+		overrides: overrides,
+	}, signers)
+}
+
+// NewHistoricWithStateAndOverrides creates an Invoker to test-execute things
+// with some given state or block and the chain state changed by the given
+// overrides. Verify is not supported by such Invoker.
+func NewHistoricWithStateAndOverrides(rootOrBlock util.Uint256, client RPCInvokeHistoricWithOverrides, signers []transaction.Signer, overrides *neorpc.StateOverrides) *Invoker {
+	return New(&historicOverridesConverter{
+		client:    client,
+		root:      &rootOrBlock,
+		overrides: overrides,
+	}, signers)
+}
+
 // NewHistoricAtHeight creates an Invoker to test-execute things at some given height.
 func NewHistoricAtHeight(height uint32, client RPCInvokeHistoric, signers []transaction.Signer) *Invoker {
 	return New(&historicConverter{
@@ -99,6 +158,18 @@ func NewHistoricWithState(rootOrBlock util.Uint256, client RPCInvokeHistoric, si
 	}, signers)
 }
 
+type overridesConverter struct {
+	client    RPCInvokeWithOverrides
+	overrides *neorpc.StateOverrides
+}
+
+type historicOverridesConverter struct {
+	client    RPCInvokeHistoricWithOverrides
+	height    *uint32
+	root      *util.Uint256
+	overrides *neorpc.StateOverrides
+}
+
 func (h *historicConverter) InvokeScript(script []byte, signers []transaction.Signer) (*result.Invoke, error) {
 	if h.height != nil {
 		return h.client.InvokeScriptAtHeight(*h.height, script, signers)
@@ -137,6 +208,58 @@ func (h *historicConverter) TraverseIterator(sessionID, iteratorID uuid.UUID, ma
 	return h.client.TraverseIterator(sessionID, iteratorID, maxItemsCount)
 }
 
+func (o *overridesConverter) InvokeScript(script []byte, signers []transaction.Signer) (*result.Invoke, error) {
+	return o.client.InvokeScriptWithOverrides(script, signers, o.overrides)
+}
+
+func (o *overridesConverter) InvokeFunction(contract util.Uint160, operation string, params []smartcontract.Parameter, signers []transaction.Signer) (*result.Invoke, error) {
+	return o.client.InvokeFunctionWithOverrides(contract, operation, params, signers, o.overrides)
+}
+
+func (o *overridesConverter) InvokeContractVerify(contract util.Uint160, params []smartcontract.Parameter, signers []transaction.Signer, witnesses ...transaction.Witness) (*result.Invoke, error) {
+	return nil, ErrOverridesVerify
+}
+
+func (o *overridesConverter) TerminateSession(sessionID uuid.UUID) (bool, error) {
+	return o.client.TerminateSession(sessionID)
+}
+
+func (o *overridesConverter) TraverseIterator(sessionID, iteratorID uuid.UUID, maxItemsCount int) ([]stackitem.Item, error) {
+	return o.client.TraverseIterator(sessionID, iteratorID, maxItemsCount)
+}
+
+func (h *historicOverridesConverter) InvokeScript(script []byte, signers []transaction.Signer) (*result.Invoke, error) {
+	if h.height != nil {
+		return h.client.InvokeScriptAtHeightWithOverrides(*h.height, script, signers, h.overrides)
+	}
+	if h.root != nil {
+		return h.client.InvokeScriptWithStateAndOverrides(*h.root, script, signers, h.overrides)
+	}
+	panic("uninitialized historicOverridesConverter")
+}
+
+func (h *historicOverridesConverter) InvokeFunction(contract util.Uint160, operation string, params []smartcontract.Parameter, signers []transaction.Signer) (*result.Invoke, error) {
+	if h.height != nil {
+		return h.client.InvokeFunctionAtHeightWithOverrides(*h.height, contract, operation, params, signers, h.overrides)
+	}
+	if h.root != nil {
+		return h.client.InvokeFunctionWithStateAndOverrides(*h.root, contract, operation, params, signers, h.overrides)
+	}
+	panic("uninitialized historicOverridesConverter")
+}
+
+func (h *historicOverridesConverter) InvokeContractVerify(contract util.Uint160, params []smartcontract.Parameter, signers []transaction.Signer, witnesses ...transaction.Witness) (*result.Invoke, error) {
+	return nil, ErrOverridesVerify
+}
+
+func (h *historicOverridesConverter) TerminateSession(sessionID uuid.UUID) (bool, error) {
+	return h.client.TerminateSession(sessionID)
+}
+
+func (h *historicOverridesConverter) TraverseIterator(sessionID, iteratorID uuid.UUID, maxItemsCount int) ([]stackitem.Item, error) {
+	return h.client.TraverseIterator(sessionID, iteratorID, maxItemsCount)
+}
+
 // Signers returns the set of current invoker signers which is mostly useful
 // when working with upper-layer actors. Returned slice is a newly allocated
 // one (if this invoker has them), so it's safe to modify.
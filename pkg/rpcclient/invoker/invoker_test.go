@@ -6,6 +6,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc"
 	"github.com/nspcc-dev/neo-go/pkg/neorpc/result"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
 	"github.com/nspcc-dev/neo-go/pkg/util"
@@ -47,6 +48,24 @@ func (r *rpcInv) InvokeScriptAtHeight(height uint32, script []byte, signers []tr
 func (r *rpcInv) InvokeScriptWithState(stateroot util.Uint256, script []byte, signers []transaction.Signer) (*result.Invoke, error) {
 	return r.resInv, r.err
 }
+func (r *rpcInv) InvokeFunctionWithOverrides(contract util.Uint160, operation string, params []smartcontract.Parameter, signers []transaction.Signer, overrides *neorpc.StateOverrides) (*result.Invoke, error) {
+	return r.resInv, r.err
+}
+func (r *rpcInv) InvokeScriptWithOverrides(script []byte, signers []transaction.Signer, overrides *neorpc.StateOverrides) (*result.Invoke, error) {
+	return r.resInv, r.err
+}
+func (r *rpcInv) InvokeFunctionAtHeightWithOverrides(height uint32, contract util.Uint160, operation string, params []smartcontract.Parameter, signers []transaction.Signer, overrides *neorpc.StateOverrides) (*result.Invoke, error) {
+	return r.resInv, r.err
+}
+func (r *rpcInv) InvokeFunctionWithStateAndOverrides(stateroot util.Uint256, contract util.Uint160, operation string, params []smartcontract.Parameter, signers []transaction.Signer, overrides *neorpc.StateOverrides) (*result.Invoke, error) {
+	return r.resInv, r.err
+}
+func (r *rpcInv) InvokeScriptAtHeightWithOverrides(height uint32, script []byte, signers []transaction.Signer, overrides *neorpc.StateOverrides) (*result.Invoke, error) {
+	return r.resInv, r.err
+}
+func (r *rpcInv) InvokeScriptWithStateAndOverrides(stateroot util.Uint256, script []byte, signers []transaction.Signer, overrides *neorpc.StateOverrides) (*result.Invoke, error) {
+	return r.resInv, r.err
+}
 func (r *rpcInv) TerminateSession(sessionID uuid.UUID) (bool, error) {
 	return r.resTrm, r.err
 }
@@ -111,6 +130,33 @@ func TestInvoker(t *testing.T) {
 	t.Run("historic, state", func(t *testing.T) {
 		testInv(t, NewHistoricWithState(util.Uint256{}, ri, nil))
 	})
+	testOverrides := func(t *testing.T, inv *Invoker) {
+		res, err := inv.Call(util.Uint160{}, "method", 42)
+		require.NoError(t, err)
+		require.Equal(t, resExp, res)
+
+		res, err = inv.Run([]byte{1})
+		require.NoError(t, err)
+		require.Equal(t, resExp, res)
+
+		_, err = inv.Verify(util.Uint160{}, nil)
+		require.ErrorIs(t, err, ErrOverridesVerify)
+	}
+	overrides := &neorpc.StateOverrides{}
+	t.Run("overrides", func(t *testing.T) {
+		testOverrides(t, NewWithOverrides(ri, nil, overrides))
+	})
+	t.Run("historic, height, overrides", func(t *testing.T) {
+		testOverrides(t, NewHistoricAtHeightWithOverrides(100500, ri, nil, overrides))
+	})
+	t.Run("historic, state, overrides", func(t *testing.T) {
+		testOverrides(t, NewHistoricWithStateAndOverrides(util.Uint256{}, ri, nil, overrides))
+	})
+	t.Run("broken historic overrides", func(t *testing.T) {
+		inv := New(&historicOverridesConverter{client: ri}, nil) // It's not possible to do this from outside.
+		require.Panics(t, func() { _, _ = inv.Call(util.Uint160{}, "method") })
+		require.Panics(t, func() { _, _ = inv.Run([]byte{1}) })
+	})
 	t.Run("broken historic", func(t *testing.T) {
 		inv := New(&historicConverter{client: ri}, nil) // It's not possible to do this from outside.
 		require.Panics(t, func() { _, _ = inv.Call(util.Uint160{}, "method") })
@@ -118,7 +164,8 @@ func TestInvoker(t *testing.T) {
 		require.Panics(t, func() { _, _ = inv.Run([]byte{1}) })
 	})
 	t.Run("terminate session", func(t *testing.T) {
-		for _, inv := range []*Invoker{New(ri, nil), NewHistoricWithState(util.Uint256{}, ri, nil)} {
+		for _, inv := range []*Invoker{New(ri, nil), NewHistoricWithState(util.Uint256{}, ri, nil),
+			NewWithOverrides(ri, nil, nil), NewHistoricWithStateAndOverrides(util.Uint256{}, ri, nil, nil)} {
 			ri.err = errors.New("")
 			require.Error(t, inv.TerminateSession(uuid.UUID{}))
 			ri.err = nil
@@ -129,7 +176,8 @@ func TestInvoker(t *testing.T) {
 		}
 	})
 	t.Run("traverse iterator", func(t *testing.T) {
-		for _, inv := range []*Invoker{New(ri, nil), NewHistoricWithState(util.Uint256{}, ri, nil)} {
+		for _, inv := range []*Invoker{New(ri, nil), NewHistoricWithState(util.Uint256{}, ri, nil),
+			NewWithOverrides(ri, nil, nil), NewHistoricWithStateAndOverrides(util.Uint256{}, ri, nil, nil)} {
 			res, err := inv.TraverseIterator(uuid.UUID{}, &result.Iterator{
 				Values: []stackitem.Item{stackitem.Make(42)},
 			}, 0)
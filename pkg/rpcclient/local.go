@@ -30,7 +30,6 @@ func NewInternal(ctx context.Context, register InternalHook) (*Internal, error)
 
 			shutdown:      make(chan struct{}),
 			readerDone:    make(chan struct{}),
-			writerDone:    make(chan struct{}),
 			subscriptions: make(map[string]notificationReceiver),
 			receivers:     make(map[any][]string),
 		},
@@ -324,6 +324,48 @@ func (c *Client) GetNEP17Balances(address util.Uint160) (*result.NEP17Balances,
 	return resp, nil
 }
 
+// GetNEP11BalancesAtHeight returns NEP-11 balances of the given address at
+// the given blockchain height (NeoGo extension).
+func (c *Client) GetNEP11BalancesAtHeight(height uint32, address util.Uint160) (*result.NEP11Balances, error) {
+	resp := new(result.NEP11Balances)
+	if err := c.performRequest("getnep11balanceshistoric", []any{height, address.StringLE()}, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetNEP11BalancesWithState returns NEP-11 balances of the given address at
+// the given blockchain state defined by the specified state root or block
+// hash (NeoGo extension).
+func (c *Client) GetNEP11BalancesWithState(stateOrBlock util.Uint256, address util.Uint160) (*result.NEP11Balances, error) {
+	resp := new(result.NEP11Balances)
+	if err := c.performRequest("getnep11balanceshistoric", []any{stateOrBlock.StringLE(), address.StringLE()}, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetNEP17BalancesAtHeight returns NEP-17 balances of the given address at
+// the given blockchain height (NeoGo extension).
+func (c *Client) GetNEP17BalancesAtHeight(height uint32, address util.Uint160) (*result.NEP17Balances, error) {
+	resp := new(result.NEP17Balances)
+	if err := c.performRequest("getnep17balanceshistoric", []any{height, address.StringLE()}, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetNEP17BalancesWithState returns NEP-17 balances of the given address at
+// the given blockchain state defined by the specified state root or block
+// hash (NeoGo extension).
+func (c *Client) GetNEP17BalancesWithState(stateOrBlock util.Uint256, address util.Uint160) (*result.NEP17Balances, error) {
+	resp := new(result.NEP17Balances)
+	if err := c.performRequest("getnep17balanceshistoric", []any{stateOrBlock.StringLE(), address.StringLE()}, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
 // GetNEP11Properties is a wrapper for getnep11properties RPC. We recommend using
 // nep11 package and Properties method there to receive proper VM types and work with them.
 // This method is provided mostly for the sake of completeness. For well-known
@@ -355,6 +397,35 @@ func (c *Client) GetNEP11Properties(asset util.Uint160, token []byte) (map[strin
 	return resp, nil
 }
 
+// GetAddressTransactions returns hashes of transactions touching the given
+// address (signed by it or mentioning it in notifications) from the newest to
+// the oldest one. Address parameter is mandatory while all the others are
+// optional and work the same way as for [Client.GetNEP17Transfers]. This
+// method is only supported by NeoGo servers with SaveAddressIndex setting
+// enabled.
+func (c *Client) GetAddressTransactions(address util.Uint160, start, stop *uint64, limit, page *int) (*result.AddressTransactions, error) {
+	params, err := packTransfersParams(address, start, stop, limit, page)
+	if err != nil {
+		return nil, err
+	}
+	return c.getAddressTransactions(params)
+}
+
+// GetAddressTransactionsByHeight is the same as [Client.GetAddressTransactions],
+// but it uses a range of block heights (inclusive) instead of timestamps. All
+// parameters are mandatory here.
+func (c *Client) GetAddressTransactionsByHeight(address util.Uint160, start, stop uint32, limit, page int) (*result.AddressTransactions, error) {
+	return c.getAddressTransactions([]any{address.StringLE(), start, stop, limit, page, true})
+}
+
+func (c *Client) getAddressTransactions(params []any) (*result.AddressTransactions, error) {
+	resp := new(result.AddressTransactions)
+	if err := c.performRequest("getaddresstransactions", params, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
 // GetNEP11Transfers is a wrapper for getnep11transfers RPC. Address parameter
 // is mandatory, while all others are optional. start and stop parameters are
 // timestamps in milliseconds (just like block timestamps are). limit and page
@@ -558,6 +629,41 @@ func (c *Client) GetStateHeight() (*result.StateHeight, error) {
 	return resp, nil
 }
 
+// GetStorageDiff returns contract storage changes made by the block or
+// transaction with the given hash. This method is only supported by NeoGo
+// servers with SaveStorageDiffs setting enabled.
+func (c *Client) GetStorageDiff(hash util.Uint256) (*result.StorageDiff, error) {
+	var (
+		params = []any{hash.StringLE()}
+		resp   = new(result.StorageDiff)
+	)
+	if err := c.performRequest("getstoragediff", params, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// FindNotifications returns notifications matching the given filter that
+// were emitted in blocks from `from` to `to` (inclusive). Contract must be
+// specified in the filter. Notifications following the `start` cursor are
+// returned (from the beginning if it's nil), use
+// [result.FindNotifications.Next] as a cursor to get the next page if the
+// result is truncated. This method is only supported by NeoGo servers with
+// SaveNotificationIndex setting enabled.
+func (c *Client) FindNotifications(filter neorpc.NotificationFilter, from, to uint32, start []byte) (*result.FindNotifications, error) {
+	var (
+		params = []any{filter, from, to}
+		resp   = new(result.FindNotifications)
+	)
+	if start != nil {
+		params = append(params, start)
+	}
+	if err := c.performRequest("findnotifications", params, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
 // GetStorageByID returns the stored value according to the contract ID and the stored key.
 func (c *Client) GetStorageByID(id int32, key []byte) ([]byte, error) {
 	return c.getStorage([]any{id, key})
@@ -855,6 +961,116 @@ func (c *Client) InvokeContractVerifyWithState(stateOrBlock util.Uint256, contra
 	return c.invokeSomething("invokecontractverifyhistoric", p, signers, witnesses...)
 }
 
+// InvokeScriptWithOverrides returns the result of the given script after
+// running it true the VM with the chain state changed by the given overrides
+// (NeoGo extension).
+// NOTE: This is a test invoke and will not affect the blockchain.
+func (c *Client) InvokeScriptWithOverrides(script []byte, signers []transaction.Signer, overrides *neorpc.StateOverrides) (*result.Invoke, error) {
+	var p = []any{script}
+	return c.invokeWithOverrides("invokescript", p, signers, overrides)
+}
+
+// InvokeScriptAtHeightWithOverrides returns the result of the given script
+// after running it true the VM using the chain state retrieved from the
+// specified chain height and changed by the given overrides (NeoGo extension).
+// NOTE: This is a test invoke and will not affect the blockchain.
+func (c *Client) InvokeScriptAtHeightWithOverrides(height uint32, script []byte, signers []transaction.Signer, overrides *neorpc.StateOverrides) (*result.Invoke, error) {
+	var p = []any{height, script}
+	return c.invokeWithOverrides("invokescripthistoric", p, signers, overrides)
+}
+
+// InvokeScriptWithStateAndOverrides returns the result of the given script
+// after running it true the VM using the chain state retrieved from the
+// specified state root or block hash and changed by the given overrides
+// (NeoGo extension).
+// NOTE: This is a test invoke and will not affect the blockchain.
+func (c *Client) InvokeScriptWithStateAndOverrides(stateOrBlock util.Uint256, script []byte, signers []transaction.Signer, overrides *neorpc.StateOverrides) (*result.Invoke, error) {
+	var p = []any{stateOrBlock.StringLE(), script}
+	return c.invokeWithOverrides("invokescripthistoric", p, signers, overrides)
+}
+
+// InvokeFunctionWithOverrides returns the results after calling the smart
+// contract with the given operation and parameters with the chain state changed
+// by the given overrides (NeoGo extension).
+// NOTE: this is test invoke and will not affect the blockchain.
+func (c *Client) InvokeFunctionWithOverrides(contract util.Uint160, operation string, params []smartcontract.Parameter, signers []transaction.Signer, overrides *neorpc.StateOverrides) (*result.Invoke, error) {
+	var p = []any{contract.StringLE(), operation, params}
+	return c.invokeWithOverrides("invokefunction", p, signers, overrides)
+}
+
+// InvokeFunctionAtHeightWithOverrides returns the results after calling the
+// smart contract with the given operation and parameters at the given
+// blockchain height with the chain state changed by the given overrides (NeoGo
+// extension).
+// NOTE: this is test invoke and will not affect the blockchain.
+func (c *Client) InvokeFunctionAtHeightWithOverrides(height uint32, contract util.Uint160, operation string, params []smartcontract.Parameter, signers []transaction.Signer, overrides *neorpc.StateOverrides) (*result.Invoke, error) {
+	var p = []any{height, contract.StringLE(), operation, params}
+	return c.invokeWithOverrides("invokefunctionhistoric", p, signers, overrides)
+}
+
+// InvokeFunctionWithStateAndOverrides returns the results after calling the
+// smart contract with the given operation and parameters at the given
+// blockchain state defined by the specified state root or block hash with the
+// chain state changed by the given overrides (NeoGo extension).
+// NOTE: this is test invoke and will not affect the blockchain.
+func (c *Client) InvokeFunctionWithStateAndOverrides(stateOrBlock util.Uint256, contract util.Uint160, operation string, params []smartcontract.Parameter, signers []transaction.Signer, overrides *neorpc.StateOverrides) (*result.Invoke, error) {
+	var p = []any{stateOrBlock.StringLE(), contract.StringLE(), operation, params}
+	return c.invokeWithOverrides("invokefunctionhistoric", p, signers, overrides)
+}
+
+// SimulateTransactions executes the given transactions and/or scripts one by
+// one using the current chain state changed by the given overrides (nil means
+// no overrides), so that every step sees the changes made by the preceding
+// ones (NeoGo extension). Changes made by FAULTed steps are discarded.
+// NOTE: This is a test invoke and will not affect the blockchain.
+func (c *Client) SimulateTransactions(steps []neorpc.SimulationStep, overrides *neorpc.StateOverrides) (*result.Simulation, error) {
+	return c.simulateTransactions("simulatetransactions", nil, steps, overrides)
+}
+
+// SimulateTransactionsAtHeight is similar to SimulateTransactions, but uses
+// the chain state retrieved from the specified chain height (NeoGo extension).
+// NOTE: This is a test invoke and will not affect the blockchain.
+func (c *Client) SimulateTransactionsAtHeight(height uint32, steps []neorpc.SimulationStep, overrides *neorpc.StateOverrides) (*result.Simulation, error) {
+	var p = []any{height}
+	return c.simulateTransactions("simulatetransactionshistoric", p, steps, overrides)
+}
+
+// SimulateTransactionsWithState is similar to SimulateTransactions, but uses
+// the chain state retrieved from the specified state root or block hash (NeoGo
+// extension).
+// NOTE: This is a test invoke and will not affect the blockchain.
+func (c *Client) SimulateTransactionsWithState(stateOrBlock util.Uint256, steps []neorpc.SimulationStep, overrides *neorpc.StateOverrides) (*result.Simulation, error) {
+	var p = []any{stateOrBlock.StringLE()}
+	return c.simulateTransactions("simulatetransactionshistoric", p, steps, overrides)
+}
+
+// simulateTransactions is an inner wrapper for SimulateTransactions* functions.
+func (c *Client) simulateTransactions(method string, p []any, steps []neorpc.SimulationStep, overrides *neorpc.StateOverrides) (*result.Simulation, error) {
+	var resp = new(result.Simulation)
+	p = append(p, steps)
+	if overrides != nil {
+		p = append(p, false, overrides)
+	}
+	if err := c.performRequest(method, p, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// invokeWithOverrides is an inner wrapper for Invoke*WithOverrides functions,
+// overrides follow signers and verbose flag in the parameters list.
+func (c *Client) invokeWithOverrides(method string, p []any, signers []transaction.Signer, overrides *neorpc.StateOverrides) (*result.Invoke, error) {
+	var resp = new(result.Invoke)
+	if signers == nil {
+		signers = []transaction.Signer{}
+	}
+	p = append(p, signers, false, overrides)
+	if err := c.performRequest(method, p, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
 // invokeSomething is an inner wrapper for Invoke* functions.
 func (c *Client) invokeSomething(method string, p []any, signers []transaction.Signer, witnesses ...transaction.Witness) (*result.Invoke, error) {
 	var resp = new(result.Invoke)
@@ -964,6 +1180,22 @@ func (c *Client) stateRootInHeader() (bool, error) {
 	return c.cache.stateRootInHeader, nil
 }
 
+// TraceTransaction re-executes the persisted transaction with the given hash
+// against the state it was originally executed in and returns per-instruction
+// execution trace. withStack and withStorage enable evaluation stack snapshots
+// and contract storage accesses for each instruction. This method is only
+// supported by NeoGo servers and requires historic state to be available.
+func (c *Client) TraceTransaction(hash util.Uint256, withStack bool, withStorage bool) (*result.ExecutionTrace, error) {
+	var (
+		params = []any{hash.StringLE(), withStack, withStorage}
+		resp   = new(result.ExecutionTrace)
+	)
+	if err := c.performRequest("tracetransaction", params, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
 // TraverseIterator returns a set of iterator values (maxItemsCount at max) for
 // the specified iterator and session. If result contains no elements, then either
 // Iterator has no elements or session was expired and terminated by the server.
@@ -26,6 +26,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/encoding/bigint"
 	"github.com/nspcc-dev/neo-go/pkg/encoding/fixedn"
 	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc"
 	"github.com/nspcc-dev/neo-go/pkg/neorpc/result"
 	"github.com/nspcc-dev/neo-go/pkg/services/rpcsrv/params"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
@@ -111,6 +112,36 @@ func getTxMoveNeo() *result.TransactionOutputRaw {
 // published in the official C# JSON-RPC API v2.10.3 reference
 // (see https://docs.neo.org/docs/en-us/reference/rpc/latest-version/api.html)
 var rpcClientTestCases = map[string][]rpcClientTestCase{
+	"getaddresstransactions": {
+		{
+			name: "positive",
+			invoke: func(c *Client) (any, error) {
+				return c.GetAddressTransactions(util.Uint160{}, nil, nil, nil, nil)
+			},
+			serverResponse: `{"jsonrpc":"2.0","id":1,"result":{"address":"NKuyBkoGdZZSLyPbJEetheRhMjeznFZszf","transactions":[{"txhash":"0x0000000000000000000000000000000000000000000000000000000000000001","blockindex":5,"timestamp":1000}]}}`,
+			result: func(c *Client) any {
+				return &result.AddressTransactions{
+					Address: "NKuyBkoGdZZSLyPbJEetheRhMjeznFZszf",
+					Transactions: []result.AddressTransaction{
+						{TxHash: util.Uint256{1}, Index: 5, Timestamp: 1000},
+					},
+				}
+			},
+		},
+		{
+			name: "by height, positive",
+			invoke: func(c *Client) (any, error) {
+				return c.GetAddressTransactionsByHeight(util.Uint160{}, 0, 10, 5, 0)
+			},
+			serverResponse: `{"jsonrpc":"2.0","id":1,"result":{"address":"NKuyBkoGdZZSLyPbJEetheRhMjeznFZszf","transactions":[]}}`,
+			result: func(c *Client) any {
+				return &result.AddressTransactions{
+					Address:      "NKuyBkoGdZZSLyPbJEetheRhMjeznFZszf",
+					Transactions: []result.AddressTransaction{},
+				}
+			},
+		},
+	},
 	"getapplicationlog": {
 		{
 			name: "positive",
@@ -494,6 +525,78 @@ var rpcClientTestCases = map[string][]rpcClientTestCase{
 			},
 		},
 	},
+	"getnep17balanceshistoric": {
+		{
+			name: "at height, positive",
+			invoke: func(c *Client) (any, error) {
+				return c.GetNEP17BalancesAtHeight(5, util.Uint160{1})
+			},
+			serverResponse: `{"jsonrpc":"2.0","id":1,"result":{"balance":[{"assethash":"0x0000000000000000000000000000000000000002","symbol":"N17","decimals":"8","name":"Token","amount":"100","lastupdatedblock":3}],"address":"NKuyBkoGdZZSLyPbJEetheRhMjeznFZszf"}}`,
+			result: func(c *Client) any {
+				return &result.NEP17Balances{
+					Balances: []result.NEP17Balance{{
+						Asset:       util.Uint160{2},
+						Decimals:    8,
+						Name:        "Token",
+						Symbol:      "N17",
+						Amount:      "100",
+						LastUpdated: 3,
+					}},
+					Address: "NKuyBkoGdZZSLyPbJEetheRhMjeznFZszf",
+				}
+			},
+		},
+		{
+			name: "with state, positive",
+			invoke: func(c *Client) (any, error) {
+				return c.GetNEP17BalancesWithState(util.Uint256{1}, util.Uint160{1})
+			},
+			serverResponse: `{"jsonrpc":"2.0","id":1,"result":{"balance":[],"address":"NKuyBkoGdZZSLyPbJEetheRhMjeznFZszf"}}`,
+			result: func(c *Client) any {
+				return &result.NEP17Balances{
+					Balances: []result.NEP17Balance{},
+					Address:  "NKuyBkoGdZZSLyPbJEetheRhMjeznFZszf",
+				}
+			},
+		},
+	},
+	"getnep11balanceshistoric": {
+		{
+			name: "at height, positive",
+			invoke: func(c *Client) (any, error) {
+				return c.GetNEP11BalancesAtHeight(5, util.Uint160{1})
+			},
+			serverResponse: `{"jsonrpc":"2.0","id":1,"result":{"balance":[{"assethash":"0x0000000000000000000000000000000000000002","symbol":"N11","decimals":"0","name":"NFT","tokens":[{"tokenid":"0102","amount":"1","lastupdatedblock":3}]}],"address":"NKuyBkoGdZZSLyPbJEetheRhMjeznFZszf"}}`,
+			result: func(c *Client) any {
+				return &result.NEP11Balances{
+					Balances: []result.NEP11AssetBalance{{
+						Asset:  util.Uint160{2},
+						Name:   "NFT",
+						Symbol: "N11",
+						Tokens: []result.NEP11TokenBalance{{
+							ID:          "0102",
+							Amount:      "1",
+							LastUpdated: 3,
+						}},
+					}},
+					Address: "NKuyBkoGdZZSLyPbJEetheRhMjeznFZszf",
+				}
+			},
+		},
+		{
+			name: "with state, positive",
+			invoke: func(c *Client) (any, error) {
+				return c.GetNEP11BalancesWithState(util.Uint256{1}, util.Uint160{1})
+			},
+			serverResponse: `{"jsonrpc":"2.0","id":1,"result":{"balance":[],"address":"NKuyBkoGdZZSLyPbJEetheRhMjeznFZszf"}}`,
+			result: func(c *Client) any {
+				return &result.NEP11Balances{
+					Balances: []result.NEP11AssetBalance{},
+					Address:  "NKuyBkoGdZZSLyPbJEetheRhMjeznFZszf",
+				}
+			},
+		},
+	},
 	"getnep11properties": {
 		{
 			name: "positive",
@@ -940,6 +1043,54 @@ var rpcClientTestCases = map[string][]rpcClientTestCase{
 			},
 		},
 	},
+	"getstoragediff": {
+		{
+			name: "positive",
+			invoke: func(c *Client) (any, error) {
+				return c.GetStorageDiff(util.Uint256{})
+			},
+			serverResponse: `{"jsonrpc":"2.0","id":1,"result":{"hash":"0x0000000000000000000000000000000000000000000000000000000000000000","added":[{"id":1,"contract":"0x0000000000000000000000000000000000000001","key":"AQ==","oldvalue":null,"value":"Ag=="}],"changed":[{"id":-5,"contract":"0x0000000000000000000000000000000000000002","key":"Aw==","oldvalue":"BA==","value":"BQ=="}],"deleted":[{"id":2,"key":"Bg==","oldvalue":"Bw==","value":null}]}}`,
+			result: func(c *Client) any {
+				return &result.StorageDiff{
+					Added: []result.StorageChange{
+						{ID: 1, Contract: &util.Uint160{1}, Key: []byte{1}, Value: []byte{2}},
+					},
+					Changed: []result.StorageChange{
+						{ID: -5, Contract: &util.Uint160{2}, Key: []byte{3}, OldValue: []byte{4}, Value: []byte{5}},
+					},
+					Deleted: []result.StorageChange{
+						{ID: 2, Key: []byte{6}, OldValue: []byte{7}},
+					},
+				}
+			},
+		},
+	},
+	"findnotifications": {
+		{
+			name: "positive",
+			invoke: func(c *Client) (any, error) {
+				return c.FindNotifications(neorpc.NotificationFilter{Contract: &util.Uint160{1}}, 0, 10, []byte{1})
+			},
+			serverResponse: `{"jsonrpc":"2.0","id":1,"result":{"results":[{"blockindex":5,"container":"0x0000000000000000000000000000000000000000000000000000000000000002","contract":"0x0000000000000000000000000000000000000001","eventname":"Transfer","state":{"type":"Array","value":[{"type":"Integer","value":"1"}]}}],"next":"AQI=","truncated":true}}`,
+			result: func(c *Client) any {
+				return &result.FindNotifications{
+					Results: []result.IndexedNotification{{
+						BlockIndex: 5,
+						ContainedNotificationEvent: state.ContainedNotificationEvent{
+							Container: util.Uint256{2},
+							NotificationEvent: state.NotificationEvent{
+								ScriptHash: util.Uint160{1},
+								Name:       "Transfer",
+								Item:       stackitem.NewArray([]stackitem.Item{stackitem.Make(1)}),
+							},
+						},
+					}},
+					Next:      []byte{1, 2},
+					Truncated: true,
+				}
+			},
+		},
+	},
 	"getstoragehistoric": {
 		{
 			name: "by hash, positive",
@@ -1379,6 +1530,37 @@ var rpcClientTestCases = map[string][]rpcClientTestCase{
 			},
 		},
 	},
+	"tracetransaction": {
+		{
+			name: "positive",
+			invoke: func(c *Client) (any, error) {
+				return c.TraceTransaction(util.Uint256{}, true, true)
+			},
+			serverResponse: `{"jsonrpc":"2.0","id":1,"result":{"txid":"0x17145a039fca704fcdbeb46e6b210af98a1a9e5b9768e46ffc38f71c79ac2521","vmstate":"HALT","gasconsumed":"1050","steps":[{"contract":"0x0000000000000000000000000000000000000001","ip":0,"opcode":"PUSH1","gasconsumed":"30"},{"contract":"0x0000000000000000000000000000000000000001","ip":1,"opcode":"SYSCALL","gasconsumed":"1020","stack":[{"type":"Integer","value":"1"}],"storage":[{"op":"get","id":1,"key":"AQ==","value":"Ag=="}]}]}}`,
+			result: func(c *Client) any {
+				txHash, err := util.Uint256DecodeStringLE("17145a039fca704fcdbeb46e6b210af98a1a9e5b9768e46ffc38f71c79ac2521")
+				if err != nil {
+					panic(err)
+				}
+				return &result.ExecutionTrace{
+					TxHash:      txHash,
+					State:       "HALT",
+					GasConsumed: 1050,
+					Steps: []result.TraceStep{
+						{Contract: util.Uint160{1}, IP: 0, Opcode: opcode.PUSH1, GasConsumed: 30},
+						{
+							Contract:    util.Uint160{1},
+							IP:          1,
+							Opcode:      opcode.SYSCALL,
+							GasConsumed: 1020,
+							Stack:       []stackitem.Item{stackitem.NewBigInteger(big.NewInt(1))},
+							Storage:     []result.StorageAccess{{Operation: "get", ID: 1, Key: []byte{1}, Value: []byte{2}}},
+						},
+					},
+				}
+			},
+		},
+	},
 	"validateaddress": {
 		{
 			name: "positive",
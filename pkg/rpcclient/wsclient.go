@@ -57,14 +57,20 @@ import (
 // will also be closed on disconnection from server or on situation when it's
 // impossible to send a subsequent notification to the subscriber's channel and
 // CloseNotificationChannelIfFull option is on.
+//
+// If Reconnect option is set, then connection loss doesn't close subscriber
+// channels, instead WSClient tries to reestablish the connection and restore
+// all active subscriptions (optionally delivering events missed while being
+// offline), see ReconnectOptions documentation for details.
 type WSClient struct {
 	Client
 
-	ws          *websocket.Conn
+	connLock sync.RWMutex
+	// conn is the current websocket connection, it's replaced on every
+	// successful reconnection.
+	conn        *wsConn
 	wsOpts      WSOptions
 	readerDone  chan struct{}
-	writerDone  chan struct{}
-	requests    chan *neorpc.Request
 	shutdown    chan struct{}
 	closeCalled atomic.Bool
 
@@ -81,6 +87,17 @@ type WSClient struct {
 	// subscriptionsOrderLock manages sequential order of "subscribe" and "unsubscribe" WS
 	// requests processing in order to avoid server-side subscription ID conflicts.
 	subscriptionsOrderLock sync.Mutex
+	// serverIDs is a mapping from subscription IDs returned to the user to
+	// the current server-side subscription IDs. It's only used in reconnect
+	// mode (server-side IDs change on every reconnection) and must be accessed
+	// with subscriptionsLock taken. Empty server ID means that the server
+	// has refused to restore the subscription.
+	serverIDs map[string]string
+	// lastSubID is the last client-side subscription ID issued in reconnect
+	// mode. It must be accessed with subscriptionsOrderLock taken.
+	lastSubID uint64
+
+	backfill backfillState
 
 	respLock     sync.RWMutex
 	respChannels map[uint64]chan *neorpc.Response
@@ -99,6 +116,22 @@ type WSOptions struct {
 	// thus it's still the caller's duty to call Unsubscribe() for this
 	// subscription.
 	CloseNotificationChannelIfFull bool
+	// Reconnect enables automatic reconnection to the server in case of
+	// connection loss. Nil value (default) disables it, making WSClient
+	// unusable after disconnection.
+	Reconnect *ReconnectOptions
+}
+
+// wsConn is a single websocket connection along with the state of its
+// reader and writer routines.
+type wsConn struct {
+	ws         *websocket.Conn
+	requests   chan *neorpc.Request
+	readerDone chan struct{}
+	writerDone chan struct{}
+
+	errLock sync.Mutex
+	err     error
 }
 
 // notificationReceiver is an interface aimed to provide WS subscriber functionality
@@ -506,35 +539,23 @@ var ErrConnClosedByUser = errors.New("closed by user")
 // You should call Init method to initialize the network magic the client is
 // operating on.
 func NewWS(ctx context.Context, endpoint string, opts WSOptions) (*WSClient, error) {
-	dialer := websocket.Dialer{HandshakeTimeout: opts.DialTimeout}
-	ws, resp, err := dialer.DialContext(ctx, endpoint, nil)
-	if resp != nil && resp.Body != nil { // Can be non-nil even with error returned.
-		defer resp.Body.Close() // Not exactly required by websocket, but let's do this for bodyclose checker.
-	}
+	ws, err := dialWS(ctx, endpoint, opts.DialTimeout)
 	if err != nil {
-		if resp != nil && resp.Body != nil {
-			var srvErr neorpc.HeaderAndError
-
-			dec := json.NewDecoder(resp.Body)
-			decErr := dec.Decode(&srvErr)
-			if decErr == nil && srvErr.Error != nil {
-				err = srvErr.Error
-			}
-		}
 		return nil, err
 	}
 	wsc := &WSClient{
 		Client: Client{},
 
-		ws:            ws,
 		wsOpts:        opts,
 		shutdown:      make(chan struct{}),
 		readerDone:    make(chan struct{}),
-		writerDone:    make(chan struct{}),
 		respChannels:  make(map[uint64]chan *neorpc.Response),
-		requests:      make(chan *neorpc.Request),
 		subscriptions: make(map[string]notificationReceiver),
 		receivers:     make(map[any][]string),
+		serverIDs:     make(map[string]string),
+	}
+	if opts.Reconnect != nil {
+		wsc.wsOpts.Reconnect = opts.Reconnect.withDefaults()
 	}
 
 	err = initClient(ctx, &wsc.Client, endpoint, opts.Options)
@@ -543,12 +564,80 @@ func NewWS(ctx context.Context, endpoint string, opts WSOptions) (*WSClient, err
 	}
 	wsc.Client.cli = nil
 
-	go wsc.wsReader()
-	go wsc.wsWriter()
+	conn := wsc.startConn(ws)
+	if wsc.wsOpts.Reconnect != nil {
+		go wsc.reconnectLoop(conn)
+	}
 	wsc.requestF = wsc.makeWsRequest
 	return wsc, nil
 }
 
+// dialWS establishes a new websocket connection to the given endpoint.
+func dialWS(ctx context.Context, endpoint string, timeout time.Duration) (*websocket.Conn, error) {
+	dialer := websocket.Dialer{HandshakeTimeout: timeout}
+	ws, resp, err := dialer.DialContext(ctx, endpoint, nil)
+	if resp != nil && resp.Body != nil { // Can be non-nil even with error returned.
+		defer resp.Body.Close() // Not exactly required by websocket, but let's do this for bodyclose checker.
+	}
+	if err != nil {
+		if resp != nil && resp.Body != nil {
+			var srvErr neorpc.HeaderAndError
+
+			dec := json.NewDecoder(resp.Body)
+			decErr := dec.Decode(&srvErr)
+			if decErr == nil && srvErr.Error != nil {
+				err = srvErr.Error
+			}
+		}
+		return nil, err
+	}
+	return ws, nil
+}
+
+// startConn makes the given websocket connection the current one and starts
+// its reader and writer routines.
+func (c *WSClient) startConn(ws *websocket.Conn) *wsConn {
+	conn := &wsConn{
+		ws:         ws,
+		requests:   make(chan *neorpc.Request),
+		readerDone: make(chan struct{}),
+		writerDone: make(chan struct{}),
+	}
+	c.connLock.Lock()
+	c.conn = conn
+	c.connLock.Unlock()
+
+	go c.wsReader(conn)
+	go c.wsWriter(conn)
+	return conn
+}
+
+// getConn returns the current websocket connection.
+func (c *WSClient) getConn() *wsConn {
+	c.connLock.RLock()
+	defer c.connLock.RUnlock()
+	return c.conn
+}
+
+// setErr is a thread-safe method setting connection closing reason in case
+// if it's not yet set.
+func (conn *wsConn) setErr(err error) {
+	conn.errLock.Lock()
+	defer conn.errLock.Unlock()
+
+	if conn.err == nil {
+		conn.err = err
+	}
+}
+
+// getErr returns connection closing reason.
+func (conn *wsConn) getErr() error {
+	conn.errLock.Lock()
+	defer conn.errLock.Unlock()
+
+	return conn.err
+}
+
 // Close closes connection to the remote side rendering this client instance
 // unusable.
 func (c *WSClient) Close() {
@@ -565,12 +654,12 @@ func (c *WSClient) Close() {
 	<-c.readerDone
 }
 
-func (c *WSClient) wsReader() {
-	c.ws.SetReadLimit(wsReadLimit)
-	c.ws.SetPongHandler(func(string) error {
-		err := c.ws.SetReadDeadline(time.Now().Add(wsPongLimit))
+func (c *WSClient) wsReader(conn *wsConn) {
+	conn.ws.SetReadLimit(wsReadLimit)
+	conn.ws.SetPongHandler(func(string) error {
+		err := conn.ws.SetReadDeadline(time.Now().Add(wsPongLimit))
 		if err != nil {
-			c.setCloseErr(fmt.Errorf("failed to set pong read deadline: %w", err))
+			conn.setErr(fmt.Errorf("failed to set pong read deadline: %w", err))
 		}
 		return err
 	})
@@ -578,12 +667,12 @@ func (c *WSClient) wsReader() {
 readloop:
 	for {
 		rr := new(requestResponse)
-		err := c.ws.SetReadDeadline(time.Now().Add(wsPongLimit))
+		err := conn.ws.SetReadDeadline(time.Now().Add(wsPongLimit))
 		if err != nil {
 			connCloseErr = fmt.Errorf("failed to set response read deadline: %w", err)
 			break readloop
 		}
-		err = c.ws.ReadJSON(rr)
+		err = conn.ws.ReadJSON(rr)
 		if err != nil {
 			// Timeout/connection loss/malformed response.
 			connCloseErr = fmt.Errorf("failed to read JSON response (timeout/connection loss/malformed response): %w", err)
@@ -644,7 +733,7 @@ readloop:
 					break readloop
 				}
 			}
-			c.notifySubscribers(ntf)
+			c.dispatch(ntf)
 		} else if rr.ID != nil && (rr.Error != nil || rr.Result != nil) {
 			id, err := strconv.ParseUint(string(rr.ID), 10, 64)
 			if err != nil {
@@ -657,7 +746,7 @@ readloop:
 				break readloop // Unknown response (unexpected response ID).
 			}
 			select {
-			case <-c.writerDone:
+			case <-conn.writerDone:
 				break readloop
 			case <-c.shutdown:
 				break readloop
@@ -670,15 +759,27 @@ readloop:
 		}
 	}
 	if connCloseErr != nil {
-		c.setCloseErr(connCloseErr)
+		conn.setErr(connCloseErr)
 	}
-	close(c.readerDone)
+	close(conn.readerDone)
 	c.respLock.Lock()
 	for _, ch := range c.respChannels {
 		close(ch)
 	}
-	c.respChannels = nil
+	c.respChannels = make(map[uint64]chan *neorpc.Response)
 	c.respLock.Unlock()
+	if c.wsOpts.Reconnect == nil {
+		c.finish(conn.getErr())
+	}
+}
+
+// finish stops the client for good: it records the closing reason, closes
+// every subscriber channel and cancels the client's context.
+func (c *WSClient) finish(err error) {
+	if err != nil {
+		c.setCloseErr(err)
+	}
+	close(c.readerDone)
 	c.subscriptionsLock.Lock()
 	for rcvrCh, ids := range c.receivers {
 		c.dropSubCh(rcvrCh, ids[0], true)
@@ -700,43 +801,43 @@ func (c *WSClient) dropSubCh(rcvrCh any, id string, ignoreCloseNotificationChann
 	}
 }
 
-func (c *WSClient) wsWriter() {
+func (c *WSClient) wsWriter(conn *wsConn) {
 	pingTicker := time.NewTicker(wsPingPeriod)
-	defer c.ws.Close()
-	defer close(c.writerDone)
+	defer conn.ws.Close()
+	defer close(conn.writerDone)
 	var connCloseErr error
 writeloop:
 	for {
 		select {
 		case <-c.shutdown:
 			return
-		case <-c.readerDone:
+		case <-conn.readerDone:
 			return
-		case req, ok := <-c.requests:
+		case req, ok := <-conn.requests:
 			if !ok {
 				return
 			}
-			if err := c.ws.SetWriteDeadline(time.Now().Add(c.opts.RequestTimeout)); err != nil {
+			if err := conn.ws.SetWriteDeadline(time.Now().Add(c.opts.RequestTimeout)); err != nil {
 				connCloseErr = fmt.Errorf("failed to set request write deadline: %w", err)
 				break writeloop
 			}
-			if err := c.ws.WriteJSON(req); err != nil {
+			if err := conn.ws.WriteJSON(req); err != nil {
 				connCloseErr = fmt.Errorf("failed to write JSON request (%s / %d): %w", req.Method, len(req.Params), err)
 				break writeloop
 			}
 		case <-pingTicker.C:
-			if err := c.ws.SetWriteDeadline(time.Now().Add(wsWriteLimit)); err != nil {
+			if err := conn.ws.SetWriteDeadline(time.Now().Add(wsWriteLimit)); err != nil {
 				connCloseErr = fmt.Errorf("failed to set ping write deadline: %w", err)
 				break writeloop
 			}
-			if err := c.ws.WriteMessage(websocket.PingMessage, []byte{}); err != nil {
+			if err := conn.ws.WriteMessage(websocket.PingMessage, []byte{}); err != nil {
 				connCloseErr = fmt.Errorf("failed to write ping message: %w", err)
 				break writeloop
 			}
 		}
 	}
 	if connCloseErr != nil {
-		c.setCloseErr(connCloseErr)
+		conn.setErr(connCloseErr)
 	}
 }
 
@@ -782,12 +883,15 @@ func (c *WSClient) getResponseChannel(id uint64) chan *neorpc.Response {
 }
 
 // closeErrOrConnLost returns ErrWSConnLost with details (set by wsReader or
-// wsWriter) if available.
-func (c *WSClient) closeErrOrConnLost() error {
+// wsWriter of the given connection) if available.
+func (c *WSClient) closeErrOrConnLost(conn *wsConn) error {
 	var (
 		err      = ErrWSConnLost
 		closeErr = c.getErrorOrClosedByUser()
 	)
+	if closeErr == nil {
+		closeErr = conn.getErr()
+	}
 	if closeErr != nil {
 		err = fmt.Errorf("%w: %w", err, closeErr)
 	}
@@ -795,31 +899,32 @@ func (c *WSClient) closeErrOrConnLost() error {
 }
 
 func (c *WSClient) makeWsRequest(r *neorpc.Request) (*neorpc.Response, error) {
+	conn := c.getConn()
 	ch := make(chan *neorpc.Response)
 	c.respLock.Lock()
 	select {
-	case <-c.readerDone:
+	case <-conn.readerDone:
 		c.respLock.Unlock()
-		return nil, fmt.Errorf("before registering response channel: %w", c.closeErrOrConnLost())
+		return nil, fmt.Errorf("before registering response channel: %w", c.closeErrOrConnLost(conn))
 	default:
 		c.respChannels[r.ID] = ch
 		c.respLock.Unlock()
 	}
 	select {
-	case <-c.readerDone:
-		return nil, fmt.Errorf("before sending the request: %w", c.closeErrOrConnLost())
-	case <-c.writerDone:
-		return nil, fmt.Errorf("before sending the request: %w", c.closeErrOrConnLost())
-	case c.requests <- r:
+	case <-conn.readerDone:
+		return nil, fmt.Errorf("before sending the request: %w", c.closeErrOrConnLost(conn))
+	case <-conn.writerDone:
+		return nil, fmt.Errorf("before sending the request: %w", c.closeErrOrConnLost(conn))
+	case conn.requests <- r:
 	}
 	select {
-	case <-c.readerDone:
-		return nil, fmt.Errorf("waiting for the response: %w", c.closeErrOrConnLost())
-	case <-c.writerDone:
-		return nil, fmt.Errorf("waiting for the response: %w", c.closeErrOrConnLost())
+	case <-conn.readerDone:
+		return nil, fmt.Errorf("waiting for the response: %w", c.closeErrOrConnLost(conn))
+	case <-conn.writerDone:
+		return nil, fmt.Errorf("waiting for the response: %w", c.closeErrOrConnLost(conn))
 	case resp, ok := <-ch:
 		if !ok {
-			return nil, fmt.Errorf("waiting for the response: %w", c.closeErrOrConnLost())
+			return nil, fmt.Errorf("waiting for the response: %w", c.closeErrOrConnLost(conn))
 		}
 		c.unregisterRespChannel(r.ID)
 		return resp, nil
@@ -838,6 +943,9 @@ func (c *WSClient) performSubscription(params []any, rcvr notificationReceiver)
 	// Protect from concurrent subscribe/ubsubscribe requests, ref. #3093.
 	c.subscriptionsOrderLock.Lock()
 	defer c.subscriptionsOrderLock.Unlock()
+	if err := c.initBackfill(rcvr.EventID()); err != nil {
+		return "", err
+	}
 	if err := c.performRequest("subscribe", params, &resp); err != nil {
 		return "", err
 	}
@@ -845,10 +953,16 @@ func (c *WSClient) performSubscription(params []any, rcvr notificationReceiver)
 	c.subscriptionsLock.Lock()
 	defer c.subscriptionsLock.Unlock()
 
-	c.subscriptions[resp] = rcvr
+	var id = resp
+	if c.wsOpts.Reconnect != nil {
+		c.lastSubID++
+		id = strconv.FormatUint(c.lastSubID, 10)
+		c.serverIDs[id] = resp
+	}
+	c.subscriptions[id] = rcvr
 	ch := rcvr.Receiver()
-	c.receivers[ch] = append(c.receivers[ch], resp)
-	return resp, nil
+	c.receivers[ch] = append(c.receivers[ch], id)
+	return id, nil
 }
 
 // ReceiveBlocks registers provided channel as a receiver for the new block events.
@@ -1055,18 +1169,26 @@ func (c *WSClient) performUnsubscription(id string) error {
 
 	c.subscriptionsLock.RLock()
 	rcvr, ok := c.subscriptions[id]
+	srvID, remapped := c.serverIDs[id]
 	c.subscriptionsLock.RUnlock()
 
 	if !ok {
 		return errors.New("no subscription with this ID")
 	}
-
-	var resp bool
-	if err := c.performRequest("unsubscribe", []any{id}, &resp); err != nil {
-		return err
+	if !remapped {
+		srvID = id
 	}
-	if !resp {
-		return errors.New("unsubscribe method returned false result")
+
+	// Subscriptions refused by the server on reconnection don't exist
+	// server-side, so they're only removed locally.
+	if srvID != "" {
+		var resp bool
+		if err := c.performRequest("unsubscribe", []any{srvID}, &resp); err != nil {
+			return err
+		}
+		if !resp {
+			return errors.New("unsubscribe method returned false result")
+		}
 	}
 
 	c.subscriptionsLock.Lock()
@@ -1091,6 +1213,7 @@ func (c *WSClient) performUnsubscription(id string) error {
 		c.receivers[ch] = ids
 	}
 	delete(c.subscriptions, id)
+	delete(c.serverIDs, id)
 	return nil
 }
 
@@ -0,0 +1,441 @@
+package rpcclient
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/trigger"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/vmstate"
+)
+
+const (
+	defaultReconnectMinBackoff = time.Second
+	defaultReconnectMaxBackoff = 30 * time.Second
+)
+
+// ReconnectOptions defines WSClient behaviour on connection loss. When they're
+// set, WSClient doesn't close subscriber channels on disconnection, instead it
+// redials the server (waiting for MinBackoff before the first attempt and
+// doubling the delay after every failed one up to MaxBackoff) and restores
+// every active subscription with its original filter. Subscription IDs
+// returned by Receive* methods are client-side ones in this mode, they stay
+// valid across reconnections.
+//
+// Any requests made while the connection is being reestablished fail with
+// ErrWSConnLost, GetError returns nil until WSClient gives up reconnecting.
+// Subscriptions that the server refuses to restore get their channels closed
+// (it's still the user responsibility to unsubscribe). Subscriber channels
+// are closed and the client becomes unusable if the connection can't be
+// reestablished in MaxAttempts attempts.
+type ReconnectOptions struct {
+	// MinBackoff is the delay before the first redial attempt, one second
+	// is used if not set.
+	MinBackoff time.Duration
+	// MaxBackoff is the maximum delay between redial attempts, 30 seconds
+	// are used if not set.
+	MaxBackoff time.Duration
+	// MaxAttempts is the number of consecutive failed redial attempts after
+	// which WSClient gives up, zero means no limit.
+	MaxAttempts int
+	// Backfill enables delivery of block_added, header_of_added_block,
+	// transaction_added, transaction_executed and notification_from_execution
+	// events missed while being offline. They're retrieved via getblock and
+	// getapplicationlog (so the server must have application logs enabled)
+	// starting from the last block seen by the client and delivered in the
+	// usual order before any new event, so consumers get a gap-free stream.
+	// Events are matched against subscription filters on the client side.
+	// The last seen block is tracked via block_added, header_of_added_block
+	// and PostPersist transaction_executed events; when none of them are
+	// received, the chain height at the moment of subscription is used which
+	// may lead to repeated transaction_added, transaction_executed and
+	// notification_from_execution events. Mempool and notary request events
+	// are never backfilled.
+	Backfill bool
+}
+
+// backfillState tracks the last block seen by subscribers and queues live
+// events while the missed ones are being backfilled.
+type backfillState struct {
+	lock sync.Mutex
+	// active denotes backfilling process, live events are queued while
+	// it's active.
+	active bool
+	queue  []Notification
+	// seen contains hashes of blocks and transactions whose events are
+	// delivered during backfilling.
+	seen map[util.Uint256]struct{}
+
+	// known denotes whether lastIndex is valid.
+	known     bool
+	lastIndex uint32
+	// lastPersisted is a hash of the last block that is only known from
+	// the PostPersist execution event, it's resolved into lastIndex on
+	// reconnection.
+	lastPersisted util.Uint256
+}
+
+// withDefaults returns a copy of options with default values set for
+// unspecified fields.
+func (o *ReconnectOptions) withDefaults() *ReconnectOptions {
+	res := *o
+	if res.MinBackoff <= 0 {
+		res.MinBackoff = defaultReconnectMinBackoff
+	}
+	if res.MaxBackoff <= 0 {
+		res.MaxBackoff = defaultReconnectMaxBackoff
+	}
+	res.MaxBackoff = max(res.MaxBackoff, res.MinBackoff)
+	return &res
+}
+
+// isChainEvent returns whether the event is emitted during block processing
+// and thus can be backfilled.
+func isChainEvent(e neorpc.EventID) bool {
+	switch e {
+	case neorpc.BlockEventID, neorpc.HeaderOfAddedBlockEventID, neorpc.TransactionEventID,
+		neorpc.ExecutionEventID, neorpc.NotificationEventID:
+		return true
+	default:
+		return false
+	}
+}
+
+// subscriptionParams returns "subscribe" request parameters for the given
+// receiver.
+func subscriptionParams(rcvr notificationReceiver) []any {
+	params := []any{rcvr.EventID().String()}
+	if flt := rcvr.Filter(); flt != nil {
+		params = append(params, flt)
+	}
+	return params
+}
+
+// reconnectLoop waits for the given connection to be lost and reestablishes
+// it until the client is closed or reconnection attempts are exhausted.
+func (c *WSClient) reconnectLoop(conn *wsConn) {
+	var err error
+	for {
+		<-conn.readerDone
+		if c.closeCalled.Load() {
+			break
+		}
+		conn, err = c.reconnect(conn.getErr())
+		if conn == nil {
+			break
+		}
+	}
+	c.finish(err)
+}
+
+// reconnect redials the server with backoff and restores subscriptions over
+// the new connection. It returns nil connection if the client is closed or
+// if it gives up reconnecting (with the reason).
+func (c *WSClient) reconnect(reason error) (*wsConn, error) {
+	var (
+		opts    = c.wsOpts.Reconnect
+		backoff = opts.MinBackoff
+		err     error
+	)
+	for i := 0; opts.MaxAttempts <= 0 || i < opts.MaxAttempts; i++ {
+		select {
+		case <-c.shutdown:
+			return nil, nil
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, opts.MaxBackoff)
+
+		var ws *websocket.Conn
+		ws, err = dialWS(c.ctx, c.endpoint.String(), c.opts.DialTimeout)
+		if err != nil {
+			continue
+		}
+		conn := c.startConn(ws)
+		err = c.restore()
+		if err == nil {
+			return conn, nil
+		}
+		_ = ws.Close()
+		<-conn.readerDone
+	}
+	return nil, fmt.Errorf("%w; failed to reconnect: %w", reason, err)
+}
+
+// restore resubscribes every active subscription using the current
+// connection and backfills missed events if needed.
+func (c *WSClient) restore() (err error) {
+	c.subscriptionsOrderLock.Lock()
+	defer c.subscriptionsOrderLock.Unlock()
+
+	if c.wsOpts.Reconnect.Backfill {
+		c.startBackfill()
+		defer func() { c.stopBackfill(err == nil) }()
+	}
+
+	c.subscriptionsLock.RLock()
+	subs := make(map[string]notificationReceiver, len(c.subscriptions))
+	for id, rcvr := range c.subscriptions {
+		_, active := c.receivers[rcvr.Receiver()]
+		if active && c.serverIDs[id] != "" {
+			subs[id] = rcvr
+		}
+	}
+	c.subscriptionsLock.RUnlock()
+
+	for id, rcvr := range subs {
+		var srvID string
+		subErr := c.performRequest("subscribe", subscriptionParams(rcvr), &srvID)
+		if subErr != nil {
+			var rpcErr *neorpc.Error
+			if !errors.As(subErr, &rpcErr) {
+				return fmt.Errorf("failed to restore subscription %s: %w", id, subErr)
+			}
+			srvID = ""
+		}
+		c.subscriptionsLock.Lock()
+		c.serverIDs[id] = srvID
+		if srvID == "" {
+			ch := rcvr.Receiver()
+			if _, ok := c.receivers[ch]; ok {
+				c.dropSubCh(ch, id, true)
+			}
+		}
+		c.subscriptionsLock.Unlock()
+	}
+	if c.wsOpts.Reconnect.Backfill {
+		return c.backfillEvents()
+	}
+	return nil
+}
+
+// initBackfill remembers the current chain height as the last seen one if
+// it's not yet known and chain-related events are to be backfilled. It must
+// be called with subscriptionsOrderLock taken.
+func (c *WSClient) initBackfill(e neorpc.EventID) error {
+	if c.wsOpts.Reconnect == nil || !c.wsOpts.Reconnect.Backfill || !isChainEvent(e) {
+		return nil
+	}
+	c.backfill.lock.Lock()
+	known := c.backfill.known
+	c.backfill.lock.Unlock()
+	if known {
+		return nil
+	}
+	count, err := c.GetBlockCount()
+	if err != nil {
+		return fmt.Errorf("failed to get block count: %w", err)
+	}
+	c.backfill.lock.Lock()
+	if !c.backfill.known {
+		c.backfill.known = true
+		c.backfill.lastIndex = count - 1
+	}
+	c.backfill.lock.Unlock()
+	return nil
+}
+
+// dispatch delivers live notification to subscribers or queues it if missed
+// events are being backfilled.
+func (c *WSClient) dispatch(ntf Notification) {
+	if c.wsOpts.Reconnect == nil || !c.wsOpts.Reconnect.Backfill {
+		c.notifySubscribers(ntf)
+		return
+	}
+	c.backfill.lock.Lock()
+	defer c.backfill.lock.Unlock()
+	if c.backfill.active {
+		c.backfill.queue = append(c.backfill.queue, ntf)
+		return
+	}
+	c.backfill.track(ntf)
+	c.notifySubscribers(ntf)
+}
+
+// track updates the last seen block using the given notification. It must be
+// called with the lock taken.
+func (b *backfillState) track(ntf Notification) {
+	switch ntf.Type {
+	case neorpc.BlockEventID:
+		b.setIndex(ntf.Value.(*block.Block).Index)
+	case neorpc.HeaderOfAddedBlockEventID:
+		b.setIndex(ntf.Value.(*block.Header).Index)
+	case neorpc.ExecutionEventID:
+		if aer := ntf.Value.(*state.AppExecResult); aer.Trigger == trigger.PostPersist {
+			b.known = true
+			b.lastPersisted = aer.Container
+		}
+	}
+}
+
+// setIndex sets the last seen block index. It must be called with the lock
+// taken.
+func (b *backfillState) setIndex(index uint32) {
+	b.known = true
+	b.lastIndex = index
+	b.lastPersisted = util.Uint256{}
+}
+
+// startBackfill makes live events to be queued until stopBackfill is called.
+func (c *WSClient) startBackfill() {
+	c.backfill.lock.Lock()
+	defer c.backfill.lock.Unlock()
+	c.backfill.active = true
+	c.backfill.seen = make(map[util.Uint256]struct{})
+}
+
+// stopBackfill delivers queued live events skipping those that were already
+// backfilled if flush is true and drops them otherwise (they'll be backfilled
+// on the next reconnection then).
+func (c *WSClient) stopBackfill(flush bool) {
+	c.backfill.lock.Lock()
+	defer c.backfill.lock.Unlock()
+	if flush {
+		for _, ntf := range c.backfill.queue {
+			if c.backfill.delivered(ntf) {
+				continue
+			}
+			c.backfill.track(ntf)
+			c.notifySubscribers(ntf)
+		}
+	}
+	c.backfill.active = false
+	c.backfill.queue = nil
+	c.backfill.seen = nil
+}
+
+// delivered returns whether the given live notification was already delivered
+// during backfilling. It must be called with the lock taken.
+func (b *backfillState) delivered(ntf Notification) bool {
+	var container util.Uint256
+	switch ntf.Type {
+	case neorpc.BlockEventID:
+		return b.known && ntf.Value.(*block.Block).Index <= b.lastIndex
+	case neorpc.HeaderOfAddedBlockEventID:
+		return b.known && ntf.Value.(*block.Header).Index <= b.lastIndex
+	case neorpc.TransactionEventID:
+		container = ntf.Value.(*transaction.Transaction).Hash()
+	case neorpc.ExecutionEventID:
+		container = ntf.Value.(*state.AppExecResult).Container
+	case neorpc.NotificationEventID:
+		container = ntf.Value.(*state.ContainedNotificationEvent).Container
+	default:
+		return false
+	}
+	_, ok := b.seen[container]
+	return ok
+}
+
+// deliverBackfilled sends backfilled notification to subscribers remembering
+// its container.
+func (c *WSClient) deliverBackfilled(ntf Notification, container util.Uint256) {
+	c.backfill.lock.Lock()
+	defer c.backfill.lock.Unlock()
+	c.backfill.seen[container] = struct{}{}
+	c.backfill.track(ntf)
+	c.notifySubscribers(ntf)
+}
+
+// backfillEvents retrieves and delivers chain events missed since the last
+// seen block.
+func (c *WSClient) backfillEvents() error {
+	var need = make(map[neorpc.EventID]bool)
+	c.subscriptionsLock.RLock()
+	for _, ids := range c.receivers {
+		for _, id := range ids {
+			need[c.subscriptions[id].EventID()] = true
+		}
+	}
+	c.subscriptionsLock.RUnlock()
+	needLogs := need[neorpc.ExecutionEventID] || need[neorpc.NotificationEventID]
+	if !needLogs && !need[neorpc.BlockEventID] && !need[neorpc.HeaderOfAddedBlockEventID] &&
+		!need[neorpc.TransactionEventID] {
+		return nil
+	}
+
+	c.backfill.lock.Lock()
+	known, lastIndex, lastPersisted := c.backfill.known, c.backfill.lastIndex, c.backfill.lastPersisted
+	c.backfill.lock.Unlock()
+	if !known {
+		return nil
+	}
+	if !lastPersisted.Equals(util.Uint256{}) {
+		h, err := c.GetBlockHeaderByHash(lastPersisted)
+		if err != nil {
+			return fmt.Errorf("failed to get last seen block header %s: %w", lastPersisted.StringLE(), err)
+		}
+		lastIndex = h.Index
+	}
+	count, err := c.GetBlockCount()
+	if err != nil {
+		return fmt.Errorf("failed to get block count: %w", err)
+	}
+
+	// deliverExec sends execution and its notifications (only successful
+	// transactions emit notifications).
+	deliverExec := func(container util.Uint256, exec state.Execution, isTx bool) {
+		if need[neorpc.ExecutionEventID] {
+			c.deliverBackfilled(Notification{
+				Type:  neorpc.ExecutionEventID,
+				Value: &state.AppExecResult{Container: container, Execution: exec},
+			}, container)
+		}
+		if need[neorpc.NotificationEventID] && (!isTx || exec.VMState == vmstate.Halt) {
+			for _, ev := range exec.Events {
+				c.deliverBackfilled(Notification{
+					Type:  neorpc.NotificationEventID,
+					Value: &state.ContainedNotificationEvent{Container: container, NotificationEvent: ev},
+				}, container)
+			}
+		}
+	}
+	for index := lastIndex + 1; index < count; index++ {
+		b, err := c.GetBlockByIndex(index)
+		if err != nil {
+			return fmt.Errorf("failed to get block %d: %w", index, err)
+		}
+		var blockExecs = make(map[trigger.Type]state.Execution)
+		if needLogs {
+			log, err := c.GetApplicationLog(b.Hash(), nil)
+			if err != nil {
+				return fmt.Errorf("failed to get application log for block %d: %w", index, err)
+			}
+			for _, exec := range log.Executions {
+				blockExecs[exec.Trigger] = exec
+			}
+			if exec, ok := blockExecs[trigger.OnPersist]; ok {
+				deliverExec(b.Hash(), exec, false)
+			}
+		}
+		for _, tx := range b.Transactions {
+			if needLogs {
+				log, err := c.GetApplicationLog(tx.Hash(), nil)
+				if err != nil {
+					return fmt.Errorf("failed to get application log for transaction %s: %w", tx.Hash().StringLE(), err)
+				}
+				for _, exec := range log.Executions {
+					deliverExec(tx.Hash(), exec, true)
+				}
+			}
+			if need[neorpc.TransactionEventID] {
+				c.deliverBackfilled(Notification{Type: neorpc.TransactionEventID, Value: tx}, tx.Hash())
+			}
+		}
+		if exec, ok := blockExecs[trigger.PostPersist]; ok {
+			deliverExec(b.Hash(), exec, false)
+		}
+		if need[neorpc.HeaderOfAddedBlockEventID] {
+			c.deliverBackfilled(Notification{Type: neorpc.HeaderOfAddedBlockEventID, Value: &b.Header}, b.Hash())
+		}
+		// Block announcement is the last one, so the block is marked as seen
+		// even if there are no block subscribers.
+		c.deliverBackfilled(Notification{Type: neorpc.BlockEventID, Value: b}, b.Hash())
+	}
+	return nil
+}
@@ -1,13 +1,16 @@
 package rpcsrv
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math/big"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"slices"
@@ -34,6 +37,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
 	"github.com/nspcc-dev/neo-go/pkg/encoding/bigint"
 	"github.com/nspcc-dev/neo-go/pkg/io"
 	"github.com/nspcc-dev/neo-go/pkg/neorpc"
@@ -1112,6 +1116,132 @@ func TestCreateNEP17TransferTx(t *testing.T) {
 	})
 }
 
+func TestClient_InvokeWithOverrides(t *testing.T) {
+	chain, _, httpSrv := initServerWithInMemoryChain(t)
+
+	c, err := rpcclient.New(context.Background(), httpSrv.URL, rpcclient.Options{})
+	require.NoError(t, err)
+	t.Cleanup(c.Close)
+	require.NoError(t, c.Init())
+
+	var (
+		acc       = util.Uint160{1, 2, 3}
+		overrides = &neorpc.StateOverrides{
+			Storage: []neorpc.StorageOverride{{Contract: testContractHash, Key: acc.BytesBE(), Value: []byte{42}}},
+			Balances: []neorpc.BalanceOverride{{
+				Asset:   nativehashes.GasToken,
+				Account: acc,
+				Amount:  big.NewInt(100500),
+			}},
+		}
+		h = chain.BlockHeight() - 1
+	)
+	for name, inv := range map[string]*invoker.Invoker{
+		"current":  invoker.NewWithOverrides(c, nil, overrides),
+		"by index": invoker.NewHistoricAtHeightWithOverrides(h, c, nil, overrides),
+		"by block": invoker.NewHistoricWithStateAndOverrides(chain.GetHeaderHash(h), c, nil, overrides),
+	} {
+		t.Run(name, func(t *testing.T) {
+			bal, err := gas.NewReader(inv).BalanceOf(acc)
+			require.NoError(t, err)
+			require.EqualValues(t, 100500, bal.Int64())
+
+			bal, err = nep17.NewReader(inv, testContractHash).BalanceOf(acc)
+			require.NoError(t, err)
+			require.EqualValues(t, 42, bal.Int64())
+
+			_, err = inv.Verify(testContractHash, nil)
+			require.ErrorIs(t, err, invoker.ErrOverridesVerify)
+		})
+	}
+
+	t.Run("not persisted", func(t *testing.T) {
+		bal, err := gas.NewReader(invoker.New(c, nil)).BalanceOf(acc)
+		require.NoError(t, err)
+		require.Zero(t, bal.Sign())
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := c.InvokeFunctionWithOverrides(nativehashes.GasToken, "balanceOf", nil, nil, &neorpc.StateOverrides{
+			Balances: []neorpc.BalanceOverride{{Asset: testContractHash, Account: acc, Amount: big.NewInt(1)}},
+		})
+		require.ErrorIs(t, err, neorpc.ErrInvalidParams)
+	})
+}
+
+func TestClient_SimulateTransactions(t *testing.T) {
+	chain, _, httpSrv := initServerWithInMemoryChain(t)
+
+	c, err := rpcclient.New(context.Background(), httpSrv.URL, rpcclient.Options{})
+	require.NoError(t, err)
+	t.Cleanup(c.Close)
+	require.NoError(t, c.Init())
+
+	var (
+		acc       = util.Uint160{1, 2, 3}
+		recipient = util.Uint160{4, 5, 6}
+		overrides = &neorpc.StateOverrides{
+			Balances: []neorpc.BalanceOverride{{
+				Asset:   nativehashes.GasToken,
+				Account: acc,
+				Amount:  big.NewInt(100500),
+			}},
+		}
+		transfer, _ = smartcontract.CreateCallWithAssertScript(nativehashes.GasToken, "transfer", acc, recipient, 500, nil)
+		balance, _  = smartcontract.CreateCallScript(nativehashes.GasToken, "balanceOf", recipient)
+		steps       = []neorpc.SimulationStep{
+			{
+				Script: transfer,
+				Signers: []neorpc.SignerWithWitness{{
+					Signer: transaction.Signer{Account: acc, Scopes: transaction.CalledByEntry},
+				}},
+			},
+			{Script: balance},
+		}
+		h = chain.BlockHeight() - 1
+	)
+	for name, simulate := range map[string]func() (*result.Simulation, error){
+		"current": func() (*result.Simulation, error) {
+			return c.SimulateTransactions(steps, overrides)
+		},
+		"by index": func() (*result.Simulation, error) {
+			return c.SimulateTransactionsAtHeight(h, steps, overrides)
+		},
+		"by block": func() (*result.Simulation, error) {
+			return c.SimulateTransactionsWithState(chain.GetHeaderHash(h), steps, overrides)
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			res, err := simulate()
+			require.NoError(t, err)
+			require.Equal(t, 2, len(res.Transactions))
+			require.Equal(t, "HALT", res.Transactions[0].Result.State, res.Transactions[0].Result.FaultException)
+			require.Equal(t, 2, len(res.Transactions[0].StorageChanges))
+			require.Equal(t, []stackitem.Item{stackitem.Make(500)}, res.Transactions[1].Result.Stack)
+			require.Equal(t, res.GasConsumed, res.Transactions[1].CumulativeGasConsumed)
+		})
+	}
+
+	t.Run("without overrides", func(t *testing.T) {
+		res, err := c.SimulateTransactions(steps, nil)
+		require.NoError(t, err)
+		require.Equal(t, 2, len(res.Transactions))
+		require.Equal(t, "FAULT", res.Transactions[0].Result.State)
+		require.Equal(t, []stackitem.Item{stackitem.Make(0)}, res.Transactions[1].Result.Stack)
+	})
+
+	t.Run("not persisted", func(t *testing.T) {
+		bal, err := gas.NewReader(invoker.New(c, nil)).BalanceOf(recipient)
+		require.NoError(t, err)
+		require.Zero(t, bal.Sign())
+	})
+
+	t.Run("no steps", func(t *testing.T) {
+		_, err := c.SimulateTransactions(nil, nil)
+		require.ErrorIs(t, err, neorpc.ErrInvalidParams)
+	})
+}
+
 func TestInvokeVerify(t *testing.T) {
 	chain, _, httpSrv := initServerWithInMemoryChain(t)
 
@@ -2508,6 +2638,398 @@ func TestClient_GetVersion(t *testing.T) {
 	require.True(t, version.Application.RemoveUntraceableBlocks)
 }
 
+func TestClient_GetStorageDiff(t *testing.T) {
+	chain, _, httpSrv := initClearServerWithCustomConfig(t, func(cfg *config.Config) {
+		cfg.ApplicationConfiguration.SaveStorageDiffs = true
+	})
+	for _, b := range getTestBlocks(t) {
+		require.NoError(t, chain.AddBlock(b))
+	}
+
+	c, err := rpcclient.New(context.Background(), httpSrv.URL, rpcclient.Options{})
+	require.NoError(t, err)
+	t.Cleanup(c.Close)
+	require.NoError(t, c.Init())
+
+	t.Run("transaction", func(t *testing.T) {
+		h, err := util.Uint256DecodeStringLE(storageDeploymentTxHash)
+		require.NoError(t, err)
+		diff, err := c.GetStorageDiff(h)
+		require.NoError(t, err)
+		require.Equal(t, h, diff.Hash)
+		require.NotEmpty(t, diff.Added)
+		var contractAdded bool
+		for _, ch := range diff.Added {
+			require.Nil(t, ch.OldValue)
+			require.NotNil(t, ch.Value)
+			if ch.Contract != nil && *ch.Contract == nativehashes.ContractManagement {
+				contractAdded = true
+			}
+		}
+		require.True(t, contractAdded)
+		for _, ch := range diff.Changed {
+			require.NotNil(t, ch.OldValue)
+			require.NotNil(t, ch.Value)
+			require.NotEqual(t, ch.OldValue, ch.Value)
+		}
+	})
+	t.Run("faulted transaction", func(t *testing.T) {
+		h, err := util.Uint256DecodeStringLE(faultedTxHashLE)
+		require.NoError(t, err)
+		diff, err := c.GetStorageDiff(h)
+		require.NoError(t, err)
+		require.Empty(t, diff.Added)
+		require.Empty(t, diff.Changed)
+		require.Empty(t, diff.Deleted)
+	})
+	t.Run("block", func(t *testing.T) {
+		h := chain.CurrentBlockHash()
+		diff, err := c.GetStorageDiff(h)
+		require.NoError(t, err)
+		require.Equal(t, h, diff.Hash)
+		var gasChanged bool
+		for _, changes := range [][]result.StorageChange{diff.Added, diff.Changed, diff.Deleted} {
+			for _, ch := range changes {
+				si := chain.GetStorageItem(ch.ID, ch.Key)
+				require.Equal(t, []byte(si), ch.Value)
+				if ch.Contract != nil && *ch.Contract == nativehashes.GasToken {
+					gasChanged = true
+				}
+			}
+		}
+		require.True(t, gasChanged)
+	})
+	t.Run("unknown", func(t *testing.T) {
+		_, err := c.GetStorageDiff(util.Uint256{1, 2, 3})
+		require.ErrorIs(t, err, neorpc.ErrUnknownScriptContainer)
+	})
+}
+
+func TestClient_FindNotifications(t *testing.T) {
+	chain, _, httpSrv := initClearServerWithCustomConfig(t, func(cfg *config.Config) {
+		cfg.ApplicationConfiguration.SaveNotificationIndex = true
+		cfg.ApplicationConfiguration.RPC.MaxFindResultItems = 2 // Small value to test paging.
+	})
+	for _, b := range getTestBlocks(t) {
+		require.NoError(t, chain.AddBlock(b))
+	}
+
+	c, err := rpcclient.New(context.Background(), httpSrv.URL, rpcclient.Options{})
+	require.NoError(t, err)
+	t.Cleanup(c.Close)
+	require.NoError(t, c.Init())
+
+	contract, err := util.Uint160DecodeStringLE(testContractHashLE)
+	require.NoError(t, err)
+	transfer := "Transfer"
+
+	// getExpected collects matching notifications block by block.
+	getExpected := func(filter *neorpc.NotificationFilter, from, to uint32) []result.IndexedNotification {
+		var res []result.IndexedNotification
+		for i := from; i <= to; i++ {
+			ntfs, err := c.GetBlockNotifications(chain.GetHeaderHash(i), filter)
+			require.NoError(t, err)
+			for _, ntf := range slices.Concat(ntfs.OnPersist, ntfs.Application, ntfs.PostPersist) {
+				res = append(res, result.IndexedNotification{BlockIndex: i, ContainedNotificationEvent: ntf})
+			}
+		}
+		return res
+	}
+	// findAll retrieves all matching notifications page by page.
+	findAll := func(c *rpcclient.Client, filter neorpc.NotificationFilter, from, to uint32) []result.IndexedNotification {
+		var (
+			res   []result.IndexedNotification
+			start []byte
+		)
+		for {
+			page, err := c.FindNotifications(filter, from, to, start)
+			require.NoError(t, err)
+			require.LessOrEqual(t, len(page.Results), 2)
+			res = append(res, page.Results...)
+			if !page.Truncated {
+				require.Nil(t, page.Next)
+				return res
+			}
+			require.NotNil(t, page.Next)
+			start = page.Next
+		}
+	}
+
+	t.Run("by name", func(t *testing.T) {
+		filter := neorpc.NotificationFilter{Contract: &contract, Name: &transfer}
+		expected := getExpected(&filter, 0, chain.BlockHeight())
+		require.Greater(t, len(expected), 2)
+		require.Equal(t, expected, findAll(c, filter, 0, chain.BlockHeight()))
+	})
+	t.Run("range", func(t *testing.T) {
+		filter := neorpc.NotificationFilter{Contract: &nativehashes.GasToken, Name: &transfer}
+		expected := getExpected(&filter, 5, 10)
+		require.NotEmpty(t, expected)
+		require.Equal(t, expected, findAll(c, filter, 5, 10))
+	})
+	t.Run("by parameters", func(t *testing.T) {
+		filter := neorpc.NotificationFilter{
+			Contract:   &nativehashes.GasToken,
+			Name:       &transfer,
+			Parameters: []smartcontract.Parameter{{Type: smartcontract.Hash160Type, Value: testchain.MultisigScriptHash()}},
+		}
+		expected := getExpected(&filter, 0, chain.BlockHeight())
+		require.NotEmpty(t, expected)
+		require.Equal(t, expected, findAll(c, filter, 0, chain.BlockHeight()))
+	})
+	t.Run("any name", func(t *testing.T) {
+		res := findAll(c, neorpc.NotificationFilter{Contract: &contract}, 0, chain.BlockHeight())
+		require.Len(t, res, len(getExpected(&neorpc.NotificationFilter{Contract: &contract}, 0, chain.BlockHeight())))
+	})
+	t.Run("scan limit", func(t *testing.T) {
+		chain, _, httpSrv := initClearServerWithCustomConfig(t, func(cfg *config.Config) {
+			cfg.ApplicationConfiguration.SaveNotificationIndex = true
+			cfg.ApplicationConfiguration.RPC.MaxFindResultItems = 2
+			cfg.ApplicationConfiguration.RPC.MaxScannedNotifications = 1
+		})
+		for _, b := range getTestBlocks(t) {
+			require.NoError(t, chain.AddBlock(b))
+		}
+		c, err := rpcclient.New(context.Background(), httpSrv.URL, rpcclient.Options{})
+		require.NoError(t, err)
+		t.Cleanup(c.Close)
+		require.NoError(t, c.Init())
+
+		filter := neorpc.NotificationFilter{
+			Contract:   &nativehashes.GasToken,
+			Name:       &transfer,
+			Parameters: []smartcontract.Parameter{{Type: smartcontract.Hash160Type, Value: testchain.MultisigScriptHash()}},
+		}
+		expected := getExpected(&filter, 0, chain.BlockHeight())
+		require.NotEmpty(t, expected)
+		require.Equal(t, expected, findAll(c, filter, 0, chain.BlockHeight()))
+
+		// Every request scans a single record only.
+		page, err := c.FindNotifications(neorpc.NotificationFilter{Contract: &nativehashes.GasToken, Name: &transfer}, 0, chain.BlockHeight(), nil)
+		require.NoError(t, err)
+		require.Len(t, page.Results, 1)
+		require.True(t, page.Truncated)
+	})
+	t.Run("cursor past the end", func(t *testing.T) {
+		res, err := c.FindNotifications(neorpc.NotificationFilter{Contract: &contract}, 0, chain.BlockHeight(), []byte{0xff})
+		require.NoError(t, err)
+		require.Empty(t, res.Results)
+	})
+	t.Run("unknown contract", func(t *testing.T) {
+		res, err := c.FindNotifications(neorpc.NotificationFilter{Contract: &util.Uint160{1, 2, 3}}, 0, chain.BlockHeight(), nil)
+		require.NoError(t, err)
+		require.Empty(t, res.Results)
+		require.False(t, res.Truncated)
+	})
+	t.Run("no contract", func(t *testing.T) {
+		_, err := c.FindNotifications(neorpc.NotificationFilter{Name: &transfer}, 0, chain.BlockHeight(), nil)
+		require.ErrorIs(t, err, neorpc.ErrInvalidParams)
+	})
+	t.Run("bad range", func(t *testing.T) {
+		_, err := c.FindNotifications(neorpc.NotificationFilter{Contract: &contract}, 10, 5, nil)
+		require.ErrorIs(t, err, neorpc.ErrInvalidParams)
+	})
+}
+
+func TestClient_GetAddressTransactions(t *testing.T) {
+	chain, _, httpSrv := initClearServerWithCustomConfig(t, func(cfg *config.Config) {
+		cfg.ApplicationConfiguration.SaveAddressIndex = true
+	})
+	for _, b := range getTestBlocks(t) {
+		require.NoError(t, chain.AddBlock(b))
+	}
+
+	c, err := rpcclient.New(context.Background(), httpSrv.URL, rpcclient.Options{})
+	require.NoError(t, err)
+	t.Cleanup(c.Close)
+	require.NoError(t, c.Init())
+
+	// getExpected collects transactions touching acc from the newest to the
+	// oldest one.
+	getExpected := func(acc util.Uint160) []result.AddressTransaction {
+		var res []result.AddressTransaction
+		for i := int(chain.BlockHeight()); i >= 0; i-- {
+			b, err := chain.GetBlock(chain.GetHeaderHash(uint32(i)))
+			require.NoError(t, err)
+			for _, tx := range slices.Backward(b.Transactions) {
+				touched := slices.ContainsFunc(tx.Signers, func(s transaction.Signer) bool { return s.Account == acc })
+				aers, err := chain.GetAppExecResults(tx.Hash(), trigger.Application)
+				require.NoError(t, err)
+				if aers[0].VMState == vmstate.Halt {
+					for _, ev := range aers[0].Events {
+						for _, it := range ev.Item.Value().([]stackitem.Item) {
+							bs, err := it.TryBytes()
+							touched = touched || (err == nil && it.Type() != stackitem.IntegerT && bytes.Equal(bs, acc.BytesBE()))
+						}
+					}
+				}
+				if touched {
+					res = append(res, result.AddressTransaction{TxHash: tx.Hash(), Index: b.Index, Timestamp: b.Timestamp})
+				}
+			}
+		}
+		return res
+	}
+	acc := testchain.MultisigScriptHash()
+	expected := getExpected(acc)
+	require.Greater(t, len(expected), 2)
+
+	t.Run("by height", func(t *testing.T) {
+		res, err := c.GetAddressTransactionsByHeight(acc, 0, chain.BlockHeight(), 1000, 0)
+		require.NoError(t, err)
+		require.Equal(t, address.Uint160ToString(acc), res.Address)
+		require.Equal(t, expected, res.Transactions)
+	})
+	t.Run("height range", func(t *testing.T) {
+		var filtered []result.AddressTransaction
+		for _, tx := range expected {
+			if tx.Index >= 5 && tx.Index <= 10 {
+				filtered = append(filtered, tx)
+			}
+		}
+		require.NotEmpty(t, filtered)
+		res, err := c.GetAddressTransactionsByHeight(acc, 5, 10, 1000, 0)
+		require.NoError(t, err)
+		require.Equal(t, filtered, res.Transactions)
+	})
+	t.Run("height range out of chain", func(t *testing.T) {
+		res, err := c.GetAddressTransactionsByHeight(acc, 0, chain.BlockHeight()+100, 1000, 0)
+		require.NoError(t, err)
+		require.Equal(t, expected, res.Transactions)
+
+		res, err = c.GetAddressTransactionsByHeight(acc, chain.BlockHeight()+1, chain.BlockHeight()+100, 1000, 0)
+		require.NoError(t, err)
+		require.Empty(t, res.Transactions)
+
+		res, err = c.GetAddressTransactionsByHeight(acc, 10, 5, 1000, 0)
+		require.NoError(t, err)
+		require.Empty(t, res.Transactions)
+	})
+	t.Run("by time, paged", func(t *testing.T) {
+		var (
+			start, stop = uint64(0), expected[0].Timestamp
+			limit       = 2
+			actual      []result.AddressTransaction
+		)
+		for page := 0; ; page++ {
+			res, err := c.GetAddressTransactions(acc, &start, &stop, &limit, &page)
+			require.NoError(t, err)
+			require.LessOrEqual(t, len(res.Transactions), limit)
+			actual = append(actual, res.Transactions...)
+			if len(res.Transactions) < limit {
+				break
+			}
+		}
+		require.Equal(t, expected, actual)
+	})
+	t.Run("time range", func(t *testing.T) {
+		var (
+			start, stop = expected[len(expected)-1].Timestamp + 1, expected[0].Timestamp - 1
+			filtered    []result.AddressTransaction
+		)
+		for _, tx := range expected {
+			if tx.Timestamp >= start && tx.Timestamp <= stop {
+				filtered = append(filtered, tx)
+			}
+		}
+		require.NotEmpty(t, filtered)
+		res, err := c.GetAddressTransactions(acc, &start, &stop, nil, nil)
+		require.NoError(t, err)
+		require.Equal(t, filtered, res.Transactions)
+	})
+	t.Run("notification only", func(t *testing.T) {
+		acc := testchain.PrivateKeyByID(1).GetScriptHash()
+		expected := getExpected(acc)
+		require.True(t, slices.ContainsFunc(expected, func(e result.AddressTransaction) bool {
+			tx, _, err := chain.GetTransaction(e.TxHash)
+			require.NoError(t, err)
+			return !slices.ContainsFunc(tx.Signers, func(s transaction.Signer) bool { return s.Account == acc })
+		}))
+		res, err := c.GetAddressTransactionsByHeight(acc, 0, chain.BlockHeight(), 1000, 0)
+		require.NoError(t, err)
+		require.Equal(t, expected, res.Transactions)
+	})
+	t.Run("unknown", func(t *testing.T) {
+		res, err := c.GetAddressTransactionsByHeight(util.Uint160{1, 2, 3}, 0, chain.BlockHeight(), 1000, 0)
+		require.NoError(t, err)
+		require.Empty(t, res.Transactions)
+	})
+	t.Run("bad limit", func(t *testing.T) {
+		_, err := c.GetAddressTransactionsByHeight(acc, 0, chain.BlockHeight(), 0, 0)
+		require.ErrorIs(t, err, neorpc.ErrInvalidParams)
+	})
+}
+
+func TestClient_GetBalancesHistoric(t *testing.T) {
+	chain, _, httpSrv := initServerWithInMemoryChain(t)
+
+	c, err := rpcclient.New(context.Background(), httpSrv.URL, rpcclient.Options{})
+	require.NoError(t, err)
+	t.Cleanup(c.Close)
+	require.NoError(t, c.Init())
+
+	acc := testchain.PrivateKeyByID(0).GetScriptHash()
+	top := chain.BlockHeight()
+
+	t.Run("NEP-17", func(t *testing.T) {
+		actual, err := c.GetNEP17BalancesAtHeight(top, acc)
+		require.NoError(t, err)
+		expected, err := c.GetNEP17Balances(acc)
+		require.NoError(t, err)
+		require.Equal(t, expected.Address, actual.Address)
+		require.ElementsMatch(t, expected.Balances, actual.Balances)
+
+		for h := range top {
+			actual, err := c.GetNEP17BalancesAtHeight(h, acc)
+			require.NoError(t, err)
+			inv := invoker.NewHistoricAtHeight(h, c, nil)
+			for _, b := range actual.Balances {
+				require.LessOrEqual(t, b.LastUpdated, h)
+				bal, err := nep17.NewReader(inv, b.Asset).BalanceOf(acc)
+				require.NoError(t, err)
+				require.Equal(t, bal.String(), b.Amount, "asset %s at %d", b.Asset.StringLE(), h)
+			}
+			if h == 1 {
+				require.False(t, slices.ContainsFunc(actual.Balances, func(b result.NEP17Balance) bool {
+					return b.Asset == testContractHash
+				}))
+			}
+		}
+
+		h := top / 2
+		byHeight, err := c.GetNEP17BalancesAtHeight(h, acc)
+		require.NoError(t, err)
+		byBlock, err := c.GetNEP17BalancesWithState(chain.GetHeaderHash(h), acc)
+		require.NoError(t, err)
+		require.Equal(t, byHeight, byBlock)
+	})
+
+	t.Run("NEP-11", func(t *testing.T) {
+		actual, err := c.GetNEP11BalancesAtHeight(top, acc)
+		require.NoError(t, err)
+		expected, err := c.GetNEP11Balances(acc)
+		require.NoError(t, err)
+		require.Equal(t, expected.Address, actual.Address)
+		require.ElementsMatch(t, expected.Balances, actual.Balances)
+
+		hasNNS := func(res *result.NEP11Balances) bool {
+			return slices.ContainsFunc(res.Balances, func(b result.NEP11AssetBalance) bool {
+				return b.Asset == nnsHash
+			})
+		}
+		actual, err = c.GetNEP11BalancesAtHeight(13, acc)
+		require.NoError(t, err)
+		require.False(t, hasNNS(actual))
+
+		actual, err = c.GetNEP11BalancesAtHeight(14, acc)
+		require.NoError(t, err)
+		require.True(t, hasNNS(actual))
+		byBlock, err := c.GetNEP11BalancesWithState(chain.GetHeaderHash(14), acc)
+		require.NoError(t, err)
+		require.Equal(t, actual, byBlock)
+	})
+}
+
 func TestClient_NEP24(t *testing.T) {
 	_, _, httpSrv := initServerWithInMemoryChain(t)
 
@@ -2894,3 +3416,170 @@ func TestClient_GetBlockHeader(t *testing.T) {
 		require.Equal(t, expectedRes, h)
 	})
 }
+
+// hijackRecorder is an http.ResponseWriter that allows to track connections
+// hijacked by websocket upgrader.
+type hijackRecorder struct {
+	http.ResponseWriter
+	onHijack func(net.Conn)
+}
+
+// Hijack implements http.Hijacker interface.
+func (h *hijackRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := h.ResponseWriter.(http.Hijacker).Hijack()
+	if err == nil {
+		h.onHijack(conn)
+	}
+	return conn, rw, err
+}
+
+func TestWSClient_Reconnect(t *testing.T) {
+	chain, rpcSrv, _ := initClearServerWithInMemoryChain(t)
+
+	var (
+		lock    sync.Mutex
+		offline bool
+		conns   []net.Conn
+	)
+	httpSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		lock.Lock()
+		off := offline
+		lock.Unlock()
+		if off {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		rpcSrv.handleHTTPRequest(&hijackRecorder{ResponseWriter: w, onHijack: func(conn net.Conn) {
+			lock.Lock()
+			conns = append(conns, conn)
+			lock.Unlock()
+		}}, req)
+	}))
+	t.Cleanup(httpSrv.Close)
+
+	url := "ws" + strings.TrimPrefix(httpSrv.URL, "http") + "/ws"
+	c, err := rpcclient.NewWS(context.Background(), url, rpcclient.WSOptions{
+		Reconnect: &rpcclient.ReconnectOptions{
+			MinBackoff: 10 * time.Millisecond,
+			MaxBackoff: 50 * time.Millisecond,
+			Backfill:   true,
+		},
+	})
+	require.NoError(t, err)
+	t.Cleanup(c.Close)
+	require.NoError(t, c.Init())
+
+	bCh := make(chan *block.Block, 10)
+	aerCh := make(chan *state.AppExecResult, 100)
+	bID, err := c.ReceiveBlocks(nil, bCh)
+	require.NoError(t, err)
+	_, err = c.ReceiveExecutions(nil, aerCh)
+	require.NoError(t, err)
+
+	var (
+		blocks       = getTestBlocks(t)[:4]
+		expectedAERs []util.Uint256
+	)
+	for _, b := range blocks {
+		expectedAERs = append(expectedAERs, b.Hash())
+		for _, tx := range b.Transactions {
+			expectedAERs = append(expectedAERs, tx.Hash())
+		}
+		expectedAERs = append(expectedAERs, b.Hash())
+	}
+	checkBlock := func(t *testing.T, expected *block.Block) {
+		select {
+		case b := <-bCh:
+			require.Equal(t, expected.Hash(), b.Hash())
+		case <-time.After(5 * time.Second):
+			t.Fatalf("block %d wasn't received", expected.Index)
+		}
+	}
+
+	require.NoError(t, chain.AddBlock(blocks[0]))
+	checkBlock(t, blocks[0])
+
+	// Drop the connection and make the server unavailable for a while,
+	// blocks added meanwhile must be backfilled.
+	lock.Lock()
+	offline = true
+	for _, conn := range conns {
+		require.NoError(t, conn.Close())
+	}
+	conns = nil
+	lock.Unlock()
+	require.Eventually(t, func() bool {
+		_, err := c.GetBlockCount()
+		return errors.Is(err, rpcclient.ErrWSConnLost)
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, chain.AddBlock(blocks[1]))
+	require.NoError(t, chain.AddBlock(blocks[2]))
+	lock.Lock()
+	offline = false
+	lock.Unlock()
+
+	checkBlock(t, blocks[1])
+	checkBlock(t, blocks[2])
+	require.NoError(t, chain.AddBlock(blocks[3]))
+	checkBlock(t, blocks[3])
+
+	for i, h := range expectedAERs {
+		select {
+		case aer := <-aerCh:
+			require.Equal(t, h, aer.Container, i)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("execution %d wasn't received", i)
+		}
+	}
+	require.NoError(t, c.GetError())
+	require.NoError(t, c.Unsubscribe(bID))
+	require.NoError(t, c.UnsubscribeAll())
+	select {
+	case b := <-bCh:
+		t.Fatalf("unexpected block %d", b.Index)
+	case aer := <-aerCh:
+		t.Fatalf("unexpected execution %s", aer.Container.StringLE())
+	default:
+	}
+}
+
+func TestClient_TraceTransactionLimits(t *testing.T) {
+	chain, _, httpSrv := initClearServerWithCustomConfig(t, func(cfg *config.Config) {
+		cfg.ApplicationConfiguration.RPC.MaxTraceSteps = 10
+		cfg.ApplicationConfiguration.RPC.MaxTraceSize = 4096
+	})
+	for _, b := range getTestBlocks(t) {
+		require.NoError(t, chain.AddBlock(b))
+	}
+
+	c, err := rpcclient.New(context.Background(), httpSrv.URL, rpcclient.Options{})
+	require.NoError(t, err)
+	t.Cleanup(c.Close)
+	require.NoError(t, c.Init())
+
+	txHash, err := util.Uint256DecodeStringLE(storageDeploymentTxHash)
+	require.NoError(t, err)
+	aers, err := chain.GetAppExecResults(txHash, trigger.Application)
+	require.NoError(t, err)
+
+	t.Run("steps", func(t *testing.T) {
+		trace, err := c.TraceTransaction(txHash, false, false)
+		require.NoError(t, err)
+		require.True(t, trace.Truncated)
+		require.Equal(t, 10, len(trace.Steps))
+		require.Equal(t, aers[0].VMState.String(), trace.State)
+		require.Equal(t, aers[0].GasConsumed, trace.GasConsumed)
+		for _, step := range trace.Steps {
+			require.NotZero(t, step.GasConsumed)
+		}
+	})
+
+	t.Run("size", func(t *testing.T) {
+		trace, err := c.TraceTransaction(txHash, true, true)
+		require.NoError(t, err)
+		require.True(t, trace.Truncated)
+		require.Less(t, len(trace.Steps), 10)
+		require.Equal(t, aers[0].VMState.String(), trace.State)
+		require.Equal(t, aers[0].GasConsumed, trace.GasConsumed)
+	})
+}
@@ -415,6 +415,37 @@ func (p *Param) GetSignerWithWitness() (neorpc.SignerWithWitness, error) {
 	return c, nil
 }
 
+// GetStateOverrides returns neorpc.StateOverrides value of the parameter, nil
+// is returned for null parameter.
+func (p *Param) GetStateOverrides() (*neorpc.StateOverrides, error) {
+	if p.IsNull() {
+		return nil, nil
+	}
+	// This one doesn't need to be cached, it's used only once.
+	o := new(neorpc.StateOverrides)
+	err := json.Unmarshal(p.RawMessage, o)
+	if err != nil {
+		return nil, fmt.Errorf("not a state overrides: %w", err)
+	}
+	return o, nil
+}
+
+// GetSimulationSteps returns a slice of neorpc.SimulationStep from an array
+// of serialized transactions and/or scripts with signers stored in the
+// parameter.
+func (p *Param) GetSimulationSteps() ([]neorpc.SimulationStep, error) {
+	// This one doesn't need to be cached, it's used only once.
+	var steps []neorpc.SimulationStep
+	err := json.Unmarshal(p.RawMessage, &steps)
+	if err != nil {
+		return nil, fmt.Errorf("not a simulation steps: %w", err)
+	}
+	if len(steps) == 0 {
+		return nil, errors.New("no simulation steps")
+	}
+	return steps, nil
+}
+
 // GetSignersWithWitnesses returns a slice of SignerWithWitness with CalledByEntry
 // scope from an array of Uint160 or an array of serialized transaction.Signer stored
 // in the parameter.
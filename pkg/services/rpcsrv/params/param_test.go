@@ -10,6 +10,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/nspcc-dev/neo-go/pkg/core/native/nativehashes"
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
 	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
 	"github.com/nspcc-dev/neo-go/pkg/neorpc"
@@ -554,6 +555,56 @@ func TestParamGetSigners(t *testing.T) {
 	})
 }
 
+func TestParamGetStateOverrides(t *testing.T) {
+	u := util.Uint160{1, 2, 3, 4}
+	p := Param{RawMessage: []byte(fmt.Sprintf(`{"storage":[{"contract":"0x%s","key":"AQ==","value":null}],"balances":[{"asset":"0x%s","account":"0x%s","amount":"100"}]}`,
+		u.StringLE(), nativehashes.GasToken.StringLE(), u.StringLE()))}
+	o, err := p.GetStateOverrides()
+	require.NoError(t, err)
+	require.Equal(t, &neorpc.StateOverrides{
+		Storage: []neorpc.StorageOverride{{Contract: u, Key: []byte{1}}},
+		Balances: []neorpc.BalanceOverride{{
+			Asset:   nativehashes.GasToken,
+			Account: u,
+			Amount:  big.NewInt(100),
+		}},
+	}, o)
+
+	p = Param{RawMessage: []byte(`null`)}
+	o, err = p.GetStateOverrides()
+	require.NoError(t, err)
+	require.Nil(t, o)
+
+	p = Param{RawMessage: []byte(`[]`)}
+	_, err = p.GetStateOverrides()
+	require.Error(t, err)
+}
+
+func TestParamGetSimulationSteps(t *testing.T) {
+	u := util.Uint160{1, 2, 3, 4}
+	tx := transaction.New([]byte{1}, 1)
+	tx.Signers = []transaction.Signer{{Account: u}}
+	tx.Scripts = []transaction.Witness{{}}
+	p := Param{RawMessage: []byte(fmt.Sprintf(`["%s",{"script":"AgM=","signers":[{"account":"0x%s","scopes":"Global"}]}]`,
+		base64.StdEncoding.EncodeToString(tx.Bytes()), u.StringLE()))}
+	steps, err := p.GetSimulationSteps()
+	require.NoError(t, err)
+	require.Equal(t, 2, len(steps))
+	require.Equal(t, tx.Hash(), steps[0].Transaction.Hash())
+	require.Equal(t, neorpc.SimulationStep{
+		Script: []byte{2, 3},
+		Signers: []neorpc.SignerWithWitness{{
+			Signer: transaction.Signer{Account: u, Scopes: transaction.Global},
+		}},
+	}, steps[1])
+
+	for _, raw := range []string{`[]`, `null`, `{}`, `[{"script":""}]`, `["AQID"]`} {
+		p = Param{RawMessage: []byte(raw)}
+		_, err = p.GetSimulationSteps()
+		require.Error(t, err, raw)
+	}
+}
+
 func TestParamGetUUID(t *testing.T) {
 	t.Run("from null", func(t *testing.T) {
 		p := Param{RawMessage: []byte("null")}
@@ -13,6 +13,7 @@ import (
 	"math/big"
 	"net"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -27,11 +28,14 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/core"
 	"github.com/nspcc-dev/neo-go/pkg/core/block"
 	"github.com/nspcc-dev/neo-go/pkg/core/interop"
+	"github.com/nspcc-dev/neo-go/pkg/core/interop/interopnames"
 	"github.com/nspcc-dev/neo-go/pkg/core/interop/iterator"
+	istorage "github.com/nspcc-dev/neo-go/pkg/core/interop/storage"
 	"github.com/nspcc-dev/neo-go/pkg/core/mempool"
 	"github.com/nspcc-dev/neo-go/pkg/core/mempoolevent"
 	"github.com/nspcc-dev/neo-go/pkg/core/mpt"
 	"github.com/nspcc-dev/neo-go/pkg/core/native"
+	"github.com/nspcc-dev/neo-go/pkg/core/native/nativehashes"
 	"github.com/nspcc-dev/neo-go/pkg/core/state"
 	"github.com/nspcc-dev/neo-go/pkg/core/storage"
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
@@ -50,12 +54,14 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest/standard"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/nef"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/trigger"
 	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/nspcc-dev/neo-go/pkg/vm"
 	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
 	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
 	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"github.com/nspcc-dev/neo-go/pkg/vm/vmstate"
 	"go.uber.org/zap"
 )
 
@@ -91,17 +97,21 @@ type (
 		GetNatives() []state.Contract
 		GetNextBlockValidators() ([]*keys.PublicKey, error)
 		GetStateModule() core.StateRoot
+		GetStorageDiff(hash util.Uint256) (state.StorageDiff, error)
 		GetStorageItem(id int32, key []byte) state.StorageItem
 		GetTestHistoricVM(t trigger.Type, tx *transaction.Transaction, nextBlockHeight uint32) (*interop.Context, error)
 		GetTestVM(t trigger.Type, tx *transaction.Transaction, b *block.Block) (*interop.Context, error)
 		GetTokenLastUpdated(acc util.Uint160) (map[int32]uint32, error)
 		GetTransaction(util.Uint256) (*transaction.Transaction, uint32, error)
+		GetTxReplayVM(hash util.Uint256) (*interop.Context, error)
 		HeaderHeight() uint32
 		InitVerificationContext(ic *interop.Context, hash util.Uint160, witness *transaction.Witness) error
 		GetMillisecondsPerBlock() uint32
 		GetMaxValidUntilBlockIncrement() uint32
 		NativeManagementID() int32
 		P2PSigExtensionsEnabled() bool
+		SeekAddressTransactions(acc util.Uint160, end uint64, f func(h util.Uint256, index uint32, timestamp uint64) bool) error
+		SeekNotifications(contract util.Uint160, name *string, start, end uint32, cursor []byte, f func(cursor []byte, index uint32, ev *state.ContainedNotificationEvent) bool) error
 		SubscribeForBlocks(ch chan *block.Block)
 		SubscribeForHeadersOfAddedBlocks(ch chan *block.Header)
 		SubscribeForExecutions(ch chan *state.AppExecResult)
@@ -220,9 +230,11 @@ const (
 
 var rpcHandlers = map[string]func(*Server, params.Params) (any, *neorpc.Error){
 	"calculatenetworkfee":          (*Server).calculateNetworkFee,
+	"findnotifications":            (*Server).findNotifications,
 	"findstates":                   (*Server).findStates,
 	"findstorage":                  (*Server).findStorage,
 	"findstoragehistoric":          (*Server).findStorageHistoric,
+	"getaddresstransactions":       (*Server).getAddressTransactions,
 	"getapplicationlog":            (*Server).getApplicationLog,
 	"getbestblockhash":             (*Server).getBestBlockHash,
 	"getblock":                     (*Server).getBlock,
@@ -238,9 +250,11 @@ var rpcHandlers = map[string]func(*Server, params.Params) (any, *neorpc.Error){
 	"getcontractstate":             (*Server).getContractState,
 	"getnativecontracts":           (*Server).getNativeContracts,
 	"getnep11balances":             (*Server).getNEP11Balances,
+	"getnep11balanceshistoric":     (*Server).getNEP11BalancesHistoric,
 	"getnep11properties":           (*Server).getNEP11Properties,
 	"getnep11transfers":            (*Server).getNEP11Transfers,
 	"getnep17balances":             (*Server).getNEP17Balances,
+	"getnep17balanceshistoric":     (*Server).getNEP17BalancesHistoric,
 	"getnep17transfers":            (*Server).getNEP17Transfers,
 	"getpeers":                     (*Server).getPeers,
 	"getproof":                     (*Server).getProof,
@@ -252,6 +266,7 @@ var rpcHandlers = map[string]func(*Server, params.Params) (any, *neorpc.Error){
 	"getstateheight":               (*Server).getStateHeight,
 	"getstateroot":                 (*Server).getStateRoot,
 	"getstorage":                   (*Server).getStorage,
+	"getstoragediff":               (*Server).getStorageDiff,
 	"getstoragehistoric":           (*Server).getStorageHistoric,
 	"gettransactionheight":         (*Server).getTransactionHeight,
 	"getunclaimedgas":              (*Server).getUnclaimedGas,
@@ -265,10 +280,13 @@ var rpcHandlers = map[string]func(*Server, params.Params) (any, *neorpc.Error){
 	"invokecontractverify":         (*Server).invokeContractVerify,
 	"invokecontractverifyhistoric": (*Server).invokeContractVerifyHistoric,
 	"sendrawtransaction":           (*Server).sendrawtransaction,
+	"simulatetransactions":         (*Server).simulateTransactions,
+	"simulatetransactionshistoric": (*Server).simulateTransactionsHistoric,
 	"submitblock":                  (*Server).submitBlock,
 	"submitnotaryrequest":          (*Server).submitNotaryRequest,
 	"submitoracleresponse":         (*Server).submitOracleResponse,
 	"terminatesession":             (*Server).terminateSession,
+	"tracetransaction":             (*Server).traceTransaction,
 	"traverseiterator":             (*Server).traverseIterator,
 	"validateaddress":              (*Server).validateAddress,
 	"verifyproof":                  (*Server).verifyProof,
@@ -323,6 +341,22 @@ func New(chain Ledger, conf config.RPC, coreServer *network.Server,
 		conf.MaxRequestHeaderBytes = config.DefaultMaxRequestHeaderBytes
 		log.Info("MaxRequestHeaderBytes is not set or wong, setting default value", zap.Int("MaxRequestHeaderBytes", config.DefaultMaxRequestHeaderBytes))
 	}
+	if conf.MaxScannedNotifications <= 0 {
+		conf.MaxScannedNotifications = config.DefaultMaxScannedNotifications
+		log.Info("MaxScannedNotifications is not set or wrong, setting default value", zap.Int("MaxScannedNotifications", config.DefaultMaxScannedNotifications))
+	}
+	if conf.MaxSimulationSteps <= 0 {
+		conf.MaxSimulationSteps = config.DefaultMaxSimulationSteps
+		log.Info("MaxSimulationSteps is not set or wrong, setting default value", zap.Int("MaxSimulationSteps", config.DefaultMaxSimulationSteps))
+	}
+	if conf.MaxTraceSize <= 0 {
+		conf.MaxTraceSize = config.DefaultMaxTraceSize
+		log.Info("MaxTraceSize is not set or wrong, setting default value", zap.Int("MaxTraceSize", config.DefaultMaxTraceSize))
+	}
+	if conf.MaxTraceSteps <= 0 {
+		conf.MaxTraceSteps = config.DefaultMaxTraceSteps
+		log.Info("MaxTraceSteps is not set or wrong, setting default value", zap.Int("MaxTraceSteps", config.DefaultMaxTraceSteps))
+	}
 	if conf.MaxWebSocketClients == 0 {
 		conf.MaxWebSocketClients = defaultMaxWebSocketClients
 		log.Info("MaxWebSocketClients is not set or wrong, setting default value", zap.Int("MaxWebSocketClients", defaultMaxWebSocketClients))
@@ -930,6 +964,7 @@ func (s *Server) getPeers(_ params.Params) (any, *neorpc.Error) {
 	peers.AddUnconnected(s.coreServer.UnconnectedPeers())
 	peers.AddConnected(s.coreServer.ConnectedPeers())
 	peers.AddBad(s.coreServer.BadPeers())
+	peers.AddBanned(s.coreServer.BannedPeers())
 	return peers, nil
 }
 
@@ -1057,8 +1092,8 @@ func (s *Server) getApplicationLog(reqParams params.Params) (any, *neorpc.Error)
 	return result.NewApplicationLog(hash, appExecResults, trig), nil
 }
 
-func (s *Server) getNEP11Tokens(h util.Uint160, acc util.Uint160, bw *io.BufBinWriter) ([]stackitem.Item, string, int, error) {
-	items, finalize, err := s.invokeReadOnlyMulti(bw, h, []string{"tokensOf", "symbol", "decimals"}, [][]any{{acc}, nil, nil})
+func (s *Server) getNEP11Tokens(h util.Uint160, acc util.Uint160, bw *io.BufBinWriter, nextH *uint32) ([]stackitem.Item, string, int, error) {
+	items, finalize, err := s.invokeReadOnlyMulti(bw, nextH, h, []string{"tokensOf", "symbol", "decimals"}, [][]any{{acc}, nil, nil})
 	if err != nil {
 		return nil, "", 0, err
 	}
@@ -1098,9 +1133,8 @@ func (s *Server) getNEP11Balances(ps params.Params) (any, *neorpc.Error) {
 	var count int
 	stateSyncPoint := lastUpdated[math.MinInt32]
 	bw := io.NewBufBinWriter()
-contract_loop:
 	for _, h := range s.chain.GetNEP11Contracts() {
-		toks, sym, dec, err := s.getNEP11Tokens(h, u, bw)
+		toks, sym, dec, err := s.getNEP11Tokens(h, u, bw, nil)
 		if err != nil {
 			continue
 		}
@@ -1120,46 +1154,57 @@ contract_loop:
 			}
 			lub = stateSyncPoint
 		}
+		var tokens []result.NEP11TokenBalance
+		tokens, count = s.getNEP11TokenBalances(h, u, toks, isDivisible, lub, count, bw, nil)
 		bs.Balances = append(bs.Balances, result.NEP11AssetBalance{
 			Asset:    h,
 			Decimals: dec,
 			Name:     cs.Manifest.Name,
 			Symbol:   sym,
-			Tokens:   make([]result.NEP11TokenBalance, 0, len(toks)),
+			Tokens:   tokens,
 		})
-		curAsset := &bs.Balances[len(bs.Balances)-1]
-		for i := range toks {
-			id, err := toks[i].TryBytes()
-			if err != nil || len(id) > limits.MaxStorageKeyLen {
+		if count >= s.config.MaxNEP11Tokens {
+			break
+		}
+	}
+	return bs, nil
+}
+
+// getNEP11TokenBalances converts the list of NEP-11 tokens owned by the account
+// into balances checking amounts of divisible tokens using the latest state or
+// the state of the block preceding nextH if it's not nil. count is the number
+// of tokens converted so far, no more than MaxNEP11Tokens are converted in
+// total. The updated count is returned along with balances.
+func (s *Server) getNEP11TokenBalances(h util.Uint160, acc util.Uint160, toks []stackitem.Item, isDivisible bool, lub uint32, count int, bw *io.BufBinWriter, nextH *uint32) ([]result.NEP11TokenBalance, int) {
+	var res = make([]result.NEP11TokenBalance, 0, len(toks))
+	for i := 0; i < len(toks) && count < s.config.MaxNEP11Tokens; i++ {
+		id, err := toks[i].TryBytes()
+		if err != nil || len(id) > limits.MaxStorageKeyLen {
+			continue
+		}
+		var amount = "1"
+		if isDivisible {
+			balance, err := s.getNEP11DTokenBalance(h, acc, id, bw, nextH)
+			if err != nil {
 				continue
 			}
-			var amount = "1"
-			if isDivisible {
-				balance, err := s.getNEP11DTokenBalance(h, u, id, bw)
-				if err != nil {
-					continue
-				}
-				if balance.Sign() == 0 {
-					continue
-				}
-				amount = balance.String()
-			}
-			count++
-			curAsset.Tokens = append(curAsset.Tokens, result.NEP11TokenBalance{
-				ID:          hex.EncodeToString(id),
-				Amount:      amount,
-				LastUpdated: lub,
-			})
-			if count >= s.config.MaxNEP11Tokens {
-				break contract_loop
+			if balance.Sign() == 0 {
+				continue
 			}
+			amount = balance.String()
 		}
+		count++
+		res = append(res, result.NEP11TokenBalance{
+			ID:          hex.EncodeToString(id),
+			Amount:      amount,
+			LastUpdated: lub,
+		})
 	}
-	return bs, nil
+	return res, count
 }
 
 func (s *Server) invokeNEP11Properties(h util.Uint160, id []byte, bw *io.BufBinWriter) ([]stackitem.MapElement, error) {
-	item, finalize, err := s.invokeReadOnly(bw, h, "properties", id)
+	item, finalize, err := s.invokeReadOnly(bw, nil, h, "properties", id)
 	if err != nil {
 		return nil, err
 	}
@@ -1223,7 +1268,7 @@ func (s *Server) getNEP17Balances(ps params.Params) (any, *neorpc.Error) {
 	stateSyncPoint := lastUpdated[math.MinInt32]
 	bw := io.NewBufBinWriter()
 	for _, h := range s.chain.GetNEP17Contracts() {
-		balance, sym, dec, err := s.getNEP17TokenBalance(h, u, bw)
+		balance, sym, dec, err := s.getNEP17TokenBalance(h, u, bw, nil)
 		if err != nil {
 			continue
 		}
@@ -1254,15 +1299,177 @@ func (s *Server) getNEP17Balances(ps params.Params) (any, *neorpc.Error) {
 	return bs, nil
 }
 
-func (s *Server) invokeReadOnly(bw *io.BufBinWriter, h util.Uint160, method string, params ...any) (stackitem.Item, func(), error) {
-	r, f, err := s.invokeReadOnlyMulti(bw, h, []string{method}, [][]any{params})
+func (s *Server) getNEP11BalancesHistoric(ps params.Params) (any, *neorpc.Error) {
+	nextH, u, tokens, respErr := s.getHistoricTokens(ps, true)
+	if respErr != nil {
+		return nil, respErr
+	}
+
+	bs := &result.NEP11Balances{
+		Address:  address.Uint160ToString(u),
+		Balances: []result.NEP11AssetBalance{},
+	}
+	var count int
+	bw := io.NewBufBinWriter()
+	for _, t := range tokens {
+		h := t.cs.Hash
+		toks, sym, dec, err := s.getNEP11Tokens(h, u, bw, &nextH)
+		if err != nil {
+			continue
+		}
+		if len(toks) == 0 {
+			continue
+		}
+		isDivisible := (standard.ComplyABI(&t.cs.Manifest, standard.Nep11Divisible) == nil)
+		var balances []result.NEP11TokenBalance
+		balances, count = s.getNEP11TokenBalances(h, u, toks, isDivisible, t.lastUpdated, count, bw, &nextH)
+		bs.Balances = append(bs.Balances, result.NEP11AssetBalance{
+			Asset:    h,
+			Decimals: dec,
+			Name:     t.cs.Manifest.Name,
+			Symbol:   sym,
+			Tokens:   balances,
+		})
+		if count >= s.config.MaxNEP11Tokens {
+			break
+		}
+	}
+	return bs, nil
+}
+
+func (s *Server) getNEP17BalancesHistoric(ps params.Params) (any, *neorpc.Error) {
+	nextH, u, tokens, respErr := s.getHistoricTokens(ps, false)
+	if respErr != nil {
+		return nil, respErr
+	}
+
+	bs := &result.NEP17Balances{
+		Address:  address.Uint160ToString(u),
+		Balances: []result.NEP17Balance{},
+	}
+	bw := io.NewBufBinWriter()
+	for _, t := range tokens {
+		balance, sym, dec, err := s.getNEP17TokenBalance(t.cs.Hash, u, bw, &nextH)
+		if err != nil {
+			continue
+		}
+		if balance.Sign() == 0 {
+			continue
+		}
+		bs.Balances = append(bs.Balances, result.NEP17Balance{
+			Asset:       t.cs.Hash,
+			Amount:      balance.String(),
+			Decimals:    dec,
+			LastUpdated: t.lastUpdated,
+			Name:        t.cs.Manifest.Name,
+			Symbol:      sym,
+		})
+	}
+	return bs, nil
+}
+
+// historicToken is a token contract state along with the index of the latest
+// block with transfers of this token not exceeding the requested one.
+type historicToken struct {
+	cs          *state.Contract
+	lastUpdated uint32
+}
+
+// getHistoricTokens parses parameters of get*balanceshistoric calls and returns
+// the index of a fake next block to perform historic calls with, the account
+// and the list of NEP-11 or NEP-17 tokens this account had transfers of up to
+// the requested block. Contract states are taken from the requested block
+// state, tokens are ordered by contract ID.
+func (s *Server) getHistoricTokens(ps params.Params, isNEP11 bool) (uint32, util.Uint160, []historicToken, *neorpc.Error) {
+	nextH, respErr := s.getHistoricParams(ps)
+	if respErr != nil {
+		return 0, util.Uint160{}, nil, respErr
+	}
+	u, err := ps.Value(1).GetUint160FromAddressOrHex()
+	if err != nil {
+		return 0, util.Uint160{}, nil, neorpc.ErrInvalidParams
+	}
+
+	var (
+		height      = nextH - 1
+		ids         []int32
+		lastUpdated = make(map[int32]uint32)
+		handle      = func(tr *state.NEP17Transfer) (bool, error) {
+			if _, ok := lastUpdated[tr.Asset]; !ok && tr.Block <= height {
+				lastUpdated[tr.Asset] = tr.Block
+				ids = append(ids, tr.Asset)
+			}
+			return true, nil
+		}
+	)
+	hdr, err := s.chain.GetHeader(s.chain.GetHeaderHash(height))
+	if err != nil {
+		return 0, util.Uint160{}, nil, neorpc.NewInternalServerError(fmt.Sprintf("failed to get header %d: %s", height, err))
+	}
+	// Transfer log is ordered by timestamp, block timestamps always increase.
+	if isNEP11 {
+		err = s.chain.ForEachNEP11Transfer(u, hdr.Timestamp, func(tr *state.NEP11Transfer) (bool, error) {
+			return handle(&tr.NEP17Transfer)
+		})
+	} else {
+		err = s.chain.ForEachNEP17Transfer(u, hdr.Timestamp, handle)
+	}
+	if err != nil {
+		return 0, util.Uint160{}, nil, neorpc.NewInternalServerError(fmt.Sprintf("failed to get transfers: %s", err))
+	}
+
+	ic, err := s.chain.GetTestHistoricVM(trigger.Application, nil, nextH)
+	if err != nil {
+		return 0, util.Uint160{}, nil, neorpc.NewInternalServerError(fmt.Sprintf("failed to create historic VM: %s", err))
+	}
+	defer ic.Finalize()
+
+	// Transfers made before the state synchronisation point are not logged,
+	// so every known token is to be checked in this case.
+	info, err := s.chain.GetTokenLastUpdated(u)
+	if err != nil {
+		return 0, util.Uint160{}, nil, neorpc.NewInternalServerError(fmt.Sprintf("failed to get last updated blocks: %s", err))
+	}
+	if stateSyncPoint, ok := info[math.MinInt32]; ok {
+		var hashes = s.chain.GetNEP17Contracts()
+		if isNEP11 {
+			hashes = s.chain.GetNEP11Contracts()
+		}
+		for _, h := range hashes {
+			cs, err := native.GetContract(ic.DAO, s.chain.NativeManagementID(), h)
+			if err != nil {
+				continue
+			}
+			if _, ok := lastUpdated[cs.ID]; !ok {
+				lastUpdated[cs.ID] = min(stateSyncPoint, height)
+				ids = append(ids, cs.ID)
+			}
+		}
+	}
+
+	slices.Sort(ids)
+	tokens := make([]historicToken, 0, len(ids))
+	for _, id := range ids {
+		cs, err := native.GetContractByID(ic.DAO, s.chain.NativeManagementID(), id)
+		if err != nil {
+			continue // Not yet deployed or already destroyed.
+		}
+		tokens = append(tokens, historicToken{cs: cs, lastUpdated: lastUpdated[id]})
+	}
+	return nextH, u, tokens, nil
+}
+
+func (s *Server) invokeReadOnly(bw *io.BufBinWriter, nextH *uint32, h util.Uint160, method string, params ...any) (stackitem.Item, func(), error) {
+	r, f, err := s.invokeReadOnlyMulti(bw, nextH, h, []string{method}, [][]any{params})
 	if err != nil {
 		return nil, nil, err
 	}
 	return r[0], f, nil
 }
 
-func (s *Server) invokeReadOnlyMulti(bw *io.BufBinWriter, h util.Uint160, methods []string, params [][]any) ([]stackitem.Item, func(), error) {
+// invokeReadOnlyMulti calls the given read-only methods of the contract using
+// the latest state or the state of the block preceding nextH if it's not nil.
+func (s *Server) invokeReadOnlyMulti(bw *io.BufBinWriter, nextH *uint32, h util.Uint160, methods []string, params [][]any) ([]stackitem.Item, func(), error) {
 	if bw == nil {
 		bw = io.NewBufBinWriter()
 	} else {
@@ -1277,9 +1484,17 @@ func (s *Server) invokeReadOnlyMulti(bw *io.BufBinWriter, h util.Uint160, method
 			return nil, nil, fmt.Errorf("failed to create `%s` invocation script: %w", methods[i], bw.Err)
 		}
 	}
-	script := bw.Bytes()
-	tx := &transaction.Transaction{Script: script}
-	ic, err := s.chain.GetTestVM(trigger.Application, tx, nil)
+	var (
+		script = bw.Bytes()
+		tx     = &transaction.Transaction{Script: script}
+		ic     *interop.Context
+		err    error
+	)
+	if nextH == nil {
+		ic, err = s.chain.GetTestVM(trigger.Application, tx, nil)
+	} else {
+		ic, err = s.chain.GetTestHistoricVM(trigger.Application, tx, *nextH)
+	}
 	if err != nil {
 		return nil, nil, fmt.Errorf("faile to prepare test VM: %w", err)
 	}
@@ -1298,8 +1513,8 @@ func (s *Server) invokeReadOnlyMulti(bw *io.BufBinWriter, h util.Uint160, method
 	return estack.ToArray(), ic.Finalize, nil
 }
 
-func (s *Server) getNEP17TokenBalance(h util.Uint160, acc util.Uint160, bw *io.BufBinWriter) (*big.Int, string, int, error) {
-	items, finalize, err := s.invokeReadOnlyMulti(bw, h, []string{"balanceOf", "symbol", "decimals"}, [][]any{{acc}, nil, nil})
+func (s *Server) getNEP17TokenBalance(h util.Uint160, acc util.Uint160, bw *io.BufBinWriter, nextH *uint32) (*big.Int, string, int, error) {
+	items, finalize, err := s.invokeReadOnlyMulti(bw, nextH, h, []string{"balanceOf", "symbol", "decimals"}, [][]any{{acc}, nil, nil})
 	if err != nil {
 		return nil, "", 0, err
 	}
@@ -1322,8 +1537,8 @@ func (s *Server) getNEP17TokenBalance(h util.Uint160, acc util.Uint160, bw *io.B
 	return res, sym, int(dec.Int64()), nil
 }
 
-func (s *Server) getNEP11DTokenBalance(h util.Uint160, acc util.Uint160, id []byte, bw *io.BufBinWriter) (*big.Int, error) {
-	item, finalize, err := s.invokeReadOnly(bw, h, "balanceOf", acc, id)
+func (s *Server) getNEP11DTokenBalance(h util.Uint160, acc util.Uint160, id []byte, bw *io.BufBinWriter, nextH *uint32) (*big.Int, error) {
+	item, finalize, err := s.invokeReadOnly(bw, nextH, h, "balanceOf", acc, id)
 	if err != nil {
 		return nil, err
 	}
@@ -1385,6 +1600,89 @@ func getTimestampsAndLimit(ps params.Params, index int) (uint64, uint64, int, in
 	return start, end, limit, page, nil
 }
 
+func (s *Server) getAddressTransactions(ps params.Params) (any, *neorpc.Error) {
+	if !s.chain.GetConfig().Ledger.SaveAddressIndex {
+		return nil, neorpc.WrapErrorWithData(neorpc.ErrUnsupportedState, "'SaveAddressIndex' setting is disabled")
+	}
+	u, err := ps.Value(0).GetUint160FromAddressOrHex()
+	if err != nil {
+		return nil, neorpc.ErrInvalidParams
+	}
+
+	start, end, limit, page, err := getTimestampsAndLimit(ps, 1)
+	if err != nil {
+		return nil, neorpc.NewInvalidParamsError(fmt.Sprintf("malformed timestamps/limit: %s", err))
+	}
+	var (
+		seekEnd = end
+		// frameValue returns the value to be compared with start and end.
+		frameValue = func(_ uint32, timestamp uint64) uint64 { return timestamp }
+		frameCount int
+		res        = &result.AddressTransactions{
+			Address:      address.Uint160ToString(u),
+			Transactions: []result.AddressTransaction{},
+		}
+	)
+	if byHeight, _ := ps.Value(5).GetBoolean(); byHeight {
+		var current = uint64(s.chain.BlockHeight())
+		start, end = 0, current
+		for i, v := range []*uint64{&start, &end} {
+			p := ps.Value(1 + i)
+			if p == nil {
+				continue
+			}
+			h, err := p.GetInt()
+			if err == nil {
+				err = checkUint32(h)
+			}
+			if err != nil {
+				return nil, neorpc.NewInvalidParamsError(fmt.Sprintf("malformed heights: %s", err))
+			}
+			*v = uint64(h)
+		}
+		end = min(end, current)
+		if start > end {
+			return res, nil
+		}
+		// Block timestamps grow with height, so the search starts from
+		// the last transaction of the end block.
+		hdr, err := s.chain.GetHeader(s.chain.GetHeaderHash(uint32(end)))
+		if err != nil {
+			return nil, neorpc.NewInternalServerError(fmt.Sprintf("failed to get header %d: %s", end, err))
+		}
+		seekEnd = hdr.Timestamp
+		frameValue = func(index uint32, _ uint64) uint64 { return uint64(index) }
+	}
+
+	err = s.chain.SeekAddressTransactions(u, seekEnd, func(h util.Uint256, index uint32, timestamp uint64) bool {
+		v := frameValue(index, timestamp)
+		// Iterating from the newest to the oldest, not yet reached required
+		// frame, continue looping.
+		if v > end {
+			return true
+		}
+		// Moved past required frame, stop looping.
+		if v < start {
+			return false
+		}
+		frameCount++
+		// Using limits, not yet reached required page.
+		if page*limit >= frameCount {
+			return true
+		}
+		res.Transactions = append(res.Transactions, result.AddressTransaction{
+			TxHash:    h,
+			Index:     index,
+			Timestamp: timestamp,
+		})
+		return len(res.Transactions) < limit
+	})
+	if err != nil {
+		return nil, neorpc.NewInternalServerError(fmt.Sprintf("failed to search for transactions: %s", err))
+	}
+	return res, nil
+}
+
 func (s *Server) getNEP11Transfers(ps params.Params) (any, *neorpc.Error) {
 	return s.getTokenTransfers(ps, true)
 }
@@ -1960,6 +2258,143 @@ func (s *Server) getStorageHistoric(ps params.Params) (any, *neorpc.Error) {
 	return v, nil
 }
 
+func (s *Server) getStorageDiff(reqParams params.Params) (any, *neorpc.Error) {
+	if !s.chain.GetConfig().Ledger.SaveStorageDiffs {
+		return nil, neorpc.WrapErrorWithData(neorpc.ErrUnsupportedState, "'SaveStorageDiffs' setting is disabled")
+	}
+	hash, err := reqParams.Value(0).GetUint256()
+	if err != nil {
+		return nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, fmt.Sprintf("invalid block or transaction hash: %s", err))
+	}
+	diff, err := s.chain.GetStorageDiff(hash)
+	if err != nil {
+		return nil, neorpc.WrapErrorWithData(neorpc.ErrUnknownScriptContainer, fmt.Sprintf("failed to locate storage diff: %s", err))
+	}
+	var (
+		res = &result.StorageDiff{
+			Hash:    hash,
+			Added:   []result.StorageChange{},
+			Changed: []result.StorageChange{},
+			Deleted: []result.StorageChange{},
+		}
+		hashes = make(map[int32]*util.Uint160)
+	)
+	for _, c := range diff {
+		h, ok := hashes[c.ID]
+		if !ok {
+			if u, err := s.chain.GetContractScriptHash(c.ID); err == nil {
+				h = &u
+			}
+			hashes[c.ID] = h
+		}
+		change := result.StorageChange{
+			ID:       c.ID,
+			Contract: h,
+			Key:      c.Key,
+			OldValue: c.Old,
+			Value:    c.New,
+		}
+		switch {
+		case c.Old == nil:
+			res.Added = append(res.Added, change)
+		case c.New == nil:
+			res.Deleted = append(res.Deleted, change)
+		default:
+			res.Changed = append(res.Changed, change)
+		}
+	}
+	return res, nil
+}
+
+func (s *Server) findNotifications(reqParams params.Params) (any, *neorpc.Error) {
+	if !s.chain.GetConfig().Ledger.SaveNotificationIndex {
+		return nil, neorpc.WrapErrorWithData(neorpc.ErrUnsupportedState, "'SaveNotificationIndex' setting is disabled")
+	}
+	if len(reqParams) < 1 {
+		return nil, neorpc.ErrInvalidParams
+	}
+	var (
+		reader  = bytes.NewBuffer([]byte(reqParams[0].RawMessage))
+		decoder = json.NewDecoder(reader)
+		filter  = new(neorpc.NotificationFilter)
+	)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(filter); err != nil {
+		return nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, fmt.Sprintf("invalid filter: %s", err))
+	}
+	if err := filter.IsValid(); err != nil {
+		return nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, fmt.Sprintf("invalid filter: %s", err))
+	}
+	if filter.Contract == nil {
+		return nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, "invalid filter: contract is mandatory")
+	}
+
+	var (
+		from   uint32
+		to     = s.chain.BlockHeight()
+		cursor []byte
+	)
+	if len(reqParams) > 1 {
+		h, err := reqParams.Value(1).GetInt()
+		if err == nil {
+			err = checkUint32(h)
+		}
+		if err != nil {
+			return nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, fmt.Sprintf("invalid from: %s", err))
+		}
+		from = uint32(h)
+	}
+	if len(reqParams) > 2 {
+		h, err := reqParams.Value(2).GetInt()
+		if err == nil {
+			err = checkUint32(h)
+		}
+		if err != nil {
+			return nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, fmt.Sprintf("invalid to: %s", err))
+		}
+		to = uint32(h)
+	}
+	if from > to {
+		return nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, fmt.Sprintf("invalid range: %d > %d", from, to))
+	}
+	if len(reqParams) > 3 {
+		var err error
+		cursor, err = reqParams.Value(3).GetBytesBase64()
+		if err != nil {
+			return nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, fmt.Sprintf("invalid start: %s", err))
+		}
+	}
+
+	var (
+		scanned    int
+		last       []byte
+		comparator = &notificationEventComparator{filter: *filter}
+		res        = &result.FindNotifications{Results: make([]result.IndexedNotification, 0)}
+	)
+	err := s.chain.SeekNotifications(*filter.Contract, filter.Name, from, to, cursor, func(cur []byte, index uint32, ev *state.ContainedNotificationEvent) bool {
+		if len(res.Results) == s.config.MaxFindResultItems || scanned == s.config.MaxScannedNotifications {
+			res.Truncated = true
+			return false
+		}
+		scanned++
+		last = append(last[:0], cur...)
+		if rpcevent.Matches(comparator, &notificationEventContainer{ntf: ev}) {
+			res.Results = append(res.Results, result.IndexedNotification{
+				BlockIndex:                 index,
+				ContainedNotificationEvent: *ev,
+			})
+		}
+		return true
+	})
+	if err != nil {
+		return nil, neorpc.NewInternalServerError(fmt.Sprintf("failed to search for notifications: %s", err))
+	}
+	if res.Truncated {
+		res.Next = last
+	}
+	return res, nil
+}
+
 func (s *Server) getrawtransaction(reqParams params.Params) (any, *neorpc.Error) {
 	txHash, err := reqParams.Value(0).GetUint256()
 	if err != nil {
@@ -2164,11 +2599,11 @@ func (s *Server) getCommittee(_ params.Params) (any, *neorpc.Error) {
 
 // invokeFunction implements the `invokeFunction` RPC call.
 func (s *Server) invokeFunction(reqParams params.Params) (any, *neorpc.Error) {
-	tx, verbose, respErr := s.getInvokeFunctionParams(reqParams)
+	tx, verbose, overrides, respErr := s.getInvokeFunctionParams(reqParams)
 	if respErr != nil {
 		return nil, respErr
 	}
-	return s.runScriptInVM(trigger.Application, tx.Script, util.Uint160{}, tx, nil, nil, verbose)
+	return s.runScriptInVM(trigger.Application, tx.Script, util.Uint160{}, tx, nil, nil, verbose, overrides)
 }
 
 // invokeFunctionHistoric implements the `invokeFunctionHistoric` RPC call.
@@ -2180,24 +2615,24 @@ func (s *Server) invokeFunctionHistoric(reqParams params.Params) (any, *neorpc.E
 	if len(reqParams) < 2 {
 		return nil, neorpc.ErrInvalidParams
 	}
-	tx, verbose, respErr := s.getInvokeFunctionParams(reqParams[1:])
+	tx, verbose, overrides, respErr := s.getInvokeFunctionParams(reqParams[1:])
 	if respErr != nil {
 		return nil, respErr
 	}
-	return s.runScriptInVM(trigger.Application, tx.Script, util.Uint160{}, tx, nil, &nextH, verbose)
+	return s.runScriptInVM(trigger.Application, tx.Script, util.Uint160{}, tx, nil, &nextH, verbose, overrides)
 }
 
-func (s *Server) getInvokeFunctionParams(reqParams params.Params) (*transaction.Transaction, bool, *neorpc.Error) {
+func (s *Server) getInvokeFunctionParams(reqParams params.Params) (*transaction.Transaction, bool, *neorpc.StateOverrides, *neorpc.Error) {
 	if len(reqParams) < 2 {
-		return nil, false, neorpc.ErrInvalidParams
+		return nil, false, nil, neorpc.ErrInvalidParams
 	}
 	scriptHash, responseErr := s.contractScriptHashFromParam(reqParams.Value(0))
 	if responseErr != nil {
-		return nil, false, responseErr
+		return nil, false, nil, responseErr
 	}
 	method, err := reqParams[1].GetString()
 	if err != nil {
-		return nil, false, neorpc.ErrInvalidParams
+		return nil, false, nil, neorpc.ErrInvalidParams
 	}
 	var invparams *params.Param
 	if len(reqParams) > 2 {
@@ -2207,7 +2642,7 @@ func (s *Server) getInvokeFunctionParams(reqParams params.Params) (*transaction.
 	if len(reqParams) > 3 {
 		signers, _, err := reqParams[3].GetSignersWithWitnesses()
 		if err != nil {
-			return nil, false, neorpc.ErrInvalidParams
+			return nil, false, nil, neorpc.ErrInvalidParams
 		}
 		tx.Signers = signers
 	}
@@ -2215,7 +2650,14 @@ func (s *Server) getInvokeFunctionParams(reqParams params.Params) (*transaction.
 	if len(reqParams) > 4 {
 		verbose, err = reqParams[4].GetBoolean()
 		if err != nil {
-			return nil, false, neorpc.ErrInvalidParams
+			return nil, false, nil, neorpc.ErrInvalidParams
+		}
+	}
+	var overrides *neorpc.StateOverrides
+	if len(reqParams) > 5 {
+		overrides, err = reqParams[5].GetStateOverrides()
+		if err != nil {
+			return nil, false, nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, err.Error())
 		}
 	}
 	if len(tx.Signers) == 0 {
@@ -2223,19 +2665,19 @@ func (s *Server) getInvokeFunctionParams(reqParams params.Params) (*transaction.
 	}
 	script, err := params.CreateFunctionInvocationScript(scriptHash, method, invparams)
 	if err != nil {
-		return nil, false, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, fmt.Sprintf("can't create invocation script: %s", err))
+		return nil, false, nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, fmt.Sprintf("can't create invocation script: %s", err))
 	}
 	tx.Script = script
-	return tx, verbose, nil
+	return tx, verbose, overrides, nil
 }
 
 // invokescript implements the `invokescript` RPC call.
 func (s *Server) invokescript(reqParams params.Params) (any, *neorpc.Error) {
-	tx, verbose, respErr := s.getInvokeScriptParams(reqParams)
+	tx, verbose, overrides, respErr := s.getInvokeScriptParams(reqParams)
 	if respErr != nil {
 		return nil, respErr
 	}
-	return s.runScriptInVM(trigger.Application, tx.Script, util.Uint160{}, tx, nil, nil, verbose)
+	return s.runScriptInVM(trigger.Application, tx.Script, util.Uint160{}, tx, nil, nil, verbose, overrides)
 }
 
 // invokescripthistoric implements the `invokescripthistoric` RPC call.
@@ -2247,24 +2689,24 @@ func (s *Server) invokescripthistoric(reqParams params.Params) (any, *neorpc.Err
 	if len(reqParams) < 2 {
 		return nil, neorpc.ErrInvalidParams
 	}
-	tx, verbose, respErr := s.getInvokeScriptParams(reqParams[1:])
+	tx, verbose, overrides, respErr := s.getInvokeScriptParams(reqParams[1:])
 	if respErr != nil {
 		return nil, respErr
 	}
-	return s.runScriptInVM(trigger.Application, tx.Script, util.Uint160{}, tx, nil, &nextH, verbose)
+	return s.runScriptInVM(trigger.Application, tx.Script, util.Uint160{}, tx, nil, &nextH, verbose, overrides)
 }
 
-func (s *Server) getInvokeScriptParams(reqParams params.Params) (*transaction.Transaction, bool, *neorpc.Error) {
+func (s *Server) getInvokeScriptParams(reqParams params.Params) (*transaction.Transaction, bool, *neorpc.StateOverrides, *neorpc.Error) {
 	script, err := reqParams.Value(0).GetBytesBase64()
 	if err != nil {
-		return nil, false, neorpc.ErrInvalidParams
+		return nil, false, nil, neorpc.ErrInvalidParams
 	}
 
 	tx := &transaction.Transaction{}
 	if len(reqParams) > 1 {
 		signers, witnesses, err := reqParams[1].GetSignersWithWitnesses()
 		if err != nil {
-			return nil, false, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, err.Error())
+			return nil, false, nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, err.Error())
 		}
 		tx.Signers = signers
 		tx.Scripts = witnesses
@@ -2273,14 +2715,21 @@ func (s *Server) getInvokeScriptParams(reqParams params.Params) (*transaction.Tr
 	if len(reqParams) > 2 {
 		verbose, err = reqParams[2].GetBoolean()
 		if err != nil {
-			return nil, false, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, err.Error())
+			return nil, false, nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, err.Error())
+		}
+	}
+	var overrides *neorpc.StateOverrides
+	if len(reqParams) > 3 {
+		overrides, err = reqParams[3].GetStateOverrides()
+		if err != nil {
+			return nil, false, nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, err.Error())
 		}
 	}
 	if len(tx.Signers) == 0 {
 		tx.Signers = []transaction.Signer{{Account: util.Uint160{}, Scopes: transaction.None}}
 	}
 	tx.Script = script
-	return tx, verbose, nil
+	return tx, verbose, overrides, nil
 }
 
 func (s *Server) fakeTxFromParam(p *params.Param) (*transaction.Transaction, *neorpc.Error) {
@@ -2374,7 +2823,433 @@ func (s *Server) invokeContainedScript(reqParams params.Params) (any, *neorpc.Er
 		}
 	}
 
-	return s.runScriptInVM(trig, tx.Script, util.Uint160{}, tx, b, nil, verbose)
+	return s.runScriptInVM(trig, tx.Script, util.Uint160{}, tx, b, nil, verbose, nil)
+}
+
+// simulateTransactions implements the `simulatetransactions` RPC call.
+func (s *Server) simulateTransactions(reqParams params.Params) (any, *neorpc.Error) {
+	txes, verbose, overrides, respErr := s.getSimulateTransactionsParams(reqParams)
+	if respErr != nil {
+		return nil, respErr
+	}
+	return s.simulate(txes, nil, verbose, overrides)
+}
+
+// simulateTransactionsHistoric implements the `simulatetransactionshistoric`
+// RPC call.
+func (s *Server) simulateTransactionsHistoric(reqParams params.Params) (any, *neorpc.Error) {
+	nextH, respErr := s.getHistoricParams(reqParams)
+	if respErr != nil {
+		return nil, respErr
+	}
+	if len(reqParams) < 2 {
+		return nil, neorpc.ErrInvalidParams
+	}
+	txes, verbose, overrides, respErr := s.getSimulateTransactionsParams(reqParams[1:])
+	if respErr != nil {
+		return nil, respErr
+	}
+	return s.simulate(txes, &nextH, verbose, overrides)
+}
+
+func (s *Server) getSimulateTransactionsParams(reqParams params.Params) ([]*transaction.Transaction, bool, *neorpc.StateOverrides, *neorpc.Error) {
+	if len(reqParams) < 1 {
+		return nil, false, nil, neorpc.ErrInvalidParams
+	}
+	steps, err := reqParams[0].GetSimulationSteps()
+	if err != nil {
+		return nil, false, nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, err.Error())
+	}
+	if len(steps) > s.config.MaxSimulationSteps {
+		return nil, false, nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, fmt.Sprintf("too many simulation steps: %d, maximum is %d", len(steps), s.config.MaxSimulationSteps))
+	}
+	var verbose bool
+	if len(reqParams) > 1 {
+		verbose, err = reqParams[1].GetBoolean()
+		if err != nil {
+			return nil, false, nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, err.Error())
+		}
+	}
+	var overrides *neorpc.StateOverrides
+	if len(reqParams) > 2 {
+		overrides, err = reqParams[2].GetStateOverrides()
+		if err != nil {
+			return nil, false, nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, err.Error())
+		}
+	}
+	txes := make([]*transaction.Transaction, len(steps))
+	for i, step := range steps {
+		if step.Transaction != nil {
+			txes[i] = step.Transaction
+			continue
+		}
+		tx := &transaction.Transaction{
+			Script:  step.Script,
+			Signers: make([]transaction.Signer, len(step.Signers)),
+			Scripts: make([]transaction.Witness, len(step.Signers)),
+		}
+		for j := range step.Signers {
+			tx.Signers[j] = step.Signers[j].Signer
+			tx.Scripts[j] = step.Signers[j].Witness
+		}
+		if len(tx.Signers) == 0 {
+			tx.Signers = []transaction.Signer{{Account: util.Uint160{}, Scopes: transaction.None}}
+		}
+		txes[i] = tx
+	}
+	return txes, verbose, overrides, nil
+}
+
+// errSimulationGasExhausted is reported for simulation steps that are not
+// executed because MaxGasInvoke limit is exhausted by the preceding ones.
+var errSimulationGasExhausted = errors.New("not executed: MaxGasInvoke limit is exhausted")
+
+// simulate runs the given transactions one by one in the same test invocation
+// context, so that every transaction sees the changes made by the preceding
+// ones. Changes made by FAULTed transactions are discarded. MaxGasInvoke
+// limits the total amount of GAS consumed by all transactions, once it's
+// exhausted the remaining transactions are not executed.
+func (s *Server) simulate(txes []*transaction.Transaction, nextH *uint32, verbose bool, overrides *neorpc.StateOverrides) (*result.Simulation, *neorpc.Error) {
+	ic, respErr := s.prepareInvocationContext(trigger.Application, txes[0].Script, util.Uint160{}, txes[0], nil, nextH, verbose, overrides)
+	if respErr != nil {
+		return nil, respErr
+	}
+	var (
+		base = ic.DAO
+		res  = &result.Simulation{
+			Transactions: make([]result.SimulatedTransaction, 0, len(txes)),
+		}
+	)
+	for i, tx := range txes {
+		if res.GasConsumed >= int64(s.config.MaxGasInvoke) {
+			res.Transactions = append(res.Transactions, result.SimulatedTransaction{
+				Hash: tx.Hash(),
+				Result: result.Invoke{
+					State:          vmstate.None.String(),
+					Script:         tx.Script,
+					Stack:          []stackitem.Item{},
+					FaultException: errSimulationGasExhausted.Error(),
+					Notifications:  []state.NotificationEvent{},
+				},
+				StorageChanges:        []result.StorageChange{},
+				CumulativeGasConsumed: res.GasConsumed,
+			})
+			continue
+		}
+		if i != 0 {
+			ic.Tx = tx
+			ic.Container = tx
+			ic.Notifications = nil
+			ic.Invocations = make(map[util.Uint160]int)
+			ic.InvocationCalls = nil
+			ic.GetRandomCounter = 0
+			ic.InitNonceData()
+			ic.SpawnVM()
+			if verbose {
+				ic.VM.EnableInvocationTree()
+			}
+			ic.VM.LoadScriptWithFlags(tx.Script, callflag.All)
+		}
+		ic.DAO = base.GetPrivate()
+		ic.VM.GasLimit = int64(s.config.MaxGasInvoke) - res.GasConsumed
+		err := ic.VM.Run()
+		var faultException string
+		if err != nil {
+			faultException = err.Error()
+		}
+		items := ic.VM.Estack().ToArray()
+		for j := range items {
+			items[j] = s.dumpIterator(items[j])
+		}
+		ic.Finalize()
+
+		var (
+			tree    = ic.VM.GetInvocationTree()
+			diag    *result.InvokeDiag
+			changes = []result.StorageChange{}
+		)
+		if tree != nil {
+			diag = &result.InvokeDiag{
+				Invocations: tree.Calls,
+				Changes:     storage.BatchToOperations(ic.DAO.GetBatch()),
+			}
+		}
+		if err == nil {
+			diff := core.MakeStorageDiff(ic.DAO.Store.GetStorageChanges(), base.Store)
+			for _, c := range diff {
+				change := result.StorageChange{
+					ID:       c.ID,
+					Key:      c.Key,
+					OldValue: c.Old,
+					Value:    c.New,
+				}
+				if u, err := native.GetContractScriptHash(ic.DAO, ic.Chain.NativeManagementID(), c.ID); err == nil {
+					change.Contract = &u
+				}
+				changes = append(changes, change)
+			}
+			_, err = ic.DAO.Persist()
+			if err != nil {
+				return nil, neorpc.NewInternalServerError(fmt.Sprintf("failed to persist transaction #%d changes: %s", i, err))
+			}
+		}
+		notifications := ic.Notifications
+		if notifications == nil {
+			notifications = make([]state.NotificationEvent, 0)
+		}
+		res.GasConsumed += ic.VM.GasConsumed()
+		res.Transactions = append(res.Transactions, result.SimulatedTransaction{
+			Hash: tx.Hash(),
+			Result: result.Invoke{
+				State:          ic.VM.State().String(),
+				GasConsumed:    ic.VM.GasConsumed(),
+				Script:         tx.Script,
+				Stack:          items,
+				FaultException: faultException,
+				Notifications:  notifications,
+				Diagnostics:    diag,
+			},
+			StorageChanges:        changes,
+			CumulativeGasConsumed: res.GasConsumed,
+		})
+	}
+	return res, nil
+}
+
+// traceTransaction implements the `tracetransaction` RPC call.
+func (s *Server) traceTransaction(reqParams params.Params) (any, *neorpc.Error) {
+	if s.chain.GetConfig().Ledger.KeepOnlyLatestState {
+		return nil, neorpc.WrapErrorWithData(neorpc.ErrUnsupportedState, fmt.Sprintf("only latest state is supported: %s", errKeepOnlyLatestState))
+	}
+	txHash, err := reqParams.Value(0).GetUint256()
+	if err != nil {
+		return nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, fmt.Sprintf("invalid transaction hash: %s", err))
+	}
+	var withStack, withStorage bool
+	if len(reqParams) > 1 {
+		withStack, err = reqParams[1].GetBoolean()
+		if err != nil {
+			return nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, fmt.Sprintf("invalid stack parameter: %s", err))
+		}
+	}
+	if len(reqParams) > 2 {
+		withStorage, err = reqParams[2].GetBoolean()
+		if err != nil {
+			return nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, fmt.Sprintf("invalid storage parameter: %s", err))
+		}
+	}
+	_, height, err := s.chain.GetTransaction(txHash)
+	if err != nil || height == math.MaxUint32 {
+		return nil, neorpc.ErrUnknownTransaction
+	}
+	ic, err := s.chain.GetTxReplayVM(txHash)
+	if err != nil {
+		return nil, neorpc.NewInternalServerError(fmt.Sprintf("failed to create replay VM: %s", err))
+	}
+	t := newTxTracer(ic.VM, withStack, withStorage, s.config.MaxTraceSteps, s.config.MaxTraceSize)
+	err = ic.Exec()
+	t.settle()
+	res := &result.ExecutionTrace{
+		TxHash:      txHash,
+		State:       ic.VM.State().String(),
+		GasConsumed: ic.VM.GasConsumed(),
+		Steps:       t.steps,
+		Truncated:   t.truncated,
+	}
+	if err != nil {
+		res.FaultException = err.Error()
+	}
+	return res, nil
+}
+
+var (
+	storageGetID    = interopnames.ToID([]byte(interopnames.SystemStorageGet))
+	storagePutID    = interopnames.ToID([]byte(interopnames.SystemStoragePut))
+	storageDeleteID = interopnames.ToID([]byte(interopnames.SystemStorageDelete))
+)
+
+// traceStepSize is an estimated size of a trace step without stack and
+// storage data.
+const traceStepSize = 64
+
+// txTracer collects per-instruction execution trace of the VM. Collection
+// stops once the number of steps or their estimated size exceeds the limits,
+// execution continues then, but nothing is recorded.
+type txTracer struct {
+	v         *vm.VM
+	steps     []result.TraceStep
+	lastGas   int64
+	stack     bool
+	maxSteps  int
+	maxSize   int
+	size      int
+	truncated bool
+	settled   bool // Last step is settled after truncation.
+}
+
+// newTxTracer attaches a new tracer to the given VM that is not yet started.
+// If storage accesses are to be traced, VM's syscall handler is wrapped.
+func newTxTracer(v *vm.VM, withStack bool, withStorage bool, maxSteps int, maxSize int) *txTracer {
+	t := &txTracer{
+		v:        v,
+		steps:    make([]result.TraceStep, 0),
+		stack:    withStack,
+		maxSteps: maxSteps,
+		maxSize:  maxSize,
+	}
+	v.SetOnExecHook(t.onExec)
+	if withStorage {
+		handler := v.SyscallHandler
+		v.SyscallHandler = func(v *vm.VM, id uint32) error {
+			var acc *result.StorageAccess
+			if id == storageGetID || id == storagePutID || id == storageDeleteID {
+				acc = peekStorageAccess(v.Estack(), id)
+			}
+			err := handler(v, id)
+			if acc != nil && err == nil && len(t.steps) != 0 && !t.truncated {
+				if id == storageGetID && v.Estack().Len() != 0 {
+					if item := v.Estack().Peek(0).Item(); item.Type() != stackitem.AnyT {
+						acc.Value, _ = item.TryBytes()
+					}
+				}
+				if !t.grow(traceStepSize + len(acc.Key) + len(acc.Value)) {
+					return err
+				}
+				step := &t.steps[len(t.steps)-1]
+				step.Storage = append(step.Storage, *acc)
+			}
+			return err
+		}
+	}
+	return t
+}
+
+// onExec implements vm.OnExecHook.
+func (t *txTracer) onExec(h util.Uint160, ip int, op opcode.Opcode) {
+	t.settle()
+	if t.truncated || len(t.steps) >= t.maxSteps {
+		t.truncated, t.settled = true, true
+		return
+	}
+	step := result.TraceStep{
+		Contract: h,
+		IP:       ip,
+		Opcode:   op,
+	}
+	var size = traceStepSize
+	if t.stack {
+		var (
+			estack = t.v.Estack()
+			seen   = make(map[stackitem.Item]bool)
+		)
+		estack.Iter(func(e vm.Element) {
+			size += traceItemSize(e.Item(), seen)
+		})
+	}
+	if !t.grow(size) {
+		t.settled = true
+		return
+	}
+	if t.stack {
+		estack := t.v.Estack()
+		step.Stack = make([]stackitem.Item, 0, estack.Len())
+		estack.Iter(func(e vm.Element) {
+			// Items can be changed by subsequent instructions.
+			step.Stack = append(step.Stack, stackitem.DeepCopy(e.Item(), false))
+		})
+	}
+	t.steps = append(t.steps, step)
+}
+
+// grow accounts the given amount of data to the trace size, it returns false
+// and marks the trace as truncated if the limit is exceeded.
+func (t *txTracer) grow(size int) bool {
+	if t.size+size > t.maxSize {
+		t.truncated = true
+		return false
+	}
+	t.size += size
+	return true
+}
+
+// traceItemSize returns an estimated size of the stack item copy, items that
+// are already seen are not accounted again.
+func traceItemSize(item stackitem.Item, seen map[stackitem.Item]bool) int {
+	switch it := item.(type) {
+	case *stackitem.Array, *stackitem.Struct, *stackitem.Map:
+		if seen[item] {
+			return 0
+		}
+		seen[item] = true
+		var size = traceStepSize
+		if m, ok := it.(*stackitem.Map); ok {
+			for _, e := range m.Value().([]stackitem.MapElement) {
+				size += traceItemSize(e.Key, seen) + traceItemSize(e.Value, seen)
+			}
+			return size
+		}
+		for _, e := range it.Value().([]stackitem.Item) {
+			size += traceItemSize(e, seen)
+		}
+		return size
+	case *stackitem.ByteArray, *stackitem.Buffer:
+		b, _ := it.TryBytes()
+		return traceStepSize + len(b)
+	case *stackitem.BigInteger:
+		return traceStepSize + len(it.Bytes())
+	default:
+		return traceStepSize
+	}
+}
+
+// settle accounts GAS consumed since the start of the last traced instruction
+// to this instruction.
+func (t *txTracer) settle() {
+	gas := t.v.GasConsumed()
+	if len(t.steps) != 0 && !t.settled {
+		t.steps[len(t.steps)-1].GasConsumed = gas - t.lastGas
+		t.settled = t.truncated
+	}
+	t.lastGas = gas
+}
+
+// peekStorageAccess returns storage access description for the storage
+// syscall with the given ID based on its arguments, nil is returned if
+// arguments are not valid (syscall will fail then).
+func peekStorageAccess(estack *vm.Stack, id uint32) *result.StorageAccess {
+	var argc = 2
+	if id == storagePutID {
+		argc = 3
+	}
+	if estack.Len() < argc {
+		return nil
+	}
+	stc, ok := estack.Peek(0).Value().(*istorage.Context)
+	if !ok {
+		return nil
+	}
+	key, err := estack.Peek(1).Item().TryBytes()
+	if err != nil {
+		return nil
+	}
+	acc := &result.StorageAccess{
+		ID:  stc.ID,
+		Key: bytes.Clone(key),
+	}
+	switch id {
+	case storageGetID:
+		acc.Operation = "get"
+	case storagePutID:
+		acc.Operation = "put"
+		acc.Value, err = estack.Peek(2).Item().TryBytes()
+		if err != nil {
+			return nil
+		}
+		acc.Value = bytes.Clone(acc.Value)
+	case storageDeleteID:
+		acc.Operation = "delete"
+	}
+	return acc
 }
 
 // invokeContractVerify implements the `invokecontractverify` RPC call.
@@ -2383,7 +3258,7 @@ func (s *Server) invokeContractVerify(reqParams params.Params) (any, *neorpc.Err
 	if respErr != nil {
 		return nil, respErr
 	}
-	return s.runScriptInVM(trigger.Verification, invocationScript, scriptHash, tx, nil, nil, false)
+	return s.runScriptInVM(trigger.Verification, invocationScript, scriptHash, tx, nil, nil, false, nil)
 }
 
 // invokeContractVerifyHistoric implements the `invokecontractverifyhistoric` RPC call.
@@ -2399,7 +3274,7 @@ func (s *Server) invokeContractVerifyHistoric(reqParams params.Params) (any, *ne
 	if respErr != nil {
 		return nil, respErr
 	}
-	return s.runScriptInVM(trigger.Verification, invocationScript, scriptHash, tx, nil, &nextH, false)
+	return s.runScriptInVM(trigger.Verification, invocationScript, scriptHash, tx, nil, &nextH, false, nil)
 }
 
 func (s *Server) getInvokeContractVerifyParams(reqParams params.Params) (util.Uint160, *transaction.Transaction, []byte, *neorpc.Error) {
@@ -2469,7 +3344,7 @@ func (s *Server) getHistoricParams(reqParams params.Params) (uint32, *neorpc.Err
 	return height + 1, nil
 }
 
-func (s *Server) prepareInvocationContext(t trigger.Type, script []byte, contractScriptHash util.Uint160, tx *transaction.Transaction, b *block.Block, nextH *uint32, verbose bool) (*interop.Context, *neorpc.Error) {
+func (s *Server) prepareInvocationContext(t trigger.Type, script []byte, contractScriptHash util.Uint160, tx *transaction.Transaction, b *block.Block, nextH *uint32, verbose bool, overrides *neorpc.StateOverrides) (*interop.Context, *neorpc.Error) {
 	var (
 		err error
 		ic  *interop.Context
@@ -2485,6 +3360,15 @@ func (s *Server) prepareInvocationContext(t trigger.Type, script []byte, contrac
 			return nil, neorpc.NewInternalServerError(fmt.Sprintf("failed to create historic VM: %s", err))
 		}
 	}
+	if overrides != nil {
+		err = applyStateOverrides(ic, overrides)
+		if err != nil {
+			ic.Finalize()
+			return nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, fmt.Sprintf("invalid state overrides: %s", err))
+		}
+		// Keep overrides out of the storage changes made by the invocation.
+		ic.DAO = ic.DAO.GetPrivate()
+	}
 	if verbose {
 		ic.VM.EnableInvocationTree()
 	}
@@ -2513,14 +3397,84 @@ func (s *Server) prepareInvocationContext(t trigger.Type, script []byte, contrac
 	return ic, nil
 }
 
+// applyStateOverrides changes the state of the given invocation context
+// according to the overrides.
+func applyStateOverrides(ic *interop.Context, o *neorpc.StateOverrides) error {
+	type (
+		contractSetter interface {
+			SetContract(ic *interop.Context, hash util.Uint160, neff *nef.File, manif *manifest.Manifest) (*state.Contract, error)
+		}
+		balanceSetter interface {
+			SetBalance(ic *interop.Context, h util.Uint160, amount *big.Int) error
+		}
+	)
+	var getNative = func(h util.Uint160) interop.Contract {
+		for _, n := range ic.Natives {
+			if n.Metadata().Hash.Equals(h) {
+				return n
+			}
+		}
+		return nil
+	}
+	for i, c := range o.Contracts {
+		var neff *nef.File
+		if c.NEF != nil {
+			f, err := nef.FileFromBytes(c.NEF)
+			if err != nil {
+				return fmt.Errorf("contract #%d: invalid NEF: %w", i, err)
+			}
+			neff = &f
+		}
+		mgmt, ok := getNative(nativehashes.ContractManagement).(contractSetter)
+		if !ok {
+			return errors.New("contract overrides are not supported")
+		}
+		_, err := mgmt.SetContract(ic, c.Hash, neff, c.Manifest)
+		if err != nil {
+			return fmt.Errorf("contract #%d: %w", i, err)
+		}
+	}
+	for i, si := range o.Storage {
+		cs, err := ic.GetContract(si.Contract)
+		if err != nil {
+			return fmt.Errorf("storage item #%d: unknown contract %s", i, si.Contract.StringLE())
+		}
+		if len(si.Key) > limits.MaxStorageKeyLen {
+			return fmt.Errorf("storage item #%d: key is too big", i)
+		}
+		if si.Value == nil {
+			ic.DAO.DeleteStorageItem(cs.ID, si.Key)
+			continue
+		}
+		if len(si.Value) > limits.MaxStorageValueLen {
+			return fmt.Errorf("storage item #%d: value is too big", i)
+		}
+		ic.DAO.PutStorageItem(cs.ID, si.Key, si.Value)
+	}
+	for i, b := range o.Balances {
+		token, ok := getNative(b.Asset).(balanceSetter)
+		if !ok {
+			return fmt.Errorf("balance #%d: asset %s doesn't support balance overrides", i, b.Asset.StringLE())
+		}
+		if b.Amount == nil {
+			return fmt.Errorf("balance #%d: missing amount", i)
+		}
+		err := token.SetBalance(ic, b.Account, b.Amount)
+		if err != nil {
+			return fmt.Errorf("balance #%d: %w", i, err)
+		}
+	}
+	return nil
+}
+
 // runScriptInVM runs the given script in a new test VM and returns the invocation
 // result. The script is either a simple transaction's script in case of
 // `application` trigger or witness invocation script in case of `verification`
 // trigger (it pushes `verify` arguments on stack before verification). If block
 // is specified, it will be used to set up execution container parameters. In case
 // of contract verification contractScriptHash should be specified.
-func (s *Server) runScriptInVM(t trigger.Type, script []byte, contractScriptHash util.Uint160, tx *transaction.Transaction, b *block.Block, nextH *uint32, verbose bool) (*result.Invoke, *neorpc.Error) {
-	ic, respErr := s.prepareInvocationContext(t, script, contractScriptHash, tx, b, nextH, verbose)
+func (s *Server) runScriptInVM(t trigger.Type, script []byte, contractScriptHash util.Uint160, tx *transaction.Transaction, b *block.Block, nextH *uint32, verbose bool, overrides *neorpc.StateOverrides) (*result.Invoke, *neorpc.Error) {
+	ic, respErr := s.prepareInvocationContext(t, script, contractScriptHash, tx, b, nextH, verbose, overrides)
 	if respErr != nil {
 		return nil, respErr
 	}
@@ -2539,7 +3493,7 @@ func (s *Server) runScriptInVM(t trigger.Type, script []byte, contractScriptHash
 		if s.config.SessionBackedByMPT && nextH == nil {
 			ic.Finalize()
 			// Rerun with MPT-backed storage.
-			return s.runScriptInVM(t, script, contractScriptHash, tx, b, &ic.Block.Index, verbose)
+			return s.runScriptInVM(t, script, contractScriptHash, tx, b, &ic.Block.Index, verbose, overrides)
 		}
 		id = uuid.New()
 		sessionID := id.String()
@@ -2622,6 +3576,18 @@ func (s *Server) postProcessExecStack(stack []stackitem.Item) *session {
 	return nil
 }
 
+// dumpIterator changes iterator interop stack item into result.Iterator
+// interop stack item containing iterator values (up to MaxIteratorResultItems).
+// All the other stack items are not changed.
+func (s *Server) dumpIterator(item stackitem.Item) stackitem.Item {
+	if (item.Type() != stackitem.InteropT) || !iterator.IsIterator(item) {
+		return item
+	}
+	var resIterator result.Iterator
+	resIterator.Values, resIterator.Truncated, _ = iterator.ValuesTruncated(item, s.config.MaxIteratorResultItems)
+	return stackitem.NewInterop(resIterator)
+}
+
 // registerOrDumpIterator changes iterator interop stack items into result.Iterator
 // interop stack items and returns a uuid for it if sessions are enabled. All the other stack
 // items are not changed. The third return value is the current iterator value if it's not nil.
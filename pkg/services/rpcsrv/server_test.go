@@ -39,6 +39,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
 	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
+	"github.com/nspcc-dev/neo-go/pkg/encoding/fixedn"
 	"github.com/nspcc-dev/neo-go/pkg/io"
 	"github.com/nspcc-dev/neo-go/pkg/neorpc"
 	"github.com/nspcc-dev/neo-go/pkg/neorpc/result"
@@ -47,6 +48,8 @@ import (
 	rpc2 "github.com/nspcc-dev/neo-go/pkg/services/oracle/broadcaster"
 	"github.com/nspcc-dev/neo-go/pkg/services/rpcsrv/params"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/nef"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/trigger"
 	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
@@ -111,6 +114,12 @@ const (
 	// storageContractHash is an LE hash of "Storage" contract deployed at block #22
 	// of basic testing chain.
 	storageContractHash = "ebc0c16a76c808cd4dde6bcc063f09e45e331ec7"
+	// storageDeploymentTxHash is an LE hash of transaction that deploys "Storage"
+	// contract at block #22 of basic testing chain.
+	storageDeploymentTxHash = "68c85cc17a6c8381c9b982578865572b356a39bbe53dbb4aa686e4a62ea10288"
+	// lastBlock16TxHash is an LE hash of the last transaction of block #16 of
+	// basic testing chain (there are four of them in this block).
+	lastBlock16TxHash = "aed1682fb1db897b8f99aeb84d7234a9605909b86ec62fe7081d19fbc306f7ee"
 	// faultedTxHashLE is an LE hash of FAULTed transaction accepted at block #23 of
 	// basic testing chain.
 	faultedTxHashLE = "82279bfe9bada282ca0f8cb8e0bb124b921af36f00c69a518320322c6f4fef60"
@@ -146,8 +155,135 @@ var (
 	// nfsoToken1ContainerID contains data used to mint the first NFSO token in the
 	// basic testing chain (see [nfsoToken1ID] documentation).
 	nfsoToken1ObjectID = util.Uint256{4, 5, 6}
+	// overrideAccount is an account used in state overrides tests, it has 100500
+	// GAS, 1000 NEO and 42 Rubles with testStateOverrides.
+	overrideAccount = util.Uint160{0xaa, 0xbb}
+	// overrideContractHash is a hash of the contract created with
+	// testStateOverrides, its "get" method returns 7.
+	overrideContractHash = util.Uint160{1, 2, 3}
+	// testStateOverrides is a JSON-encoded neorpc.StateOverrides parameter.
+	testStateOverrides = newTestStateOverrides()
+	// overrideGASBalanceScript is a base64-encoded script returning the GAS
+	// balance of overrideAccount.
+	overrideGASBalanceScript = base64.StdEncoding.EncodeToString(
+		fixScript(smartcontract.CreateCallScript(nativehashes.GasToken, "balanceOf", overrideAccount)))
+	// simulationRecipient is an account receiving GAS in testSimulationSteps.
+	simulationRecipient = util.Uint160{0xcc, 0xdd}
+	// testSimulationSteps is a JSON-encoded list of neorpc.SimulationStep
+	// to be used with testStateOverrides: 500 GAS is transferred from
+	// overrideAccount to simulationRecipient, then recipient balance is
+	// checked, then a FAULTed transfer back is performed (as a complete
+	// transaction) and recipient balance is checked again.
+	testSimulationSteps = newTestSimulationSteps()
 )
 
+// newTestSimulationSteps returns testSimulationSteps value.
+func newTestSimulationSteps() string {
+	var (
+		balanceScript = fixScript(smartcontract.CreateCallScript(nativehashes.GasToken, "balanceOf", simulationRecipient))
+		transferBack  = append(fixScript(smartcontract.CreateCallWithAssertScript(nativehashes.GasToken, "transfer",
+			simulationRecipient, overrideAccount, 100, nil)), byte(opcode.ABORT))
+	)
+	tx := transaction.New(transferBack, 0)
+	tx.ValidUntilBlock = 1
+	tx.Signers = []transaction.Signer{{Account: simulationRecipient, Scopes: transaction.CalledByEntry}}
+	tx.Scripts = []transaction.Witness{{}}
+	data, err := json.Marshal([]neorpc.SimulationStep{
+		{
+			Script: fixScript(smartcontract.CreateCallWithAssertScript(nativehashes.GasToken, "transfer",
+				overrideAccount, simulationRecipient, 500, nil)),
+			Signers: []neorpc.SignerWithWitness{{
+				Signer: transaction.Signer{Account: overrideAccount, Scopes: transaction.CalledByEntry},
+			}},
+		},
+		{Script: balanceScript},
+		{Transaction: tx},
+		{Script: balanceScript},
+	})
+	if err != nil {
+		panic(err)
+	}
+	return string(data)
+}
+
+// newTestStateOverrides returns testStateOverrides value.
+func newTestStateOverrides() string {
+	script := []byte{byte(opcode.PUSH7), byte(opcode.RET)}
+	ne, err := nef.NewFile(script)
+	if err != nil {
+		panic(err)
+	}
+	rawNEF, err := ne.Bytes()
+	if err != nil {
+		panic(err)
+	}
+	m := manifest.DefaultManifest("Override")
+	m.ABI.Methods = []manifest.Method{{
+		Name:       "get",
+		Parameters: []manifest.Parameter{},
+		ReturnType: smartcontract.IntegerType,
+		Safe:       true,
+	}}
+	data, err := json.Marshal(&neorpc.StateOverrides{
+		Contracts: []neorpc.ContractOverride{{Hash: overrideContractHash, NEF: rawNEF, Manifest: m}},
+		Storage:   []neorpc.StorageOverride{{Contract: testContractHash, Key: overrideAccount.BytesBE(), Value: []byte{42}}},
+		Balances: []neorpc.BalanceOverride{
+			{Asset: nativehashes.GasToken, Account: overrideAccount, Amount: big.NewInt(100500)},
+			{Asset: nativehashes.NeoToken, Account: overrideAccount, Amount: big.NewInt(1000)},
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+	return string(data)
+}
+
+// fixScript panics on script creation error.
+func fixScript(script []byte, err error) []byte {
+	if err != nil {
+		panic(err)
+	}
+	return script
+}
+
+// checkTestSimulation checks the result of testSimulationSteps simulation
+// with testStateOverrides applied.
+func checkTestSimulation(t *testing.T, res *result.Simulation, verbose bool) {
+	require.Equal(t, 4, len(res.Transactions))
+	var cumulative int64
+	for i, tx := range res.Transactions {
+		cumulative += tx.Result.GasConsumed
+		require.Equal(t, cumulative, tx.CumulativeGasConsumed, i)
+		require.Equal(t, verbose, tx.Result.Diagnostics != nil, i)
+	}
+	require.Equal(t, cumulative, res.GasConsumed)
+
+	transfer := res.Transactions[0]
+	require.Equal(t, "HALT", transfer.Result.State, transfer.Result.FaultException)
+	require.Equal(t, 1, len(transfer.Result.Notifications))
+	require.Equal(t, "Transfer", transfer.Result.Notifications[0].Name)
+	require.Equal(t, 2, len(transfer.StorageChanges))
+	for _, c := range transfer.StorageChanges {
+		require.Equal(t, nativehashes.GasToken, *c.Contract)
+		require.NotNil(t, c.Value)
+	}
+	// Sender balance is set by overrides, recipient balance is created.
+	require.NotNil(t, transfer.StorageChanges[0].OldValue)
+	require.Nil(t, transfer.StorageChanges[1].OldValue)
+
+	require.Equal(t, "HALT", res.Transactions[1].Result.State)
+	require.Equal(t, []stackitem.Item{stackitem.Make(500)}, res.Transactions[1].Result.Stack)
+	require.Equal(t, 0, len(res.Transactions[1].StorageChanges))
+
+	back := res.Transactions[2]
+	require.Equal(t, "FAULT", back.Result.State)
+	require.Equal(t, 0, len(back.StorageChanges))
+
+	require.Equal(t, "HALT", res.Transactions[3].Result.State)
+	require.Equal(t, []stackitem.Item{stackitem.Make(500)}, res.Transactions[3].Result.Stack)
+	require.Equal(t, res.Transactions[1].Hash, res.Transactions[3].Hash)
+}
+
 var rpcFunctionsWithUnsupportedStatesTestCases = map[string][]rpcTestCase{
 	"getproof": {
 		{
@@ -197,6 +333,30 @@ var rpcFunctionsWithUnsupportedStatesTestCases = map[string][]rpcTestCase{
 			errCode: neorpc.ErrUnsupportedStateCode,
 		},
 	},
+	"simulatetransactionshistoric": {
+		{
+			name:    "unsupported state",
+			params:  `[]`,
+			fail:    true,
+			errCode: neorpc.ErrUnsupportedStateCode,
+		},
+	},
+	"getnep11balanceshistoric": {
+		{
+			name:    "unsupported state",
+			params:  `[20, "` + testchain.PrivateKeyByID(0).Address() + `"]`,
+			fail:    true,
+			errCode: neorpc.ErrUnsupportedStateCode,
+		},
+	},
+	"getnep17balanceshistoric": {
+		{
+			name:    "unsupported state",
+			params:  `[20, "` + testchain.PrivateKeyByID(0).Address() + `"]`,
+			fail:    true,
+			errCode: neorpc.ErrUnsupportedStateCode,
+		},
+	},
 }
 
 var rpcTestCases = map[string][]rpcTestCase{
@@ -353,6 +513,38 @@ var rpcTestCases = map[string][]rpcTestCase{
 			errCode: neorpc.InvalidParamsCode,
 		},
 	},
+	"getnep11balanceshistoric": {
+		{
+			name:    "no params",
+			params:  `[]`,
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
+		{
+			name:    "no address",
+			params:  `[20]`,
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
+		{
+			name:    "invalid address",
+			params:  `[20, "notahex"]`,
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
+		{
+			name:    "unknown block",
+			params:  `[100500, "` + address.Uint160ToString(testchain.PrivateKeyByID(0).GetScriptHash()) + `"]`,
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
+		{
+			name:   "positive, by index",
+			params: `[` + strconv.Itoa(int(faultedTxBlock)) + `, "` + address.Uint160ToString(testchain.PrivateKeyByID(0).GetScriptHash()) + `"]`,
+			result: func(e *executor) any { return &result.NEP11Balances{} },
+			check:  checkNep11Balances,
+		},
+	},
 	"getnep11balances": {
 		{
 			name:    "no params",
@@ -448,6 +640,38 @@ var rpcTestCases = map[string][]rpcTestCase{
 			check:  checkNep11Transfers,
 		},
 	},
+	"getnep17balanceshistoric": {
+		{
+			name:    "no params",
+			params:  `[]`,
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
+		{
+			name:    "no address",
+			params:  `[20]`,
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
+		{
+			name:    "invalid address",
+			params:  `[20, "notahex"]`,
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
+		{
+			name:    "unknown block",
+			params:  `[100500, "` + address.Uint160ToString(testchain.PrivateKeyByID(0).GetScriptHash()) + `"]`,
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
+		{
+			name:   "positive, by index",
+			params: `[` + strconv.Itoa(int(faultedTxBlock)) + `, "` + address.Uint160ToString(testchain.PrivateKeyByID(0).GetScriptHash()) + `"]`,
+			result: func(e *executor) any { return &result.NEP17Balances{} },
+			check:  checkNep17Balances,
+		},
+	},
 	"getnep17balances": {
 		{
 			name:    "no params",
@@ -712,6 +936,30 @@ var rpcTestCases = map[string][]rpcTestCase{
 			errCode: neorpc.InvalidParamsCode,
 		},
 	},
+	"getstoragediff": {
+		{
+			name:    "disabled",
+			params:  `["` + deploymentTxHash + `"]`,
+			fail:    true,
+			errCode: neorpc.ErrUnsupportedStateCode,
+		},
+	},
+	"getaddresstransactions": {
+		{
+			name:    "disabled",
+			params:  `["` + testchain.PrivateKeyByID(0).Address() + `"]`,
+			fail:    true,
+			errCode: neorpc.ErrUnsupportedStateCode,
+		},
+	},
+	"findnotifications": {
+		{
+			name:    "disabled",
+			params:  `[{"contract":"0x` + testContractHashLE + `"}]`,
+			fail:    true,
+			errCode: neorpc.ErrUnsupportedStateCode,
+		},
+	},
 	"getstoragehistoric": {
 		{
 			name:   "positive",
@@ -1415,6 +1663,86 @@ var rpcTestCases = map[string][]rpcTestCase{
 		},
 	},
 	"invokefunction": {
+		{
+			name:   "positive, with balance override",
+			params: `["` + nativehashes.GasToken.StringLE() + `", "balanceOf", [{"type":"Hash160", "value":"` + overrideAccount.StringLE() + `"}], [], false, ` + testStateOverrides + `]`,
+			result: func(e *executor) any { return &result.Invoke{} },
+			check: func(t *testing.T, e *executor, inv any) {
+				res, ok := inv.(*result.Invoke)
+				require.True(t, ok)
+				require.Equal(t, "HALT", res.State, res.FaultException)
+				require.Equal(t, []stackitem.Item{stackitem.Make(100500)}, res.Stack)
+			},
+		},
+		{
+			name:   "positive, with NEO balance override",
+			params: `["` + nativehashes.NeoToken.StringLE() + `", "balanceOf", [{"type":"Hash160", "value":"` + overrideAccount.StringLE() + `"}], [], false, ` + testStateOverrides + `]`,
+			result: func(e *executor) any { return &result.Invoke{} },
+			check: func(t *testing.T, e *executor, inv any) {
+				res, ok := inv.(*result.Invoke)
+				require.True(t, ok)
+				require.Equal(t, "HALT", res.State, res.FaultException)
+				require.Equal(t, []stackitem.Item{stackitem.Make(1000)}, res.Stack)
+			},
+		},
+		{
+			name:   "positive, with storage override",
+			params: `["` + testContractHashLE + `", "balanceOf", [{"type":"Hash160", "value":"` + overrideAccount.StringLE() + `"}], [], false, ` + testStateOverrides + `]`,
+			result: func(e *executor) any { return &result.Invoke{} },
+			check: func(t *testing.T, e *executor, inv any) {
+				res, ok := inv.(*result.Invoke)
+				require.True(t, ok)
+				require.Equal(t, "HALT", res.State, res.FaultException)
+				require.Equal(t, []stackitem.Item{stackitem.Make(42)}, res.Stack)
+			},
+		},
+		{
+			name:   "positive, with contract override",
+			params: `["` + overrideContractHash.StringLE() + `", "get", [], [], true, ` + testStateOverrides + `]`,
+			result: func(e *executor) any { return &result.Invoke{} },
+			check: func(t *testing.T, e *executor, inv any) {
+				res, ok := inv.(*result.Invoke)
+				require.True(t, ok)
+				require.Equal(t, "HALT", res.State, res.FaultException)
+				require.Equal(t, []stackitem.Item{stackitem.Make(7)}, res.Stack)
+				require.NotNil(t, res.Diagnostics)
+				require.Empty(t, res.Diagnostics.Changes) // Overrides are not included.
+			},
+		},
+		{
+			name:   "positive, null state overrides",
+			params: `["` + overrideContractHash.StringLE() + `", "get", [], [], false, null]`,
+			result: func(e *executor) any { return &result.Invoke{} },
+			check: func(t *testing.T, e *executor, inv any) {
+				res, ok := inv.(*result.Invoke)
+				require.True(t, ok)
+				require.Equal(t, "FAULT", res.State)
+			},
+		},
+		{
+			name:    "invalid state overrides",
+			params:  `["` + overrideContractHash.StringLE() + `", "get", [], [], false, "overrides"]`,
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
+		{
+			name:    "invalid balance override",
+			params:  `["` + testContractHashLE + `", "balanceOf", [{"type":"Hash160", "value":"` + overrideAccount.StringLE() + `"}], [], false, {"balances":[{"asset":"` + testContractHashLE + `","account":"` + overrideAccount.StringLE() + `","amount":"1"}]}]`,
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
+		{
+			name:    "storage override for unknown contract",
+			params:  `["` + testContractHashLE + `", "balanceOf", [{"type":"Hash160", "value":"` + overrideAccount.StringLE() + `"}], [], false, {"storage":[{"contract":"` + overrideContractHash.StringLE() + `","key":"AQ==","value":"AQ=="}]}]`,
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
+		{
+			name:    "native contract override",
+			params:  `["` + testContractHashLE + `", "balanceOf", [{"type":"Hash160", "value":"` + overrideAccount.StringLE() + `"}], [], false, {"contracts":[{"hash":"` + nativehashes.GasToken.StringLE() + `"}]}]`,
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
 		{
 			name:   "positive",
 			params: `["50befd26fdf6e4d957c11e078b24ebce6291456f", "test", []]`,
@@ -1554,6 +1882,17 @@ var rpcTestCases = map[string][]rpcTestCase{
 		},
 	},
 	"invokefunctionhistoric": {
+		{
+			name:   "positive, with state overrides",
+			params: `[20, "` + testContractHashLE + `", "balanceOf", [{"type":"Hash160", "value":"` + overrideAccount.StringLE() + `"}], [], false, ` + testStateOverrides + `]`,
+			result: func(e *executor) any { return &result.Invoke{} },
+			check: func(t *testing.T, e *executor, inv any) {
+				res, ok := inv.(*result.Invoke)
+				require.True(t, ok)
+				require.Equal(t, "HALT", res.State, res.FaultException)
+				require.Equal(t, []stackitem.Item{stackitem.Make(42)}, res.Stack)
+			},
+		},
 		{
 			name:   "positive, by index",
 			params: `[20, "50befd26fdf6e4d957c11e078b24ebce6291456f", "test", []]`,
@@ -1691,6 +2030,23 @@ var rpcTestCases = map[string][]rpcTestCase{
 		},
 	},
 	"invokescript": {
+		{
+			name:   "positive, with state overrides",
+			params: `["` + overrideGASBalanceScript + `", [], false, ` + testStateOverrides + `]`,
+			result: func(e *executor) any { return &result.Invoke{} },
+			check: func(t *testing.T, e *executor, inv any) {
+				res, ok := inv.(*result.Invoke)
+				require.True(t, ok)
+				require.Equal(t, "HALT", res.State, res.FaultException)
+				require.Equal(t, []stackitem.Item{stackitem.Make(100500)}, res.Stack)
+			},
+		},
+		{
+			name:    "invalid state overrides",
+			params:  `["` + overrideGASBalanceScript + `", [], false, 1]`,
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
 		{
 			name:   "positive",
 			params: `["UcVrDUhlbGxvLCB3b3JsZCFoD05lby5SdW50aW1lLkxvZ2FsdWY="]`,
@@ -1793,6 +2149,17 @@ var rpcTestCases = map[string][]rpcTestCase{
 		},
 	},
 	"invokescripthistoric": {
+		{
+			name:   "positive, with state overrides",
+			params: `[20, "` + overrideGASBalanceScript + `", [], false, ` + testStateOverrides + `]`,
+			result: func(e *executor) any { return &result.Invoke{} },
+			check: func(t *testing.T, e *executor, inv any) {
+				res, ok := inv.(*result.Invoke)
+				require.True(t, ok)
+				require.Equal(t, "HALT", res.State, res.FaultException)
+				require.Equal(t, []stackitem.Item{stackitem.Make(100500)}, res.Stack)
+			},
+		},
 		{
 			name:   "positive, by index",
 			params: `[20,"UcVrDUhlbGxvLCB3b3JsZCFoD05lby5SdW50aW1lLkxvZ2FsdWY="]`,
@@ -2191,6 +2558,95 @@ var rpcTestCases = map[string][]rpcTestCase{
 			errCode: neorpc.ErrUnknownContractCode,
 		},
 	},
+	"simulatetransactions": {
+		{
+			name:   "positive, with state overrides",
+			params: `[` + testSimulationSteps + `, false, ` + testStateOverrides + `]`,
+			result: func(e *executor) any { return &result.Simulation{} },
+			check: func(t *testing.T, e *executor, sim any) {
+				res, ok := sim.(*result.Simulation)
+				require.True(t, ok)
+				checkTestSimulation(t, res, false)
+			},
+		},
+		{
+			name:   "verbose",
+			params: `[` + testSimulationSteps + `, true, ` + testStateOverrides + `]`,
+			result: func(e *executor) any { return &result.Simulation{} },
+			check: func(t *testing.T, e *executor, sim any) {
+				res, ok := sim.(*result.Simulation)
+				require.True(t, ok)
+				checkTestSimulation(t, res, true)
+			},
+		},
+		{
+			name:   "without overrides",
+			params: `[` + testSimulationSteps + `]`,
+			result: func(e *executor) any { return &result.Simulation{} },
+			check: func(t *testing.T, e *executor, sim any) {
+				res, ok := sim.(*result.Simulation)
+				require.True(t, ok)
+				require.Equal(t, 4, len(res.Transactions))
+				require.Equal(t, "FAULT", res.Transactions[0].Result.State)
+				require.Equal(t, 0, len(res.Transactions[0].StorageChanges))
+				require.Equal(t, []stackitem.Item{stackitem.Make(0)}, res.Transactions[1].Result.Stack)
+			},
+		},
+		{
+			name:    "no params",
+			params:  `[]`,
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
+		{
+			name:    "empty steps",
+			params:  `[[]]`,
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
+		{
+			name:    "invalid step",
+			params:  `[[{"script":"qwerty"}]]`,
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
+		{
+			name:    "invalid verbose",
+			params:  `[` + testSimulationSteps + `, [], null]`,
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
+		{
+			name:    "invalid state overrides",
+			params:  `[` + testSimulationSteps + `, false, 1]`,
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
+	},
+	"simulatetransactionshistoric": {
+		{
+			name:   "positive, by index",
+			params: `[20, ` + testSimulationSteps + `, false, ` + testStateOverrides + `]`,
+			result: func(e *executor) any { return &result.Simulation{} },
+			check: func(t *testing.T, e *executor, sim any) {
+				res, ok := sim.(*result.Simulation)
+				require.True(t, ok)
+				checkTestSimulation(t, res, false)
+			},
+		},
+		{
+			name:    "no steps",
+			params:  `[20]`,
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
+		{
+			name:    "invalid height",
+			params:  `[100500, ` + testSimulationSteps + `]`,
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
+	},
 	"sendrawtransaction": {
 		{
 			name:   "positive",
@@ -2270,6 +2726,88 @@ var rpcTestCases = map[string][]rpcTestCase{
 			errCode: neorpc.InvalidParamsCode,
 		},
 	},
+	"tracetransaction": {
+		{
+			name:   "positive",
+			params: `["` + deploymentTxHash + `"]`,
+			result: func(*executor) any { return &result.ExecutionTrace{} },
+			check: func(t *testing.T, e *executor, res any) {
+				trace, ok := res.(*result.ExecutionTrace)
+				require.True(t, ok)
+				checkExecutionTrace(t, e, deploymentTxHash, trace)
+				for _, step := range trace.Steps {
+					require.Nil(t, step.Stack)
+					require.Nil(t, step.Storage)
+				}
+			},
+		},
+		{
+			name:   "positive, not the first transaction in block",
+			params: `["` + lastBlock16TxHash + `"]`,
+			result: func(*executor) any { return &result.ExecutionTrace{} },
+			check: func(t *testing.T, e *executor, res any) {
+				trace, ok := res.(*result.ExecutionTrace)
+				require.True(t, ok)
+				checkExecutionTrace(t, e, lastBlock16TxHash, trace)
+			},
+		},
+		{
+			name:   "positive, FAULT",
+			params: `["` + faultedTxHashLE + `"]`,
+			result: func(*executor) any { return &result.ExecutionTrace{} },
+			check: func(t *testing.T, e *executor, res any) {
+				trace, ok := res.(*result.ExecutionTrace)
+				require.True(t, ok)
+				checkExecutionTrace(t, e, faultedTxHashLE, trace)
+				require.Equal(t, 1, len(trace.Steps))
+				require.Equal(t, opcode.ABORT, trace.Steps[0].Opcode)
+			},
+		},
+		{
+			name:   "positive, with stack and storage",
+			params: `["` + storageDeploymentTxHash + `", true, true]`,
+			result: func(*executor) any { return &result.ExecutionTrace{} },
+			check: func(t *testing.T, e *executor, res any) {
+				trace, ok := res.(*result.ExecutionTrace)
+				require.True(t, ok)
+				checkExecutionTrace(t, e, storageDeploymentTxHash, trace)
+				require.Equal(t, 0, len(trace.Steps[0].Stack))
+				var (
+					stackSeen bool
+					puts      int
+				)
+				for _, step := range trace.Steps {
+					stackSeen = stackSeen || len(step.Stack) != 0
+					for _, acc := range step.Storage {
+						require.Equal(t, opcode.SYSCALL, step.Opcode)
+						require.Equal(t, "put", acc.Operation)
+						require.NotEmpty(t, acc.Key)
+						puts++
+					}
+				}
+				require.True(t, stackSeen)
+				require.NotEqual(t, 0, puts)
+			},
+		},
+		{
+			name:    "no params",
+			params:  `[]`,
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
+		{
+			name:    "invalid hash",
+			params:  `["notahash"]`,
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
+		{
+			name:    "unknown transaction",
+			params:  `["` + util.Uint256{1, 2, 3}.StringLE() + `"]`,
+			fail:    true,
+			errCode: neorpc.ErrUnknownTransactionCode,
+		},
+	},
 	"validateaddress": {
 		{
 			name:   "positive",
@@ -2408,6 +2946,39 @@ func TestSubmitOracle(t *testing.T) {
 	t.Run("Valid", runCase(t, false, 0, pubStr, `1`, txSigStr, msgSigStr))
 }
 
+func TestSimulateTransactionsLimits(t *testing.T) {
+	_, _, httpSrv := initClearServerWithCustomConfig(t, func(c *config.Config) {
+		c.ApplicationConfiguration.RPC.MaxGasInvoke = fixedn.Fixed8FromInt64(1)
+		c.ApplicationConfiguration.RPC.MaxSimulationSteps = 2
+	})
+	rpc := `{"jsonrpc": "2.0", "id": 1, "method": "simulatetransactions", "params": [[%s]]}`
+	// JMP 0, infinite loop.
+	loop := `{"script": "IgA="}`
+
+	t.Run("gas", func(t *testing.T) {
+		body := doRPCCallOverHTTP(fmt.Sprintf(rpc, loop+`,`+loop), httpSrv.URL, t)
+		raw := checkErrGetResult(t, body, false, 0)
+		res := new(result.Simulation)
+		require.NoError(t, json.Unmarshal(raw, res))
+		require.Equal(t, 2, len(res.Transactions))
+
+		first := res.Transactions[0]
+		require.Equal(t, vmstate.Fault.String(), first.Result.State)
+		require.GreaterOrEqual(t, first.Result.GasConsumed, int64(fixedn.Fixed8FromInt64(1)))
+
+		second := res.Transactions[1]
+		require.Equal(t, vmstate.None.String(), second.Result.State)
+		require.Contains(t, second.Result.FaultException, "not executed")
+		require.Equal(t, int64(0), second.Result.GasConsumed)
+		require.Equal(t, first.Result.GasConsumed, second.CumulativeGasConsumed)
+		require.Equal(t, first.Result.GasConsumed, res.GasConsumed)
+	})
+	t.Run("too many steps", func(t *testing.T) {
+		body := doRPCCallOverHTTP(fmt.Sprintf(rpc, loop+`,`+loop+`,`+loop), httpSrv.URL, t)
+		checkErrGetResult(t, body, true, neorpc.InvalidParamsCode, "too many simulation steps")
+	})
+}
+
 func TestNotaryRequestRPC(t *testing.T) {
 	var notaryRequest1, notaryRequest2 *payload.P2PNotaryRequest
 	rpcSubmit := `{"jsonrpc": "2.0", "id": 1, "method": "submitnotaryrequest", "params": %s}`
@@ -4476,3 +5047,30 @@ func TestErrorResponseContentType(t *testing.T) {
 	contentType := resp.Header.Get("Content-Type")
 	require.Equal(t, expectedContentType, contentType)
 }
+
+func checkExecutionTrace(t *testing.T, e *executor, txHashLE string, trace *result.ExecutionTrace) {
+	txHash, err := util.Uint256DecodeStringLE(txHashLE)
+	require.NoError(t, err)
+	require.Equal(t, txHash, trace.TxHash)
+	aers, err := e.chain.GetAppExecResults(txHash, trigger.Application)
+	require.NoError(t, err)
+	require.Equal(t, aers[0].VMState.String(), trace.State)
+	require.Equal(t, aers[0].GasConsumed, trace.GasConsumed)
+	require.Equal(t, aers[0].FaultException, trace.FaultException)
+
+	tx, _, err := e.chain.GetTransaction(txHash)
+	require.NoError(t, err)
+	require.NotEqual(t, 0, len(trace.Steps))
+	require.Equal(t, hash.Hash160(tx.Script), trace.Steps[0].Contract)
+	require.Equal(t, 0, trace.Steps[0].IP)
+
+	var gas int64
+	for _, step := range trace.Steps {
+		gas += step.GasConsumed
+	}
+	if trace.Truncated {
+		require.Less(t, gas, trace.GasConsumed)
+	} else {
+		require.Equal(t, trace.GasConsumed, gas)
+	}
+}
@@ -0,0 +1,875 @@
+package tsbinding
+
+import (
+	"cmp"
+	"encoding/binary"
+	"fmt"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/interop/interopnames"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/binding"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/rpcbinding"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+)
+
+// The set of constants containing parts of TypeScript binding template. The
+// runtime part contains types and helpers shared by the generated code, it
+// doesn't depend on any external library, so the binding can be used with any
+// RPC client implementing Invoker interface.
+const (
+	runtimeDefinition = `// Code generated by neo-go contract generate-tswrapper --manifest <file.json> --out <file.ts> [--hash <hash>] [--config <config>]; DO NOT EDIT.
+
+// This file contains TypeScript RPC wrappers for {{.ContractName}} contract.
+
+/** StackItem is a JSON representation of a stack item returned by RPC. */
+export interface StackItem {
+	type: string;
+	value?: any;
+	interface?: string;
+	id?: string;
+}
+
+/** ContractParam is a JSON representation of a contract method parameter. */
+export interface ContractParam {
+	type: string;
+	value?: any;
+}
+
+/** InvokeResult is a part of invokefunction RPC call result used by the wrapper. */
+export interface InvokeResult {
+	state: string;
+	exception?: string | null;
+	stack: StackItem[];
+	session?: string;
+}
+
+/** Invoker is used by ContractReader to call various safe methods. */
+export interface Invoker {
+	invokeFunction(scriptHash: string, operation: string, params: ContractParam[]): Promise<InvokeResult>;
+}
+
+/** Notification is a contract notification from getapplicationlog RPC call result. */
+export interface Notification {
+	contract: string;
+	eventname: string;
+	state: StackItem;
+}
+
+/** Iterator is a reference to the iterator returned by the safe method, it can be traversed with traverseiterator RPC call. */
+export interface Iterator {
+	session: string;
+	id: string;
+}
+
+function hexToBytes(hex: string): Uint8Array {
+	const s = hex.startsWith("0x") ? hex.slice(2) : hex;
+	if (s.length % 2 !== 0 || !/^[0-9a-fA-F]*$/.test(s)) {
+		throw new Error(` + "`invalid hex string: ${hex}`" + `);
+	}
+	const res = new Uint8Array(s.length / 2);
+	for (let i = 0; i < res.length; i++) {
+		res[i] = parseInt(s.substring(2 * i, 2 * i + 2), 16);
+	}
+	return res;
+}
+
+function hexToFixed(hex: string, size: number, name: string): Uint8Array {
+	const b = hexToBytes(hex);
+	if (b.length !== size) {
+		throw new Error(` + "`invalid ${name} length: ${b.length}`" + `);
+	}
+	return b;
+}
+
+function bytesToHex(b: Uint8Array): string {
+	return Array.from(b, (x) => x.toString(16).padStart(2, "0")).join("");
+}
+
+function base64ToBytes(s: string): Uint8Array {
+	return Uint8Array.from(atob(s), (c) => c.charCodeAt(0));
+}
+
+function bytesToBase64(b: Uint8Array): string {
+	return btoa(Array.from(b, (x) => String.fromCharCode(x)).join(""));
+}
+
+function reversed(b: Uint8Array): Uint8Array {
+	return Uint8Array.from(b).reverse();
+}
+
+function bigIntToBytes(n: bigint): number[] {
+	const res: number[] = [];
+	if (n === 0n) {
+		return res;
+	}
+	for (;;) {
+		const b = Number(BigInt.asUintN(8, n));
+		res.push(b);
+		n >>= 8n;
+		if ((n === 0n && (b & 0x80) === 0) || (n === -1n && (b & 0x80) !== 0)) {
+			return res;
+		}
+	}
+}
+
+function bytesToBigInt(b: Uint8Array): bigint {
+	let res = 0n;
+	for (let i = b.length - 1; i >= 0; i--) {
+		res = (res << 8n) | BigInt(b[i]);
+	}
+	if (b.length > 0 && (b[b.length - 1] & 0x80) !== 0) {
+		res -= 1n << BigInt(8 * b.length);
+	}
+	return res;
+}
+
+function itemToAny(item: StackItem): unknown {
+	return item;
+}
+
+function itemToBool(item: StackItem): boolean {
+	switch (item.type) {
+		case "Boolean":
+			return item.value as boolean;
+		case "Integer":
+			return BigInt(item.value) !== 0n;
+		case "ByteString":
+		case "Buffer":
+			return base64ToBytes(item.value).some((x) => x !== 0);
+		default:
+			throw new Error(` + "`can't convert ${item.type} to boolean`" + `);
+	}
+}
+
+function itemToBigInt(item: StackItem): bigint {
+	switch (item.type) {
+		case "Integer":
+			return BigInt(item.value);
+		case "Boolean":
+			return item.value ? 1n : 0n;
+		case "ByteString":
+		case "Buffer":
+			return bytesToBigInt(base64ToBytes(item.value));
+		default:
+			throw new Error(` + "`can't convert ${item.type} to integer`" + `);
+	}
+}
+
+function itemToBytes(item: StackItem): Uint8Array {
+	switch (item.type) {
+		case "ByteString":
+		case "Buffer":
+			return base64ToBytes(item.value);
+		case "Integer":
+			return Uint8Array.from(bigIntToBytes(BigInt(item.value)));
+		case "Boolean":
+			return Uint8Array.of(item.value ? 1 : 0);
+		default:
+			throw new Error(` + "`can't convert ${item.type} to bytes`" + `);
+	}
+}
+
+function itemToFixed(item: StackItem, size: number, name: string): Uint8Array {
+	const b = itemToBytes(item);
+	if (b.length !== size) {
+		throw new Error(` + "`invalid ${name} length: ${b.length}`" + `);
+	}
+	return b;
+}
+
+function itemToString(item: StackItem): string {
+	return new TextDecoder("utf-8", { fatal: true }).decode(itemToBytes(item));
+}
+
+function itemToHash160(item: StackItem): string {
+	return bytesToHex(reversed(itemToFixed(item, 20, "Hash160")));
+}
+
+function itemToHash256(item: StackItem): string {
+	return bytesToHex(reversed(itemToFixed(item, 32, "Hash256")));
+}
+
+function itemToPublicKey(item: StackItem): string {
+	return bytesToHex(itemToFixed(item, 33, "PublicKey"));
+}
+
+function itemToArray<T>(item: StackItem, f: (item: StackItem) => T): T[] {
+	if (item.type !== "Array" && item.type !== "Struct") {
+		throw new Error(` + "`${item.type} is not an array`" + `);
+	}
+	return (item.value as StackItem[]).map((v) => f(v));
+}
+
+function itemToMap<K, V>(item: StackItem, fk: (item: StackItem) => K, fv: (item: StackItem) => V): Map<K, V> {
+	if (item.type !== "Map") {
+		throw new Error(` + "`${item.type} is not a map`" + `);
+	}
+	const res = new Map<K, V>();
+	for (const e of item.value as { key: StackItem; value: StackItem }[]) {
+		res.set(fk(e.key), fv(e.value));
+	}
+	return res;
+}
+
+function itemToFields(item: StackItem, n: number): StackItem[] | null {
+	if (item.type === "Any") {
+		return null;
+	}
+	const arr = itemToArray(item, (v) => v);
+	if (arr.length !== n) {
+		throw new Error(` + "`wrong number of structure elements: ${arr.length}`" + `);
+	}
+	return arr;
+}
+
+function itemToIterator(res: InvokeResult): Iterator {
+	const item = res.stack[0];
+	if (item.type !== "InteropInterface" || item.id === undefined || res.session === undefined) {
+		throw new Error("not an iterator (sessions may be disabled on the server)");
+	}
+	return { session: res.session, id: item.id };
+}
+
+function anyParam(v: unknown): ContractParam {
+	if (v === null || v === undefined) {
+		return { type: "Any" };
+	}
+	if (typeof v === "boolean") {
+		return boolParam(v);
+	}
+	if (typeof v === "bigint" || typeof v === "number") {
+		return bigIntParam(BigInt(v));
+	}
+	if (typeof v === "string") {
+		return stringParam(v);
+	}
+	if (v instanceof Uint8Array) {
+		return bytesParam(v);
+	}
+	if (Array.isArray(v)) {
+		return arrayParam(v, anyParam);
+	}
+	if (v instanceof Map) {
+		return mapParam(v, anyParam, anyParam);
+	}
+	if (typeof v === "object" && "type" in v) {
+		return v as ContractParam;
+	}
+	throw new Error(` + "`unsupported parameter value: ${v}`" + `);
+}
+
+function boolParam(v: boolean): ContractParam {
+	return { type: "Boolean", value: v };
+}
+
+function bigIntParam(v: bigint): ContractParam {
+	return { type: "Integer", value: v.toString() };
+}
+
+function bytesParam(v: Uint8Array): ContractParam {
+	return { type: "ByteArray", value: bytesToBase64(v) };
+}
+
+function signatureParam(v: Uint8Array): ContractParam {
+	return { type: "Signature", value: bytesToBase64(v) };
+}
+
+function stringParam(v: string): ContractParam {
+	return { type: "String", value: v };
+}
+
+function hash160Param(v: string): ContractParam {
+	return { type: "Hash160", value: "0x" + bytesToHex(hexToFixed(v, 20, "Hash160")) };
+}
+
+function hash256Param(v: string): ContractParam {
+	return { type: "Hash256", value: "0x" + bytesToHex(hexToFixed(v, 32, "Hash256")) };
+}
+
+function publicKeyParam(v: string): ContractParam {
+	return { type: "PublicKey", value: bytesToHex(hexToFixed(v, 33, "PublicKey")) };
+}
+
+function arrayParam<T>(v: T[] | null, f: (v: T) => ContractParam): ContractParam {
+	if (v === null) {
+		return { type: "Any" };
+	}
+	return { type: "Array", value: v.map((e) => f(e)) };
+}
+
+function mapParam<K, V>(v: Map<K, V> | null, fk: (k: K) => ContractParam, fv: (v: V) => ContractParam): ContractParam {
+	if (v === null) {
+		return { type: "Any" };
+	}
+	return { type: "Map", value: Array.from(v, ([k, e]) => ({ key: fk(k), value: fv(e) })) };
+}
+
+function emitInt(script: number[], n: bigint): void {
+	if (n >= -1n && n < 16n) {
+		script.push(0x10 + Number(n)); // PUSHM1, PUSH0..PUSH15
+		return;
+	}
+	const b = bigIntToBytes(n);
+	let size = 0;
+	while (1 << size < b.length) {
+		size++;
+	}
+	if (size > 5) {
+		throw new Error("integer is too big");
+	}
+	const pad = n < 0n ? 0xff : 0x00;
+	while (b.length < 1 << size) {
+		b.push(pad);
+	}
+	script.push(size, ...b); // PUSHINT8..PUSHINT256
+}
+
+function emitBytes(script: number[], b: Uint8Array): void {
+	const n = b.length;
+	if (n < 0x100) {
+		script.push(0x0c, n); // PUSHDATA1
+	} else if (n < 0x10000) {
+		script.push(0x0d, n & 0xff, n >> 8); // PUSHDATA2
+	} else {
+		script.push(0x0e, n & 0xff, (n >> 8) & 0xff, (n >> 16) & 0xff, n >>> 24); // PUSHDATA4
+	}
+	for (const x of b) {
+		script.push(x);
+	}
+}
+
+function emitParam(script: number[], p: ContractParam): void {
+	switch (p.type) {
+		case "Any":
+		case "InteropInterface":
+			script.push(0x0b); // PUSHNULL
+			return;
+		case "Boolean":
+			script.push(p.value ? 0x08 : 0x09); // PUSHT, PUSHF
+			return;
+		case "Integer":
+			emitInt(script, BigInt(p.value));
+			return;
+		case "ByteArray":
+		case "Signature":
+			emitBytes(script, base64ToBytes(p.value));
+			return;
+		case "String":
+			emitBytes(script, new TextEncoder().encode(p.value));
+			return;
+		case "Hash160":
+		case "Hash256":
+			emitBytes(script, reversed(hexToBytes(p.value)));
+			return;
+		case "PublicKey":
+			emitBytes(script, hexToBytes(p.value));
+			return;
+		case "Array": {
+			const arr = p.value as ContractParam[];
+			if (arr.length === 0) {
+				script.push(0xc2); // NEWARRAY0
+				return;
+			}
+			for (let i = arr.length - 1; i >= 0; i--) {
+				emitParam(script, arr[i]);
+			}
+			emitInt(script, BigInt(arr.length));
+			script.push(0xc0); // PACK
+			return;
+		}
+		case "Map": {
+			const m = p.value as { key: ContractParam; value: ContractParam }[];
+			for (let i = m.length - 1; i >= 0; i--) {
+				emitParam(script, m[i].value);
+				emitParam(script, m[i].key);
+			}
+			emitInt(script, BigInt(m.length));
+			script.push(0xbe); // PACKMAP
+			return;
+		}
+		default:
+			throw new Error(` + "`unsupported parameter type: ${p.type}`" + `);
+	}
+}
+
+function buildCall(hash: string, method: string, flags: number, params: ContractParam[], withAssert: boolean): Uint8Array {
+	const script: number[] = [];
+	emitParam(script, { type: "Array", value: params });
+	emitInt(script, BigInt(flags));
+	emitBytes(script, new TextEncoder().encode(method));
+	emitBytes(script, reversed(hexToFixed(hash, 20, "Hash160")));
+	script.push(0x41, {{.SyscallID}}); // SYSCALL System.Contract.Call
+	if (withAssert) {
+		script.push(0x39); // ASSERT
+	}
+	return Uint8Array.from(script);
+}
+{{- if .Hash}}
+
+/** Hash contains contract hash. */
+export const Hash = "{{.Hash}}";
+{{- end}}
+`
+
+	typesDefinition = `
+{{- range $t := .NamedTypes}}
+
+/** {{.Name}} is a contract-specific {{.OrigName}} type used by its methods. */
+export interface {{.Name}} {
+{{- range $f := .Fields}}
+	{{.Name}}: {{.Type}};
+{{- end}}
+}
+
+/** itemTo{{.Name}} converts stack item into {{.Name}}, Null item is converted into null. */
+export function itemTo{{.Name}}(item: StackItem): {{.Name}} | null {
+	const arr = itemToFields(item, {{len .Fields}});
+	if (arr === null) {
+		return null;
+	}
+	return {
+{{- range $i, $f := .Fields}}
+		{{.Name}}: {{.Decoder}}(arr[{{$i}}]),
+{{- end}}
+	};
+}
+
+/** paramFrom{{.Name}} converts {{.Name}} into contract parameter. */
+export function paramFrom{{.Name}}(v: {{.Name}} | null): ContractParam {
+	if (v === null) {
+		return { type: "Any" };
+	}
+	return {
+		type: "Array",
+		value: [
+{{- range $f := .Fields}}
+			{{.Encoder}}(v.{{.Name}}),
+{{- end}}
+		],
+	};
+}
+{{- end}}
+{{- range $e := .Events}}
+
+/** {{.Name}} represents "{{.ManifestName}}" event emitted by the contract. */
+export interface {{.Name}} {
+{{- range $f := .Fields}}
+	{{.Name}}: {{.Type}};
+{{- end}}
+}
+
+/** itemTo{{.Name}} converts notification state into {{.Name}}. */
+export function itemTo{{.Name}}(item: StackItem): {{.Name}} {
+	const arr = itemToArray(item, (v) => v);
+	if (arr.length !== {{len .Fields}}) {
+		throw new Error("wrong number of structure elements");
+	}
+	return {
+{{- range $i, $f := .Fields}}
+		{{.Name}}: {{.Decoder}}(arr[{{$i}}]),
+{{- end}}
+	};
+}
+
+/** {{.FuncName}} retrieves a set of all emitted events with "{{.ManifestName}}" name from the given notifications. */
+export function {{.FuncName}}(notifications: Notification[]): {{.Name}}[] {
+	return notifications.filter((n) => n.eventname === "{{.ManifestName}}").map((n) => itemTo{{.Name}}(n.state));
+}
+{{- end}}
+`
+
+	classesDefinition = `
+/** ContractReader implements safe contract methods. */
+export class ContractReader {
+	protected invoker: Invoker;
+	protected hash: string;
+
+	/** Creates an instance of ContractReader using {{if .Hash}}Hash and {{end}}the given Invoker. */
+	constructor(invoker: Invoker, hash: string{{if .Hash}} = Hash{{end}}) {
+		this.invoker = invoker;
+		this.hash = hash;
+	}
+
+	protected async call(method: string, params: ContractParam[]): Promise<InvokeResult> {
+		const res = await this.invoker.invokeFunction(this.hash, method, params);
+		if (res.state !== "HALT") {
+			throw new Error(` + "`invocation failed: ${res.exception}`" + `);
+		}
+		if (res.stack.length !== 1) {
+			throw new Error(` + "`result stack length is ${res.stack.length}, expected 1`" + `);
+		}
+		return res;
+	}
+{{- range $m := .SafeMethods}}
+
+	/** {{.Name}} {{.Comment}} */
+	async {{.Name}}({{range $i, $a := .Arguments}}{{if $i}}, {{end}}{{.Name}}: {{.Type}}{{end}}): Promise<{{.ReturnType}}> {
+		const params: ContractParam[] = [{{range $i, $a := .Arguments}}{{if $i}}, {{end}}{{.Encoder}}({{.Name}}){{end}}];
+{{- if eq .ReturnType "InvokeResult"}}
+		return this.invoker.invokeFunction(this.hash, "{{.NameABI}}", params);
+{{- else if eq .ReturnType "Iterator"}}
+		return itemToIterator(await this.call("{{.NameABI}}", params));
+{{- else}}
+		return {{.Decoder}}((await this.call("{{.NameABI}}", params)).stack[0]);
+{{- end}}
+	}
+{{- end}}
+}
+
+/** Contract implements all contract methods, state-changing ones are represented by script builders. */
+export class Contract extends ContractReader {
+{{- range $i, $m := .Methods}}
+{{- if $i}}
+{{end}}
+	/** {{.Name}} {{.Comment}} */
+	{{.Name}}({{range $i, $a := .Arguments}}{{if $i}}, {{end}}{{.Name}}: {{.Type}}{{end}}): Uint8Array {
+		return buildCall(this.hash, "{{.NameABI}}", {{printf "%d" .CallFlag}}, [{{range $i, $a := .Arguments}}{{if $i}}, {{end}}{{.Encoder}}({{.Name}}){{end}}], {{.WithAssert}});
+	}
+{{- end}}
+}
+`
+
+	srcTmpl = runtimeDefinition + typesDefinition + classesDefinition
+)
+
+type (
+	contractTmpl struct {
+		ContractName string
+		Hash         string
+		SyscallID    string
+		NamedTypes   []namedTypeTmpl
+		Events       []eventTmpl
+		SafeMethods  []methodTmpl
+		Methods      []methodTmpl
+	}
+
+	namedTypeTmpl struct {
+		Name     string
+		OrigName string
+		Fields   []fieldTmpl
+	}
+
+	eventTmpl struct {
+		Name         string
+		ManifestName string
+		FuncName     string
+		Fields       []fieldTmpl
+	}
+
+	fieldTmpl struct {
+		Name    string
+		Type    string
+		Decoder string
+		Encoder string
+	}
+
+	methodTmpl struct {
+		Name       string
+		NameABI    string
+		Comment    string
+		CallFlag   callflag.CallFlag
+		WithAssert bool
+		Arguments  []fieldTmpl
+		ReturnType string
+		Decoder    string
+	}
+)
+
+// reservedWords contains TypeScript reserved words that can't be used as
+// parameter names.
+var reservedWords = map[string]bool{
+	"break": true, "case": true, "catch": true, "class": true, "const": true, "continue": true,
+	"debugger": true, "default": true, "delete": true, "do": true, "else": true, "enum": true,
+	"export": true, "extends": true, "false": true, "finally": true, "for": true, "function": true,
+	"if": true, "import": true, "in": true, "instanceof": true, "new": true, "null": true,
+	"return": true, "super": true, "switch": true, "this": true, "throw": true, "true": true,
+	"try": true, "typeof": true, "var": true, "void": true, "while": true, "with": true,
+	"implements": true, "interface": true, "let": true, "package": true, "private": true,
+	"protected": true, "public": true, "static": true, "yield": true, "await": true,
+	"arguments": true, "eval": true, "params": true,
+}
+
+var srcTemplate = template.Must(template.New("generate").Parse(srcTmpl))
+
+// Generate writes TypeScript file containing smartcontract bindings to the
+// `cfg.Output`. It uses the same configuration as Go RPC bindings generator
+// (see [rpcbinding.Generate]) including extended type information. It doesn't
+// check manifest from Config for validity, incorrect manifest can lead to
+// unexpected results.
+func Generate(cfg binding.Config) error {
+	var (
+		ctr = contractTmpl{
+			ContractName: cfg.Manifest.Name,
+			SyscallID:    syscallID(interopnames.SystemContractCall),
+		}
+		members = map[string]bool{"call": true, "invoker": true, "hash": true, "constructor": true}
+	)
+	if !cfg.Hash.Equals(util.Uint160{}) {
+		ctr.Hash = cfg.Hash.StringLE()
+	}
+
+	for _, et := range cfg.NamedTypes {
+		t := namedTypeTmpl{
+			Name:     toTypeName(et.Name),
+			OrigName: et.Name,
+		}
+		for _, f := range et.Fields {
+			t.Fields = append(t.Fields, fieldFromET(lowerFirst(rpcbinding.ToParameterBindingName(f.Field)), f.ExtendedType))
+		}
+		ctr.NamedTypes = append(ctr.NamedTypes, t)
+	}
+	slices.SortFunc(ctr.NamedTypes, func(a, b namedTypeTmpl) int { return cmp.Compare(a.Name, b.Name) })
+
+	for _, e := range cfg.Manifest.ABI.Events {
+		eName := rpcbinding.ToEventBindingName(e.Name)
+		et := eventTmpl{
+			Name:         eName,
+			ManifestName: e.Name,
+			FuncName:     lowerFirst(eName) + "sFromNotifications",
+		}
+		for _, p := range e.Parameters {
+			pName := rpcbinding.ToParameterBindingName(p.Name)
+			ext, ok := cfg.Types[eName+"."+pName]
+			if !ok {
+				ext = binding.ExtendedType{Base: p.Type}
+			}
+			et.Fields = append(et.Fields, fieldFromET(lowerFirst(pName), ext))
+		}
+		ctr.Events = append(ctr.Events, et)
+	}
+
+	// Go-specific type names are not used, the template is only needed for
+	// method naming and ordering.
+	tmpl := binding.TemplateFromManifest(cfg, func(string, smartcontract.ParamType, *binding.Config) (string, string) {
+		return "", ""
+	})
+	for _, m := range tmpl.Methods {
+		abim := cfg.Manifest.ABI.GetMethod(m.NameABI, len(m.Arguments))
+		mtd := methodTmpl{
+			Name:     lowerFirst(m.Name),
+			NameABI:  m.NameABI,
+			CallFlag: callflag.All,
+		}
+		if f, ok := cfg.CallFlags[m.NameABI]; ok {
+			mtd.CallFlag = f
+		} else if abim.Safe {
+			mtd.CallFlag = callflag.ReadOnly
+		}
+		var argNames = make(map[string]bool)
+		for i, p := range abim.Parameters {
+			name := lowerFirst(m.Arguments[i].Name)
+			if reservedWords[name] {
+				name += "v"
+			}
+			for argNames[name] {
+				name += "_"
+			}
+			argNames[name] = true
+			ext, ok := cfg.Types[abim.Name+"."+p.Name]
+			if !ok {
+				ext = binding.ExtendedType{Base: p.Type}
+			}
+			mtd.Arguments = append(mtd.Arguments, fieldFromET(name, ext))
+		}
+		ret, ok := cfg.Types[abim.Name]
+		if !ok {
+			ret = binding.ExtendedType{Base: abim.ReturnType}
+		}
+		if abim.Safe {
+			mtd.Comment = fmt.Sprintf("invokes `%s` method of contract.", m.NameABI)
+			switch ret.Base {
+			case smartcontract.VoidType:
+				mtd.ReturnType = "InvokeResult"
+			case smartcontract.InteropInterfaceType:
+				mtd.ReturnType = "Iterator"
+			default:
+				mtd.ReturnType = etToTS(ret)
+				mtd.Decoder = etDecoder(ret)
+			}
+		} else {
+			mtd.Name += "Script"
+			mtd.Comment = fmt.Sprintf("creates a script invoking `%s` method of the contract.", m.NameABI)
+			if ret.Base == smartcontract.BoolType {
+				mtd.Comment += " This script also contains ASSERT for the method result."
+				mtd.WithAssert = true
+			}
+		}
+		for members[mtd.Name] {
+			mtd.Name += "_"
+		}
+		members[mtd.Name] = true
+		if abim.Safe {
+			ctr.SafeMethods = append(ctr.SafeMethods, mtd)
+		} else {
+			ctr.Methods = append(ctr.Methods, mtd)
+		}
+	}
+
+	return fexecute(cfg, ctr)
+}
+
+func fexecute(cfg binding.Config, ctr contractTmpl) error {
+	err := srcTemplate.Execute(cfg.Output, ctr)
+	if err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+	return nil
+}
+
+func fieldFromET(name string, et binding.ExtendedType) fieldTmpl {
+	return fieldTmpl{
+		Name:    name,
+		Type:    etToTS(et),
+		Decoder: etDecoder(et),
+		Encoder: etEncoder(et),
+	}
+}
+
+func etToTS(et binding.ExtendedType) string {
+	switch et.Base {
+	case smartcontract.AnyType, smartcontract.InteropInterfaceType:
+		return "unknown"
+	case smartcontract.BoolType:
+		return "boolean"
+	case smartcontract.IntegerType:
+		return "bigint"
+	case smartcontract.ByteArrayType, smartcontract.SignatureType:
+		return "Uint8Array"
+	case smartcontract.StringType, smartcontract.Hash160Type, smartcontract.Hash256Type,
+		smartcontract.PublicKeyType:
+		return "string"
+	case smartcontract.ArrayType:
+		if len(et.Name) > 0 {
+			return toTypeName(et.Name) + " | null"
+		} else if et.Value != nil {
+			sub := etToTS(*et.Value)
+			if strings.Contains(sub, "|") {
+				sub = "(" + sub + ")"
+			}
+			return sub + "[]"
+		}
+		return "unknown[]"
+	case smartcontract.MapType:
+		var vt = "unknown"
+		if et.Value != nil {
+			vt = etToTS(*et.Value)
+		}
+		return "Map<" + etToTS(binding.ExtendedType{Base: et.Key}) + ", " + vt + ">"
+	case smartcontract.VoidType:
+		return "void"
+	default:
+		panic("unreachable")
+	}
+}
+
+func etDecoder(et binding.ExtendedType) string {
+	switch et.Base {
+	case smartcontract.AnyType, smartcontract.InteropInterfaceType, smartcontract.VoidType:
+		return "itemToAny"
+	case smartcontract.BoolType:
+		return "itemToBool"
+	case smartcontract.IntegerType:
+		return "itemToBigInt"
+	case smartcontract.ByteArrayType, smartcontract.SignatureType:
+		return "itemToBytes"
+	case smartcontract.StringType:
+		return "itemToString"
+	case smartcontract.Hash160Type:
+		return "itemToHash160"
+	case smartcontract.Hash256Type:
+		return "itemToHash256"
+	case smartcontract.PublicKeyType:
+		return "itemToPublicKey"
+	case smartcontract.ArrayType:
+		if len(et.Name) > 0 {
+			return "itemTo" + toTypeName(et.Name)
+		}
+		var sub = "itemToAny"
+		if et.Value != nil {
+			sub = etDecoder(*et.Value)
+		}
+		return "((item: StackItem) => itemToArray(item, " + sub + "))"
+	case smartcontract.MapType:
+		var sub = "itemToAny"
+		if et.Value != nil {
+			sub = etDecoder(*et.Value)
+		}
+		return "((item: StackItem) => itemToMap(item, " + etDecoder(binding.ExtendedType{Base: et.Key}) + ", " + sub + "))"
+	default:
+		panic("unreachable")
+	}
+}
+
+func etEncoder(et binding.ExtendedType) string {
+	switch et.Base {
+	case smartcontract.AnyType, smartcontract.InteropInterfaceType, smartcontract.VoidType:
+		return "anyParam"
+	case smartcontract.BoolType:
+		return "boolParam"
+	case smartcontract.IntegerType:
+		return "bigIntParam"
+	case smartcontract.ByteArrayType:
+		return "bytesParam"
+	case smartcontract.SignatureType:
+		return "signatureParam"
+	case smartcontract.StringType:
+		return "stringParam"
+	case smartcontract.Hash160Type:
+		return "hash160Param"
+	case smartcontract.Hash256Type:
+		return "hash256Param"
+	case smartcontract.PublicKeyType:
+		return "publicKeyParam"
+	case smartcontract.ArrayType:
+		if len(et.Name) > 0 {
+			return "paramFrom" + toTypeName(et.Name)
+		}
+		var sub = binding.ExtendedType{Base: smartcontract.AnyType}
+		if et.Value != nil {
+			sub = *et.Value
+		}
+		return "((v: " + etToTS(et) + ") => arrayParam(v, " + etEncoder(sub) + "))"
+	case smartcontract.MapType:
+		var sub = binding.ExtendedType{Base: smartcontract.AnyType}
+		if et.Value != nil {
+			sub = *et.Value
+		}
+		return "((v: " + etToTS(et) + ") => mapParam(v, " + etEncoder(binding.ExtendedType{Base: et.Key}) + ", " + etEncoder(sub) + "))"
+	default:
+		panic("unreachable")
+	}
+}
+
+// syscallID returns comma-separated SYSCALL instruction parameter bytes for
+// the given interop name.
+func syscallID(name string) string {
+	var (
+		buf = binary.LittleEndian.AppendUint32(nil, interopnames.ToID([]byte(name)))
+		res = make([]string, 0, len(buf))
+	)
+	for _, b := range buf {
+		res = append(res, fmt.Sprintf("0x%02x", b))
+	}
+	return strings.Join(res, ", ")
+}
+
+func toTypeName(s string) string {
+	return strings.Map(func(c rune) rune {
+		if c == '.' {
+			return -1
+		}
+		return c
+	}, upperFirst(s))
+}
+
+func upperFirst(s string) string {
+	return strings.ToUpper(s[0:1]) + s[1:]
+}
+
+func lowerFirst(s string) string {
+	return strings.ToLower(s[0:1]) + s[1:]
+}
@@ -0,0 +1,431 @@
+/*
+Package decompiler reconstructs readable pseudo-Go code from NEF scripts.
+
+Script is split into functions using method offsets from the manifest (and
+debug info if available) and targets of CALL and PUSHA instructions. Every
+function is then split into basic blocks that are converted to statements by
+simulating evaluation stack with symbolic expressions. Values that are left
+on the stack between blocks are stored in `sN` variables (N is the stack
+depth). Finally, control flow graph is structured into if/else statements,
+loops and try/catch/finally blocks, goto is used for anything that can't be
+structured.
+
+The result is not intended to be compiled back, it uses a number of
+pseudo-builtins (like `abort`, `isType` or `keys`) for operations that have
+no direct Go counterparts and try/catch/finally blocks for exception
+handling. Functions that can't be decompiled (because of inconsistent stack
+usage, for example) are printed as disassembly.
+*/
+package decompiler
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/nspcc-dev/neo-go/pkg/compiler"
+	"github.com/nspcc-dev/neo-go/pkg/core/native/nativenames"
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/nef"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+)
+
+// instr is a decoded script instruction.
+type instr struct {
+	offset int
+	op     opcode.Opcode
+	param  []byte
+	// next is the offset of the next instruction.
+	next int
+	// target is the jump (call, catch block) target offset, -1 if none.
+	target int
+	// finally is the finally block offset for TRY, -1 if none.
+	finally int
+}
+
+// decompiler holds the contract-wide data.
+type decompiler struct {
+	nef      *nef.File
+	manifest *manifest.Manifest
+	di       *compiler.DebugInfo
+
+	instrs  []*instr
+	index   map[int]int // Instruction offset -> instrs index.
+	funcs   []*function
+	funcAt  map[int]*function
+	statics []string
+	// staticPointers are the static slots holding known function pointers.
+	staticPointers map[int]int
+	natives        map[util.Uint160]string
+}
+
+// Decompile writes pseudo-Go representation of the given NEF file to w.
+// Manifest and debug info are optional, they're used to name methods, their
+// parameters and variables. The script must be correct (see
+// [vm.IsScriptCorrect]).
+func Decompile(w io.Writer, nf *nef.File, m *manifest.Manifest, di *compiler.DebugInfo) error {
+	if err := vm.IsScriptCorrect(nf.Script, nil); err != nil {
+		return fmt.Errorf("invalid script: %w", err)
+	}
+	d := &decompiler{
+		nef:      nf,
+		manifest: m,
+		di:       di,
+		index:    make(map[int]int),
+		funcAt:   make(map[int]*function),
+		natives:  make(map[util.Uint160]string, len(nativenames.All)),
+	}
+	for _, name := range nativenames.All {
+		d.natives[state.CreateNativeContractHash(name)] = nativePackages[name]
+	}
+	if err := d.decode(); err != nil {
+		return err
+	}
+	d.splitFunctions()
+	d.analyze()
+	return d.write(w)
+}
+
+func (d *decompiler) decode() error {
+	script := d.nef.Script
+	ctx := vm.NewContext(script)
+	for op, param, err := ctx.Next(); ctx.IP() < len(script); op, param, err = ctx.Next() {
+		if err != nil {
+			return fmt.Errorf("can't decode instruction at %d: %w", ctx.IP(), err)
+		}
+		in := &instr{
+			offset:  ctx.IP(),
+			op:      op,
+			param:   param,
+			next:    ctx.NextIP(),
+			target:  -1,
+			finally: -1,
+		}
+		switch op {
+		case opcode.TRY, opcode.TRYL:
+			catch, finally := jumpOffset(op, param), jumpOffset(op, param[len(param)/2:])
+			if catch != 0 {
+				in.target = in.offset + catch
+			}
+			if finally != 0 {
+				in.finally = in.offset + finally
+			}
+		default:
+			if hasTarget(op) {
+				in.target = in.offset + jumpOffset(op, param)
+			}
+		}
+		d.index[in.offset] = len(d.instrs)
+		d.instrs = append(d.instrs, in)
+	}
+	for _, in := range d.instrs {
+		for _, t := range []int{in.target, in.finally} {
+			if _, ok := d.index[t]; t >= 0 && !ok {
+				return fmt.Errorf("invalid jump target %d at %d", t, in.offset)
+			}
+		}
+	}
+	return nil
+}
+
+// splitFunctions determines function boundaries and names.
+func (d *decompiler) splitFunctions() {
+	if len(d.instrs) == 0 {
+		return
+	}
+	starts := map[int]bool{0: true}
+	if d.manifest != nil {
+		for _, m := range d.manifest.ABI.Methods {
+			starts[m.Offset] = true
+		}
+	}
+	if d.di != nil {
+		for _, m := range d.di.Methods {
+			starts[int(m.Range.Start)] = true
+		}
+	}
+	for _, in := range d.instrs {
+		switch in.op {
+		case opcode.CALL, opcode.CALLL, opcode.PUSHA:
+			starts[in.target] = true
+		}
+	}
+	offs := d.sortedStarts(starts)
+	if d.manifest == nil && d.di == nil {
+		// Entry points are not known, so code that can't be reached from
+		// the function start is treated as a separate function.
+		for i := 0; i < len(offs); i++ {
+			end := len(d.nef.Script)
+			if i+1 < len(offs) {
+				end = offs[i+1]
+			}
+			if off := d.firstUnreachable(offs[i], end); off >= 0 {
+				starts[off] = true
+				offs = d.sortedStarts(starts)
+			}
+		}
+	}
+
+	names := make(map[string]int)
+	for i, off := range offs {
+		f := &function{
+			d:       d,
+			start:   off,
+			end:     len(d.nef.Script),
+			returns: -1,
+		}
+		hi := len(d.instrs)
+		if i+1 < len(offs) {
+			f.end = offs[i+1]
+			hi = d.index[f.end]
+		}
+		f.instrs = d.instrs[d.index[f.start]:hi]
+		f.init(names)
+		d.funcs = append(d.funcs, f)
+		d.funcAt[off] = f
+	}
+
+	d.staticPointers = pointerSlots(d.instrs, opcode.STSFLD0)
+	for _, in := range d.instrs {
+		if in.op == opcode.INITSSLOT {
+			n := int(in.param[0])
+			d.statics = make([]string, n)
+			var names []string
+			if d.di != nil && len(d.di.StaticVariables) == n {
+				names = d.di.StaticVariables
+			}
+			for i := range d.statics {
+				d.statics[i] = fmt.Sprintf("static%d", i)
+				if names != nil {
+					d.statics[i], _, _ = strings.Cut(names[i], ",")
+				}
+			}
+			break
+		}
+	}
+}
+
+func (d *decompiler) sortedStarts(starts map[int]bool) []int {
+	offs := make([]int, 0, len(starts))
+	for off := range starts {
+		if _, ok := d.index[off]; ok {
+			offs = append(offs, off)
+		}
+	}
+	slices.Sort(offs)
+	return offs
+}
+
+// firstUnreachable returns the offset of the instruction following the last
+// one reachable from start in the [start, end) range, -1 if there is none.
+func (d *decompiler) firstUnreachable(start, end int) int {
+	var (
+		seen  = make(map[int]bool)
+		queue = []int{start}
+	)
+	for len(queue) > 0 {
+		off := queue[0]
+		queue = queue[1:]
+		if off < start || off >= end || seen[off] {
+			continue
+		}
+		seen[off] = true
+		in := d.instrs[d.index[off]]
+		switch in.op {
+		case opcode.CALL, opcode.CALLL, opcode.PUSHA:
+		default:
+			queue = append(queue, in.target, in.finally)
+		}
+		switch in.op {
+		case opcode.JMP, opcode.JMPL, opcode.RET, opcode.THROW, opcode.ABORT, opcode.ABORTMSG,
+			opcode.ENDTRY, opcode.ENDTRYL, opcode.ENDFINALLY:
+		default:
+			queue = append(queue, in.next)
+		}
+	}
+	// Dead code in the middle of the function is not a separate function.
+	last := start
+	for off := range seen {
+		last = max(last, off)
+	}
+	if next := d.instrs[d.index[last]].next; next < end {
+		return next
+	}
+	return -1
+}
+
+// analyze determines the number of values returned by every function and
+// decompiles all functions. Functions are analyzed repeatedly until no new
+// information is obtained, since the number of values a function returns
+// depends on its callees.
+func (d *decompiler) analyze() {
+	for changed := true; changed; {
+		changed = false
+		for _, f := range d.funcs {
+			if f.returns < 0 && f.probeReturns() {
+				changed = true
+			}
+		}
+	}
+	for _, f := range d.funcs {
+		if f.returns < 0 {
+			f.returns = f.declaredReturns()
+		}
+	}
+	for _, f := range d.funcs {
+		f.decompile()
+	}
+}
+
+func (d *decompiler) write(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("// Code generated by neo-go contract decompile")
+	if d.nef.Compiler != "" {
+		b.WriteString(" from NEF compiled by " + d.nef.Compiler)
+	}
+	b.WriteString(".\n")
+	if d.manifest != nil {
+		fmt.Fprintf(&b, "// Contract: %q.\n", d.manifest.Name)
+		if len(d.manifest.SupportedStandards) != 0 {
+			fmt.Fprintf(&b, "// Supported standards: %s.\n", strings.Join(d.manifest.SupportedStandards, ", "))
+		}
+		for _, e := range d.manifest.ABI.Events {
+			params := make([]string, len(e.Parameters))
+			for i, p := range e.Parameters {
+				params[i] = p.Name + " " + goType(p.Type)
+			}
+			fmt.Fprintf(&b, "// Event %q(%s).\n", e.Name, strings.Join(params, ", "))
+		}
+	}
+	b.WriteString("package contract\n")
+
+	if len(d.statics) != 0 {
+		b.WriteString("\nvar (\n")
+		for _, s := range d.statics {
+			b.WriteString("\t" + s + " any\n")
+		}
+		b.WriteString(")\n")
+	}
+	for _, f := range d.funcs {
+		b.WriteString("\n")
+		f.write(&b)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// nativePackages contains interop package names of native contracts.
+var nativePackages = map[string]string{
+	nativenames.Management:  "management",
+	nativenames.Ledger:      "ledger",
+	nativenames.Neo:         "neo",
+	nativenames.Gas:         "gas",
+	nativenames.Policy:      "policy",
+	nativenames.Oracle:      "oracle",
+	nativenames.Designation: "roles",
+	nativenames.Notary:      "notary",
+	nativenames.CryptoLib:   "crypto",
+	nativenames.StdLib:      "std",
+}
+
+// nativeName returns the interop package name of the native contract with the
+// given hash.
+func (d *decompiler) nativeName(h util.Uint160) (string, bool) {
+	name, ok := d.natives[h]
+	return name, ok
+}
+
+// manifestMethod returns ABI method with the given offset.
+func (d *decompiler) manifestMethod(off int) *manifest.Method {
+	if d.manifest == nil {
+		return nil
+	}
+	for i := range d.manifest.ABI.Methods {
+		if d.manifest.ABI.Methods[i].Offset == off {
+			return &d.manifest.ABI.Methods[i]
+		}
+	}
+	return nil
+}
+
+// debugMethod returns debug info of the method starting at the given offset.
+func (d *decompiler) debugMethod(off int) *compiler.MethodDebugInfo {
+	if d.di == nil {
+		return nil
+	}
+	for i := range d.di.Methods {
+		if int(d.di.Methods[i].Range.Start) == off {
+			return &d.di.Methods[i]
+		}
+	}
+	return nil
+}
+
+// goType returns Go type name for the given parameter type.
+func goType(t smartcontract.ParamType) string {
+	switch t {
+	case smartcontract.BoolType:
+		return "bool"
+	case smartcontract.IntegerType:
+		return "int"
+	case smartcontract.ByteArrayType:
+		return "[]byte"
+	case smartcontract.StringType:
+		return "string"
+	case smartcontract.Hash160Type:
+		return "interop.Hash160"
+	case smartcontract.Hash256Type:
+		return "interop.Hash256"
+	case smartcontract.PublicKeyType:
+		return "interop.PublicKey"
+	case smartcontract.SignatureType:
+		return "interop.Signature"
+	case smartcontract.ArrayType:
+		return "[]any"
+	case smartcontract.MapType:
+		return "map[any]any"
+	case smartcontract.InteropInterfaceType:
+		return "interop.Interface"
+	case smartcontract.VoidType:
+		return ""
+	default:
+		return "any"
+	}
+}
+
+// goTypeFromString is the same as goType, but accepts type name. Unknown types
+// are represented as any.
+func goTypeFromString(s string) string {
+	t, err := smartcontract.ParseParamType(s)
+	if err != nil {
+		return "any"
+	}
+	return goType(t)
+}
+
+// jumpOffset returns the jump offset stored in the given parameter.
+func jumpOffset(op opcode.Opcode, param []byte) int {
+	switch op {
+	case opcode.JMPL, opcode.JMPIFL, opcode.JMPIFNOTL, opcode.JMPEQL, opcode.JMPNEL,
+		opcode.JMPGTL, opcode.JMPGEL, opcode.JMPLTL, opcode.JMPLEL,
+		opcode.CALLL, opcode.PUSHA, opcode.ENDTRYL, opcode.TRYL:
+		return int(int32(binary.LittleEndian.Uint32(param)))
+	default:
+		return int(int8(param[0]))
+	}
+}
+
+// hasTarget returns true for instructions with a single offset parameter.
+func hasTarget(op opcode.Opcode) bool {
+	return opcode.JMP <= op && op <= opcode.JMPLEL || op == opcode.CALL || op == opcode.CALLL ||
+		op == opcode.ENDTRY || op == opcode.ENDTRYL || op == opcode.PUSHA
+}
+
+var errUnknownCallee = errors.New("callee return values are unknown")
@@ -0,0 +1,175 @@
+package decompiler_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/compiler"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/nef"
+	"github.com/nspcc-dev/neo-go/pkg/vm/decompiler"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+	"github.com/stretchr/testify/require"
+)
+
+const src = `package foo
+import (
+	"github.com/nspcc-dev/neo-go/pkg/interop"
+	"github.com/nspcc-dev/neo-go/pkg/interop/contract"
+	"github.com/nspcc-dev/neo-go/pkg/interop/native/gas"
+	"github.com/nspcc-dev/neo-go/pkg/interop/native/std"
+	"github.com/nspcc-dev/neo-go/pkg/interop/runtime"
+)
+func Sum(n int) int {
+	s := 0
+	for i := 0; i < n; i++ {
+		if i%2 == 0 {
+			continue
+		}
+		if i > 100 {
+			break
+		}
+		s += i
+	}
+	return s
+}
+func Sign(a int) string {
+	if a > 0 {
+		runtime.Log("positive")
+	} else {
+		runtime.Log("not positive")
+	}
+	return std.Itoa(a, 10)
+}
+func Balance(h interop.Hash160) int {
+	return contract.Call(interop.Hash160(gas.Hash), "balanceOf", contract.ReadStates, h).(int)
+}
+func pair(a int) (int, string) {
+	return a, "pair"
+}
+func First(a int) int {
+	x, _ := pair(a)
+	return x
+}
+func Safe() (res int) {
+	defer func() {
+		if r := recover(); r != nil {
+			runtime.Log("recovered")
+		}
+	}()
+	panic("oops")
+}`
+
+func decompile(t *testing.T, ne *nef.File, m *manifest.Manifest, di *compiler.DebugInfo) string {
+	var b strings.Builder
+	require.NoError(t, decompiler.Decompile(&b, ne, m, di))
+	return b.String()
+}
+
+func compile(t *testing.T, optimize bool) (*nef.File, *manifest.Manifest, *compiler.DebugInfo) {
+	ne, di, err := compiler.CompileWithOptions("foo.go", strings.NewReader(src), &compiler.Options{Optimize: optimize})
+	require.NoError(t, err)
+	m, err := compiler.CreateManifest(di, &compiler.Options{Name: "foo", NoPermissionsCheck: true})
+	require.NoError(t, err)
+	return ne, m, di
+}
+
+func TestDecompile(t *testing.T) {
+	ne, m, di := compile(t, false)
+	out := decompile(t, ne, m, di)
+
+	require.True(t, strings.HasPrefix(out, "// Code generated by neo-go contract decompile"))
+	require.Contains(t, out, `// Contract: "foo".`)
+	require.Contains(t, out, `// ABI method "sum".`)
+	require.NotContains(t, out, "Can't decompile")
+	require.NotContains(t, out, "goto")
+
+	// Loops and conditions.
+	require.Contains(t, out, "func Sum(n int) int {")
+	require.Contains(t, out, "\tfor i < n {\n")
+	require.Contains(t, out, "\t\tif i % 2 != 0 {\n")
+	require.Contains(t, out, "\t\t\t\tbreak\n")
+	require.Contains(t, out, "\t\ti = i + 1\n")
+	require.Contains(t, out, `		runtime.Log("positive")
+	} else {
+		runtime.Log("not positive")
+	}`)
+
+	// Native contracts and syscalls.
+	require.Contains(t, out, "return std.Itoa(a, 10)")
+	require.Contains(t, out, `loc0 = []any{h}
+	return int(contract.Call(gas.Hash, "balanceOf", 1, loc0))`)
+
+	// Multiple return values.
+	require.Contains(t, out, "func pair(a int) (any, any) {\n\treturn a, \"pair\"\n}")
+	require.Contains(t, out, "t0, t1 = pair(a)")
+
+	// Exception handling.
+	require.Contains(t, out, "\ttry {\n")
+	require.Contains(t, out, "\t} catch e {\n")
+	require.Contains(t, out, "\t} finally {\n")
+}
+
+func TestDecompileOptimized(t *testing.T) {
+	ne, m, di := compile(t, true)
+	out := decompile(t, ne, m, di)
+	require.NotContains(t, out, "Can't decompile")
+	require.Contains(t, out, "runtime.Log(\"positive\")")
+}
+
+func TestDecompileNoMetadata(t *testing.T) {
+	ne, _, _ := compile(t, false)
+	out := decompile(t, ne, nil, nil)
+	require.NotContains(t, out, "// Contract:")
+	require.NotContains(t, out, "Can't decompile")
+	require.Contains(t, out, "func sub_0000(")
+	require.Contains(t, out, "for loc1 < arg0 {")
+	require.Contains(t, out, "std.Itoa(arg0, 10)")
+}
+
+func TestDecompileManifestOnly(t *testing.T) {
+	ne, m, _ := compile(t, false)
+	out := decompile(t, ne, m, nil)
+	require.Contains(t, out, "func sum(n int) int {")
+	require.Contains(t, out, "for loc1 < n {")
+}
+
+func TestDecompileFallback(t *testing.T) {
+	// Stack depth at RET depends on the branch taken.
+	ne := &nef.File{Script: []byte{
+		byte(opcode.PUSH0),
+		byte(opcode.JMPIFNOT), 3,
+		byte(opcode.PUSH1),
+		byte(opcode.RET),
+	}}
+	out := decompile(t, ne, nil, nil)
+	require.Contains(t, out, "// Can't decompile: ")
+	require.Contains(t, out, "// 3: PUSH1\n")
+
+	t.Run("invalid jump", func(t *testing.T) {
+		ne := &nef.File{Script: []byte{byte(opcode.JMP), 0x7f, byte(opcode.RET)}}
+		require.Error(t, decompiler.Decompile(&strings.Builder{}, ne, nil, nil))
+	})
+	t.Run("invalid instruction", func(t *testing.T) {
+		ne := &nef.File{Script: []byte{byte(opcode.PUSHDATA1), 10}}
+		require.Error(t, decompiler.Decompile(&strings.Builder{}, ne, nil, nil))
+	})
+	t.Run("infinite loop", func(t *testing.T) {
+		ne := &nef.File{Script: []byte{byte(opcode.JMP), 0}}
+		out := decompile(t, ne, nil, nil)
+		require.Contains(t, out, "\tfor {\n\t}\n")
+	})
+}
+
+func FuzzDecompile(f *testing.F) {
+	ne, _, err := compiler.CompileWithOptions("foo.go", strings.NewReader(src), nil)
+	require.NoError(f, err)
+	f.Add(ne.Script)
+	f.Add([]byte{byte(opcode.JMP), 0})
+	f.Add([]byte{byte(opcode.PUSH0), byte(opcode.JMPIFNOT), 3, byte(opcode.PUSH1), byte(opcode.RET)})
+	f.Add([]byte{byte(opcode.PUSH1), byte(opcode.TRY), 3, 0, byte(opcode.ENDFINALLY), byte(opcode.RET)})
+	f.Fuzz(func(t *testing.T, script []byte) {
+		_ = decompiler.Decompile(io.Discard, &nef.File{Script: script}, nil, nil)
+	})
+}
@@ -0,0 +1,211 @@
+package decompiler
+
+import (
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Operator precedence levels (the same as in Go).
+const (
+	precOr = iota + 1
+	precAnd
+	precCmp
+	precAdd
+	precMul
+	precUnary
+	precPrimary
+)
+
+// expr is an expression built from stack items.
+type expr struct {
+	text string
+	prec int
+	// val is a known integer value of the constant expression.
+	val *big.Int
+	// items are the elements of the array created by PACK.
+	items []*expr
+	// target is the offset of the function for PUSHA pointers (-1 otherwise).
+	target int
+	// simple is set for variables and literals.
+	simple bool
+	// impure is set for expressions that have side effects or depend on
+	// mutable state other than variables (calls, item accesses).
+	impure bool
+	// isCall is set for function calls that can be used as statements.
+	isCall bool
+	// vars are the variables the expression reads.
+	vars []string
+	// Comparisons and negations keep their operands to be inverted nicely.
+	op   string
+	l, r *expr
+}
+
+func newExpr(text string, prec int) *expr {
+	return &expr{text: text, prec: prec, target: -1}
+}
+
+// ident returns a variable expression.
+func ident(name string) *expr {
+	e := newExpr(name, precPrimary)
+	e.vars = []string{name}
+	e.simple = true
+	return e
+}
+
+func intLit(v *big.Int) *expr {
+	prec := precPrimary
+	if v.Sign() < 0 {
+		prec = precUnary
+	}
+	e := newExpr(v.String(), prec)
+	e.val = v
+	e.simple = true
+	return e
+}
+
+func boolLit(b bool) *expr {
+	e := newExpr(strconv.FormatBool(b), precPrimary)
+	e.simple = true
+	if b {
+		e.val = big.NewInt(1)
+	} else {
+		e.val = big.NewInt(0)
+	}
+	return e
+}
+
+// bytesLit returns a string literal for the given data. Printable strings are
+// quoted as is, everything else is represented byte by byte.
+func bytesLit(b []byte) *expr {
+	if isPrintable(b) {
+		return lit(strconv.Quote(string(b)))
+	}
+	var sb strings.Builder
+	sb.WriteByte('"')
+	for _, c := range b {
+		sb.WriteString(`\x`)
+		sb.WriteString(strconv.FormatUint(uint64(c)>>4, 16))
+		sb.WriteString(strconv.FormatUint(uint64(c)&0xF, 16))
+	}
+	sb.WriteByte('"')
+	return lit(sb.String())
+}
+
+// lit returns a literal expression with the given text.
+func lit(text string) *expr {
+	e := newExpr(text, precPrimary)
+	e.simple = true
+	return e
+}
+
+func isPrintable(b []byte) bool {
+	if !utf8.Valid(b) {
+		return false
+	}
+	for _, r := range string(b) {
+		if !unicode.IsPrint(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func (e *expr) reads(name string) bool {
+	return slices.Contains(e.vars, name)
+}
+
+// wrap returns the expression text wrapped in parentheses if its precedence
+// is lower than the given one.
+func (e *expr) wrap(prec int) string {
+	if e.prec < prec {
+		return "(" + e.text + ")"
+	}
+	return e.text
+}
+
+// combine returns a new expression with the given text depending on args.
+func combine(text string, prec int, args ...*expr) *expr {
+	e := newExpr(text, prec)
+	for _, a := range args {
+		e.impure = e.impure || a.impure
+		for _, v := range a.vars {
+			if !slices.Contains(e.vars, v) {
+				e.vars = append(e.vars, v)
+			}
+		}
+	}
+	return e
+}
+
+// call returns a function call expression.
+func call(fn string, args ...*expr) *expr {
+	e := combine(fn+"("+joinExprs(args)+")", precPrimary, args...)
+	e.isCall = true
+	return e
+}
+
+func joinExprs(args []*expr) string {
+	var sb strings.Builder
+	for i, a := range args {
+		if i != 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(a.text)
+	}
+	return sb.String()
+}
+
+var negatedOps = map[string]string{
+	"==": "!=", "!=": "==",
+	"<": ">=", ">=": "<",
+	">": "<=", "<=": ">",
+}
+
+func binOp(op string, prec int, l, r *expr) *expr {
+	e := combine(l.wrap(prec)+" "+op+" "+r.wrap(prec+1), prec, l, r)
+	if prec == precCmp || prec == precAnd || prec == precOr {
+		e.op, e.l, e.r = op, l, r
+	}
+	return e
+}
+
+func unary(op string, x *expr) *expr {
+	if x.val != nil && op == "-" {
+		return intLit(new(big.Int).Neg(x.val))
+	}
+	e := combine(op+x.wrap(precUnary), precUnary, x)
+	if op == "!" {
+		e.op, e.l = op, x
+	}
+	return e
+}
+
+// not returns logical negation of the given expression.
+func not(x *expr) *expr {
+	switch x.op {
+	case "!":
+		return x.l
+	case "&&":
+		return binOp("||", precOr, not(x.l), not(x.r))
+	case "||":
+		return binOp("&&", precAnd, not(x.l), not(x.r))
+	}
+	if op, ok := negatedOps[x.op]; ok {
+		return binOp(op, precCmp, x.l, x.r)
+	}
+	if x.val != nil {
+		return boolLit(x.val.Sign() == 0)
+	}
+	return unary("!", x)
+}
+
+// index returns an item access expression.
+func index(x, key *expr) *expr {
+	e := combine(x.wrap(precPrimary)+"["+key.text+"]", precPrimary, x, key)
+	e.impure = true
+	return e
+}
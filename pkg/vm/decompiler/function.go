@@ -0,0 +1,578 @@
+package decompiler
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+)
+
+// function is a single script function.
+type function struct {
+	d *decompiler
+
+	name       string
+	comments   []string
+	start, end int
+	instrs     []*instr
+
+	nargs, nlocals int
+	// pointers are the local slots holding known function pointers.
+	pointers   map[int]int
+	params     []string
+	paramTypes []string
+	locals     []string
+	localTypes []string
+	// retType is the declared return type, empty if unknown or void.
+	retType string
+	// declared is set if the function is known from debug info or manifest.
+	declared bool
+	// returns is the number of values returned by the function, -1 if
+	// it's not known yet.
+	returns int
+
+	blocks  []*block
+	blockAt map[int]*block
+	temps   int
+	svars   int
+	body    string
+	err     error
+}
+
+// termKind is a kind of the basic block terminator.
+type termKind int
+
+const (
+	// termFall means that execution continues with the next block.
+	termFall termKind = iota
+	termJump
+	termCond
+	termReturn
+	// termExit is used for THROW, ABORT and ABORTMSG.
+	termExit
+	termTry
+	termEndTry
+	termEndFinally
+)
+
+// block is a basic block of the function.
+type block struct {
+	start, end int
+	instrs     []*instr
+	// depth is the evaluation stack depth at the block entry, -1 for
+	// unreachable blocks.
+	depth int
+	// catch is set for catch blocks (exception is pushed on the stack).
+	catch bool
+
+	stmts []string
+	term  termKind
+	// target is the jump target (catch block for TRY) offset.
+	target int
+	// finally is the finally block offset for TRY, -1 if none.
+	finally int
+	// cond is the condition the jump is taken with (for termCond).
+	cond *expr
+	// text is the return/panic statement.
+	text string
+	// preds is the number of block predecessors.
+	preds int
+}
+
+// init sets up function name, parameters and local variables.
+func (f *function) init(names map[string]int) {
+	if len(f.instrs) != 0 && f.instrs[0].op == opcode.INITSLOT {
+		f.nlocals, f.nargs = int(f.instrs[0].param[0]), int(f.instrs[0].param[1])
+	}
+	var (
+		dm = f.d.debugMethod(f.start)
+		mm = f.d.manifestMethod(f.start)
+	)
+	switch {
+	case dm != nil:
+		f.name = dm.ID
+	case mm != nil:
+		f.name = mm.Name
+	default:
+		f.name = fmt.Sprintf("sub_%04x", f.start)
+	}
+	if n := names[f.name]; n != 0 {
+		names[f.name]++
+		f.name = fmt.Sprintf("%s_%d", f.name, n)
+	} else {
+		names[f.name] = 1
+	}
+
+	f.comments = append(f.comments, fmt.Sprintf("Offset: %d.", f.start))
+	if mm != nil {
+		c := fmt.Sprintf("ABI method %q", mm.Name)
+		if mm.Safe {
+			c += " (safe)"
+		}
+		f.comments = append(f.comments, c+".")
+	}
+
+	f.params = make([]string, f.nargs)
+	f.paramTypes = make([]string, f.nargs)
+	for i := range f.params {
+		f.params[i] = fmt.Sprintf("arg%d", i)
+		f.paramTypes[i] = "any"
+		switch {
+		case dm != nil && len(dm.Parameters) == f.nargs:
+			f.params[i] = dm.Parameters[i].Name
+			f.paramTypes[i] = goTypeFromString(dm.Parameters[i].Type)
+		case dm != nil && len(dm.Parameters)+1 == f.nargs:
+			// Methods have receiver as the first argument.
+			if i == 0 {
+				f.params[i] = "recv"
+			} else {
+				f.params[i] = dm.Parameters[i-1].Name
+				f.paramTypes[i] = goTypeFromString(dm.Parameters[i-1].Type)
+			}
+		case mm != nil && len(mm.Parameters) == f.nargs:
+			f.params[i] = mm.Parameters[i].Name
+			f.paramTypes[i] = goType(mm.Parameters[i].Type)
+		}
+	}
+	switch {
+	case dm != nil:
+		f.declared = true
+		f.retType = goTypeFromString(dm.ReturnType)
+	case mm != nil:
+		f.declared = true
+		f.retType = goType(mm.ReturnType)
+	}
+
+	f.locals = make([]string, f.nlocals)
+	f.localTypes = make([]string, f.nlocals)
+	for i := range f.locals {
+		f.locals[i] = fmt.Sprintf("loc%d", i)
+		f.localTypes[i] = "any"
+	}
+	if dm != nil {
+		// Slots are only known for freshly compiled contracts, debug info
+		// files have variables listed in the order of slot allocation.
+		slots := dm.VariableSlots
+		if len(slots) != len(dm.Variables) && len(dm.Variables) == f.nlocals {
+			slots = make([]int, f.nlocals)
+			for i := range slots {
+				slots[i] = i
+			}
+		}
+		if len(slots) == len(dm.Variables) {
+			for i, v := range dm.Variables {
+				if slots[i] < 0 || slots[i] >= f.nlocals {
+					continue
+				}
+				name, typ, _ := strings.Cut(v, ",")
+				f.locals[slots[i]] = name
+				f.localTypes[slots[i]] = goTypeFromString(typ)
+			}
+		}
+	}
+	f.pointers = pointerSlots(f.instrs, opcode.STLOC0)
+
+	// Shadowed variables can have the same names.
+	used := make(map[string]bool)
+	for i := range f.params {
+		f.params[i] = uniqueName(used, f.params[i], i)
+	}
+	for i := range f.locals {
+		f.locals[i] = uniqueName(used, f.locals[i], i)
+	}
+}
+
+func uniqueName(used map[string]bool, name string, i int) string {
+	if used[name] || name == "_" || name == "" {
+		name = fmt.Sprintf("%s_%d", strings.TrimSuffix(name, "_"), i)
+	}
+	used[name] = true
+	return name
+}
+
+// declaredReturns returns the number of values returned by the function
+// according to debug info or manifest.
+func (f *function) declaredReturns() int {
+	if f.declared && f.retType != "" {
+		return 1
+	}
+	return 0
+}
+
+// buildBlocks splits function into basic blocks.
+func (f *function) buildBlocks() error {
+	if f.blocks != nil {
+		return nil
+	}
+	instrs := f.simplify()
+	leaders := map[int]bool{f.start: true}
+	for _, in := range instrs {
+		if isTerminator(in.op) {
+			leaders[in.next] = true
+		}
+		if in.op == opcode.CALL || in.op == opcode.CALLL || in.op == opcode.PUSHA {
+			continue
+		}
+		for _, t := range []int{in.target, in.finally} {
+			if t < 0 {
+				continue
+			}
+			if t < f.start || t >= f.end {
+				return fmt.Errorf("jump outside of the function at %d", in.offset)
+			}
+			leaders[t] = true
+		}
+	}
+	f.blockAt = make(map[int]*block)
+	var b *block
+	for _, in := range instrs {
+		if leaders[in.offset] {
+			b = &block{start: in.offset, depth: -1, target: -1, finally: -1}
+			f.blocks = append(f.blocks, b)
+			f.blockAt[in.offset] = b
+		}
+		b.instrs = append(b.instrs, in)
+		b.end = in.next
+	}
+	return nil
+}
+
+// idioms are instruction sequences emitted by the compiler for a single
+// operation. Jumps map instruction indices to the indices of their targets
+// (sequence length means the instruction after the sequence), op is the opcode
+// the sequence is replaced with, param is the index of the instruction
+// whose parameter is used.
+var idioms = []struct {
+	ops   []opcode.Opcode
+	jumps map[int]int
+	op    opcode.Opcode
+	param int
+}{
+	{ // Conversion: DUP ISTYPE JMPIF CONVERT.
+		ops:   []opcode.Opcode{opcode.DUP, opcode.ISTYPE, opcode.JMPIF, opcode.CONVERT},
+		jumps: map[int]int{2: 4},
+		op:    opcode.CONVERT,
+		param: 3,
+	},
+	{ // Nil-safe len(): DUP ISNULL JMPIF SIZE JMP DROP PUSH0.
+		ops:   []opcode.Opcode{opcode.DUP, opcode.ISNULL, opcode.JMPIF, opcode.SIZE, opcode.JMP, opcode.DROP, opcode.PUSH0},
+		jumps: map[int]int{2: 5, 4: 7},
+		op:    opcode.SIZE,
+		param: -1,
+	},
+	{ // Nil slice append: DUP ISNULL JMPIFNOT DROP NEWARRAY0.
+		ops:   []opcode.Opcode{opcode.DUP, opcode.ISNULL, opcode.JMPIFNOT, opcode.DROP, opcode.NEWARRAY0},
+		jumps: map[int]int{2: 5},
+		op:    opcode.NOP,
+		param: -1,
+	},
+	{ // Nil byte slice append: DUP ISNULL JMPIFNOT DROP PUSHDATA1.
+		ops:   []opcode.Opcode{opcode.DUP, opcode.ISNULL, opcode.JMPIFNOT, opcode.DROP, opcode.PUSHDATA1},
+		jumps: map[int]int{2: 5},
+		op:    opcode.NOP,
+		param: -1,
+	},
+}
+
+// simplify returns function instructions with compiler idioms replaced by
+// single instructions.
+func (f *function) simplify() []*instr {
+	targets := make(map[int]int)
+	for _, in := range f.instrs {
+		for _, t := range []int{in.target, in.finally} {
+			if t >= 0 {
+				targets[t]++
+			}
+		}
+	}
+	var res []*instr
+loop:
+	for i := 0; i < len(f.instrs); i++ {
+		for _, id := range idioms {
+			if i+len(id.ops) > len(f.instrs) {
+				continue
+			}
+			seq := f.instrs[i : i+len(id.ops)]
+			if !idiomMatches(seq, id.ops, id.jumps, targets) {
+				continue
+			}
+			if id.ops[len(id.ops)-1] == opcode.PUSHDATA1 && len(seq[len(seq)-1].param) != 0 {
+				continue
+			}
+			in := &instr{offset: seq[0].offset, op: id.op, next: seq[len(seq)-1].next, target: -1, finally: -1}
+			if id.param >= 0 {
+				in.param = seq[id.param].param
+				if seq[1].op == opcode.ISTYPE && seq[1].param[0] != in.param[0] {
+					continue
+				}
+			}
+			res = append(res, in)
+			i += len(id.ops) - 1
+			continue loop
+		}
+		res = append(res, f.instrs[i])
+	}
+	return res
+}
+
+func idiomMatches(seq []*instr, ops []opcode.Opcode, jumps map[int]int, targets map[int]int) bool {
+	for j, in := range seq {
+		if in.op != ops[j] {
+			return false
+		}
+		if t, ok := jumps[j]; ok && in.target != targetOf(seq, t) {
+			return false
+		}
+	}
+	// Only the sequence itself can jump into its middle.
+	for j := 1; j < len(seq); j++ {
+		n := 0
+		for _, t := range jumps {
+			if t == j {
+				n++
+			}
+		}
+		if targets[seq[j].offset] > n {
+			return false
+		}
+	}
+	return true
+}
+
+// targetOf returns the offset of the j-th instruction of the sequence, j can
+// point right after the sequence.
+func targetOf(seq []*instr, j int) int {
+	if j == len(seq) {
+		return seq[len(seq)-1].next
+	}
+	return seq[j].offset
+}
+
+func isTerminator(op opcode.Opcode) bool {
+	switch {
+	case opcode.JMP <= op && op <= opcode.JMPLEL:
+		return true
+	}
+	switch op {
+	case opcode.RET, opcode.THROW, opcode.ABORT, opcode.ABORTMSG, opcode.TRY, opcode.TRYL,
+		opcode.ENDTRY, opcode.ENDTRYL, opcode.ENDFINALLY:
+		return true
+	default:
+		return false
+	}
+}
+
+// probeReturns tries to determine the number of values returned by the
+// function, it returns true if succeeded.
+func (f *function) probeReturns() (ok bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			ok = false
+		}
+	}()
+	if f.buildBlocks() != nil {
+		return false
+	}
+	_ = f.run(true)
+	return f.returns >= 0
+}
+
+// decompile converts function code into statements. Any failure (including
+// unexpected panics on malformed code) makes the function to be printed as
+// disassembly.
+func (f *function) decompile() {
+	defer func() {
+		if r := recover(); r != nil {
+			f.err = fmt.Errorf("internal error: %v", r)
+		}
+	}()
+	if f.err = f.buildBlocks(); f.err != nil {
+		return
+	}
+	f.temps, f.svars = 0, 0
+	if f.err = f.run(false); f.err != nil {
+		return
+	}
+	f.mergeConditions()
+	f.body, f.err = f.structure()
+}
+
+// run simulates execution of all reachable blocks. In probe mode blocks
+// calling functions with unknown number of return values are skipped and the
+// number of values returned by this function is set once RET is reached.
+func (f *function) run(probe bool) error {
+	for _, b := range f.blocks {
+		b.depth, b.catch, b.preds = -1, false, 0
+	}
+	entry := f.blocks[0]
+	entry.depth = f.nargs
+	entry.preds = 1
+	queue := []*block{entry}
+	for len(queue) > 0 {
+		b := queue[0]
+		queue = queue[1:]
+		s := &simulator{f: f, probe: probe}
+		err := s.run(b)
+		if probe && errors.Is(err, errUnknownCallee) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("block at %d: %w", b.start, err)
+		}
+		depth := len(s.stack)
+		next := func(off, depth int, catch bool) error {
+			nb, ok := f.blockAt[off]
+			if !ok {
+				return fmt.Errorf("block at %d: execution falls off the function", b.start)
+			}
+			nb.preds++
+			if nb.depth < 0 {
+				nb.depth, nb.catch = depth, catch
+				queue = append(queue, nb)
+				return nil
+			}
+			if nb.depth != depth || nb.catch != catch {
+				return fmt.Errorf("inconsistent stack depth at %d", off)
+			}
+			return nil
+		}
+		switch b.term {
+		case termFall:
+			err = next(b.end, depth, false)
+		case termJump, termEndTry:
+			err = next(b.target, depth, false)
+		case termCond:
+			err = next(b.end, depth, false)
+			if err == nil {
+				err = next(b.target, depth, false)
+			}
+		case termTry:
+			err = next(b.end, depth, false)
+			if err == nil && b.target >= 0 {
+				err = next(b.target, depth+1, true)
+			}
+			if err == nil && b.finally >= 0 {
+				err = next(b.finally, depth, false)
+			}
+		case termReturn:
+			switch {
+			case f.returns < 0 && probe:
+				f.returns = depth
+			case f.returns != depth && !probe:
+				err = fmt.Errorf("RET at %d: %d values returned instead of %d", b.end-1, depth, f.returns)
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// entryStack returns the initial stack of the block.
+func (f *function) entryStack(b *block) []*expr {
+	stack := make([]*expr, b.depth)
+	for i := range stack {
+		if b == f.blocks[0] {
+			stack[i] = lit("arg") // Consumed by INITSLOT.
+			continue
+		}
+		stack[i] = ident(svar(i))
+	}
+	if b.catch {
+		stack[len(stack)-1] = ident("e")
+	}
+	return stack
+}
+
+func svar(i int) string {
+	return fmt.Sprintf("s%d", i)
+}
+
+func (f *function) newTemp() string {
+	f.temps++
+	return fmt.Sprintf("t%d", f.temps-1)
+}
+
+// mergeConditions merges blocks consisting of a single conditional jump into
+// the conditions of preceding blocks, which recovers && and || operators.
+func (f *function) mergeConditions() {
+	for changed := true; changed; {
+		changed = false
+		for i := 0; i+1 < len(f.blocks); i++ {
+			a, b := f.blocks[i], f.blocks[i+1]
+			if a.term != termCond || b.term != termCond || b.preds != 1 ||
+				len(b.stmts) != 0 || b.depth < 0 || b.catch {
+				continue
+			}
+			switch a.target {
+			case b.target:
+				a.cond = binOp("||", precOr, a.cond, b.cond)
+			case b.end:
+				a.cond = binOp("&&", precAnd, not(a.cond), b.cond)
+			default:
+				continue
+			}
+			// Two edges to the same block are merged into one.
+			if t, ok := f.blockAt[a.target]; ok {
+				t.preds--
+			}
+			a.target, a.end = b.target, b.end
+			a.instrs = append(a.instrs, b.instrs...)
+			delete(f.blockAt, b.start)
+			f.blocks = append(f.blocks[:i+1], f.blocks[i+2:]...)
+			changed = true
+		}
+	}
+}
+
+// write prints the function.
+func (f *function) write(b *strings.Builder) {
+	for _, c := range f.comments {
+		b.WriteString("// " + c + "\n")
+	}
+	params := make([]string, len(f.params))
+	for i := range params {
+		params[i] = f.params[i] + " " + f.paramTypes[i]
+	}
+	fmt.Fprintf(b, "func %s(%s)", f.name, strings.Join(params, ", "))
+	switch {
+	case f.returns == 1 && f.retType != "":
+		b.WriteString(" " + f.retType)
+	case f.returns == 1:
+		b.WriteString(" any")
+	case f.returns > 1:
+		b.WriteString(" (" + strings.TrimSuffix(strings.Repeat("any, ", f.returns), ", ") + ")")
+	}
+	b.WriteString(" {\n")
+	if f.err != nil {
+		fmt.Fprintf(b, "\t// Can't decompile: %s.\n", f.err)
+		for _, in := range f.instrs {
+			fmt.Fprintf(b, "\t// %d: %s", in.offset, in.op)
+			if len(in.param) != 0 {
+				fmt.Fprintf(b, " %x", in.param)
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("}\n")
+		return
+	}
+	for i, name := range f.locals {
+		fmt.Fprintf(b, "\tvar %s %s\n", name, f.localTypes[i])
+	}
+	var tmps []string
+	for i := range f.temps {
+		tmps = append(tmps, fmt.Sprintf("t%d", i))
+	}
+	for i := range f.svars {
+		tmps = append(tmps, svar(i))
+	}
+	if len(tmps) != 0 {
+		fmt.Fprintf(b, "\tvar %s any\n", strings.Join(tmps, ", "))
+	}
+	if f.body != "" {
+		b.WriteString(f.body + "\n")
+	}
+	b.WriteString("}\n")
+}
@@ -0,0 +1,766 @@
+package decompiler
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/interop/interopnames"
+	"github.com/nspcc-dev/neo-go/pkg/encoding/bigint"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+)
+
+// syscallSignature describes syscall stack usage.
+type syscallSignature struct {
+	params, results int
+}
+
+// syscalls contains the number of parameters and return values of all
+// known syscalls.
+var syscalls = map[string]syscallSignature{
+	interopnames.SystemContractCall:                  {4, 1},
+	interopnames.SystemContractCallNative:            {1, 0},
+	interopnames.SystemContractCreateMultisigAccount: {2, 1},
+	interopnames.SystemContractCreateStandardAccount: {1, 1},
+	interopnames.SystemContractGetCallFlags:          {0, 1},
+	interopnames.SystemContractNativeOnPersist:       {0, 0},
+	interopnames.SystemContractNativePostPersist:     {0, 0},
+	interopnames.SystemCryptoCheckMultisig:           {2, 1},
+	interopnames.SystemCryptoCheckSig:                {2, 1},
+	interopnames.SystemIteratorNext:                  {1, 1},
+	interopnames.SystemIteratorValue:                 {1, 1},
+	interopnames.SystemRuntimeBurnGas:                {1, 0},
+	interopnames.SystemRuntimeCheckWitness:           {1, 1},
+	interopnames.SystemRuntimeCurrentSigners:         {0, 1},
+	interopnames.SystemRuntimeGasLeft:                {0, 1},
+	interopnames.SystemRuntimeGetAddressVersion:      {0, 1},
+	interopnames.SystemRuntimeGetCallingScriptHash:   {0, 1},
+	interopnames.SystemRuntimeGetEntryScriptHash:     {0, 1},
+	interopnames.SystemRuntimeGetExecutingScriptHash: {0, 1},
+	interopnames.SystemRuntimeGetInvocationCounter:   {0, 1},
+	interopnames.SystemRuntimeGetNetwork:             {0, 1},
+	interopnames.SystemRuntimeGetNotifications:       {1, 1},
+	interopnames.SystemRuntimeGetRandom:              {0, 1},
+	interopnames.SystemRuntimeGetScriptContainer:     {0, 1},
+	interopnames.SystemRuntimeGetTime:                {0, 1},
+	interopnames.SystemRuntimeGetTrigger:             {0, 1},
+	interopnames.SystemRuntimeLoadScript:             {3, 1},
+	interopnames.SystemRuntimeLog:                    {1, 0},
+	interopnames.SystemRuntimeNotify:                 {2, 0},
+	interopnames.SystemRuntimePlatform:               {0, 1},
+	interopnames.SystemStorageAsReadOnly:             {1, 1},
+	interopnames.SystemStorageDelete:                 {2, 0},
+	interopnames.SystemStorageFind:                   {3, 1},
+	interopnames.SystemStorageGet:                    {2, 1},
+	interopnames.SystemStorageGetContext:             {0, 1},
+	interopnames.SystemStorageGetReadOnlyContext:     {0, 1},
+	interopnames.SystemStoragePut:                    {3, 0},
+}
+
+// syscallFunc returns the name of the interop function for the syscall, e.g.
+// `runtime.Log` for `System.Runtime.Log`.
+func syscallFunc(name string) string {
+	parts := strings.Split(name, ".")
+	if len(parts) != 3 {
+		return name
+	}
+	return strings.ToLower(parts[1]) + "." + parts[2]
+}
+
+// simError is used to abort block simulation.
+type simError struct {
+	err error
+}
+
+// simulator converts a single basic block into statements.
+type simulator struct {
+	f     *function
+	probe bool
+	stack []*expr
+	stmts []string
+}
+
+// run simulates the block and stores the result in it.
+func (s *simulator) run(b *block) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			se, ok := r.(simError)
+			if !ok {
+				panic(r)
+			}
+			err = se.err
+		}
+	}()
+	s.stack = s.f.entryStack(b)
+	for _, in := range b.instrs {
+		if isTerminator(in.op) {
+			s.terminate(b, in)
+			b.stmts = s.stmts
+			return nil
+		}
+		s.exec(in)
+	}
+	b.term, b.target = termFall, b.end
+	s.spill()
+	b.stmts = s.stmts
+	return nil
+}
+
+func (s *simulator) fail(format string, args ...any) {
+	panic(simError{fmt.Errorf(format, args...)})
+}
+
+func (s *simulator) push(e *expr) {
+	s.stack = append(s.stack, e)
+}
+
+func (s *simulator) pop() *expr {
+	if len(s.stack) == 0 {
+		s.fail("stack underflow")
+	}
+	e := s.stack[len(s.stack)-1]
+	s.stack = s.stack[:len(s.stack)-1]
+	return e
+}
+
+// popN pops n items, the top one is the first.
+func (s *simulator) popN(n int) []*expr {
+	items := make([]*expr, n)
+	for i := range items {
+		items[i] = s.pop()
+	}
+	return items
+}
+
+// popInt pops an item that must be a constant integer.
+func (s *simulator) popInt(op opcode.Opcode) int {
+	e := s.pop()
+	if e.val == nil || !e.val.IsInt64() || e.val.Sign() < 0 || e.val.Int64() > int64(len(s.stack)+1024) {
+		s.fail("%s with non-constant argument %s", op, e.text)
+	}
+	return int(e.val.Int64())
+}
+
+// peek returns the index of the n-th item from the top of the stack.
+func (s *simulator) peek(n int) int {
+	if n < 0 || n >= len(s.stack) {
+		s.fail("stack underflow")
+	}
+	return len(s.stack) - 1 - n
+}
+
+// materialize stores stack items matching the predicate into temporary
+// variables.
+func (s *simulator) materialize(pred func(*expr) bool) {
+	for i, e := range s.stack {
+		if pred(e) {
+			t := s.f.newTemp()
+			s.stmts = append(s.stmts, t+" = "+e.text)
+			s.stack[i] = ident(t)
+		}
+	}
+}
+
+func isImpure(e *expr) bool { return e.impure }
+
+// emit appends a statement ensuring that the values it could change are
+// evaluated before it.
+func (s *simulator) emit(stmt string) {
+	s.materialize(isImpure)
+	s.stmts = append(s.stmts, stmt)
+}
+
+// store emits an assignment to the variable.
+func (s *simulator) store(name string, v *expr) {
+	s.materialize(func(e *expr) bool { return e.impure || e.reads(name) })
+	s.stmts = append(s.stmts, name+" = "+v.text)
+}
+
+// dup returns the copy of the i-th stack item, complex expressions are stored
+// in a variable to be evaluated once.
+func (s *simulator) dup(i int) *expr {
+	if !s.stack[i].simple {
+		// Everything above the item is evaluated later.
+		s.materialize(isImpure)
+		if e := s.stack[i]; !e.simple {
+			t := s.f.newTemp()
+			s.stmts = append(s.stmts, t+" = "+e.text)
+			s.stack[i] = ident(t)
+		}
+	}
+	return s.stack[i]
+}
+
+// drop handles the removal of the item from the stack.
+func (s *simulator) drop(e *expr) {
+	if e.impure {
+		if e.isCall {
+			s.emit(e.text)
+		} else {
+			s.emit("_ = " + e.text)
+		}
+	}
+}
+
+// spill stores all stack items in s-variables, so that the stack is the same
+// for all predecessors of the next block.
+func (s *simulator) spill() {
+	var names, values []string
+	for i, e := range s.stack {
+		name := svar(i)
+		if e.text != name {
+			names = append(names, name)
+			values = append(values, e.text)
+		}
+		s.stack[i] = ident(name)
+	}
+	s.f.svars = max(s.f.svars, len(s.stack))
+	if len(names) != 0 {
+		s.stmts = append(s.stmts, strings.Join(names, ", ")+" = "+strings.Join(values, ", "))
+	}
+}
+
+// readsStack returns true if the expression reads any s-variable.
+func (s *simulator) readsStack(e *expr) bool {
+	for i := range s.stack {
+		if e.reads(svar(i)) {
+			return true
+		}
+	}
+	return false
+}
+
+// needsSpill returns true if spill would produce any statements.
+func (s *simulator) needsSpill() bool {
+	for i, e := range s.stack {
+		if e.text != svar(i) {
+			return true
+		}
+	}
+	return false
+}
+
+// pushCall handles the result of a call expression.
+func (s *simulator) pushCall(e *expr, results int) {
+	e.impure = true
+	switch results {
+	case 0:
+		s.emit(e.text)
+	case 1:
+		s.push(e)
+	default:
+		names := make([]string, results)
+		for i := range names {
+			names[i] = s.f.newTemp()
+		}
+		s.emit(strings.Join(names, ", ") + " = " + e.text)
+		for i := len(names) - 1; i >= 0; i-- {
+			s.push(ident(names[i]))
+		}
+	}
+}
+
+// callFunc handles a call of the script function.
+func (s *simulator) callFunc(fn string, callee *function) {
+	if callee.returns < 0 {
+		if s.probe {
+			panic(simError{errUnknownCallee})
+		}
+		s.fail("unknown number of values returned by %s", callee.name)
+	}
+	s.pushCall(call(fn, s.popN(callee.nargs)...), callee.returns)
+}
+
+// variable returns the name of the slot variable.
+func (s *simulator) variable(names []string, kind string, i int) string {
+	if i >= len(names) {
+		s.fail("%s slot %d is not initialized", kind, i)
+	}
+	return names[i]
+}
+
+// pointer returns the variable expression, it's marked as a function pointer
+// if the variable is known to hold one.
+func pointer(name string, pointers map[int]int, i int) *expr {
+	e := ident(name)
+	if t, ok := pointers[i]; ok && t >= 0 {
+		e.target = t
+	}
+	return e
+}
+
+// pointerSlots returns the slots that are only assigned a single function
+// pointer in the given instructions.
+func pointerSlots(instrs []*instr, base opcode.Opcode) map[int]int {
+	res := make(map[int]int)
+	for i, in := range instrs {
+		if in.op < base || in.op > base+7 {
+			continue
+		}
+		slot, t := slotIndex(in, base), -1
+		if i > 0 && instrs[i-1].op == opcode.PUSHA {
+			t = instrs[i-1].target
+		}
+		if old, ok := res[slot]; ok && old != t {
+			t = -1
+		}
+		res[slot] = t
+	}
+	return res
+}
+
+func slotIndex(in *instr, base opcode.Opcode) int {
+	if in.op-base == 7 {
+		return int(in.param[0])
+	}
+	return int(in.op - base)
+}
+
+var binaryOps = map[opcode.Opcode]struct {
+	op   string
+	prec int
+}{
+	opcode.ADD:         {"+", precAdd},
+	opcode.SUB:         {"-", precAdd},
+	opcode.CAT:         {"+", precAdd},
+	opcode.OR:          {"|", precAdd},
+	opcode.XOR:         {"^", precAdd},
+	opcode.MUL:         {"*", precMul},
+	opcode.DIV:         {"/", precMul},
+	opcode.MOD:         {"%", precMul},
+	opcode.SHL:         {"<<", precMul},
+	opcode.SHR:         {">>", precMul},
+	opcode.AND:         {"&", precMul},
+	opcode.EQUAL:       {"==", precCmp},
+	opcode.NOTEQUAL:    {"!=", precCmp},
+	opcode.NUMEQUAL:    {"==", precCmp},
+	opcode.NUMNOTEQUAL: {"!=", precCmp},
+	opcode.LT:          {"<", precCmp},
+	opcode.LE:          {"<=", precCmp},
+	opcode.GT:          {">", precCmp},
+	opcode.GE:          {">=", precCmp},
+	opcode.BOOLAND:     {"&&", precAnd},
+	opcode.BOOLOR:      {"||", precOr},
+}
+
+// builtins are opcodes represented as pseudo-builtin calls with the given
+// number of arguments (the bottom one is the first).
+var builtins = map[opcode.Opcode]struct {
+	name string
+	args int
+}{
+	opcode.SIGN:   {"sign", 1},
+	opcode.ABS:    {"abs", 1},
+	opcode.SQRT:   {"sqrt", 1},
+	opcode.POW:    {"pow", 2},
+	opcode.MIN:    {"min", 2},
+	opcode.MAX:    {"max", 2},
+	opcode.MODMUL: {"modMul", 3},
+	opcode.MODPOW: {"modPow", 3},
+	opcode.WITHIN: {"within", 3},
+	opcode.SIZE:   {"len", 1},
+	opcode.HASKEY: {"hasKey", 2},
+	opcode.KEYS:   {"keys", 1},
+	opcode.VALUES: {"values", 1},
+}
+
+// reversed returns the items in the reversed order.
+func reversed(items []*expr) []*expr {
+	res := make([]*expr, len(items))
+	for i := range items {
+		res[len(items)-1-i] = items[i]
+	}
+	return res
+}
+
+// exec simulates a non-terminating instruction.
+func (s *simulator) exec(in *instr) {
+	var (
+		d  = s.f.d
+		op = in.op
+	)
+	switch {
+	case opcode.PUSHINT8 <= op && op <= opcode.PUSHINT256:
+		s.push(intLit(bigint.FromBytes(in.param)))
+		return
+	case opcode.PUSHM1 <= op && op <= opcode.PUSH16:
+		s.push(intLit(big.NewInt(int64(op) - int64(opcode.PUSH0))))
+		return
+	case opcode.LDSFLD0 <= op && op <= opcode.LDSFLD:
+		i := slotIndex(in, opcode.LDSFLD0)
+		s.push(pointer(s.variable(d.statics, "static", i), d.staticPointers, i))
+		return
+	case opcode.STSFLD0 <= op && op <= opcode.STSFLD:
+		s.store(s.variable(d.statics, "static", slotIndex(in, opcode.STSFLD0)), s.pop())
+		return
+	case opcode.LDLOC0 <= op && op <= opcode.LDLOC:
+		i := slotIndex(in, opcode.LDLOC0)
+		s.push(pointer(s.variable(s.f.locals, "local", i), s.f.pointers, i))
+		return
+	case opcode.STLOC0 <= op && op <= opcode.STLOC:
+		s.store(s.variable(s.f.locals, "local", slotIndex(in, opcode.STLOC0)), s.pop())
+		return
+	case opcode.LDARG0 <= op && op <= opcode.LDARG:
+		s.push(ident(s.variable(s.f.params, "argument", slotIndex(in, opcode.LDARG0))))
+		return
+	case opcode.STARG0 <= op && op <= opcode.STARG:
+		s.store(s.variable(s.f.params, "argument", slotIndex(in, opcode.STARG0)), s.pop())
+		return
+	}
+	if bo, ok := binaryOps[op]; ok {
+		r, l := s.pop(), s.pop()
+		s.push(binOp(bo.op, bo.prec, l, r))
+		return
+	}
+	if bi, ok := builtins[op]; ok {
+		e := call(bi.name, reversed(s.popN(bi.args))...)
+		if op == opcode.SIZE || op == opcode.HASKEY || op == opcode.KEYS || op == opcode.VALUES {
+			// Depend on the mutable compound item.
+			e.impure = true
+		}
+		s.push(e)
+		return
+	}
+	switch op {
+	case opcode.NOP, opcode.INITSSLOT:
+	case opcode.INITSLOT:
+		if in.offset != s.f.start {
+			s.fail("INITSLOT in the middle of the function")
+		}
+		s.popN(s.f.nargs)
+	case opcode.PUSHT, opcode.PUSHF:
+		s.push(boolLit(op == opcode.PUSHT))
+	case opcode.PUSHNULL:
+		s.push(lit("nil"))
+	case opcode.PUSHDATA1, opcode.PUSHDATA2, opcode.PUSHDATA4:
+		if len(in.param) == util.Uint160Size {
+			if name, ok := d.nativeName(util.Uint160(in.param)); ok {
+				s.push(lit(name + ".Hash"))
+				return
+			}
+		}
+		s.push(bytesLit(in.param))
+	case opcode.PUSHA:
+		e := lit(d.funcAt[in.target].name)
+		e.target = in.target
+		s.push(e)
+
+	case opcode.CALL, opcode.CALLL:
+		callee := d.funcAt[in.target]
+		s.callFunc(callee.name, callee)
+	case opcode.CALLA:
+		p := s.pop()
+		if p.target < 0 {
+			s.fail("CALLA with unknown pointer %s", p.text)
+		}
+		s.callFunc(p.text, d.funcAt[p.target])
+	case opcode.CALLT:
+		idx := int(binary.LittleEndian.Uint16(in.param))
+		if idx >= len(d.nef.Tokens) {
+			s.fail("invalid method token %d", idx)
+		}
+		tok := d.nef.Tokens[idx]
+		args := s.popN(int(tok.ParamCount))
+		var e *expr
+		if name, ok := d.nativeName(tok.Hash); ok {
+			e = call(name+"."+exported(tok.Method), args...)
+		} else {
+			e = call("callt", append([]*expr{lit("0x" + tok.Hash.StringLE()), lit(strconv.Quote(tok.Method))}, args...)...)
+		}
+		results := 0
+		if tok.HasReturn {
+			results = 1
+		}
+		s.pushCall(e, results)
+	case opcode.SYSCALL:
+		name, err := interopnames.FromID(binary.LittleEndian.Uint32(in.param))
+		if err != nil {
+			s.fail("unknown syscall %x", in.param)
+		}
+		sig, ok := syscalls[name]
+		if !ok {
+			s.fail("unknown syscall %s", name)
+		}
+		s.pushCall(call(syscallFunc(name), s.popN(sig.params)...), sig.results)
+
+	case opcode.ASSERT:
+		s.emit(call("assert", s.pop()).text)
+	case opcode.ASSERTMSG:
+		msg, x := s.pop(), s.pop()
+		s.emit(call("assert", x, msg).text)
+
+	case opcode.DEPTH:
+		s.push(intLit(big.NewInt(int64(len(s.stack)))))
+	case opcode.DROP:
+		s.drop(s.pop())
+	case opcode.NIP:
+		i := s.peek(1)
+		e := s.stack[i]
+		s.stack = append(s.stack[:i], s.stack[i+1:]...)
+		s.drop(e)
+	case opcode.XDROP:
+		i := s.peek(s.popInt(op))
+		e := s.stack[i]
+		s.stack = append(s.stack[:i], s.stack[i+1:]...)
+		s.drop(e)
+	case opcode.CLEAR:
+		for len(s.stack) != 0 {
+			s.drop(s.stack[0])
+			s.stack = s.stack[1:]
+		}
+	case opcode.DUP:
+		s.push(s.dup(s.peek(0)))
+	case opcode.OVER:
+		s.push(s.dup(s.peek(1)))
+	case opcode.PICK:
+		s.push(s.dup(s.peek(s.popInt(op))))
+	case opcode.TUCK:
+		e := s.dup(s.peek(0))
+		i := s.peek(1)
+		s.stack = append(s.stack[:i], append([]*expr{e}, s.stack[i:]...)...)
+	case opcode.SWAP:
+		s.roll(1)
+	case opcode.ROT:
+		s.roll(2)
+	case opcode.ROLL:
+		s.roll(s.popInt(op))
+	case opcode.REVERSE3:
+		s.reverse(3)
+	case opcode.REVERSE4:
+		s.reverse(4)
+	case opcode.REVERSEN:
+		s.reverse(s.popInt(op))
+
+	case opcode.INVERT:
+		s.push(unary("^", s.pop()))
+	case opcode.NEGATE:
+		s.push(unary("-", s.pop()))
+	case opcode.NOT:
+		s.push(not(s.pop()))
+	case opcode.INC, opcode.DEC:
+		x, sign := s.pop(), "+"
+		if op == opcode.DEC {
+			sign = "-"
+		}
+		s.push(binOp(sign, precAdd, x, intLit(big.NewInt(1))))
+	case opcode.NZ:
+		s.push(binOp("!=", precCmp, s.pop(), intLit(big.NewInt(0))))
+	case opcode.ISNULL:
+		s.push(binOp("==", precCmp, s.pop(), lit("nil")))
+	case opcode.ISTYPE:
+		s.push(call("isType["+itemType(stackitem.Type(in.param[0]))+"]", s.pop()))
+	case opcode.CONVERT:
+		s.push(call(itemType(stackitem.Type(in.param[0])), s.pop()))
+
+	case opcode.NEWBUFFER:
+		s.push(call("make", lit("[]byte"), s.pop()))
+	case opcode.MEMCPY:
+		n, si, src, di, dst := s.pop(), s.pop(), s.pop(), s.pop(), s.pop()
+		s.emit(call("copy",
+			combine(dst.wrap(precPrimary)+"["+di.text+":]", precPrimary, dst, di),
+			combine(src.wrap(precPrimary)+"["+si.text+":"+binOp("+", precAdd, si, n).text+"]", precPrimary, src, si, n),
+		).text)
+	case opcode.SUBSTR:
+		n, i, x := s.pop(), s.pop(), s.pop()
+		s.push(combine(x.wrap(precPrimary)+"["+i.text+":"+binOp("+", precAdd, i, n).text+"]", precPrimary, x, i, n))
+	case opcode.LEFT:
+		n, x := s.pop(), s.pop()
+		s.push(combine(x.wrap(precPrimary)+"[:"+n.text+"]", precPrimary, x, n))
+	case opcode.RIGHT:
+		n, x := s.pop(), s.pop()
+		s.push(combine(x.wrap(precPrimary)+"[len("+x.text+")-"+n.wrap(precMul)+":]", precPrimary, x, n))
+
+	case opcode.PACK, opcode.PACKSTRUCT:
+		items := s.popN(s.popInt(op))
+		typ := "[]any"
+		if op == opcode.PACKSTRUCT {
+			typ = "Struct"
+		}
+		e := combine(typ+"{"+joinExprs(items)+"}", precPrimary, items...)
+		e.items = items
+		s.push(e)
+	case opcode.PACKMAP:
+		n := s.popInt(op)
+		var (
+			args  []*expr
+			pairs []string
+		)
+		for range n {
+			k, v := s.pop(), s.pop()
+			args = append(args, k, v)
+			pairs = append(pairs, k.text+": "+v.text)
+		}
+		s.push(combine("map[any]any{"+strings.Join(pairs, ", ")+"}", precPrimary, args...))
+	case opcode.UNPACK:
+		x := s.pop()
+		if x.items == nil {
+			s.fail("UNPACK of unknown compound item %s", x.text)
+		}
+		for i := len(x.items) - 1; i >= 0; i-- {
+			s.push(x.items[i])
+		}
+		s.push(intLit(big.NewInt(int64(len(x.items)))))
+	case opcode.NEWARRAY0:
+		s.push(lit("[]any{}"))
+	case opcode.NEWARRAY:
+		s.push(call("make", lit("[]any"), s.pop()))
+	case opcode.NEWARRAYT:
+		s.push(call("make", lit("[]"+itemType(stackitem.Type(in.param[0]))), s.pop()))
+	case opcode.NEWSTRUCT0:
+		s.push(lit("Struct{}"))
+	case opcode.NEWSTRUCT:
+		s.push(call("newStruct", s.pop()))
+	case opcode.NEWMAP:
+		s.push(lit("map[any]any{}"))
+	case opcode.PICKITEM:
+		k, x := s.pop(), s.pop()
+		s.push(index(x, k))
+	case opcode.SETITEM:
+		v, k, x := s.pop(), s.pop(), s.pop()
+		s.emit(index(x, k).text + " = " + v.text)
+	case opcode.APPEND:
+		v, x := s.pop(), s.pop()
+		s.emit(call("append", x, v).text)
+	case opcode.REMOVE:
+		k, x := s.pop(), s.pop()
+		s.emit(call("delete", x, k).text)
+	case opcode.CLEARITEMS:
+		s.emit(call("clear", s.pop()).text)
+	case opcode.REVERSEITEMS:
+		s.emit(call("reverse", s.pop()).text)
+	case opcode.POPITEM:
+		e := call("popItem", s.pop())
+		e.impure = true
+		s.push(e)
+	default:
+		s.fail("unsupported instruction %s", op)
+	}
+}
+
+// roll moves the n-th item to the top of the stack.
+func (s *simulator) roll(n int) {
+	i := s.peek(n)
+	e := s.stack[i]
+	s.stack = append(append(s.stack[:i:i], s.stack[i+1:]...), e)
+}
+
+// reverse reverses n top items.
+func (s *simulator) reverse(n int) {
+	if n == 0 {
+		return
+	}
+	i := s.peek(n - 1)
+	top := s.stack[i:]
+	for l, r := 0, len(top)-1; l < r; l, r = l+1, r-1 {
+		top[l], top[r] = top[r], top[l]
+	}
+}
+
+// terminate handles the last instruction of the block.
+func (s *simulator) terminate(b *block, in *instr) {
+	b.target, b.finally, b.cond, b.text = in.target, -1, nil, ""
+	switch op := in.op; op {
+	case opcode.JMP, opcode.JMPL:
+		b.term = termJump
+		s.spill()
+	case opcode.RET:
+		b.term = termReturn
+		b.text = "return"
+		if len(s.stack) != 0 {
+			// The first value is on top of the stack.
+			b.text += " " + joinExprs(reversed(s.stack))
+		}
+	case opcode.THROW:
+		b.term = termExit
+		b.text = call("panic", s.pop()).text
+	case opcode.ABORT:
+		b.term = termExit
+		b.text = "abort()"
+	case opcode.ABORTMSG:
+		b.term = termExit
+		b.text = call("abort", s.pop()).text
+	case opcode.TRY, opcode.TRYL:
+		b.term = termTry
+		b.finally = in.finally
+		s.spill()
+	case opcode.ENDTRY, opcode.ENDTRYL:
+		b.term = termEndTry
+		s.spill()
+	case opcode.ENDFINALLY:
+		b.term = termEndFinally
+		s.spill()
+	default:
+		b.term = termCond
+		var cond *expr
+		switch op {
+		case opcode.JMPIF, opcode.JMPIFL:
+			cond = s.pop()
+		case opcode.JMPIFNOT, opcode.JMPIFNOTL:
+			cond = not(s.pop())
+		default:
+			r, l := s.pop(), s.pop()
+			cond = binOp(condOps[op], precCmp, l, r)
+		}
+		if s.needsSpill() && !cond.simple && (cond.impure || s.readsStack(cond)) {
+			// Condition is evaluated after the spill.
+			s.materialize(isImpure)
+			t := s.f.newTemp()
+			s.stmts = append(s.stmts, t+" = "+cond.text)
+			cond = ident(t)
+		}
+		s.spill()
+		b.cond = cond
+	}
+}
+
+var condOps = map[opcode.Opcode]string{
+	opcode.JMPEQ: "==", opcode.JMPEQL: "==",
+	opcode.JMPNE: "!=", opcode.JMPNEL: "!=",
+	opcode.JMPGT: ">", opcode.JMPGTL: ">",
+	opcode.JMPGE: ">=", opcode.JMPGEL: ">=",
+	opcode.JMPLT: "<", opcode.JMPLTL: "<",
+	opcode.JMPLE: "<=", opcode.JMPLEL: "<=",
+}
+
+// itemType returns the pseudo-Go type name for the stack item type.
+func itemType(t stackitem.Type) string {
+	switch t {
+	case stackitem.AnyT:
+		return "any"
+	case stackitem.PointerT:
+		return "Pointer"
+	case stackitem.BooleanT:
+		return "bool"
+	case stackitem.IntegerT:
+		return "int"
+	case stackitem.ByteArrayT:
+		return "string"
+	case stackitem.BufferT:
+		return "[]byte"
+	case stackitem.ArrayT:
+		return "[]any"
+	case stackitem.StructT:
+		return "Struct"
+	case stackitem.MapT:
+		return "map[any]any"
+	case stackitem.InteropT:
+		return "interop.Interface"
+	default:
+		return fmt.Sprintf("Type%02x", byte(t))
+	}
+}
+
+// exported returns the name with the first letter capitalized.
+func exported(name string) string {
+	if name == "" {
+		return name
+	}
+	r := []rune(name)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
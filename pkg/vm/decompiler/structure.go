@@ -0,0 +1,366 @@
+package decompiler
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// maxStructureSteps limits the number of blocks printed for a single
+// function, it protects from exponential growth of the output for irreducible
+// control flow.
+const maxStructureSteps = 10000
+
+var errComplexFlow = errors.New("control flow is too complex")
+
+// loop is the innermost loop being structured.
+type loop struct {
+	head, exit int
+}
+
+// structurer converts function blocks into nested statements.
+type structurer struct {
+	f      *function
+	blocks []*block
+	idx    map[int]int // Block offset -> index.
+	// labels are blocks referenced by goto statements.
+	labels map[int]bool
+	gotos  map[int]bool
+	lines  []string
+	indent int
+	steps  int
+	err    error
+}
+
+// structure returns function body built from its blocks. Body is rendered
+// twice, since goto statements can refer to the blocks printed before them.
+func (f *function) structure() (string, error) {
+	s := &structurer{
+		f:      f,
+		blocks: f.blocks,
+		idx:    make(map[int]int, len(f.blocks)),
+		labels: make(map[int]bool),
+	}
+	for i, b := range f.blocks {
+		s.idx[b.start] = i
+	}
+	for range 2 {
+		s.lines, s.indent, s.gotos, s.steps = nil, 1, make(map[int]bool), 0
+		s.emitRange(0, len(s.blocks), -1, nil, -1)
+		if s.err != nil {
+			return "", s.err
+		}
+		s.labels = s.gotos
+	}
+	return strings.Join(s.lines, "\n"), nil
+}
+
+func (s *structurer) line(format string, args ...any) {
+	s.lines = append(s.lines, strings.Repeat("\t", s.indent)+fmt.Sprintf(format, args...))
+}
+
+// index returns the index of the block starting at the given offset, the
+// number of blocks (end of the function) if there is no such block.
+func (s *structurer) index(off int) int {
+	if i, ok := s.idx[off]; ok {
+		return i
+	}
+	return len(s.blocks)
+}
+
+// succs returns the indices of blocks the control can be passed to from the
+// given block.
+func (s *structurer) succs(b *block) []int {
+	var offs []int
+	switch b.term {
+	case termFall:
+		offs = []int{b.end}
+	case termJump, termEndTry:
+		offs = []int{b.target}
+	case termCond:
+		offs = []int{b.end, b.target}
+	case termTry:
+		offs = []int{b.end, b.target, b.finally}
+	}
+	var res []int
+	for _, off := range offs {
+		if i, ok := s.idx[off]; ok {
+			res = append(res, i)
+		}
+	}
+	return res
+}
+
+// jumpStmt returns a statement passing the control to the block t.
+func (s *structurer) jumpStmt(t int, l *loop) string {
+	switch {
+	case l != nil && t == l.head:
+		return "continue"
+	case l != nil && t == l.exit:
+		return "break"
+	case t < 0 || t >= len(s.blocks):
+		s.err = errors.New("jump to the end of the function")
+		return ""
+	default:
+		s.gotos[t] = true
+		return fmt.Sprintf("goto L_%04x", s.blocks[t].start)
+	}
+}
+
+// flow handles the control transfer to the block t when next is the block
+// index that follows in the printed code.
+func (s *structurer) flow(t, next, to, follow int, l *loop) {
+	// The end of the function is only reachable as an exit of infinite
+	// loop, there is nothing to jump to.
+	if t == next && next < to || next >= to && t == follow || t >= len(s.blocks) {
+		return
+	}
+	s.line("%s", s.jumpStmt(t, l))
+}
+
+// emitRange prints blocks from the [from, to) range. Follow is the block the
+// control is passed to after the range, skip is the loop head that is being
+// printed.
+func (s *structurer) emitRange(from, to, follow int, l *loop, skip int) {
+	for i := from; i < to && s.err == nil; {
+		if s.blocks[i].depth < 0 {
+			i++
+			continue
+		}
+		s.steps++
+		if s.steps > maxStructureSteps {
+			s.err = errComplexFlow
+			return
+		}
+		var next int
+		if last := s.lastBackEdge(i, to); i != skip && last >= 0 {
+			next = s.emitLoop(i, last, to, follow, l)
+		} else {
+			next = s.emitBlock(i, to, follow, l)
+		}
+		if next <= i {
+			s.err = fmt.Errorf("can't structure control flow at %d", s.blocks[i].start)
+			return
+		}
+		i = next
+	}
+}
+
+// lastBackEdge returns the index of the last block in the [i, to) range
+// passing the control to the block i, -1 if there is none.
+func (s *structurer) lastBackEdge(i, to int) int {
+	last := -1
+	for j := i; j < to; j++ {
+		if s.blocks[j].depth >= 0 && containsInt(s.succs(s.blocks[j]), i) {
+			last = j
+		}
+	}
+	return last
+}
+
+func containsInt(s []int, v int) bool {
+	for _, x := range s {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *structurer) label(i int) {
+	if s.labels[i] {
+		s.lines = append(s.lines, fmt.Sprintf("L_%04x:", s.blocks[i].start))
+	}
+}
+
+// emitLoop prints the loop with the head at i and the last back edge in the
+// block last, it returns the index of the next block to print.
+func (s *structurer) emitLoop(i, last, to, follow int, outer *loop) int {
+	var (
+		head = s.blocks[i]
+		l    = &loop{head: i, exit: last + 1}
+	)
+	s.label(i)
+	if head.term == termCond && len(head.stmts) == 0 && s.index(head.target) == l.exit {
+		s.line("for %s {", not(head.cond).text)
+		s.indent++
+		s.emitRange(i+1, l.exit, i, l, -1)
+	} else {
+		s.line("for {")
+		s.indent++
+		s.emitRange(i, l.exit, i, l, i)
+	}
+	s.indent--
+	s.line("}")
+	s.flow(l.exit, l.exit, to, follow, outer)
+	return l.exit
+}
+
+// emitBlock prints the block at i and the structured statement it starts,
+// it returns the index of the next block to print.
+func (s *structurer) emitBlock(i, to, follow int, l *loop) int {
+	b := s.blocks[i]
+	s.label(i)
+	for _, st := range b.stmts {
+		s.line("%s", st)
+	}
+	switch b.term {
+	case termFall, termJump, termEndTry:
+		s.flow(s.index(b.target), i+1, to, follow, l)
+	case termReturn, termExit:
+		s.line("%s", b.text)
+	case termEndFinally:
+	case termCond:
+		return s.emitCond(i, to, follow, l)
+	case termTry:
+		return s.emitTry(i, to, follow, l)
+	}
+	return i + 1
+}
+
+// emitCond prints if/else statement started by the conditional jump in the
+// block i.
+func (s *structurer) emitCond(i, to, follow int, l *loop) int {
+	var (
+		b = s.blocks[i]
+		t = s.index(b.target)
+	)
+	switch {
+	case t == i+1:
+		if b.cond.impure {
+			s.line("_ = %s", b.cond.text)
+		}
+		return i + 1
+	case t > i+1 && t <= to:
+		if e, ok := s.elseEnd(t, to); ok {
+			s.line("if %s {", not(b.cond).text)
+			s.indent++
+			n := len(s.lines)
+			s.emitRange(i+1, t, e, l, -1)
+			s.indent--
+			if len(s.lines) == n {
+				// Empty then-branch, invert the condition.
+				s.lines[n-1] = strings.Repeat("\t", s.indent) + "if " + b.cond.text + " {"
+			} else {
+				s.line("} else {")
+			}
+			s.indent++
+			s.emitRange(t, e, e, l, -1)
+			s.indent--
+			s.line("}")
+			s.flow(e, e, to, follow, l)
+			return e
+		}
+		s.line("if %s {", not(b.cond).text)
+		s.indent++
+		s.emitRange(i+1, t, t, l, -1)
+		s.indent--
+		s.line("}")
+		s.flow(t, t, to, follow, l)
+		return t
+	case i+1 == to && t == follow:
+		s.line("if %s {", not(b.cond).text)
+		s.indent++
+		s.line("%s", s.jumpStmt(i+1, l))
+		s.indent--
+		s.line("}")
+		return i + 1
+	default:
+		s.line("if %s {", b.cond.text)
+		s.indent++
+		s.line("%s", s.jumpStmt(t, l))
+		s.indent--
+		s.line("}")
+		s.flow(i+1, i+1, to, follow, l)
+		return i + 1
+	}
+}
+
+// elseEnd checks whether the then-branch ending before the block t jumps over
+// the else-branch and returns the index of the block following the else
+// branch.
+func (s *structurer) elseEnd(t, to int) (int, bool) {
+	prev := s.blocks[t-1]
+	if prev.term != termJump || prev.depth < 0 {
+		return 0, false
+	}
+	e, ok := s.idx[prev.target]
+	if !ok || e <= t || e > to {
+		return 0, false
+	}
+	return e, true
+}
+
+// emitTry prints try/catch/finally statement started in the block i.
+func (s *structurer) emitTry(i, to, follow int, l *loop) int {
+	var (
+		b       = s.blocks[i]
+		catch   = -1
+		finally = -1
+		tryEnd  int
+		end     = -1 // The block executed after the statement.
+	)
+	if b.target >= 0 {
+		catch = s.index(b.target)
+		tryEnd = catch
+	}
+	if b.finally >= 0 {
+		finally = s.index(b.finally)
+		if catch < 0 {
+			tryEnd = finally
+		}
+	}
+	// ENDTRY targets point to the block after the statement.
+	findEnd := func(from, to int) {
+		for j := from; j < to && end < 0; j++ {
+			if bj := s.blocks[j]; bj.depth >= 0 && bj.term == termEndTry {
+				end = s.index(bj.target)
+			}
+		}
+	}
+	findEnd(i+1, tryEnd)
+	catchEnd := finally
+	if catch >= 0 && finally < 0 {
+		findEnd(catch, to)
+		catchEnd = end
+		if end < 0 || end < catch || end > to {
+			catchEnd = to
+		}
+	}
+	finallyEnd := -1
+	if finally >= 0 {
+		for j := finally; j < to; j++ {
+			if s.blocks[j].term == termEndFinally {
+				finallyEnd = j + 1
+				break
+			}
+		}
+		if finallyEnd < 0 {
+			finallyEnd = to
+		}
+	}
+	next := max(catchEnd, finallyEnd, tryEnd)
+	if end < 0 || end < next || end > to {
+		end = next
+	}
+
+	s.line("try {")
+	s.indent++
+	s.emitRange(i+1, tryEnd, end, l, -1)
+	s.indent--
+	if catch >= 0 {
+		s.line("} catch e {")
+		s.indent++
+		s.emitRange(catch, catchEnd, end, l, -1)
+		s.indent--
+	}
+	if finally >= 0 {
+		s.line("} finally {")
+		s.indent++
+		s.emitRange(finally, finallyEnd, -1, l, -1)
+		s.indent--
+	}
+	s.line("}")
+	s.flow(end, next, to, follow, l)
+	return next
+}
@@ -0,0 +1,300 @@
+/*
+Package gasprofile implements GAS profiler for NeoVM executions.
+
+Profiler accounts GAS spent by every executed instruction (including prices of
+syscalls and native contract calls) to the invocation stack it was executed
+with and maps stack frames to contract functions and source code lines using
+contract debug info. Collected data can be exported as a pprof profile, so
+that standard `go tool pprof` can be used to analyze it.
+*/
+package gasprofile
+
+import (
+	"io"
+	"slices"
+	"sync"
+
+	"github.com/google/pprof/profile"
+	"github.com/nspcc-dev/neo-go/pkg/compiler"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+)
+
+// Profiler collects GAS consumption data of VM executions. It's safe for
+// concurrent use, so the same Profiler can be attached to multiple VMs
+// running in parallel.
+type Profiler struct {
+	lock      sync.Mutex
+	contracts map[util.Uint160]*contract
+	samples   map[string]*sample
+}
+
+// Tracer accounts GAS consumption of a single VM to the Profiler it was
+// created by.
+type Tracer struct {
+	p       *Profiler
+	v       *vm.VM
+	stack   []frame
+	gas     int64
+	pending bool
+}
+
+// contract is the data used to resolve contract frames.
+type contract struct {
+	name      string
+	manifest  *manifest.Manifest
+	debugInfo *compiler.DebugInfo
+}
+
+// frame is a single invocation stack frame.
+type frame struct {
+	hash util.Uint160
+	ip   int
+}
+
+// sample is the data aggregated for a single invocation stack.
+type sample struct {
+	stack        []frame
+	gas          int64
+	instructions int64
+}
+
+// New creates a new empty Profiler.
+func New() *Profiler {
+	return &Profiler{
+		contracts: make(map[util.Uint160]*contract),
+		samples:   make(map[string]*sample),
+	}
+}
+
+// AddContract registers contract with the given hash, so that frames of this
+// contract are named after the contract methods. Both manifest and debug info
+// are optional, debug info (if present) allows to map frames to the source
+// code lines, otherwise manifest methods are used. Contracts that are not
+// registered are named after their hashes. Contract data is resolved when
+// the profile is built, so contracts can be registered after the execution
+// and subsequent registrations of the same contract replace previous ones.
+func (p *Profiler) AddContract(h util.Uint160, m *manifest.Manifest, di *compiler.DebugInfo) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	var c = &contract{
+		name:      "0x" + h.StringLE(),
+		manifest:  m,
+		debugInfo: di,
+	}
+	if m != nil && m.Name != "" {
+		c.name = m.Name
+	}
+	p.contracts[h] = c
+}
+
+// Reset drops all the data collected by the profiler, registered contracts
+// are kept.
+func (p *Profiler) Reset() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	clear(p.samples)
+}
+
+// Attach creates a new Tracer for the given VM and sets it as the VM OnExecHook.
+// It panics if the VM is already started.
+func (p *Profiler) Attach(v *vm.VM) *Tracer {
+	t := p.NewTracer(v)
+	v.SetOnExecHook(t.Hook)
+	return t
+}
+
+// NewTracer creates a new Tracer for the given VM. Tracer's Hook must be
+// called for every instruction executed by the VM, it's useful when the hook
+// is to be combined with some other OnExecHook, otherwise use Attach.
+func (p *Profiler) NewTracer(v *vm.VM) *Tracer {
+	return &Tracer{
+		p:   p,
+		v:   v,
+		gas: v.GasConsumed(),
+	}
+}
+
+// Hook is the vm.OnExecHook accounting the instruction executed before to the
+// profiler and remembering the invocation stack of the current one.
+func (t *Tracer) Hook(_ util.Uint160, _ int, _ opcode.Opcode) {
+	t.Flush()
+	istack := t.v.Istack()
+	t.stack = t.stack[:0]
+	for i := len(istack) - 1; i >= 0; i-- {
+		t.stack = append(t.stack, frame{hash: istack[i].ScriptHash(), ip: istack[i].IP()})
+	}
+	t.pending = true
+}
+
+// Flush accounts GAS spent by the last executed instruction. It must be called
+// after VM execution is finished (or interrupted) and before the VM is
+// reused. It's safe to call it multiple times, the execution can also be
+// resumed after it.
+func (t *Tracer) Flush() {
+	var gas = t.v.GasConsumed()
+	if gas < t.gas { // VM was reset.
+		t.gas = 0
+	}
+	if len(t.stack) != 0 && (t.pending || gas != t.gas) {
+		var instr int64
+		if t.pending {
+			instr = 1
+		}
+		t.p.add(t.stack, gas-t.gas, instr)
+	}
+	t.gas = gas
+	t.pending = false
+}
+
+func (p *Profiler) add(stack []frame, gas int64, instructions int64) {
+	var key = make([]byte, 0, len(stack)*(util.Uint160Size+4))
+	for _, f := range stack {
+		key = append(key, f.hash[:]...)
+		key = append(key, byte(f.ip), byte(f.ip>>8), byte(f.ip>>16), byte(f.ip>>24))
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	s, ok := p.samples[string(key)]
+	if !ok {
+		s = &sample{stack: slices.Clone(stack)}
+		p.samples[string(key)] = s
+	}
+	s.gas += gas
+	s.instructions += instructions
+}
+
+// Profile returns pprof profile built from the data collected. Sample values
+// are GAS spent (in datoshi, the default one) and the number of instructions
+// executed.
+func (p *Profiler) Profile() *profile.Profile {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	var (
+		prof = &profile.Profile{
+			SampleType: []*profile.ValueType{
+				{Type: "gas", Unit: "datoshi"},
+				{Type: "instructions", Unit: "count"},
+			},
+			DefaultSampleType: "gas",
+			PeriodType:        &profile.ValueType{Type: "gas", Unit: "datoshi"},
+			Period:            1,
+		}
+		mappings  = make(map[util.Uint160]*profile.Mapping)
+		functions = make(map[string]*profile.Function)
+		locations = make(map[frame]*profile.Location)
+		keys      = make([]string, 0, len(p.samples))
+	)
+	for k := range p.samples {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys) // Make output deterministic.
+	for _, k := range keys {
+		var (
+			s    = p.samples[k]
+			locs = make([]*profile.Location, 0, len(s.stack))
+		)
+		for _, f := range s.stack {
+			loc, ok := locations[f]
+			if !ok {
+				m, ok := mappings[f.hash]
+				if !ok {
+					m = &profile.Mapping{
+						ID:              uint64(len(prof.Mapping) + 1),
+						File:            p.contractName(f.hash),
+						BuildID:         f.hash.StringLE(),
+						HasFunctions:    true,
+						HasFilenames:    true,
+						HasLineNumbers:  true,
+						HasInlineFrames: true,
+					}
+					mappings[f.hash] = m
+					prof.Mapping = append(prof.Mapping, m)
+				}
+				name, file, line := p.resolve(f)
+				fkey := f.hash.StringLE() + "/" + name + "/" + file
+				fn, ok := functions[fkey]
+				if !ok {
+					fn = &profile.Function{
+						ID:         uint64(len(prof.Function) + 1),
+						Name:       name,
+						SystemName: name,
+						Filename:   file,
+					}
+					functions[fkey] = fn
+					prof.Function = append(prof.Function, fn)
+				}
+				loc = &profile.Location{
+					ID:      uint64(len(prof.Location) + 1),
+					Mapping: m,
+					Address: uint64(f.ip),
+					Line:    []profile.Line{{Function: fn, Line: line}},
+				}
+				locations[f] = loc
+				prof.Location = append(prof.Location, loc)
+			}
+			locs = append(locs, loc)
+		}
+		prof.Sample = append(prof.Sample, &profile.Sample{
+			Location: locs,
+			Value:    []int64{s.gas, s.instructions},
+		})
+	}
+	return prof
+}
+
+// Write writes gzip-compressed pprof profile built from the data collected
+// to the given writer.
+func (p *Profiler) Write(w io.Writer) error {
+	return p.Profile().Write(w)
+}
+
+func (p *Profiler) contractName(h util.Uint160) string {
+	if c, ok := p.contracts[h]; ok {
+		return c.name
+	}
+	return "0x" + h.StringLE()
+}
+
+// resolve returns function name, source file name and line number for the
+// given frame.
+func (p *Profiler) resolve(f frame) (string, string, int64) {
+	c, ok := p.contracts[f.hash]
+	if !ok {
+		return p.contractName(f.hash), "", 0
+	}
+	if c.debugInfo != nil {
+		m, sp := c.debugInfo.SeqPointByOffset(f.ip)
+		if m != nil {
+			var (
+				name = m.ID
+				file string
+				line int64
+			)
+			if m.Name.Namespace != "" {
+				name = m.Name.Namespace + "." + name
+			}
+			if sp != nil && sp.Document < len(c.debugInfo.Documents) {
+				file = c.debugInfo.Documents[sp.Document]
+				line = int64(sp.StartLine)
+			}
+			return name, file, line
+		}
+	}
+	if c.manifest != nil {
+		var md *manifest.Method
+		for i := range c.manifest.ABI.Methods {
+			cur := &c.manifest.ABI.Methods[i]
+			if cur.Offset <= f.ip && (md == nil || cur.Offset > md.Offset) {
+				md = cur
+			}
+		}
+		if md != nil {
+			return c.name + "." + md.Name, "", 0
+		}
+	}
+	return c.name, "", 0
+}
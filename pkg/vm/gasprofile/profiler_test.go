@@ -0,0 +1,105 @@
+package gasprofile_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/pprof/profile"
+	"github.com/nspcc-dev/neo-go/pkg/compiler"
+	"github.com/nspcc-dev/neo-go/pkg/core/native/nativenames"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
+	"github.com/nspcc-dev/neo-go/pkg/neotest"
+	"github.com/nspcc-dev/neo-go/pkg/neotest/chain"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/trigger"
+	"github.com/nspcc-dev/neo-go/pkg/vm/gasprofile"
+	"github.com/stretchr/testify/require"
+)
+
+const src = `package foo
+import (
+	"github.com/nspcc-dev/neo-go/pkg/interop/native/gas"
+	"github.com/nspcc-dev/neo-go/pkg/interop/runtime"
+	"github.com/nspcc-dev/neo-go/pkg/interop/storage"
+)
+func Main(n int) int {
+	var sum int
+	for i := 0; i < n; i++ {
+		sum += i
+	}
+	put(sum)
+	return sum + gas.BalanceOf(runtime.GetExecutingScriptHash())
+}
+func put(sum int) {
+	storage.Put(storage.GetContext(), "sum", sum)
+}`
+
+func TestProfiler(t *testing.T) {
+	bc, acc := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+	ctr := neotest.CompileSource(t, e.CommitteeHash, strings.NewReader(src), &compiler.Options{
+		Name:        "Foo",
+		Permissions: []manifest.Permission{*manifest.NewPermission(manifest.PermissionWildcard)},
+	})
+	e.DeployContract(t, ctr, nil)
+
+	p := gasprofile.New()
+	script, err := smartcontract.CreateCallScript(ctr.Hash, "main", 10)
+	require.NoError(t, err)
+
+	tx := transaction.New(script, 0)
+	ic, err := bc.GetTestVM(trigger.Application, tx, nil)
+	require.NoError(t, err)
+	tracer := p.Attach(ic.VM)
+	ic.VM.LoadWithFlags(script, callflag.All)
+	require.NoError(t, ic.VM.Run())
+	tracer.Flush()
+	tracer.Flush() // Idempotent.
+
+	p.AddContract(ctr.Hash, ctr.Manifest, ctr.DebugInfo)
+	gasHash := e.NativeHash(t, nativenames.Gas)
+	gasState := bc.GetContractState(gasHash)
+	p.AddContract(gasHash, &gasState.Manifest, nil)
+
+	var buf bytes.Buffer
+	require.NoError(t, p.Write(&buf))
+	prof, err := profile.Parse(&buf)
+	require.NoError(t, err)
+	require.NoError(t, prof.CheckValid())
+	require.Equal(t, "gas", prof.DefaultSampleType)
+
+	var (
+		total, instrs int64
+		perFunc       = make(map[string]int64)
+		perLine       = make(map[int64]int64)
+	)
+	for _, s := range prof.Sample {
+		total += s.Value[0]
+		instrs += s.Value[1]
+		leaf := s.Location[0].Line[0]
+		perFunc[leaf.Function.Name] += s.Value[0]
+		if strings.HasSuffix(leaf.Function.Filename, "contract.go") {
+			perLine[leaf.Line] += s.Value[0]
+		}
+	}
+	require.Equal(t, ic.VM.GasConsumed(), total)
+	require.Positive(t, instrs)
+
+	require.Positive(t, perFunc["foo.Main"])
+	require.Positive(t, perFunc["foo.put"])
+	require.Positive(t, perFunc["GasToken.balanceOf"])
+	// Unregistered entry script.
+	require.Positive(t, perFunc["0x"+hash.Hash160(script).StringLE()])
+
+	require.Positive(t, perLine[10]) // Loop body.
+	require.Positive(t, perLine[12]) // put call.
+
+	t.Run("reset", func(t *testing.T) {
+		p.Reset()
+		require.Empty(t, p.Profile().Sample)
+	})
+}
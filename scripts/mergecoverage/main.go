@@ -0,0 +1,92 @@
+/*
+Mergecoverage combines LCOV coverage reports written by neotest for separate
+test packages (see NEOTEST_COVER_DIR) into a single report:
+
+	NEOTEST_COVER_DIR=$PWD/cover go test ./...
+	go -C /path/to/neo-go/scripts run ./mergecoverage --format cobertura --out $PWD/cover.xml $PWD/cover
+
+Arguments are LCOV files or directories containing them (*.lcov files are
+used). The result is written to the standard output if no output file is
+specified.
+*/
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/nspcc-dev/neo-go/pkg/neotest"
+	"github.com/urfave/cli/v2"
+)
+
+func main() {
+	ctl := cli.NewApp()
+	ctl.Name = "mergecoverage"
+	ctl.Version = "1.0"
+	ctl.Usage = "Merge neotest LCOV coverage reports"
+	ctl.ArgsUsage = "<file.lcov|dir> [<file.lcov|dir>...]"
+	ctl.Flags = []cli.Flag{
+		&cli.StringFlag{
+			Name:    "format",
+			Aliases: []string{"f"},
+			Value:   neotest.CoverageFormatLCOV,
+			Usage:   "Report format (lcov, cobertura or html)",
+		},
+		&cli.StringFlag{
+			Name:    "out",
+			Aliases: []string{"o"},
+			Usage:   "Output file (standard output if not specified)",
+		},
+	}
+	ctl.Action = merge
+
+	if err := ctl.Run(os.Args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func merge(c *cli.Context) error {
+	if c.NArg() == 0 {
+		return errors.New("no reports specified")
+	}
+	var files []string
+	for _, arg := range c.Args().Slice() {
+		fi, err := os.Stat(arg)
+		if err != nil {
+			return err
+		}
+		if !fi.IsDir() {
+			files = append(files, arg)
+			continue
+		}
+		matches, err := filepath.Glob(filepath.Join(arg, "*.lcov"))
+		if err != nil {
+			return err
+		}
+		files = append(files, matches...)
+	}
+	var readers []io.Reader
+	for _, name := range files {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		readers = append(readers, f)
+	}
+
+	var w io.Writer = os.Stdout
+	if out := c.String("out"); out != "" {
+		f, err := os.Create(out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	return neotest.MergeCoverage(w, c.String("format"), readers...)
+}